@@ -6,6 +6,7 @@ toolchain go1.23.6
 
 require (
 	github.com/anthropics/anthropic-sdk-go v1.22.0
+	github.com/lib/pq v1.10.9
 	github.com/mattn/go-sqlite3 v1.14.33
 	github.com/robfig/cron/v3 v3.0.1
 	github.com/slack-go/slack v0.17.3
@@ -3,18 +3,44 @@ package app
 import (
 	"log"
 	"os"
+	"reportbot/internal/backup"
 	"reportbot/internal/config"
 	"reportbot/internal/fetch"
 	"reportbot/internal/httpx"
+	"reportbot/internal/integrations/llm"
 	slackbot "reportbot/internal/integrations/slack"
 	"reportbot/internal/nudge"
-	"reportbot/internal/storage/sqlite"
+	"reportbot/internal/retention"
+	"reportbot/internal/storage"
+	"reportbot/internal/webhook"
 
 	"github.com/slack-go/slack"
 )
 
 func Main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "eval":
+			os.Exit(runEval(os.Args[2:]))
+		case "migrate":
+			os.Exit(runMigrate(os.Args[2:]))
+		case "retention":
+			os.Exit(runRetention(os.Args[2:]))
+		case "export":
+			os.Exit(runExport(os.Args[2:]))
+		case "import":
+			os.Exit(runImport(os.Args[2:]))
+		case "rollup":
+			os.Exit(runRollup(os.Args[2:]))
+		}
+	}
+
 	cfg := config.LoadConfig()
+	// llm_provider is checked against the provider registry, so providers
+	// added with llm.RegisterProvider are accepted like the built-in ones.
+	if _, err := llm.NewProvider(cfg); err != nil {
+		log.Fatalf("Invalid LLM config: %v", err)
+	}
 	appliedHTTPTimeout := httpx.ConfigureExternalHTTPClient(cfg.ExternalHTTPTimeoutSeconds, cfg.TLSSkipVerify)
 	log.Printf(
 		"Config loaded. Team=%s Managers=%d TeamMembers=%d Timezone=%s LLMBatchSize=%d LLMConfidenceThreshold=%.2f LLMExampleCount=%d LLMExampleMaxChars=%d LLMGlossaryPath=%s OpenAIBaseURL=%s ExternalHTTPTimeout=%s",
@@ -31,23 +57,53 @@ func Main() {
 		appliedHTTPTimeout,
 	)
 
-	db, err := sqlite.InitDB(cfg.DBPath)
+	db, err := storage.Open(cfg)
 	if err != nil {
 		log.Fatalf("Failed to init database: %v", err)
 	}
-	log.Printf("Database initialized at %s", cfg.DBPath)
+	log.Printf("Database initialized: %s", storage.Describe(cfg))
 	defer db.Close()
 
-	os.MkdirAll(cfg.ReportOutputDir, 0755)
-	log.Printf("Report output dir: %s", cfg.ReportOutputDir)
+	if cfg.EmbeddingsConfigured() {
+		log.Printf("Embedding example ranking enabled model=%s base_url=%s weight=%.2f", cfg.EmbeddingModel, cfg.EmbeddingBaseURL, cfg.EmbeddingWeight)
+	}
+
+	if cfg.MultiTeam() {
+		adopted, err := db.AdoptUnassignedWorkItems(cfg.Teams[0].ID)
+		if err != nil {
+			log.Fatalf("Failed to assign work items to team %s: %v", cfg.Teams[0].ID, err)
+		}
+		if adopted > 0 {
+			log.Printf("Assigned %d work items without a team to team %s", adopted, cfg.Teams[0].ID)
+		}
+	}
+	for _, teamCfg := range cfg.TeamConfigs() {
+		os.MkdirAll(teamCfg.ReportOutputDir, 0755)
+		log.Printf("Report output dir: %s (team %s)", teamCfg.ReportOutputDir, teamCfg.TeamName)
+	}
 
 	api := slack.New(
 		cfg.SlackBotToken,
 		slack.OptionAppLevelToken(cfg.SlackAppToken),
 	)
 
-	nudge.StartNudgeScheduler(cfg, db, api)
-	fetch.StartAutoFetchScheduler(cfg, db, api)
+	for _, teamCfg := range cfg.TeamConfigs() {
+		teamDB := db
+		if cfg.MultiTeam() {
+			teamDB = storage.ForTeam(db, teamCfg.TeamID)
+		}
+		// Each team reuses only its own decisions and spends its own budget.
+		llm.SetDecisionCache(teamCfg.TeamID, teamDB)
+		llm.SetUsageLedger(teamCfg.TeamID, teamDB)
+		if cfg.EmbeddingsConfigured() {
+			llm.SetEmbeddingStore(teamCfg.TeamID, teamDB)
+		}
+		nudge.StartNudgeScheduler(teamCfg, teamDB, api)
+		fetch.StartAutoFetchScheduler(teamCfg, teamDB, api)
+	}
+	retention.StartRetentionScheduler(cfg, db, api)
+	backup.StartBackupScheduler(cfg, db, api)
+	webhook.Start(cfg, db)
 
 	log.Println("Starting Engineering Report Bot...")
 	if err := slackbot.StartSlackBot(cfg, db, api); err != nil {
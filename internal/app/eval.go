@@ -0,0 +1,129 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"reportbot/internal/config"
+	"reportbot/internal/eval"
+	"reportbot/internal/httpx"
+	"reportbot/internal/integrations/llm"
+	"reportbot/internal/storage"
+	"time"
+)
+
+// runEval implements `reportbot eval`: replay classified history through the
+// classifier and score it against the final (corrected) sections.
+func runEval(args []string) int {
+	fs := flag.NewFlagSet("eval", flag.ContinueOnError)
+	since := fs.String("since", "", "only items reported on or after this date (YYYY-MM-DD); default 12 weeks ago")
+	limit := fs.Int("limit", 1000, "maximum number of items to replay")
+	providerName := fs.String("provider", "live", "live (configured llm_provider), history (decisions stored at the time) or replay (-recording file)")
+	recording := fs.String("recording", "", "JSONL recording: read by -provider replay, written by -provider live")
+	out := fs.String("out", "", "write the result as JSON to this path")
+	baselinePath := fs.String("baseline", "", "compare against a result JSON from an earlier run")
+	threshold := fs.Float64("threshold", 0, "confidence threshold override (default llm_confidence_threshold)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := config.LoadToolConfig()
+	httpx.ConfigureExternalHTTPClient(cfg.ExternalHTTPTimeoutSeconds, cfg.TLSSkipVerify)
+	if *threshold > 0 {
+		cfg.LLMConfidence = *threshold
+	}
+	sinceTime := time.Now().In(cfg.Location).AddDate(0, 0, -84)
+	if *since != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", *since, cfg.Location)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid -since %q: %v\n", *since, err)
+			return 2
+		}
+		sinceTime = parsed
+	}
+
+	db, err := storage.Open(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	labeled, err := db.GetLabeledWorkItems(sinceTime, *limit)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load labeled items: %v\n", err)
+		return 1
+	}
+	ds := eval.BuildDataset(labeled)
+	log.Printf("eval dataset items=%d sections=%d since=%s", len(ds.Items), len(ds.Options), sinceTime.Format("2006-01-02"))
+	if len(ds.Items) == 0 {
+		fmt.Fprintln(os.Stderr, "no classified work items in range; nothing to evaluate")
+		return 1
+	}
+
+	var provider llm.Provider
+	var recorder *eval.RecordingProvider
+	switch *providerName {
+	case "live":
+		live, err := llm.NewProvider(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "llm provider: %v\n", err)
+			return 1
+		}
+		provider = live
+		if *recording != "" {
+			recorder = eval.NewRecordingProvider(live, ds)
+			provider = recorder
+		}
+	case "history":
+		provider = eval.NewHistoryProvider(ds)
+	case "replay":
+		if *recording == "" {
+			fmt.Fprintln(os.Stderr, "-provider replay requires -recording")
+			return 2
+		}
+		decisions, err := eval.LoadRecording(*recording)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		provider = eval.NewReplayProvider("replay", ds, decisions)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown -provider %q (want live, history or replay)\n", *providerName)
+		return 2
+	}
+
+	result, err := eval.Run(cfg, ds, provider)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "eval run: %v\n", err)
+		return 1
+	}
+
+	var baseline *eval.Result
+	if *baselinePath != "" {
+		b, err := eval.LoadResult(*baselinePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		baseline = &b
+	}
+	fmt.Print(eval.FormatReport(result, baseline))
+
+	if recorder != nil {
+		if err := recorder.WriteRecording(*recording); err != nil {
+			fmt.Fprintf(os.Stderr, "write recording: %v\n", err)
+			return 1
+		}
+		log.Printf("eval recording saved path=%s", *recording)
+	}
+	if *out != "" {
+		if err := eval.WriteResult(*out, result); err != nil {
+			fmt.Fprintf(os.Stderr, "write result: %v\n", err)
+			return 1
+		}
+		log.Printf("eval result saved path=%s", *out)
+	}
+	return 0
+}
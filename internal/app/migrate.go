@@ -0,0 +1,92 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reportbot/internal/config"
+	"reportbot/internal/storage"
+	"text/tabwriter"
+	"time"
+)
+
+const migrateUsage = "usage: reportbot migrate status|up"
+
+// runMigrate implements `reportbot migrate status|up`: report the schema
+// version or apply pending migrations without starting the bot.
+func runMigrate(args []string) int {
+	if len(args) != 1 || (args[0] != "status" && args[0] != "up") {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	cfg := config.LoadToolConfig()
+	m, err := storage.OpenMigrator(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "open database: %v\n", err)
+		return 1
+	}
+	defer m.Close()
+
+	if args[0] == "up" {
+		applied, err := m.Migrate()
+		for _, s := range applied {
+			fmt.Printf("applied %d %s\n", s.Version, s.Name)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "migrate up: %v\n", err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+		return 0
+	}
+
+	statuses, err := m.ListMigrations()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migrate status: %v\n", err)
+		return 1
+	}
+	fmt.Printf("database: %s\n", storage.Describe(cfg))
+	if err := printMigrationStatus(os.Stdout, statuses); err != nil {
+		fmt.Fprintf(os.Stderr, "migrate status: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// printMigrationStatus writes one line per migration. It returns
+// storage.ErrSchemaTooNew when the database has versions this binary does
+// not know.
+func printMigrationStatus(w io.Writer, statuses []storage.MigrationStatus) error {
+	current, latest, pending := 0, 0, 0
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, s := range statuses {
+		state := "pending"
+		if s.Applied {
+			state = "applied " + s.AppliedAt.UTC().Format(time.RFC3339)
+			if s.Version > current {
+				current = s.Version
+			}
+		} else {
+			pending++
+		}
+		if s.Known {
+			if s.Version > latest {
+				latest = s.Version
+			}
+		} else {
+			state += " (unknown to this binary)"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", s.Version, s.Name, state)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "schema version %d, latest known %d, pending %d\n", current, latest, pending)
+	if current > latest {
+		return fmt.Errorf("%w: upgrade reportbot before running it against this database", storage.ErrSchemaTooNew)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reportbot/internal/config"
+	"reportbot/internal/retention"
+	"reportbot/internal/storage"
+	"time"
+)
+
+// runRetention implements `reportbot retention`: apply the configured
+// retention policy once, or report what it would remove with -dry-run.
+func runRetention(args []string) int {
+	fs := flag.NewFlagSet("retention", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "report what would be removed without deleting or archiving anything")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := config.LoadToolConfig()
+	if !cfg.RetentionConfigured() {
+		fmt.Fprintln(os.Stderr, "no retention configured: set retention_*_days or retention_superseded_corrections")
+		return 2
+	}
+
+	db, err := storage.Open(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	result, err := retention.Run(cfg, db, time.Now().In(cfg.Location), *dryRun)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	fmt.Println(retention.FormatSummary(result))
+	return 0
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reportbot/internal/config"
+	"reportbot/internal/domain"
+	"reportbot/internal/report"
+	"strings"
+	"time"
+)
+
+// teamDirFlags collects repeated -team Name=dir flags.
+type teamDirFlags []report.RollupSource
+
+func (f *teamDirFlags) String() string {
+	var parts []string
+	for _, s := range *f {
+		parts = append(parts, s.TeamName+"="+s.OutputDir)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (f *teamDirFlags) Set(v string) error {
+	name, dir, ok := strings.Cut(v, "=")
+	name, dir = strings.TrimSpace(name), strings.TrimSpace(dir)
+	if !ok || name == "" || dir == "" {
+		return fmt.Errorf("want <team name>=<report dir>, got %q", v)
+	}
+	*f = append(*f, report.RollupSource{TeamName: name, OutputDir: dir})
+	return nil
+}
+
+// runRollup implements `reportbot rollup`: combine the latest report of
+// several teams into one department report (markdown and .eml).
+func runRollup(args []string) int {
+	fs := flag.NewFlagSet("rollup", flag.ContinueOnError)
+	var teams teamDirFlags
+	fs.Var(&teams, "team", "team to include as <team name>=<report dir> (repeatable); default: the teams in config.yaml")
+	date := fs.String("date", "", "a day of the week to roll up (YYYY-MM-DD); default the current report week")
+	title := fs.String("title", "", "report title and file name prefix; default rollup_title")
+	out := fs.String("out", "", "output directory; default rollup_output_dir")
+	summary := fs.Bool("summary", false, "add an LLM-written summary paragraph per team (default rollup_llm_summary)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := config.LoadToolConfig()
+	now := time.Now().In(cfg.Location)
+	monday, _ := domain.ReportWeekRange(cfg, now)
+	if *date != "" {
+		day, err := parseDayFlag("-date", *date, now, cfg.Location)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		monday, _ = domain.CurrentWeekRangeAt(day)
+	}
+	if *title != "" {
+		cfg.RollupTitle = *title
+	}
+	outputDir := cfg.RollupOutputDir
+	if *out != "" {
+		outputDir = *out
+	}
+	summarize := cfg.RollupLLMSummary
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "summary" {
+			summarize = *summary
+		}
+	})
+
+	sources := []report.RollupSource(teams)
+	if len(sources) == 0 {
+		if !cfg.MultiTeam() {
+			fmt.Fprintln(os.Stderr, "no teams to roll up: configure teams in config.yaml or pass -team <name>=<dir>")
+			return 2
+		}
+		sources = report.RollupSources(cfg)
+	}
+
+	r, err := report.BuildRollup(cfg, sources, domain.FridayOfWeek(monday), summarize)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "rollup: %v\n", err)
+		return 1
+	}
+	mdPath, emlPath, err := report.WriteRollupFiles(r, outputDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "write rollup: %v\n", err)
+		return 1
+	}
+	fmt.Println(report.FormatRollupSummary(r))
+	fmt.Printf("Written to %s and %s\n", mdPath, emlPath)
+	return 0
+}
@@ -0,0 +1,175 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"reportbot/internal/config"
+	"reportbot/internal/storage"
+	"reportbot/internal/transfer"
+	"sort"
+	"time"
+)
+
+// runExport implements `reportbot export`: write the bot state to a
+// versioned archive, or with -csv a date range of work items as CSV.
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	out := fs.String("out", "", "output path; default reportbot-export-YYYYMMDD.tar.gz, or stdout with -csv")
+	csvMode := fs.Bool("csv", false, "write work items as CSV instead of a full archive")
+	from := fs.String("from", "", "with -csv: first day to include (YYYY-MM-DD); default 4 weeks ago")
+	to := fs.String("to", "", "with -csv: last day to include (YYYY-MM-DD); default today")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := config.LoadToolConfig()
+	now := time.Now().In(cfg.Location)
+
+	var fromTime, toTime time.Time
+	if *csvMode {
+		var err error
+		if fromTime, err = parseDayFlag("-from", *from, now.AddDate(0, 0, -28), cfg.Location); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		if toTime, err = parseDayFlag("-to", *to, now, cfg.Location); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		toTime = toTime.AddDate(0, 0, 1)
+		if !fromTime.Before(toTime) {
+			fmt.Fprintln(os.Stderr, "-from must not be after -to")
+			return 2
+		}
+	} else if *from != "" || *to != "" {
+		fmt.Fprintln(os.Stderr, "-from and -to require -csv")
+		return 2
+	}
+
+	db, err := storage.Open(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	path := *out
+	if path == "" && !*csvMode {
+		path = fmt.Sprintf("reportbot-export-%s.tar.gz", now.Format("20060102"))
+	}
+	var w io.Writer = os.Stdout
+	var f *os.File
+	if path != "" && path != "-" {
+		f, err = os.Create(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "create %s: %v\n", path, err)
+			return 1
+		}
+		w = f
+	}
+
+	if *csvMode {
+		items, err := db.GetItemsByDateRange(fromTime, toTime)
+		if err == nil {
+			sort.SliceStable(items, func(i, j int) bool {
+				if !items[i].ReportedAt.Equal(items[j].ReportedAt) {
+					return items[i].ReportedAt.Before(items[j].ReportedAt)
+				}
+				return items[i].ID < items[j].ID
+			})
+			err = transfer.WriteCSV(w, items, cfg.Location)
+		}
+		if err == nil && f != nil {
+			err = f.Close()
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "export csv: %v\n", err)
+			return 1
+		}
+		log.Printf("export csv items=%d from=%s to=%s", len(items), fromTime.Format("2006-01-02"), toTime.AddDate(0, 0, -1).Format("2006-01-02"))
+		return 0
+	}
+
+	m, err := transfer.Export(w, cfg, db, now)
+	if err == nil && f != nil {
+		err = f.Close()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "exported %d work items, %d classification history rows, %d corrections, %d report files (glossary: %t) to %s\n",
+		m.WorkItems, m.ClassificationHistory, m.Corrections, m.ReportFiles, m.Glossary, path)
+	return 0
+}
+
+// runImport implements `reportbot import <archive>`.
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	overwrite := fs.Bool("overwrite", false, "replace glossary and report files that differ from the archive")
+	team := fs.String("team", "", "teams[].id to assign imported work items without a team to; reports go to its output dir")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: reportbot import [-overwrite] [-team <id>] <archive.tar.gz>")
+		return 2
+	}
+
+	cfg := config.LoadToolConfig()
+	if *team != "" {
+		teamCfg, ok := findTeam(cfg, *team)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown team %q (see teams in config.yaml)\n", *team)
+			return 2
+		}
+		cfg = teamCfg
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "open archive: %v\n", err)
+		return 1
+	}
+	defer f.Close()
+
+	db, err := storage.Open(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	if *team != "" {
+		db = storage.ForTeam(db, *team)
+	}
+	sum, err := transfer.Import(f, cfg, db, *overwrite)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import: %v\n", err)
+		return 1
+	}
+	fmt.Println(transfer.FormatImportSummary(sum))
+	return 0
+}
+
+func findTeam(cfg config.Config, id string) (config.Config, bool) {
+	for _, t := range cfg.Teams {
+		if t.ID == id {
+			return cfg.ForTeam(t), true
+		}
+	}
+	return cfg, false
+}
+
+func parseDayFlag(name, value string, def time.Time, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Date(def.Year(), def.Month(), def.Day(), 0, 0, 0, 0, loc), nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q: %v", name, value, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,227 @@
+// Package backup takes hot copies of the SQLite database with VACUUM INTO,
+// verifies each copy with PRAGMA integrity_check and rotates old copies.
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reportbot/internal/storage"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/slack-go/slack"
+)
+
+const (
+	filePrefix = "reportbot-"
+	fileSuffix = ".db"
+	// fileTimeLayout is part of the file name, so rotation can date backups
+	// without trusting file modification times.
+	fileTimeLayout = "20060102-150405"
+)
+
+// ErrUnsupported is returned for backends without online backup support.
+var ErrUnsupported = errors.New("online backup is only supported with db_driver=sqlite; back up PostgreSQL with pg_dump")
+
+// ErrBusy is returned when another backup is still running.
+var ErrBusy = errors.New("a backup is already running")
+
+// runMu keeps the scheduler and /backup from writing at the same time.
+var runMu sync.Mutex
+
+// Result describes one backup.
+type Result struct {
+	Path     string
+	Size     int64
+	Duration time.Duration
+	// Removed lists older backups deleted by rotation.
+	Removed []string
+}
+
+// Run backs up db into cfg.BackupDir, checks the copy and applies rotation.
+// The copy is written under a temporary name and only renamed into place
+// once it passed the integrity check.
+func Run(cfg Config, db Store, now time.Time) (Result, error) {
+	var res Result
+	b, ok := db.(storage.Backuper)
+	if !ok {
+		return res, ErrUnsupported
+	}
+	if !runMu.TryLock() {
+		return res, ErrBusy
+	}
+	defer runMu.Unlock()
+
+	if err := os.MkdirAll(cfg.BackupDir, 0755); err != nil {
+		return res, fmt.Errorf("create backup dir: %w", err)
+	}
+	start := time.Now()
+	final := filepath.Join(cfg.BackupDir, filePrefix+now.Format(fileTimeLayout)+fileSuffix)
+	if _, err := os.Stat(final); err == nil {
+		return res, fmt.Errorf("backup %s already exists", final)
+	}
+	tmp := final + ".tmp"
+	os.Remove(tmp)
+
+	if err := b.Backup(tmp); err != nil {
+		os.Remove(tmp)
+		return res, err
+	}
+	if err := storage.CheckBackup(tmp); err != nil {
+		os.Remove(tmp)
+		return res, err
+	}
+	if err := os.Rename(tmp, final); err != nil {
+		os.Remove(tmp)
+		return res, err
+	}
+	info, err := os.Stat(final)
+	if err != nil {
+		return res, err
+	}
+	res.Path = final
+	res.Size = info.Size()
+	res.Duration = time.Since(start)
+
+	res.Removed, err = rotate(cfg.BackupDir, cfg.BackupKeepDaily, cfg.BackupKeepWeekly, now.Location())
+	if err != nil {
+		return res, fmt.Errorf("rotate backups: %w", err)
+	}
+	return res, nil
+}
+
+type backupFile struct {
+	name string
+	at   time.Time
+}
+
+// rotate deletes backups that are neither the newest of one of the last
+// keepDaily days nor the newest of one of the last keepWeekly ISO weeks
+// that have backups. Files not named like backups are left alone.
+func rotate(dir string, keepDaily, keepWeekly int, loc *time.Location) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []backupFile
+	for _, e := range entries {
+		name := e.Name()
+		if !e.Type().IsRegular() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+		at, err := time.ParseInLocation(fileTimeLayout, strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix), loc)
+		if err != nil {
+			continue
+		}
+		files = append(files, backupFile{name: name, at: at})
+	}
+
+	var removed []string
+	for _, f := range expired(files, keepDaily, keepWeekly) {
+		if err := os.Remove(filepath.Join(dir, f.name)); err != nil {
+			return removed, err
+		}
+		removed = append(removed, f.name)
+	}
+	return removed, nil
+}
+
+func expired(files []backupFile, keepDaily, keepWeekly int) []backupFile {
+	sort.Slice(files, func(i, j int) bool { return files[i].at.After(files[j].at) })
+	keep := make(map[string]bool)
+	days := make(map[string]bool)
+	weeks := make(map[string]bool)
+	for _, f := range files {
+		day := f.at.Format("2006-01-02")
+		if !days[day] && len(days) < keepDaily {
+			days[day] = true
+			keep[f.name] = true
+		}
+		year, week := f.at.ISOWeek()
+		wk := fmt.Sprintf("%d-W%02d", year, week)
+		if !weeks[wk] && len(weeks) < keepWeekly {
+			weeks[wk] = true
+			keep[f.name] = true
+		}
+	}
+	var out []backupFile
+	for _, f := range files {
+		if !keep[f.name] {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// FormatResult renders a backup result for Slack and logs.
+func FormatResult(r Result) string {
+	msg := fmt.Sprintf("Backup written to %s (%s, %s, integrity ok).",
+		r.Path, formatSize(r.Size), r.Duration.Round(time.Millisecond))
+	if len(r.Removed) > 0 {
+		msg += fmt.Sprintf(" Rotated out %d old backup(s).", len(r.Removed))
+	}
+	return msg
+}
+
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// StartBackupScheduler backs up the database on backup_schedule (a standard
+// 5-field cron expression). Failures are posted to the notice channels.
+func StartBackupScheduler(cfg Config, db Store, api *slack.Client) {
+	schedule := strings.TrimSpace(cfg.BackupSchedule)
+	if schedule == "" {
+		log.Println("Scheduled backup disabled (backup_schedule not set)")
+		return
+	}
+	if _, ok := db.(storage.Backuper); !ok {
+		log.Printf("Scheduled backup disabled: %v", ErrUnsupported)
+		return
+	}
+
+	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+	sched, err := parser.Parse(schedule)
+	if err != nil {
+		log.Printf("Invalid backup_schedule '%s': %v — scheduled backup disabled", schedule, err)
+		return
+	}
+	log.Printf("Backup scheduled (cron: %s) dir=%s keep_daily=%d keep_weekly=%d",
+		schedule, cfg.BackupDir, cfg.BackupKeepDaily, cfg.BackupKeepWeekly)
+
+	go func() {
+		for {
+			now := time.Now().In(cfg.Location)
+			next := sched.Next(now)
+			time.Sleep(next.Sub(now))
+
+			result, err := Run(cfg, db, time.Now().In(cfg.Location))
+			if err != nil {
+				log.Printf("Backup error: %v", err)
+				for _, channelID := range cfg.NoticeChannelIDs() {
+					if _, _, postErr := api.PostMessage(channelID, slack.MsgOptionText(
+						fmt.Sprintf("Scheduled backup failed: %v", err), false)); postErr != nil {
+						log.Printf("Backup post error channel=%s: %v", channelID, postErr)
+					}
+				}
+				continue
+			}
+			log.Printf("Backup complete: %s", FormatResult(result))
+		}
+	}()
+}
@@ -0,0 +1,110 @@
+package backup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reportbot/internal/domain"
+	"reportbot/internal/storage"
+	"reportbot/internal/storage/sqlite"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestRunWritesCheckedBackupAndRotates(t *testing.T) {
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "live.db"))
+	if err != nil {
+		t.Fatalf("sqlite.Open: %v", err)
+	}
+	defer db.Close()
+	if err := db.InsertWorkItem(domain.WorkItem{Description: "Fix login", Author: "Alice", Source: "slack", ReportedAt: time.Now()}, ""); err != nil {
+		t.Fatalf("InsertWorkItem: %v", err)
+	}
+
+	cfg := Config{BackupDir: filepath.Join(t.TempDir(), "backups"), BackupKeepDaily: 1}
+	first := time.Date(2026, 3, 2, 2, 0, 0, 0, time.UTC)
+	res, err := Run(cfg, db, first)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if res.Size == 0 || filepath.Base(res.Path) != "reportbot-20260302-020000.db" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	restored, err := sqlite.Open(res.Path)
+	if err != nil {
+		t.Fatalf("open backup: %v", err)
+	}
+	items, err := restored.GetItemsByDateRange(time.Time{}, time.Now().Add(time.Hour))
+	restored.Close()
+	if err != nil || len(items) != 1 || items[0].Description != "Fix login" {
+		t.Fatalf("backup content: %+v err=%v", items, err)
+	}
+
+	res, err = Run(cfg, db, first.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+	if len(res.Removed) != 1 || res.Removed[0] != "reportbot-20260302-020000.db" {
+		t.Fatalf("expected the older backup rotated out, removed %v", res.Removed)
+	}
+	if entries, _ := os.ReadDir(cfg.BackupDir); len(entries) != 1 {
+		t.Fatalf("expected one backup left, got %d", len(entries))
+	}
+}
+
+func TestRunRejectsBackendsWithoutBackup(t *testing.T) {
+	var db struct{ storage.Store }
+	if _, err := Run(Config{BackupDir: t.TempDir()}, db, time.Now()); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported, got %v", err)
+	}
+}
+
+func TestExpiredKeepsNewestPerDayAndWeek(t *testing.T) {
+	// Daily backups at 02:00 and an extra one at 14:00 on the newest day,
+	// from Mon Jan 5 to Thu Feb 5 2026.
+	var files []backupFile
+	start := time.Date(2026, 1, 5, 2, 0, 0, 0, time.UTC)
+	for d := 0; d <= 31; d++ {
+		at := start.AddDate(0, 0, d)
+		files = append(files, backupFile{name: at.Format(fileTimeLayout), at: at})
+	}
+	newest := start.AddDate(0, 0, 31).Add(12 * time.Hour)
+	files = append(files, backupFile{name: newest.Format(fileTimeLayout), at: newest})
+
+	gone := map[string]bool{}
+	for _, f := range expired(files, 3, 3) {
+		gone[f.name] = true
+	}
+	var kept []string
+	for _, f := range files {
+		if !gone[f.name] {
+			kept = append(kept, f.name)
+		}
+	}
+	sort.Strings(kept)
+	want := []string{
+		"20260125-020000", // newest of the week of Jan 19
+		"20260201-020000", // newest of the week of Jan 26
+		"20260203-020000", // daily
+		"20260204-020000", // daily
+		"20260205-140000", // daily and newest of the current week
+	}
+	if len(kept) != len(want) {
+		t.Fatalf("kept %v, want %v", kept, want)
+	}
+	for i := range want {
+		if kept[i] != want[i] {
+			t.Fatalf("kept %v, want %v", kept, want)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	for n, want := range map[int64]string{512: "512 B", 2048: "2.0 KiB", 5 << 20: "5.0 MiB"} {
+		if got := formatSize(n); got != want {
+			t.Fatalf("formatSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package backup
+
+import (
+	"reportbot/internal/config"
+	"reportbot/internal/storage"
+)
+
+type Config = config.Config
+type Store = storage.Store
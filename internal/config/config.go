@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -14,6 +16,53 @@ import (
 const defaultExternalHTTPTimeout = 90 * time.Second
 const defaultExternalHTTPTimeoutSeconds = int(defaultExternalHTTPTimeout / time.Second)
 
+const (
+	RetentionModeArchive = "archive"
+	RetentionModePurge   = "purge"
+)
+
+// GitHub APIs the PR fetcher can use. GraphQL returns real merge times and
+// author names; the REST search API is the older, simpler fallback.
+const (
+	GitHubFetchAPIGraphQL = "graphql"
+	GitHubFetchAPISearch  = "search"
+)
+
+// LLMPrice is what a model costs in USD per million tokens. Cached input
+// tokens fall back to the input price when CachedInputPerMTok is zero.
+type LLMPrice struct {
+	InputPerMTok       float64 `yaml:"input_per_mtok"`
+	OutputPerMTok      float64 `yaml:"output_per_mtok"`
+	CachedInputPerMTok float64 `yaml:"cached_input_per_mtok"`
+}
+
+// RedactionRule is a named regular expression whose matches are replaced
+// with [NAME_n] placeholders before prompts leave the bot.
+type RedactionRule struct {
+	Name    string `yaml:"name"`
+	Pattern string `yaml:"pattern"`
+}
+
+// Team is one entry of the teams list. Members, channels and report output
+// are the team's own; managers are added to the top-level manager_slack_ids,
+// and an unset glossary or classification guide uses the top-level one.
+type Team struct {
+	ID              string   `yaml:"id"`
+	Name            string   `yaml:"name"`
+	Members         []string `yaml:"members"`
+	ManagerSlackIDs []string `yaml:"manager_slack_ids"`
+	ReportChannelID string   `yaml:"report_channel_id"`
+	// ChannelIDs are further channels whose slash commands act on this team.
+	ChannelIDs []string `yaml:"channel_ids"`
+	// ReportOutputDir holds the team's reports, the newest of which is the
+	// template for the next one. Defaults to <report_output_dir>/<id>.
+	ReportOutputDir string `yaml:"report_output_dir"`
+	LLMGlossaryPath string `yaml:"llm_glossary_path"`
+	LLMGuidePath    string `yaml:"llm_classification_guide_path"`
+}
+
+var teamIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
 type Config struct {
 	SlackBotToken string `yaml:"slack_bot_token"`
 	SlackAppToken string `yaml:"slack_app_token"`
@@ -23,9 +72,20 @@ type Config struct {
 	GitLabGroupID        string `yaml:"gitlab_group_id"`
 	GitLabRefTicketLabel string `yaml:"gitlab_ref_ticket_label"`
 
-	GitHubToken string   `yaml:"github_token"`
-	GitHubOrg   string   `yaml:"github_org"`
-	GitHubRepos []string `yaml:"github_repos"`
+	GitHubToken    string   `yaml:"github_token"`
+	GitHubOrg      string   `yaml:"github_org"`
+	GitHubRepos    []string `yaml:"github_repos"`
+	GitHubFetchAPI string   `yaml:"github_fetch_api"`
+	// GitHubBaseURL is the web URL of a GitHub Enterprise Server instance
+	// (e.g. "https://github.example.com"); empty means github.com.
+	GitHubBaseURL string `yaml:"github_base_url"`
+
+	// Webhook receiver for GitLab merge request hooks and GitHub
+	// pull_request events, disabled while webhook_listen_addr is empty. Each
+	// source is accepted only when its secret is set.
+	WebhookListenAddr   string `yaml:"webhook_listen_addr"`
+	GitLabWebhookSecret string `yaml:"gitlab_webhook_secret"`
+	GitHubWebhookSecret string `yaml:"github_webhook_secret"`
 
 	LLMProvider      string  `yaml:"llm_provider"`
 	LLMModel         string  `yaml:"llm_model"`
@@ -36,15 +96,35 @@ type Config struct {
 	LLMGlossaryPath  string  `yaml:"llm_glossary_path"`
 	LLMGuidePath     string  `yaml:"llm_classification_guide_path"`
 	LLMCriticEnabled bool    `yaml:"llm_critic_enabled"`
+	// Per-model prices in USD per million tokens, keyed by model name.
+	LLMPrices           map[string]LLMPrice `yaml:"llm_prices"`
+	LLMBudgetWeeklyUSD  float64             `yaml:"llm_budget_weekly_usd"`
+	LLMBudgetMonthlyUSD float64             `yaml:"llm_budget_monthly_usd"`
+	// Redaction of item text before it is sent to any provider.
+	LLMRedactionEnabled   bool            `yaml:"llm_redaction_enabled"`
+	LLMRedactionRules     []RedactionRule `yaml:"llm_redaction_rules"`
+	LLMRedactionNamesPath string          `yaml:"llm_redaction_names_path"`
 	// Backward compatibility for old key name.
 	ReportTemplatePath string `yaml:"report_template_path"`
 	AnthropicAPIKey    string `yaml:"anthropic_api_key"`
 	OpenAIAPIKey       string `yaml:"openai_api_key"`
 	OpenAIBaseURL      string `yaml:"openai_base_url"`
+	OpenAILogprobs     bool   `yaml:"openai_logprobs"`
+	OllamaBaseURL      string `yaml:"ollama_base_url"`
+
+	EmbeddingModel   string  `yaml:"embedding_model"`
+	EmbeddingBaseURL string  `yaml:"embedding_base_url"`
+	EmbeddingAPIKey  string  `yaml:"embedding_api_key"`
+	EmbeddingWeight  float64 `yaml:"embedding_weight"`
 
+	DBDriver                   string `yaml:"db_driver"`
 	DBPath                     string `yaml:"db_path"`
+	DBDSN                      string `yaml:"db_dsn"`
 	ReportOutputDir            string `yaml:"report_output_dir"`
 	ReportChannelID            string `yaml:"report_channel_id"`
+	// OpsChannelID receives bot-wide notices (retention, backups); see
+	// NoticeChannelIDs.
+	OpsChannelID               string `yaml:"ops_channel_id"`
 	ExternalHTTPTimeoutSeconds int  `yaml:"external_http_timeout_seconds"`
 	TLSSkipVerify              bool `yaml:"tls_skip_verify"`
 
@@ -53,14 +133,57 @@ type Config struct {
 	NudgeDay          string   `yaml:"nudge_day"`
 	NudgeTime         string   `yaml:"nudge_time"`
 	AutoFetchSchedule string   `yaml:"auto_fetch_schedule"`
+	// Retention: days to keep each table (0 keeps forever), run by a
+	// nightly job that archives to gzipped JSONL or purges outright.
+	RetentionSchedule              string `yaml:"retention_schedule"`
+	RetentionMode                  string `yaml:"retention_mode"`
+	RetentionArchiveDir            string `yaml:"retention_archive_dir"`
+	RetentionDryRun                bool   `yaml:"retention_dry_run"`
+	RetentionWorkItemsDays         int    `yaml:"retention_work_items_days"`
+	RetentionClassificationDays    int    `yaml:"retention_classification_history_days"`
+	RetentionCorrectionsDays       int    `yaml:"retention_corrections_days"`
+	RetentionSupersededCorrections bool   `yaml:"retention_superseded_corrections"`
+	// SQLite hot backups on a cron schedule, rotated to keep the newest
+	// backup of each of the last N days and M weeks.
+	BackupSchedule   string `yaml:"backup_schedule"`
+	BackupDir        string `yaml:"backup_dir"`
+	BackupKeepDaily  int    `yaml:"backup_keep_daily"`
+	BackupKeepWeekly int    `yaml:"backup_keep_weekly"`
+	// Department roll-up of the latest report of every team, written as
+	// <rollup_title>_YYYYMMDD.md and .eml to rollup_output_dir.
+	RollupTitle      string `yaml:"rollup_title"`
+	RollupOutputDir  string `yaml:"rollup_output_dir"`
+	RollupLLMSummary bool   `yaml:"rollup_llm_summary"`
+	// PeriodLLMSummary adds a theme summary per category to monthly and
+	// quarterly reports.
+	PeriodLLMSummary bool `yaml:"period_llm_summary"`
 	MondayCutoffTime  string   `yaml:"monday_cutoff_time"`
 	Timezone          string   `yaml:"timezone"`
 	TeamName          string   `yaml:"team_name"`
+	// Teams runs several teams from one deployment. When set, team_name,
+	// team_members and report_channel_id are not used; see Team.
+	Teams []Team `yaml:"teams"`
 
 	Location *time.Location `yaml:"-"` // computed from Timezone, not from YAML
+	// TeamID is the team a ForTeam copy belongs to; empty in a single-team
+	// deployment.
+	TeamID string `yaml:"-"`
+	// top is the config a ForTeam copy was derived from.
+	top *Config
 }
 
 func LoadConfig() Config {
+	return loadConfig(true)
+}
+
+// LoadToolConfig loads config for offline subcommands (eval, migrations)
+// that do not connect to Slack, so Slack tokens and LLM API keys are not
+// required. Everything else is validated as usual.
+func LoadToolConfig() Config {
+	return loadConfig(false)
+}
+
+func loadConfig(requireSecrets bool) Config {
 	var cfg Config
 
 	configPath := "config.yaml"
@@ -91,6 +214,11 @@ func LoadConfig() Config {
 			}
 		}
 	}
+	envOverride(&cfg.GitHubFetchAPI, "GITHUB_FETCH_API")
+	envOverride(&cfg.GitHubBaseURL, "GITHUB_BASE_URL")
+	envOverride(&cfg.WebhookListenAddr, "WEBHOOK_LISTEN_ADDR")
+	envOverride(&cfg.GitLabWebhookSecret, "GITLAB_WEBHOOK_SECRET")
+	envOverride(&cfg.GitHubWebhookSecret, "GITHUB_WEBHOOK_SECRET")
 	envOverride(&cfg.LLMProvider, "LLM_PROVIDER")
 	envOverride(&cfg.LLMModel, "LLM_MODEL")
 	envOverrideInt(&cfg.LLMBatchSize, "LLM_BATCH_SIZE")
@@ -100,19 +228,48 @@ func LoadConfig() Config {
 	envOverride(&cfg.LLMGlossaryPath, "LLM_GLOSSARY_PATH")
 	envOverride(&cfg.LLMGuidePath, "LLM_CLASSIFICATION_GUIDE_PATH")
 	envOverrideBool(&cfg.LLMCriticEnabled, "LLM_CRITIC_ENABLED")
+	envOverrideFloat(&cfg.LLMBudgetWeeklyUSD, "LLM_BUDGET_WEEKLY_USD")
+	envOverrideFloat(&cfg.LLMBudgetMonthlyUSD, "LLM_BUDGET_MONTHLY_USD")
+	envOverrideBool(&cfg.LLMRedactionEnabled, "LLM_REDACTION_ENABLED")
+	envOverride(&cfg.LLMRedactionNamesPath, "LLM_REDACTION_NAMES_PATH")
 	envOverride(&cfg.ReportTemplatePath, "REPORT_TEMPLATE_PATH")
 	envOverride(&cfg.AnthropicAPIKey, "ANTHROPIC_API_KEY")
 	envOverride(&cfg.OpenAIAPIKey, "OPENAI_API_KEY")
 	envOverride(&cfg.OpenAIBaseURL, "OPENAI_BASE_URL")
+	envOverrideBool(&cfg.OpenAILogprobs, "OPENAI_LOGPROBS")
+	envOverride(&cfg.OllamaBaseURL, "OLLAMA_BASE_URL")
+	envOverride(&cfg.EmbeddingModel, "EMBEDDING_MODEL")
+	envOverride(&cfg.EmbeddingBaseURL, "EMBEDDING_BASE_URL")
+	envOverride(&cfg.EmbeddingAPIKey, "EMBEDDING_API_KEY")
+	envOverrideFloat(&cfg.EmbeddingWeight, "EMBEDDING_WEIGHT")
+	envOverride(&cfg.DBDriver, "DB_DRIVER")
 	envOverride(&cfg.DBPath, "DB_PATH")
+	envOverride(&cfg.DBDSN, "DB_DSN")
 	envOverride(&cfg.ReportOutputDir, "REPORT_OUTPUT_DIR")
 	envOverride(&cfg.ReportChannelID, "REPORT_CHANNEL_ID")
+	envOverride(&cfg.OpsChannelID, "OPS_CHANNEL_ID")
 	envOverrideInt(&cfg.ExternalHTTPTimeoutSeconds, "EXTERNAL_HTTP_TIMEOUT_SECONDS")
 	envOverrideBool(&cfg.TLSSkipVerify, "TLS_SKIP_VERIFY")
 	envOverride(&cfg.TeamName, "TEAM_NAME")
 	envOverride(&cfg.NudgeDay, "NUDGE_DAY")
 	envOverride(&cfg.NudgeTime, "NUDGE_TIME")
 	envOverride(&cfg.AutoFetchSchedule, "AUTO_FETCH_SCHEDULE")
+	envOverride(&cfg.RetentionSchedule, "RETENTION_SCHEDULE")
+	envOverride(&cfg.RetentionMode, "RETENTION_MODE")
+	envOverride(&cfg.RetentionArchiveDir, "RETENTION_ARCHIVE_DIR")
+	envOverrideBool(&cfg.RetentionDryRun, "RETENTION_DRY_RUN")
+	envOverrideInt(&cfg.RetentionWorkItemsDays, "RETENTION_WORK_ITEMS_DAYS")
+	envOverrideInt(&cfg.RetentionClassificationDays, "RETENTION_CLASSIFICATION_HISTORY_DAYS")
+	envOverrideInt(&cfg.RetentionCorrectionsDays, "RETENTION_CORRECTIONS_DAYS")
+	envOverrideBool(&cfg.RetentionSupersededCorrections, "RETENTION_SUPERSEDED_CORRECTIONS")
+	envOverride(&cfg.BackupSchedule, "BACKUP_SCHEDULE")
+	envOverride(&cfg.BackupDir, "BACKUP_DIR")
+	envOverrideInt(&cfg.BackupKeepDaily, "BACKUP_KEEP_DAILY")
+	envOverrideInt(&cfg.BackupKeepWeekly, "BACKUP_KEEP_WEEKLY")
+	envOverride(&cfg.RollupTitle, "ROLLUP_TITLE")
+	envOverride(&cfg.RollupOutputDir, "ROLLUP_OUTPUT_DIR")
+	envOverrideBool(&cfg.RollupLLMSummary, "ROLLUP_LLM_SUMMARY")
+	envOverrideBool(&cfg.PeriodLLMSummary, "PERIOD_LLM_SUMMARY")
 	envOverride(&cfg.MondayCutoffTime, "MONDAY_CUTOFF_TIME")
 	envOverride(&cfg.Timezone, "TIMEZONE")
 
@@ -147,12 +304,25 @@ func LoadConfig() Config {
 	if cfg.LLMGuidePath == "" {
 		cfg.LLMGuidePath = "./llm_classification_guide.md"
 	}
+	cfg.DBDriver = strings.ToLower(strings.TrimSpace(cfg.DBDriver))
+	if cfg.DBDriver == "" {
+		cfg.DBDriver = "sqlite"
+	}
 	if cfg.DBPath == "" {
 		cfg.DBPath = "./reportbot.db"
 	}
 	if cfg.OpenAIBaseURL == "" {
 		cfg.OpenAIBaseURL = "https://api.openai.com/v1"
 	}
+	if cfg.EmbeddingModel != "" && cfg.EmbeddingBaseURL == "" {
+		cfg.EmbeddingBaseURL = cfg.OpenAIBaseURL
+		if cfg.EmbeddingAPIKey == "" {
+			cfg.EmbeddingAPIKey = cfg.OpenAIAPIKey
+		}
+	}
+	if cfg.EmbeddingWeight == 0 {
+		cfg.EmbeddingWeight = 0.5
+	}
 	if cfg.ReportOutputDir == "" {
 		cfg.ReportOutputDir = "./reports"
 	}
@@ -171,6 +341,30 @@ func LoadConfig() Config {
 	if cfg.TeamName == "" {
 		cfg.TeamName = "My Team"
 	}
+	cfg.GitHubBaseURL = strings.TrimRight(strings.TrimSpace(cfg.GitHubBaseURL), "/")
+	cfg.GitHubFetchAPI = strings.ToLower(strings.TrimSpace(cfg.GitHubFetchAPI))
+	if cfg.GitHubFetchAPI == "" {
+		cfg.GitHubFetchAPI = GitHubFetchAPIGraphQL
+	}
+	cfg.RetentionMode = strings.ToLower(strings.TrimSpace(cfg.RetentionMode))
+	if cfg.RetentionMode == "" {
+		cfg.RetentionMode = RetentionModeArchive
+	}
+	if cfg.RetentionArchiveDir == "" {
+		cfg.RetentionArchiveDir = "./reportbot-archive"
+	}
+	if cfg.BackupDir == "" {
+		cfg.BackupDir = "./reportbot-backups"
+	}
+	if cfg.BackupKeepDaily == 0 {
+		cfg.BackupKeepDaily = 7
+	}
+	if cfg.RollupTitle == "" {
+		cfg.RollupTitle = "Department"
+	}
+	if cfg.RollupOutputDir == "" {
+		cfg.RollupOutputDir = cfg.ReportOutputDir
+	}
 	if cfg.Timezone == "" {
 		cfg.Timezone = "Local"
 	}
@@ -180,7 +374,7 @@ func LoadConfig() Config {
 		"slack_app_token": cfg.SlackAppToken,
 	}
 	for name, val := range required {
-		if val == "" {
+		if val == "" && requireSecrets {
 			log.Fatalf("Required config '%s' is not set (via config.yaml or env var)", name)
 		}
 	}
@@ -212,17 +406,30 @@ func LoadConfig() Config {
 		log.Printf("WARNING: Neither GitLab nor GitHub is configured. /fetch will have nothing to fetch.")
 	}
 
-	switch cfg.LLMProvider {
-	case "anthropic":
-		if cfg.AnthropicAPIKey == "" {
-			log.Fatalf("anthropic_api_key is required when llm_provider=anthropic")
-		}
-	case "openai":
-		if cfg.OpenAIAPIKey == "" {
-			log.Fatalf("openai_api_key is required when llm_provider=openai")
+	switch cfg.DBDriver {
+	case "sqlite":
+	case "postgres":
+		if strings.TrimSpace(cfg.DBDSN) == "" {
+			log.Fatalf("db_dsn is required when db_driver=postgres")
 		}
 	default:
-		log.Fatalf("llm_provider must be 'anthropic' or 'openai', got '%s'", cfg.LLMProvider)
+		log.Fatalf("db_driver must be 'sqlite' or 'postgres', got '%s'", cfg.DBDriver)
+	}
+
+	if cfg.GitHubFetchAPI != GitHubFetchAPIGraphQL && cfg.GitHubFetchAPI != GitHubFetchAPISearch {
+		log.Fatalf("github_fetch_api must be '%s' or '%s', got '%s'", GitHubFetchAPIGraphQL, GitHubFetchAPISearch, cfg.GitHubFetchAPI)
+	}
+	if cfg.RetentionMode != RetentionModeArchive && cfg.RetentionMode != RetentionModePurge {
+		log.Fatalf("retention_mode must be '%s' or '%s', got '%s'", RetentionModeArchive, RetentionModePurge, cfg.RetentionMode)
+	}
+	if cfg.RetentionWorkItemsDays < 0 || cfg.RetentionClassificationDays < 0 || cfg.RetentionCorrectionsDays < 0 {
+		log.Fatalf("invalid retention: retention_*_days must be >= 0")
+	}
+	if cfg.WebhookListenAddr != "" && cfg.GitLabWebhookSecret == "" && cfg.GitHubWebhookSecret == "" && requireSecrets {
+		log.Fatalf("webhook_listen_addr requires gitlab_webhook_secret or github_webhook_secret")
+	}
+	if cfg.BackupKeepDaily < 1 || cfg.BackupKeepWeekly < 0 {
+		log.Fatalf("invalid backup rotation: backup_keep_daily must be >= 1 and backup_keep_weekly >= 0")
 	}
 
 	if strings.EqualFold(cfg.Timezone, "Local") {
@@ -247,6 +454,17 @@ func LoadConfig() Config {
 	if cfg.LLMExampleCount < 0 {
 		log.Fatalf("invalid llm_example_count '%d': must be >= 0", cfg.LLMExampleCount)
 	}
+	for _, rule := range cfg.LLMRedactionRules {
+		if strings.TrimSpace(rule.Name) == "" {
+			log.Fatalf("invalid llm_redaction_rules entry %q: name is required", rule.Pattern)
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			log.Fatalf("invalid llm_redaction_rules pattern for %q: %v", rule.Name, err)
+		}
+	}
+	if cfg.LLMBudgetWeeklyUSD < 0 || cfg.LLMBudgetMonthlyUSD < 0 {
+		log.Fatalf("invalid llm budget: llm_budget_weekly_usd and llm_budget_monthly_usd must be >= 0")
+	}
 	if cfg.LLMExampleMaxLen < 20 {
 		log.Fatalf("invalid llm_example_max_chars '%d': must be >= 20", cfg.LLMExampleMaxLen)
 	}
@@ -256,15 +474,56 @@ func LoadConfig() Config {
 	if cfg.OpenAIBaseURL != "" {
 		cfg.OpenAIBaseURL = strings.TrimRight(cfg.OpenAIBaseURL, "/")
 	}
+	cfg.OllamaBaseURL = strings.TrimRight(cfg.OllamaBaseURL, "/")
+	cfg.EmbeddingBaseURL = strings.TrimRight(cfg.EmbeddingBaseURL, "/")
+	if cfg.EmbeddingWeight < 0 || cfg.EmbeddingWeight > 1 {
+		log.Fatalf("invalid embedding_weight '%f': must be between 0 and 1", cfg.EmbeddingWeight)
+	}
 	if cfg.LLMGlossaryPath != "" {
 		if err := validateGlossaryPath(cfg.LLMGlossaryPath); err != nil {
 			log.Fatalf("invalid llm_glossary_path '%s': %v", cfg.LLMGlossaryPath, err)
 		}
 	}
+	if err := validateTeams(cfg.Teams); err != nil {
+		log.Fatalf("invalid teams: %v", err)
+	}
 
 	return cfg
 }
 
+func validateTeams(teams []Team) error {
+	ids := make(map[string]bool, len(teams))
+	channels := make(map[string]string)
+	for _, t := range teams {
+		if !teamIDPattern.MatchString(t.ID) {
+			return fmt.Errorf("team id %q must be non-empty and use only letters, digits, '-' and '_'", t.ID)
+		}
+		if ids[t.ID] {
+			return fmt.Errorf("duplicate team id %q", t.ID)
+		}
+		ids[t.ID] = true
+		if strings.TrimSpace(t.ReportChannelID) == "" && len(t.ChannelIDs) == 0 {
+			return fmt.Errorf("team %q needs report_channel_id or channel_ids so commands can find it", t.ID)
+		}
+		for _, ch := range append([]string{t.ReportChannelID}, t.ChannelIDs...) {
+			ch = strings.TrimSpace(ch)
+			if ch == "" {
+				continue
+			}
+			if other, ok := channels[ch]; ok && other != t.ID {
+				return fmt.Errorf("channel %s is used by teams %q and %q", ch, other, t.ID)
+			}
+			channels[ch] = t.ID
+		}
+		if t.LLMGlossaryPath != "" {
+			if err := validateGlossaryPath(t.LLMGlossaryPath); err != nil {
+				return fmt.Errorf("team %q llm_glossary_path '%s': %v", t.ID, t.LLMGlossaryPath, err)
+			}
+		}
+	}
+	return nil
+}
+
 func envOverride(field *string, envKey string) {
 	if val := os.Getenv(envKey); val != "" {
 		*field = val
@@ -320,6 +579,119 @@ func (c Config) GitHubConfigured() bool {
 	return c.GitHubToken != "" && (c.GitHubOrg != "" || len(c.GitHubRepos) > 0)
 }
 
+// MultiTeam reports whether a teams list is configured.
+func (c Config) MultiTeam() bool {
+	return len(c.Teams) > 0
+}
+
+// ForTeam returns c with the team fields replaced by t's, so code written
+// for one team (reports, nudges, stats) can run unchanged for t. Called on
+// a config that already belongs to a team, it starts from the top-level one.
+func (c Config) ForTeam(t Team) Config {
+	if c.top != nil {
+		c = *c.top
+	}
+	top := c
+	out := c
+	out.top = &top
+	out.TeamID = t.ID
+	out.TeamName = t.Name
+	if out.TeamName == "" {
+		out.TeamName = t.ID
+	}
+	out.TeamMembers = t.Members
+	out.ManagerSlackIDs = append(append([]string(nil), c.ManagerSlackIDs...), t.ManagerSlackIDs...)
+	out.ReportChannelID = t.ReportChannelID
+	out.ReportOutputDir = t.ReportOutputDir
+	if out.ReportOutputDir == "" {
+		out.ReportOutputDir = filepath.Join(c.ReportOutputDir, t.ID)
+	}
+	if t.LLMGlossaryPath != "" {
+		out.LLMGlossaryPath = t.LLMGlossaryPath
+	}
+	if t.LLMGuidePath != "" {
+		out.LLMGuidePath = t.LLMGuidePath
+	}
+	return out
+}
+
+// NoticeChannelIDs returns the channels for bot-wide notices such as
+// retention summaries and backup failures: ops_channel_id when set,
+// otherwise report_channel_id in a single-team deployment or every team's
+// report channel, since those notices concern all teams' data.
+func (c Config) NoticeChannelIDs() []string {
+	if c.top != nil {
+		c = *c.top
+	}
+	if ch := strings.TrimSpace(c.OpsChannelID); ch != "" {
+		return []string{ch}
+	}
+	if !c.MultiTeam() {
+		if ch := strings.TrimSpace(c.ReportChannelID); ch != "" {
+			return []string{ch}
+		}
+		return nil
+	}
+	var out []string
+	seen := make(map[string]bool, len(c.Teams))
+	for _, t := range c.Teams {
+		ch := strings.TrimSpace(t.ReportChannelID)
+		if ch == "" || seen[ch] {
+			continue
+		}
+		seen[ch] = true
+		out = append(out, ch)
+	}
+	return out
+}
+
+// TeamConfigs returns one config per team: the ForTeam view of each teams
+// entry, or c itself in a single-team deployment.
+func (c Config) TeamConfigs() []Config {
+	if !c.MultiTeam() {
+		return []Config{c}
+	}
+	out := make([]Config, 0, len(c.Teams))
+	for _, t := range c.Teams {
+		out = append(out, c.ForTeam(t))
+	}
+	return out
+}
+
+// TeamForChannel returns the config of the team that owns channelID. A
+// single-team deployment owns every channel.
+func (c Config) TeamForChannel(channelID string) (Config, bool) {
+	if !c.MultiTeam() {
+		return c, true
+	}
+	if channelID == "" {
+		return c, false
+	}
+	for _, t := range c.Teams {
+		if strings.TrimSpace(t.ReportChannelID) == channelID {
+			return c.ForTeam(t), true
+		}
+		for _, ch := range t.ChannelIDs {
+			if strings.TrimSpace(ch) == channelID {
+				return c.ForTeam(t), true
+			}
+		}
+	}
+	return c, false
+}
+
+// RetentionConfigured reports whether any table has a retention limit.
+func (c Config) RetentionConfigured() bool {
+	return c.RetentionWorkItemsDays > 0 || c.RetentionClassificationDays > 0 ||
+		c.RetentionCorrectionsDays > 0 || c.RetentionSupersededCorrections
+}
+
+// EmbeddingsConfigured reports whether few-shot examples should be ranked
+// with embeddings in addition to TF-IDF.
+func (c Config) EmbeddingsConfigured() bool {
+	return c.EmbeddingModel != "" && c.EmbeddingBaseURL != ""
+}
+
 func parseClock(s string) (int, int, error) {
 	var hour, min int
 	_, err := fmt.Sscanf(s, "%d:%d", &hour, &min)
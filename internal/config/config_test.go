@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -58,6 +59,16 @@ func TestLoadConfigFromEnvWithDefaults(t *testing.T) {
 	if len(cfg.ManagerSlackIDs) != 2 {
 		t.Fatalf("expected 2 manager IDs, got %d", len(cfg.ManagerSlackIDs))
 	}
+	if cfg.RetentionMode != RetentionModeArchive || cfg.RetentionArchiveDir != "./reportbot-archive" || cfg.RetentionConfigured() {
+		t.Fatalf("unexpected retention defaults: mode=%q dir=%q configured=%t", cfg.RetentionMode, cfg.RetentionArchiveDir, cfg.RetentionConfigured())
+	}
+	if notices := cfg.NoticeChannelIDs(); notices != nil {
+		t.Fatalf("expected no notice channel without report_channel_id, got %v", notices)
+	}
+	t.Setenv("REPORT_CHANNEL_ID", "CREPORT")
+	if got := strings.Join(LoadConfig().NoticeChannelIDs(), ","); got != "CREPORT" {
+		t.Fatalf("expected single-team notices in report_channel_id, got %q", got)
+	}
 }
 
 func TestLoadConfigYAMLAndEnvOverride(t *testing.T) {
@@ -125,6 +136,55 @@ func TestLoadConfigAllowsEmptyGitLabRefTicketLabelEnv(t *testing.T) {
 	}
 }
 
+func TestLoadConfigOllamaWithoutAPIKey(t *testing.T) {
+	t.Setenv("CONFIG_PATH", filepath.Join(t.TempDir(), "missing-config.yaml"))
+	t.Setenv("SLACK_BOT_TOKEN", "xoxb-test")
+	t.Setenv("SLACK_APP_TOKEN", "xapp-test")
+	t.Setenv("LLM_PROVIDER", "ollama")
+	t.Setenv("TIMEZONE", "UTC")
+
+	cfg := LoadConfig()
+	if cfg.LLMProvider != "ollama" {
+		t.Fatalf("unexpected provider: %q", cfg.LLMProvider)
+	}
+	if cfg.OllamaBaseURL != "" {
+		t.Fatalf("expected the Ollama provider to own its base URL default, got %q", cfg.OllamaBaseURL)
+	}
+
+	t.Setenv("OLLAMA_BASE_URL", "http://gpu-box:11434/")
+	cfg = LoadConfig()
+	if cfg.OllamaBaseURL != "http://gpu-box:11434" {
+		t.Fatalf("expected trimmed Ollama base URL from env, got %q", cfg.OllamaBaseURL)
+	}
+}
+
+func TestLoadConfigLeavesProviderNameToRegistry(t *testing.T) {
+	t.Setenv("CONFIG_PATH", filepath.Join(t.TempDir(), "missing-config.yaml"))
+	setMinimalValidConfigEnv(t)
+	t.Setenv("LLM_PROVIDER", "vertex")
+
+	cfg := LoadConfig()
+	if cfg.LLMProvider != "vertex" {
+		t.Fatalf("expected custom provider name to load, got %q", cfg.LLMProvider)
+	}
+}
+
+func TestLoadToolConfigSkipsSecrets(t *testing.T) {
+	t.Setenv("CONFIG_PATH", filepath.Join(t.TempDir(), "missing-config.yaml"))
+	t.Setenv("LLM_PROVIDER", "anthropic")
+	t.Setenv("TIMEZONE", "UTC")
+	t.Setenv("SLACK_BOT_TOKEN", "")
+	t.Setenv("ANTHROPIC_API_KEY", "")
+
+	cfg := LoadToolConfig()
+	if cfg.SlackBotToken != "" || cfg.AnthropicAPIKey != "" {
+		t.Fatalf("expected no secrets, got %+v", cfg)
+	}
+	if cfg.DBPath != "./reportbot.db" {
+		t.Fatalf("expected defaults to apply, got db path %q", cfg.DBPath)
+	}
+}
+
 func TestParseClock(t *testing.T) {
 	hour, min, err := parseClock("09:45")
 	if err != nil {
@@ -195,3 +255,126 @@ func TestLoadConfigInvalidTimezoneFatal(t *testing.T) {
 		t.Fatalf("expected ExitError, got: %v", err)
 	}
 }
+
+func TestLoadConfigLLMPricesAndBudgets(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+slack_bot_token: "yaml-bot"
+slack_app_token: "yaml-app"
+llm_provider: "ollama"
+timezone: "UTC"
+llm_budget_weekly_usd: 5
+llm_prices:
+  gpt-5-mini:
+    input_per_mtok: 0.25
+    output_per_mtok: 2
+    cached_input_per_mtok: 0.025
+`
+	if err := os.WriteFile(cfgPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("CONFIG_PATH", cfgPath)
+	t.Setenv("LLM_BUDGET_MONTHLY_USD", "20")
+
+	cfg := LoadConfig()
+	price, ok := cfg.LLMPrices["gpt-5-mini"]
+	if !ok || price.InputPerMTok != 0.25 || price.OutputPerMTok != 2 || price.CachedInputPerMTok != 0.025 {
+		t.Fatalf("unexpected prices: %+v", cfg.LLMPrices)
+	}
+	if cfg.LLMBudgetWeeklyUSD != 5 || cfg.LLMBudgetMonthlyUSD != 20 {
+		t.Fatalf("unexpected budgets: weekly=%v monthly=%v", cfg.LLMBudgetWeeklyUSD, cfg.LLMBudgetMonthlyUSD)
+	}
+}
+
+func TestLoadConfigTeams(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+manager_slack_ids: ["UHEAD"]
+report_output_dir: "/tmp/reports"
+report_channel_id: "CTOP"
+teams:
+  - id: platform
+    name: "Platform"
+    members: ["Alice"]
+    manager_slack_ids: ["UPLAT"]
+    report_channel_id: "CPLAT"
+    channel_ids: ["CPLATSTANDUP"]
+  - id: mobile
+    members: ["Bob"]
+    report_channel_id: "CMOB"
+    report_output_dir: "/srv/mobile-reports"
+`
+	if err := os.WriteFile(cfgPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("CONFIG_PATH", cfgPath)
+	setMinimalValidConfigEnv(t)
+
+	cfg := LoadConfig()
+	if !cfg.MultiTeam() || len(cfg.TeamConfigs()) != 2 {
+		t.Fatalf("expected two teams, got %+v", cfg.Teams)
+	}
+
+	platform, ok := cfg.TeamForChannel("CPLATSTANDUP")
+	if !ok || platform.TeamID != "platform" || platform.TeamName != "Platform" || platform.ReportChannelID != "CPLAT" {
+		t.Fatalf("unexpected platform config: ok=%v %+v", ok, platform)
+	}
+	if platform.ReportOutputDir != filepath.Join("/tmp/reports", "platform") {
+		t.Fatalf("expected output dir under report_output_dir, got %q", platform.ReportOutputDir)
+	}
+	if len(platform.ManagerSlackIDs) != 2 || platform.ManagerSlackIDs[0] != "UHEAD" || platform.ManagerSlackIDs[1] != "UPLAT" {
+		t.Fatalf("expected top-level and team managers, got %v", platform.ManagerSlackIDs)
+	}
+	if len(cfg.ManagerSlackIDs) != 1 {
+		t.Fatalf("ForTeam must not modify the top-level managers, got %v", cfg.ManagerSlackIDs)
+	}
+
+	mobile, ok := cfg.TeamForChannel("CMOB")
+	if !ok || mobile.TeamName != "mobile" || mobile.ReportOutputDir != "/srv/mobile-reports" || mobile.TeamMembers[0] != "Bob" {
+		t.Fatalf("unexpected mobile config: ok=%v %+v", ok, mobile)
+	}
+	if again, _ := platform.TeamForChannel("CPLAT"); len(again.ManagerSlackIDs) != 2 || again.ReportOutputDir != platform.ReportOutputDir {
+		t.Fatalf("ForTeam on a team config must start from the top-level config, got %+v", again)
+	}
+	if cfg.RollupTitle != "Department" || platform.RollupOutputDir != "/tmp/reports" {
+		t.Fatalf("unexpected roll-up defaults: title=%q dir=%q", cfg.RollupTitle, platform.RollupOutputDir)
+	}
+	for _, ch := range []string{"CELSEWHERE", ""} {
+		if _, ok := cfg.TeamForChannel(ch); ok {
+			t.Fatalf("expected no team for channel %q", ch)
+		}
+	}
+
+	// Bot-wide notices go to every team's channel, never the unused
+	// top-level report_channel_id, unless ops_channel_id is set.
+	if got := strings.Join(cfg.NoticeChannelIDs(), ","); got != "CPLAT,CMOB" {
+		t.Fatalf("expected notices in each team's channel, got %q", got)
+	}
+	if got := strings.Join(platform.NoticeChannelIDs(), ","); got != "CPLAT,CMOB" {
+		t.Fatalf("expected a team config to resolve notices from the top level, got %q", got)
+	}
+	t.Setenv("OPS_CHANNEL_ID", "COPS")
+	if got := strings.Join(LoadConfig().NoticeChannelIDs(), ","); got != "COPS" {
+		t.Fatalf("expected notices in ops_channel_id, got %q", got)
+	}
+}
+
+func TestValidateTeams(t *testing.T) {
+	cases := []struct {
+		name  string
+		teams []Team
+	}{
+		{"bad id", []Team{{ID: "a team", ReportChannelID: "C1"}}},
+		{"duplicate id", []Team{{ID: "a", ReportChannelID: "C1"}, {ID: "a", ReportChannelID: "C2"}}},
+		{"no channel", []Team{{ID: "a"}}},
+		{"shared channel", []Team{{ID: "a", ReportChannelID: "C1"}, {ID: "b", ChannelIDs: []string{"C1"}}}},
+	}
+	for _, c := range cases {
+		if err := validateTeams(c.teams); err == nil {
+			t.Fatalf("%s: expected error", c.name)
+		}
+	}
+	if err := validateTeams([]Team{{ID: "a", ReportChannelID: "C1"}, {ID: "b_2", ChannelIDs: []string{"C2"}}}); err != nil {
+		t.Fatalf("expected valid teams, got %v", err)
+	}
+}
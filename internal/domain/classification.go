@@ -14,6 +14,13 @@ type ClassificationRecord struct {
 	LLMProvider      string
 	LLMModel         string
 	ClassifiedAt     time.Time
+	// RawConfidence is the uncalibrated model confidence (0 if none).
+	RawConfidence float64
+	// AlternativeSectionIDs is a comma-separated list of runner-up sections.
+	AlternativeSectionIDs string
+	// CacheKey hashes the inputs of the decision so it can be reused for an
+	// unchanged item; empty for rows written before the cache existed.
+	CacheKey string
 }
 
 type ClassificationCorrection struct {
@@ -38,8 +45,76 @@ type ClassificationStats struct {
 	Bucket90Plus         int
 }
 
+type SectionCorrectionStat struct {
+	OriginalSectionID string
+	OriginalLabel     string
+	CorrectionCount   int
+}
+
+type WeeklyTrend struct {
+	WeekStart       string
+	Classifications int
+	Corrections     int
+	AvgConfidence   float64
+}
+
+// ConfidenceSample pairs a past raw model confidence with whether a manager
+// later corrected that decision; used to calibrate confidence.
+type ConfidenceSample struct {
+	RawConfidence float64
+	Corrected     bool
+}
+
+// LabeledWorkItem is a classified work item with the section the LLM last
+// chose and the section it ended up in after any manager correction.
+type LabeledWorkItem struct {
+	Item             WorkItem
+	PredictedSection string
+	PredictedLabel   string
+	FinalSection     string
+	FinalLabel       string
+	Corrected        bool
+}
+
 type HistoricalItem struct {
+	WorkItemID   int64
 	Description  string
 	SectionID    string
 	SectionLabel string
 }
+
+// Embedding is a cached embedding vector for a work item description.
+// TextHash identifies the description it was computed from so an edited
+// item is re-embedded.
+type Embedding struct {
+	WorkItemID int64
+	Model      string
+	TextHash   string
+	Vector     []float32
+}
+
+// LLMUsageRecord is one billed LLM call. Purpose is what the call was for:
+// classify, critic, retrospect, embeddings, rollup or period_summary.
+type LLMUsageRecord struct {
+	ID                       int64
+	Provider                 string
+	Model                    string
+	Purpose                  string
+	InputTokens              int64
+	OutputTokens             int64
+	CacheCreationInputTokens int64
+	CacheReadInputTokens     int64
+	CostUSD                  float64
+	CreatedAt                time.Time
+}
+
+// LLMUsageSummary aggregates usage per provider, model and purpose.
+type LLMUsageSummary struct {
+	Provider     string
+	Model        string
+	Purpose      string
+	Calls        int
+	InputTokens  int64
+	OutputTokens int64
+	CostUSD      float64
+}
@@ -1,8 +1,10 @@
 package domain
 
 import (
+	"errors"
 	"fmt"
 	"reportbot/internal/config"
+	"strings"
 	"time"
 )
 
@@ -18,17 +20,184 @@ type WorkItem struct {
 	TicketIDs   string // comma-separated: "1247202,1230118"
 	ReportedAt  time.Time
 	CreatedAt   time.Time
+	TeamID      string // teams[].id in a multi-team deployment, empty otherwise
+}
+
+// TeamScope limits reads of work items, and of the classification history
+// and corrections recorded for them, to one team. The zero value reads all
+// teams, which tools such as eval and export rely on.
+type TeamScope struct {
+	TeamID string
+	Scoped bool
+}
+
+// ScopeTeam returns the scope of one team. A single-team deployment stores
+// every item under the empty team ID.
+func ScopeTeam(teamID string) TeamScope {
+	return TeamScope{TeamID: teamID, Scoped: true}
+}
+
+// Assign returns items with the scope's team set on those that have none.
+// Unscoped, items are returned unchanged.
+func (s TeamScope) Assign(items []WorkItem) []WorkItem {
+	if !s.Scoped || s.TeamID == "" {
+		return items
+	}
+	out := make([]WorkItem, len(items))
+	for i, item := range items {
+		if item.TeamID == "" {
+			item.TeamID = s.TeamID
+		}
+		out[i] = item
+	}
+	return out
+}
+
+// SearchQuery selects work items across all weeks. Every word of Text must
+// match the description, author, ticket IDs or source_ref; Author (a name
+// substring), AuthorID, Status and Since narrow the result further. Deleted
+// items are never returned.
+type SearchQuery struct {
+	Text     string
+	Author   string
+	AuthorID string
+	Status   string
+	Since    time.Time
+	Limit    int
+	Offset   int
+}
+
+// Work item event types recorded in the audit log.
+const (
+	WorkItemEventCreated         = "created"
+	WorkItemEventEdited          = "edited"
+	WorkItemEventStatusChanged   = "status_changed"
+	WorkItemEventCategoryChanged = "category_changed"
+	WorkItemEventDeleted         = "deleted"
+	WorkItemEventRestored        = "restored"
+	WorkItemEventNudgeDone       = "nudge_done"
+)
+
+// WorkItemEvent is one audit log entry for a work item. ActorID is the Slack
+// user who made the change, or empty for the bot itself (fetch, LLM).
+type WorkItemEvent struct {
+	ID         int64
+	WorkItemID int64
+	EventType  string
+	OldValue   string
+	NewValue   string
+	ActorID    string
+	CreatedAt  time.Time
+}
+
+// Account kinds an Identity links. GitLab and GitHub match the Source of
+// the work items fetched from them; email matches the author's email where
+// the MR/PR carries one.
+const (
+	IdentityGitLab = "gitlab"
+	IdentityGitHub = "github"
+	IdentityEmail  = "email"
+)
+
+// ErrIdentityTaken is returned when linking an account another Slack user
+// has already linked.
+var ErrIdentityTaken = errors.New("account is already linked to another Slack user")
+
+// Identity links a Slack user to their GitLab username, GitHub login and
+// email, so fetched MRs/PRs are attributed by account instead of by name.
+// Accounts are stored lowercase; empty ones are not linked. An account is
+// confirmed when a manager linked it for the user, which lets it admit the
+// user's MRs/PRs to the team. Identities are shared by all teams.
+type Identity struct {
+	SlackID         string
+	GitLabUsername  string
+	GitHubLogin     string
+	Email           string
+	GitLabConfirmed bool
+	GitHubConfirmed bool
+	EmailConfirmed  bool
+	UpdatedAt       time.Time
+}
+
+// Account returns the linked account of the given kind, or "".
+func (i Identity) Account(kind string) string {
+	switch kind {
+	case IdentityGitLab:
+		return i.GitLabUsername
+	case IdentityGitHub:
+		return i.GitHubLogin
+	case IdentityEmail:
+		return i.Email
+	}
+	return ""
+}
+
+// Confirmed reports whether a manager linked the account of the given kind.
+func (i Identity) Confirmed(kind string) bool {
+	switch kind {
+	case IdentityGitLab:
+		return i.GitLabConfirmed
+	case IdentityGitHub:
+		return i.GitHubConfirmed
+	case IdentityEmail:
+		return i.EmailConfirmed
+	}
+	return false
+}
+
+// NormalizeAccount canonicalizes a username, login or email for storage and
+// lookup: trimmed, without a leading "@", lowercase.
+func NormalizeAccount(account string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(account), "@"))
+}
+
+// RetentionPolicy selects rows for the retention job. A zero cutoff keeps
+// that table forever. Removing a work item also removes its classification
+// history, corrections, embeddings and events.
+type RetentionPolicy struct {
+	WorkItemsBefore       time.Time
+	ClassificationsBefore time.Time
+	CorrectionsBefore     time.Time
+	// SupersededCorrections removes every correction of an item except the
+	// newest one.
+	SupersededCorrections bool
+}
+
+// RetentionArchive receives rows before they are deleted. Close is called
+// before the deletion commits, so a failed archive aborts the purge.
+type RetentionArchive interface {
+	Write(table string, row map[string]any) error
+	Close() error
+}
+
+// StateSnapshot is the database part of `reportbot export`: live work items
+// with their classification history and corrections. History and
+// corrections refer to items by the WorkItem.ID in the snapshot.
+type StateSnapshot struct {
+	WorkItems             []WorkItem
+	ClassificationHistory []ClassificationRecord
+	Corrections           []ClassificationCorrection
+}
+
+// ImportResult counts rows added by an import and rows skipped because the
+// database already had them.
+type ImportResult struct {
+	WorkItemsInserted, WorkItemsExisting     int
+	HistoryInserted, HistoryExisting         int
+	CorrectionsInserted, CorrectionsExisting int
 }
 
 type GitLabMR struct {
 	Title       string
 	Author      string // username
 	AuthorName  string // display name
+	AuthorEmail string // only set by webhooks; see gitlab.ParseMergeRequestHook
 	WebURL      string
 	TicketIDs   string // comma-separated ticket IDs parsed from configured MR field
 	MergedAt    time.Time
 	UpdatedAt   time.Time
 	CreatedAt   time.Time
+	ClosedAt    time.Time
 	State       string
 	Labels      []string
 	ProjectPath string
@@ -37,7 +206,8 @@ type GitLabMR struct {
 type GitHubPR struct {
 	Title        string
 	Author       string // GitHub login (username)
-	AuthorName   string // same as Author (Search API doesn't return display name)
+	AuthorName   string // profile name; the login when unset or fetched via the Search API
+	AuthorEmail  string // public profile email; only set by the GraphQL fetcher
 	HTMLURL      string // PR web URL, used as source_ref for dedup
 	MergedAt     time.Time
 	UpdatedAt    time.Time
@@ -46,6 +216,9 @@ type GitHubPR struct {
 	State        string // "open", "merged" (derived), or "closed"
 	Labels       []string
 	RepoFullName string // e.g. "org/repo-name"
+	// LinkedIssues are the issues the PR closes ("org/repo#123"); only set
+	// by the GraphQL fetcher.
+	LinkedIssues []string
 }
 
 type ReportSection struct {
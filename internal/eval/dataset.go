@@ -0,0 +1,89 @@
+package eval
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UndeterminedLabel is the label used for items left in (or expected in)
+// the Undetermined section.
+const UndeterminedLabel = "Undetermined"
+
+// Dataset is a replayable set of work items with their final sections.
+// Section IDs in past reports are positional and shift between weeks, so
+// evaluation keys everything by section label and assigns fresh, stable IDs.
+type Dataset struct {
+	Items    []WorkItem
+	Options  []SectionOption
+	Expected map[int64]string // item ID -> final section label
+	Recorded map[int64]string // item ID -> label the LLM chose at the time
+}
+
+// BuildDataset turns labeled history into a Dataset. Items without a usable
+// final label are skipped.
+func BuildDataset(labeled []LabeledWorkItem) Dataset {
+	ds := Dataset{
+		Expected: make(map[int64]string),
+		Recorded: make(map[int64]string),
+	}
+	labels := map[string]bool{}
+	for _, l := range labeled {
+		final := sectionLabel(l.FinalSection, l.FinalLabel)
+		if final == "" {
+			continue
+		}
+		ds.Items = append(ds.Items, l.Item)
+		ds.Expected[l.Item.ID] = final
+		ds.Recorded[l.Item.ID] = sectionLabel(l.PredictedSection, l.PredictedLabel)
+		for _, label := range []string{final, ds.Recorded[l.Item.ID]} {
+			if label != "" && label != UndeterminedLabel {
+				labels[label] = true
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(labels))
+	for label := range labels {
+		sorted = append(sorted, label)
+	}
+	sort.Strings(sorted)
+	for i, label := range sorted {
+		ds.Options = append(ds.Options, SectionOption{
+			ID:       fmt.Sprintf("S%d_0", i),
+			Category: i,
+			Label:    label,
+		})
+	}
+	return ds
+}
+
+func sectionLabel(sectionID, label string) string {
+	sectionID = strings.TrimSpace(sectionID)
+	label = strings.TrimSpace(label)
+	if strings.EqualFold(sectionID, "UND") {
+		return UndeterminedLabel
+	}
+	if label != "" {
+		return label
+	}
+	return sectionID
+}
+
+func (ds Dataset) labelForID(sectionID string) string {
+	for _, opt := range ds.Options {
+		if opt.ID == sectionID {
+			return opt.Label
+		}
+	}
+	return UndeterminedLabel
+}
+
+func (ds Dataset) idForLabel(label string) string {
+	for _, opt := range ds.Options {
+		if opt.Label == label {
+			return opt.ID
+		}
+	}
+	return "UND"
+}
@@ -0,0 +1,23 @@
+package eval
+
+import (
+	"reportbot/internal/config"
+	"reportbot/internal/domain"
+	illm "reportbot/internal/integrations/llm"
+)
+
+type Config = config.Config
+type WorkItem = domain.WorkItem
+type LabeledWorkItem = domain.LabeledWorkItem
+type SectionOption = illm.SectionOption
+type Provider = illm.Provider
+type LLMUsage = illm.LLMUsage
+type LLMSectionDecision = illm.LLMSectionDecision
+
+func categorizeItemsToSections(provider Provider, cfg Config, items []WorkItem, options []SectionOption) (map[int64]LLMSectionDecision, LLMUsage, error) {
+	return illm.CategorizeItemsToSectionsWithProvider(provider, cfg, items, options, nil, nil, nil)
+}
+
+func newProvider(cfg Config) (Provider, error) {
+	return illm.NewProvider(cfg)
+}
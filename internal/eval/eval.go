@@ -0,0 +1,233 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// SectionMetrics are one-vs-rest scores for a single section label.
+type SectionMetrics struct {
+	Label     string  `json:"label"`
+	Support   int     `json:"support"`
+	Predicted int     `json:"predicted"`
+	Correct   int     `json:"correct"`
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+}
+
+// Result is the outcome of one evaluation run. It is written as JSON so a
+// later run can be compared against it as a baseline.
+type Result struct {
+	Provider    string                    `json:"provider"`
+	Model       string                    `json:"model"`
+	Threshold   float64                   `json:"threshold"`
+	Items       int                       `json:"items"`
+	Correct     int                       `json:"correct"`
+	Accuracy    float64                   `json:"accuracy"`
+	Tokens      int64                     `json:"tokens"`
+	Sections    []SectionMetrics          `json:"sections"`
+	Confusion   map[string]map[string]int `json:"confusion"`
+	Predictions map[int64]string          `json:"predictions"`
+	Expected    map[int64]string          `json:"expected"`
+}
+
+// Run classifies every dataset item through provider and scores the result
+// against the final sections. Decisions below cfg.LLMConfidence count as
+// Undetermined, matching how the report builder places them.
+func Run(cfg Config, ds Dataset, provider Provider) (Result, error) {
+	threshold := cfg.LLMConfidence
+	if threshold <= 0 || threshold > 1 {
+		threshold = 0.70
+	}
+	result := Result{
+		Provider:    provider.Name(),
+		Model:       provider.Model(),
+		Threshold:   threshold,
+		Predictions: make(map[int64]string, len(ds.Items)),
+		Expected:    ds.Expected,
+	}
+	if len(ds.Items) == 0 {
+		return result, nil
+	}
+
+	decisions, usage, err := categorizeItemsToSections(provider, cfg, ds.Items, ds.Options)
+	if err != nil {
+		return result, err
+	}
+	result.Tokens = usage.TotalTokens()
+
+	for _, item := range ds.Items {
+		predicted := UndeterminedLabel
+		if d, ok := decisions[item.ID]; ok && d.Confidence >= threshold {
+			predicted = ds.labelForID(strings.TrimSpace(d.SectionID))
+		}
+		result.Predictions[item.ID] = predicted
+	}
+	score(&result)
+	return result, nil
+}
+
+func score(r *Result) {
+	r.Confusion = make(map[string]map[string]int)
+	type counts struct{ support, predicted, correct int }
+	perLabel := map[string]*counts{}
+	get := func(label string) *counts {
+		if perLabel[label] == nil {
+			perLabel[label] = &counts{}
+		}
+		return perLabel[label]
+	}
+
+	r.Items, r.Correct = 0, 0
+	for id, expected := range r.Expected {
+		predicted, ok := r.Predictions[id]
+		if !ok {
+			continue
+		}
+		r.Items++
+		if r.Confusion[expected] == nil {
+			r.Confusion[expected] = make(map[string]int)
+		}
+		r.Confusion[expected][predicted]++
+		get(expected).support++
+		get(predicted).predicted++
+		if expected == predicted {
+			r.Correct++
+			get(expected).correct++
+		}
+	}
+	if r.Items > 0 {
+		r.Accuracy = float64(r.Correct) / float64(r.Items)
+	}
+
+	r.Sections = r.Sections[:0]
+	for label, c := range perLabel {
+		m := SectionMetrics{Label: label, Support: c.support, Predicted: c.predicted, Correct: c.correct}
+		if c.predicted > 0 {
+			m.Precision = float64(c.correct) / float64(c.predicted)
+		}
+		if c.support > 0 {
+			m.Recall = float64(c.correct) / float64(c.support)
+		}
+		if m.Precision+m.Recall > 0 {
+			m.F1 = 2 * m.Precision * m.Recall / (m.Precision + m.Recall)
+		}
+		r.Sections = append(r.Sections, m)
+	}
+	sort.Slice(r.Sections, func(i, j int) bool { return r.Sections[i].Label < r.Sections[j].Label })
+}
+
+// WriteResult saves r as indented JSON.
+func WriteResult(path string, r Result) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// LoadResult reads a result written by WriteResult.
+func LoadResult(path string) (Result, error) {
+	var r Result
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return r, fmt.Errorf("read baseline: %w", err)
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return r, fmt.Errorf("parse baseline %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// FormatReport renders per-section metrics, the confusion matrix and, when
+// baseline is non-nil, what changed since the baseline run.
+func FormatReport(r Result, baseline *Result) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Evaluation: provider=%s model=%s threshold=%.2f\n", r.Provider, r.Model, r.Threshold))
+	sb.WriteString(fmt.Sprintf("Items: %d  Correct: %d  Accuracy: %.1f%%  Tokens: %d\n\n", r.Items, r.Correct, 100*r.Accuracy, r.Tokens))
+
+	sb.WriteString("Per-section metrics\n")
+	sb.WriteString(fmt.Sprintf("%-4s %-40s %7s %9s %9s %7s\n", "#", "Section", "Support", "Precision", "Recall", "F1"))
+	for i, m := range r.Sections {
+		sb.WriteString(fmt.Sprintf("%-4d %-40s %7d %8.1f%% %8.1f%% %7.2f\n", i+1, truncateLabel(m.Label, 40), m.Support, 100*m.Precision, 100*m.Recall, m.F1))
+	}
+
+	sb.WriteString("\nConfusion matrix (rows: expected, columns: predicted, by # above)\n")
+	sb.WriteString(fmt.Sprintf("%-4s", ""))
+	for i := range r.Sections {
+		sb.WriteString(fmt.Sprintf("%5d", i+1))
+	}
+	sb.WriteString("\n")
+	for i, row := range r.Sections {
+		sb.WriteString(fmt.Sprintf("%-4d", i+1))
+		for _, col := range r.Sections {
+			sb.WriteString(fmt.Sprintf("%5d", r.Confusion[row.Label][col.Label]))
+		}
+		sb.WriteString("\n")
+	}
+
+	if baseline != nil {
+		sb.WriteString("\n")
+		sb.WriteString(formatBaselineDiff(r, *baseline))
+	}
+	return sb.String()
+}
+
+func formatBaselineDiff(r, baseline Result) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Diff vs baseline (provider=%s model=%s)\n", baseline.Provider, baseline.Model))
+	sb.WriteString(fmt.Sprintf("Accuracy: %.1f%% -> %.1f%% (%+.1f pts)\n", 100*baseline.Accuracy, 100*r.Accuracy, 100*(r.Accuracy-baseline.Accuracy)))
+
+	before := make(map[string]SectionMetrics, len(baseline.Sections))
+	for _, m := range baseline.Sections {
+		before[m.Label] = m
+	}
+	for _, m := range r.Sections {
+		b, ok := before[m.Label]
+		if !ok {
+			sb.WriteString(fmt.Sprintf("  %-40s new section\n", truncateLabel(m.Label, 40)))
+			continue
+		}
+		dp, dr := m.Precision-b.Precision, m.Recall-b.Recall
+		if dp == 0 && dr == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("  %-40s precision %+6.1f pts  recall %+6.1f pts\n", truncateLabel(m.Label, 40), 100*dp, 100*dr))
+	}
+
+	var fixed, regressed []int64
+	for id, expected := range r.Expected {
+		now, okNow := r.Predictions[id]
+		was, okWas := baseline.Predictions[id]
+		if !okNow || !okWas {
+			continue
+		}
+		switch {
+		case was != expected && now == expected:
+			fixed = append(fixed, id)
+		case was == expected && now != expected:
+			regressed = append(regressed, id)
+		}
+	}
+	sort.Slice(regressed, func(i, j int) bool { return regressed[i] < regressed[j] })
+	sb.WriteString(fmt.Sprintf("Fixed: %d  Regressed: %d\n", len(fixed), len(regressed)))
+	for i, id := range regressed {
+		if i == 10 {
+			sb.WriteString(fmt.Sprintf("  ... and %d more\n", len(regressed)-10))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("  item %d: expected %q, was right, now %q\n", id, r.Expected[id], r.Predictions[id]))
+	}
+	return sb.String()
+}
+
+func truncateLabel(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-3] + "..."
+}
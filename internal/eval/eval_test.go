@@ -0,0 +1,125 @@
+package eval
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func sampleLabeled() []LabeledWorkItem {
+	return []LabeledWorkItem{
+		{Item: WorkItem{ID: 1, Description: "Upgrade k8s cluster", Status: "done"}, PredictedSection: "S0_0", PredictedLabel: "Infra", FinalSection: "S0_0", FinalLabel: "Infra"},
+		{Item: WorkItem{ID: 2, Description: "Tune postgres vacuum", Status: "done"}, PredictedSection: "S0_0", PredictedLabel: "Infra", FinalSection: "S1_0", FinalLabel: "Database", Corrected: true},
+		{Item: WorkItem{ID: 3, Description: "Add index on orders", Status: "in progress"}, PredictedSection: "S1_2", PredictedLabel: "Database", FinalSection: "S1_2", FinalLabel: "Database"},
+		{Item: WorkItem{ID: 4, Description: "Unclear spike", Status: "done"}, PredictedSection: "UND", FinalSection: "UND"},
+	}
+}
+
+func TestBuildDataset_KeysSectionsByLabel(t *testing.T) {
+	ds := BuildDataset(sampleLabeled())
+	if len(ds.Items) != 4 {
+		t.Fatalf("expected 4 items, got %d", len(ds.Items))
+	}
+	if len(ds.Options) != 2 || ds.Options[0].Label != "Database" || ds.Options[1].Label != "Infra" {
+		t.Fatalf("unexpected options: %+v", ds.Options)
+	}
+	if ds.Expected[2] != "Database" || ds.Recorded[2] != "Infra" {
+		t.Fatalf("expected corrected item to keep final and recorded labels, got %q/%q", ds.Expected[2], ds.Recorded[2])
+	}
+	if ds.Expected[4] != UndeterminedLabel {
+		t.Fatalf("expected UND to map to %q, got %q", UndeterminedLabel, ds.Expected[4])
+	}
+}
+
+func TestRun_HistoryProviderScoresRecordedDecisions(t *testing.T) {
+	ds := BuildDataset(sampleLabeled())
+	result, err := Run(Config{LLMBatchSize: 2, LLMConfidence: 0.7}, ds, NewHistoryProvider(ds))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Items != 4 || result.Correct != 3 {
+		t.Fatalf("expected 3/4 correct, got %d/%d", result.Correct, result.Items)
+	}
+	if got := result.Confusion["Database"]["Infra"]; got != 1 {
+		t.Fatalf("expected one Database item predicted as Infra, got %d", got)
+	}
+	metrics := map[string]SectionMetrics{}
+	for _, m := range result.Sections {
+		metrics[m.Label] = m
+	}
+	if m := metrics["Infra"]; m.Precision != 0.5 || m.Recall != 1 {
+		t.Fatalf("unexpected Infra metrics: %+v", m)
+	}
+	if m := metrics["Database"]; m.Precision != 1 || m.Recall != 0.5 {
+		t.Fatalf("unexpected Database metrics: %+v", m)
+	}
+}
+
+func TestRun_CannedProviderAndBaselineDiff(t *testing.T) {
+	ds := BuildDataset(sampleLabeled())
+	cfg := Config{LLMBatchSize: 10, LLMConfidence: 0.7}
+
+	baseline, err := Run(cfg, ds, NewHistoryProvider(ds))
+	if err != nil {
+		t.Fatalf("baseline Run: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := WriteResult(path, baseline); err != nil {
+		t.Fatalf("WriteResult: %v", err)
+	}
+	loaded, err := LoadResult(path)
+	if err != nil {
+		t.Fatalf("LoadResult: %v", err)
+	}
+
+	canned := NewReplayProvider("canned", ds, []RecordedDecision{
+		{ID: 1, SectionLabel: "Database", NormalizedStatus: "done", Confidence: 0.9},
+		{ID: 2, SectionLabel: "Database", NormalizedStatus: "done", Confidence: 0.9},
+		{ID: 3, SectionLabel: "Database", NormalizedStatus: "in progress", Confidence: 0.4},
+	})
+	result, err := Run(cfg, ds, canned)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	// Item 1 regressed, item 2 fixed, item 3 fell below threshold, item 4 stays Undetermined.
+	if result.Predictions[3] != UndeterminedLabel {
+		t.Fatalf("expected low-confidence item to count as Undetermined, got %q", result.Predictions[3])
+	}
+	report := FormatReport(result, &loaded)
+	for _, want := range []string{"Per-section metrics", "Confusion matrix", "Diff vs baseline", "Fixed: 1  Regressed: 2", "item 1: expected \"Infra\""} {
+		if !strings.Contains(report, want) {
+			t.Fatalf("report missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestRecordingProvider_RoundTrip(t *testing.T) {
+	ds := BuildDataset(sampleLabeled())
+	live := NewReplayProvider("live", ds, []RecordedDecision{
+		{ID: 1, SectionLabel: "Infra", Confidence: 0.95},
+		{ID: 2, SectionLabel: "Database", Confidence: 0.8},
+	})
+	recorder := NewRecordingProvider(live, ds)
+	first, err := Run(Config{LLMBatchSize: 1, LLMConfidence: 0.7}, ds, recorder)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "rec.jsonl")
+	if err := recorder.WriteRecording(path); err != nil {
+		t.Fatalf("WriteRecording: %v", err)
+	}
+	decisions, err := LoadRecording(path)
+	if err != nil {
+		t.Fatalf("LoadRecording: %v", err)
+	}
+	if len(decisions) != 4 {
+		t.Fatalf("expected a recorded decision per item, got %d", len(decisions))
+	}
+	replayed, err := Run(Config{LLMBatchSize: 3, LLMConfidence: 0.7}, ds, NewReplayProvider("replay", ds, decisions))
+	if err != nil {
+		t.Fatalf("replay Run: %v", err)
+	}
+	if replayed.Correct != first.Correct {
+		t.Fatalf("replay should reproduce the recorded run: %d vs %d", replayed.Correct, first.Correct)
+	}
+}
@@ -0,0 +1,189 @@
+package eval
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var promptItemIDRe = regexp.MustCompile(`(?m)^ID:(\d+) - `)
+
+// RecordedDecision is one item's answer in a recording. Sections are stored
+// by label so recordings survive section ID renumbering.
+type RecordedDecision struct {
+	ID               int64   `json:"id"`
+	SectionLabel     string  `json:"section_label"`
+	NormalizedStatus string  `json:"normalized_status,omitempty"`
+	Confidence       float64 `json:"confidence,omitempty"`
+}
+
+// ReplayProvider answers classification prompts from canned per-item
+// decisions, so results do not depend on how items are batched. Items it has
+// no answer for come back as Undetermined.
+type ReplayProvider struct {
+	name      string
+	ds        Dataset
+	decisions map[int64]RecordedDecision
+}
+
+// NewReplayProvider builds a provider from canned decisions. It is the
+// fake used by tests and the backend for recorded runs.
+func NewReplayProvider(name string, ds Dataset, decisions []RecordedDecision) *ReplayProvider {
+	byID := make(map[int64]RecordedDecision, len(decisions))
+	for _, d := range decisions {
+		byID[d.ID] = d
+	}
+	return &ReplayProvider{name: name, ds: ds, decisions: byID}
+}
+
+// NewHistoryProvider replays the decisions the LLM made at the time, as
+// stored in classification_history. Useful as a baseline.
+func NewHistoryProvider(ds Dataset) *ReplayProvider {
+	var decisions []RecordedDecision
+	for id, label := range ds.Recorded {
+		decisions = append(decisions, RecordedDecision{ID: id, SectionLabel: label})
+	}
+	return NewReplayProvider("history", ds, decisions)
+}
+
+func (p *ReplayProvider) Name() string  { return p.name }
+func (p *ReplayProvider) Model() string { return "replay" }
+
+func (p *ReplayProvider) ClassifySections(_, userPrompt string, _ []SectionOption) (string, LLMUsage, error) {
+	type item struct {
+		ID               int64    `json:"id"`
+		SectionID        string   `json:"section_id"`
+		NormalizedStatus string   `json:"normalized_status"`
+		TicketIDs        []string `json:"ticket_ids"`
+		DuplicateOf      string   `json:"duplicate_of"`
+		Confidence       float64  `json:"confidence,omitempty"`
+	}
+	var out []item
+	for _, m := range promptItemIDRe.FindAllStringSubmatch(userPrompt, -1) {
+		id, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		d, ok := p.decisions[id]
+		sectionID := "UND"
+		if ok {
+			sectionID = p.ds.idForLabel(d.SectionLabel)
+		}
+		status := d.NormalizedStatus
+		if status == "" {
+			status = "other"
+		}
+		out = append(out, item{ID: id, SectionID: sectionID, NormalizedStatus: status, TicketIDs: []string{}, Confidence: d.Confidence})
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return "", LLMUsage{}, err
+	}
+	return string(data), LLMUsage{}, nil
+}
+
+func (p *ReplayProvider) Complete(_, _ string) (string, LLMUsage, error) {
+	return "[]", LLMUsage{}, nil
+}
+
+// LoadRecording reads a JSONL recording written by RecordingProvider.
+func LoadRecording(path string) ([]RecordedDecision, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open recording: %w", err)
+	}
+	defer f.Close()
+
+	var out []RecordedDecision
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var d RecordedDecision
+		if err := json.Unmarshal([]byte(text), &d); err != nil {
+			return nil, fmt.Errorf("recording %s line %d: %w", path, line, err)
+		}
+		out = append(out, d)
+	}
+	return out, scanner.Err()
+}
+
+// RecordingProvider wraps a live provider and keeps each item's decision so
+// a run can be saved with WriteRecording and replayed later offline.
+type RecordingProvider struct {
+	Provider
+	ds Dataset
+
+	mu        sync.Mutex
+	decisions map[int64]RecordedDecision
+}
+
+func NewRecordingProvider(inner Provider, ds Dataset) *RecordingProvider {
+	return &RecordingProvider{Provider: inner, ds: ds, decisions: make(map[int64]RecordedDecision)}
+}
+
+func (r *RecordingProvider) ClassifySections(systemPrompt, userPrompt string, options []SectionOption) (string, LLMUsage, error) {
+	text, usage, err := r.Provider.ClassifySections(systemPrompt, userPrompt, options)
+	if err != nil {
+		return text, usage, err
+	}
+	var items []struct {
+		ID               int64   `json:"id"`
+		SectionID        string  `json:"section_id"`
+		NormalizedStatus string  `json:"normalized_status"`
+		Confidence       float64 `json:"confidence"`
+	}
+	if jsonErr := json.Unmarshal([]byte(extractArray(text)), &items); jsonErr == nil {
+		r.mu.Lock()
+		for _, it := range items {
+			r.decisions[it.ID] = RecordedDecision{
+				ID:               it.ID,
+				SectionLabel:     r.ds.labelForID(strings.TrimSpace(it.SectionID)),
+				NormalizedStatus: it.NormalizedStatus,
+				Confidence:       it.Confidence,
+			}
+		}
+		r.mu.Unlock()
+	}
+	return text, usage, nil
+}
+
+// WriteRecording saves the recorded decisions as JSONL, sorted by item ID.
+func (r *RecordingProvider) WriteRecording(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]int64, 0, len(r.decisions))
+	for id := range r.decisions {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var sb strings.Builder
+	for _, id := range ids {
+		data, err := json.Marshal(r.decisions[id])
+		if err != nil {
+			return err
+		}
+		sb.Write(data)
+		sb.WriteByte('\n')
+	}
+	return os.WriteFile(path, []byte(sb.String()), 0644)
+}
+
+func extractArray(text string) string {
+	start := strings.Index(text, "[")
+	end := strings.LastIndex(text, "]")
+	if start == -1 || end <= start {
+		return text
+	}
+	return text[start : end+1]
+}
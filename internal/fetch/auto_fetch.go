@@ -11,19 +11,24 @@ import (
 	"github.com/slack-go/slack"
 )
 
-// FetchResult tracks separate counters for each skip reason.
+// FetchResult tracks separate counters for each skip reason. Updated counts
+// tracked items whose MR/PR was merged or reopened since they were imported
+// and Closed those flagged because their MR/PR was closed without merging.
 type FetchResult struct {
 	TotalFetched   int
 	Inserted       int
 	AlreadyTracked int
+	Updated        int
+	Closed         int
 	SkippedNonTeam int
 	Errors         []string
 }
 
 // FetchAndImportMRs fetches GitLab MRs and/or GitHub PRs for the current
-// report week and inserts new items into the database. It has no Slack
-// dependency so it can be called from both the slash command and the scheduler.
-func FetchAndImportMRs(cfg Config, db *sql.DB) (FetchResult, error) {
+// report week, inserts new items into the database and brings items imported
+// earlier up to date with their MR/PR. It has no Slack dependency so it can be
+// called from both the slash command and the scheduler.
+func FetchAndImportMRs(cfg Config, db Store) (FetchResult, error) {
 	if !cfg.GitLabConfigured() && !cfg.GitHubConfigured() {
 		return FetchResult{}, fmt.Errorf("neither GitLab nor GitHub is configured")
 	}
@@ -33,6 +38,7 @@ func FetchAndImportMRs(cfg Config, db *sql.DB) (FetchResult, error) {
 
 	var result FetchResult
 	var newItems []WorkItem
+	ids := LoadIdentities(db)
 
 	// Fetch GitLab MRs if configured.
 	if cfg.GitLabConfigured() {
@@ -44,12 +50,11 @@ func FetchAndImportMRs(cfg Config, db *sql.DB) (FetchResult, error) {
 			log.Printf("auto-fetch gitlab fetched=%d", len(mrs))
 			result.TotalFetched += len(mrs)
 			for _, mr := range mrs {
-				if len(cfg.TeamMembers) > 0 {
-					if !anyNameMatches(cfg.TeamMembers, mr.AuthorName) && !anyNameMatches(cfg.TeamMembers, mr.Author) {
-						log.Printf("auto-fetch skipped non-team gitlab author=%s username=%s", mr.AuthorName, mr.Author)
-						result.SkippedNonTeam++
-						continue
-					}
+				author := ids.Author(IdentityGitLab, mr.Author, mr.AuthorEmail)
+				if !IsTeamAuthor(cfg, mr.AuthorName, mr.Author, author) {
+					log.Printf("auto-fetch skipped non-team gitlab author=%s username=%s", mr.AuthorName, mr.Author)
+					result.SkippedNonTeam++
+					continue
 				}
 				exists, dbErr := SourceRefExists(db, mr.WebURL)
 				if dbErr != nil {
@@ -57,12 +62,13 @@ func FetchAndImportMRs(cfg Config, db *sql.DB) (FetchResult, error) {
 					continue
 				}
 				if exists {
-					result.AlreadyTracked++
+					reconcileTracked(db, &result, mr.WebURL, mr.Title, mapMRStatus(mr), mrReportedAt(mr, cfg.Location), author.SlackID)
 					continue
 				}
 				newItems = append(newItems, WorkItem{
 					Description: mr.Title,
 					Author:      mr.AuthorName,
+					AuthorID:    author.SlackID,
 					Source:      "gitlab",
 					SourceRef:   mr.WebURL,
 					Status:      mapMRStatus(mr),
@@ -83,12 +89,11 @@ func FetchAndImportMRs(cfg Config, db *sql.DB) (FetchResult, error) {
 			log.Printf("auto-fetch github fetched=%d", len(prs))
 			result.TotalFetched += len(prs)
 			for _, pr := range prs {
-				if len(cfg.TeamMembers) > 0 {
-					if !anyNameMatches(cfg.TeamMembers, pr.AuthorName) && !anyNameMatches(cfg.TeamMembers, pr.Author) {
-						log.Printf("auto-fetch skipped non-team github author=%s", pr.Author)
-						result.SkippedNonTeam++
-						continue
-					}
+				author := ids.Author(IdentityGitHub, pr.Author, pr.AuthorEmail)
+				if !IsTeamAuthor(cfg, pr.AuthorName, pr.Author, author) {
+					log.Printf("auto-fetch skipped non-team github author=%s", pr.Author)
+					result.SkippedNonTeam++
+					continue
 				}
 				exists, dbErr := SourceRefExists(db, pr.HTMLURL)
 				if dbErr != nil {
@@ -96,15 +101,17 @@ func FetchAndImportMRs(cfg Config, db *sql.DB) (FetchResult, error) {
 					continue
 				}
 				if exists {
-					result.AlreadyTracked++
+					reconcileTracked(db, &result, pr.HTMLURL, pr.Title, mapPRStatus(pr), prReportedAt(pr, cfg.Location), author.SlackID)
 					continue
 				}
 				newItems = append(newItems, WorkItem{
 					Description: pr.Title,
 					Author:      pr.AuthorName,
+					AuthorID:    author.SlackID,
 					Source:      "github",
 					SourceRef:   pr.HTMLURL,
 					Status:      mapPRStatus(pr),
+					TicketIDs:   strings.Join(pr.LinkedIssues, ","),
 					ReportedAt:  prReportedAt(pr, cfg.Location),
 				})
 			}
@@ -115,6 +122,8 @@ func FetchAndImportMRs(cfg Config, db *sql.DB) (FetchResult, error) {
 		return result, fmt.Errorf("all fetches failed: %s", strings.Join(result.Errors, "; "))
 	}
 
+	flagClosedUnmerged(cfg, db, ids, &result, monday, nextMonday)
+
 	if len(newItems) > 0 {
 		inserted, err := InsertWorkItems(db, newItems)
 		if err != nil {
@@ -126,6 +135,150 @@ func FetchAndImportMRs(cfg Config, db *sql.DB) (FetchResult, error) {
 	return result, nil
 }
 
+// ImportMR applies a single MR, such as one delivered by a webhook, the way
+// FetchAndImportMRs applies fetched ones: a new MR by a team member is
+// inserted, a tracked one is reconciled, and an MR closed without merging
+// only flags its tracked item.
+func ImportMR(cfg Config, db Store, mr GitLabMR) (FetchResult, error) {
+	return importOne(cfg, db, mr.AuthorName, mr.Author, mr.AuthorEmail, WorkItem{
+		Description: mr.Title,
+		Author:      mr.AuthorName,
+		Source:      "gitlab",
+		SourceRef:   mr.WebURL,
+		Status:      mapMRStatus(mr),
+		TicketIDs:   mr.TicketIDs,
+		ReportedAt:  mrReportedAt(mr, cfg.Location),
+	})
+}
+
+// ImportPR is ImportMR for a GitHub PR.
+func ImportPR(cfg Config, db Store, pr GitHubPR) (FetchResult, error) {
+	return importOne(cfg, db, pr.AuthorName, pr.Author, pr.AuthorEmail, WorkItem{
+		Description: pr.Title,
+		Author:      pr.AuthorName,
+		Source:      "github",
+		SourceRef:   pr.HTMLURL,
+		Status:      mapPRStatus(pr),
+		TicketIDs:   strings.Join(pr.LinkedIssues, ","),
+		ReportedAt:  prReportedAt(pr, cfg.Location),
+	})
+}
+
+func importOne(cfg Config, db Store, name, username, email string, item WorkItem) (FetchResult, error) {
+	result := FetchResult{TotalFetched: 1}
+	author := LoadIdentities(db).Author(item.Source, username, email)
+	if !IsTeamAuthor(cfg, name, username, author) {
+		result.SkippedNonTeam++
+		return result, nil
+	}
+	item.AuthorID = author.SlackID
+	exists, err := SourceRefExists(db, item.SourceRef)
+	if err != nil {
+		return result, fmt.Errorf("checking %s: %w", item.SourceRef, err)
+	}
+	if exists {
+		reconcileTracked(db, &result, item.SourceRef, item.Description, item.Status, item.ReportedAt, item.AuthorID)
+		return result, nil
+	}
+	if item.Status == statusClosedUnmerged {
+		return result, nil
+	}
+	inserted, err := InsertWorkItems(db, []WorkItem{item})
+	if err != nil {
+		return result, fmt.Errorf("storing %s: %w", item.SourceRef, err)
+	}
+	result.Inserted = inserted
+	return result, nil
+}
+
+// flagClosedUnmerged marks tracked items whose MR/PR was closed without
+// merging during the week. Closed MRs/PRs that were never imported are not
+// added, and they do not count towards TotalFetched.
+func flagClosedUnmerged(cfg Config, db Store, ids Identities, result *FetchResult, from, to time.Time) {
+	if cfg.GitLabConfigured() {
+		mrs, err := FetchClosedMRs(cfg, from, to)
+		if err != nil {
+			log.Printf("auto-fetch gitlab closed error: %v", err)
+			result.Errors = append(result.Errors, fmt.Sprintf("GitLab closed MRs: %v", err))
+		}
+		for _, mr := range mrs {
+			author := ids.Author(IdentityGitLab, mr.Author, mr.AuthorEmail)
+			if IsTeamAuthor(cfg, mr.AuthorName, mr.Author, author) {
+				reconcileTracked(db, result, mr.WebURL, mr.Title, mapMRStatus(mr), mrReportedAt(mr, cfg.Location), author.SlackID)
+			}
+		}
+	}
+	if cfg.GitHubConfigured() {
+		prs, err := FetchClosedGitHubPRs(cfg, from, to)
+		if err != nil {
+			log.Printf("auto-fetch github closed error: %v", err)
+			result.Errors = append(result.Errors, fmt.Sprintf("GitHub closed PRs: %v", err))
+		}
+		for _, pr := range prs {
+			author := ids.Author(IdentityGitHub, pr.Author, pr.AuthorEmail)
+			if IsTeamAuthor(cfg, pr.AuthorName, pr.Author, author) {
+				reconcileTracked(db, result, pr.HTMLURL, pr.Title, mapPRStatus(pr), prReportedAt(pr, cfg.Location), author.SlackID)
+			}
+		}
+	}
+}
+
+// IsTeamAuthor reports whether an MR/PR author is in team_members, by
+// display name or username, or through a linked account the team can trust
+// (see Link.Admits). Every author is when team_members is empty. A
+// self-service link of someone the team doesn't list only attributes items
+// that pass the name match: anyone can link any account.
+func IsTeamAuthor(cfg Config, name, username string, author Link) bool {
+	return len(cfg.TeamMembers) == 0 || anyNameMatches(cfg.TeamMembers, name) || anyNameMatches(cfg.TeamMembers, username) ||
+		author.Admits(cfg)
+}
+
+// reconcileTracked brings the item imported from ref up to date when its
+// MR/PR changed state: it was merged, closed without merging, or reopened
+// after being closed. The title and report time are refreshed along with the
+// status. An item whose MR/PR is still open keeps any status set in Slack,
+// such as "in testing". An item without an author ID gets authorID, the
+// Slack user who linked the MR/PR author's account since it was imported.
+// Deleted items and items of other teams are left alone.
+func reconcileTracked(db Store, result *FetchResult, ref, title, status string, reportedAt time.Time, authorID string) {
+	item, err := GetWorkItemBySourceRef(db, ref)
+	if err == sql.ErrNoRows {
+		if status != statusClosedUnmerged {
+			result.AlreadyTracked++
+		}
+		return
+	}
+	if err != nil {
+		log.Printf("auto-fetch lookup error ref=%s: %v", ref, err)
+		return
+	}
+	if item.AuthorID == "" && authorID != "" {
+		if err := UpdateWorkItemAuthorID(db, item.ID, authorID); err != nil {
+			log.Printf("auto-fetch author update error id=%d ref=%s: %v", item.ID, ref, err)
+		} else {
+			log.Printf("auto-fetch attributed id=%d ref=%s author_id=%s", item.ID, ref, authorID)
+		}
+	}
+	current := strings.ToLower(strings.TrimSpace(item.Status))
+	changed := current != status && (status == "done" || status == statusClosedUnmerged || current == statusClosedUnmerged)
+	if !changed {
+		if status != statusClosedUnmerged {
+			result.AlreadyTracked++
+		}
+		return
+	}
+	if err := UpdateWorkItemFromSource(db, item.ID, title, status, reportedAt); err != nil {
+		log.Printf("auto-fetch update error id=%d ref=%s: %v", item.ID, ref, err)
+		return
+	}
+	log.Printf("auto-fetch updated id=%d ref=%s status=%q->%q", item.ID, ref, item.Status, status)
+	if status == statusClosedUnmerged {
+		result.Closed++
+	} else {
+		result.Updated++
+	}
+}
+
 // FormatFetchSummary returns a human-readable summary of a FetchResult.
 func FormatFetchSummary(result FetchResult) string {
 	if len(result.Errors) > 0 && result.TotalFetched == 0 {
@@ -137,6 +290,7 @@ func FormatFetchSummary(result FetchResult) string {
 		if result.AlreadyTracked > 0 {
 			reasons = append(reasons, fmt.Sprintf("%d already tracked", result.AlreadyTracked))
 		}
+		reasons = append(reasons, formatSyncCounts(result)...)
 		if result.SkippedNonTeam > 0 {
 			reasons = append(reasons, fmt.Sprintf("%d non-team", result.SkippedNonTeam))
 		}
@@ -156,6 +310,7 @@ func FormatFetchSummary(result FetchResult) string {
 	if result.AlreadyTracked > 0 {
 		summary = append(summary, fmt.Sprintf("%d already tracked", result.AlreadyTracked))
 	}
+	summary = append(summary, formatSyncCounts(result)...)
 	if result.SkippedNonTeam > 0 {
 		summary = append(summary, fmt.Sprintf("%d non-team", result.SkippedNonTeam))
 	}
@@ -167,11 +322,22 @@ func FormatFetchSummary(result FetchResult) string {
 	return msg
 }
 
+func formatSyncCounts(result FetchResult) []string {
+	var out []string
+	if result.Updated > 0 {
+		out = append(out, fmt.Sprintf("%d updated", result.Updated))
+	}
+	if result.Closed > 0 {
+		out = append(out, fmt.Sprintf("%d closed without merging", result.Closed))
+	}
+	return out
+}
+
 // StartAutoFetchScheduler starts a cron-based scheduler that periodically
 // fetches MRs/PRs and posts a summary to the report channel.
 // The schedule is a standard 5-field cron expression (minute hour day-of-month month day-of-week).
 // Examples: "0 9 * * *" (daily 9am), "0 9 * * 1-5" (weekdays 9am), "0 9 * * 5" (Fridays 9am).
-func StartAutoFetchScheduler(cfg Config, db *sql.DB, api *slack.Client) {
+func StartAutoFetchScheduler(cfg Config, db Store, api *slack.Client) {
 	schedule := strings.TrimSpace(cfg.AutoFetchSchedule)
 	if schedule == "" {
 		log.Println("Auto-fetch disabled (auto_fetch_schedule not set)")
@@ -196,7 +362,7 @@ func StartAutoFetchScheduler(cfg Config, db *sql.DB, api *slack.Client) {
 	if cfg.GitHubConfigured() {
 		sources = append(sources, "GitHub")
 	}
-	log.Printf("Auto-fetch scheduled (cron: %s) from %s", schedule, strings.Join(sources, " + "))
+	log.Printf("Auto-fetch scheduled (cron: %s) from %s for %s", schedule, strings.Join(sources, " + "), cfg.TeamName)
 
 	go func() {
 		for {
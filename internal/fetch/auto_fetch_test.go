@@ -1,7 +1,11 @@
 package fetch
 
 import (
+	"path/filepath"
+	"reportbot/internal/config"
+	"reportbot/internal/storage/sqlite"
 	"testing"
+	"time"
 )
 
 func TestFormatFetchSummary_AllFailed(t *testing.T) {
@@ -89,3 +93,170 @@ func TestFetchAndImportMRs_NeitherConfigured(t *testing.T) {
 		t.Errorf("unexpected error: %q", got)
 	}
 }
+
+func TestFormatFetchSummary_UpdatedAndClosed(t *testing.T) {
+	result := FetchResult{
+		TotalFetched:   6,
+		AlreadyTracked: 3,
+		Updated:        2,
+		Closed:         1,
+		SkippedNonTeam: 1,
+	}
+	got := FormatFetchSummary(result)
+	want := "Found 6 MRs/PRs (merged+open), none to add (3 already tracked, 2 updated, 1 closed without merging, 1 non-team)."
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestReconcileTracked(t *testing.T) {
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "fetch.db"))
+	if err != nil {
+		t.Fatalf("sqlite.Open: %v", err)
+	}
+	defer db.Close()
+
+	opened := time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC)
+	later := opened.Add(48 * time.Hour)
+	items := []WorkItem{
+		{Description: "Add cache", Author: "Alice", Source: "gitlab", SourceRef: "mr/1", Status: "in progress", ReportedAt: opened},
+		{Description: "Tune retries", Author: "Alice", Source: "gitlab", SourceRef: "mr/2", Status: "in testing", ReportedAt: opened},
+		{Description: "Drop legacy API", Author: "Bob", Source: "github", SourceRef: "pr/3", Status: "in progress", ReportedAt: opened},
+		{Description: "Retry on 503", Author: "Bob", Source: "github", SourceRef: "pr/4", Status: statusClosedUnmerged, ReportedAt: opened},
+	}
+	if _, err := db.InsertWorkItems(items, ""); err != nil {
+		t.Fatalf("InsertWorkItems: %v", err)
+	}
+
+	var result FetchResult
+	reconcileTracked(db, &result, "mr/1", "Add cache layer", "done", later, "")
+	reconcileTracked(db, &result, "mr/2", "Tune retries", "in progress", later, "")
+	reconcileTracked(db, &result, "pr/3", "Drop legacy API", statusClosedUnmerged, later, "")
+	reconcileTracked(db, &result, "pr/4", "Retry on 503", "in progress", later, "")
+	reconcileTracked(db, &result, "mr/1", "Add cache layer", "done", later, "")
+	reconcileTracked(db, &result, "pr/9", "Never imported", statusClosedUnmerged, later, "")
+
+	if result.Updated != 2 || result.Closed != 1 || result.AlreadyTracked != 2 {
+		t.Fatalf("counters: %+v", result)
+	}
+	want := map[string]struct {
+		description, status string
+		reportedAt          time.Time
+	}{
+		"mr/1": {"Add cache layer", "done", later},
+		"mr/2": {"Tune retries", "in testing", opened},
+		"pr/3": {"Drop legacy API", statusClosedUnmerged, later},
+		"pr/4": {"Retry on 503", "in progress", later},
+	}
+	for ref, w := range want {
+		got, err := db.GetWorkItemBySourceRef(ref)
+		if err != nil {
+			t.Fatalf("GetWorkItemBySourceRef(%s): %v", ref, err)
+		}
+		if got.Description != w.description || got.Status != w.status || !got.ReportedAt.Equal(w.reportedAt) {
+			t.Errorf("%s: got %q %q %s, want %q %q %s", ref, got.Description, got.Status, got.ReportedAt, w.description, w.status, w.reportedAt)
+		}
+	}
+}
+
+func TestImportAttributesLinkedAccounts(t *testing.T) {
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "fetch.db"))
+	if err != nil {
+		t.Fatalf("sqlite.Open: %v", err)
+	}
+	defer db.Close()
+	for _, link := range []struct {
+		slackID, kind, account string
+		manager                bool
+	}{
+		{"U_ALICE", IdentityGitLab, "asmith", false},
+		{"U_BOB", IdentityGitHub, "bob-dev", false},
+		{"U_EVE", IdentityGitLab, "e-vance", false},
+		{"U_MALLORY", IdentityGitLab, "mallory-x", false},
+		{"U_DANA", IdentityGitHub, "dk-99", true},
+		{"U_FRANK", IdentityEmail, "frank@example.com", true},
+	} {
+		linkFn := db.LinkIdentity
+		if link.manager {
+			linkFn = db.ReassignIdentity
+		}
+		if err := linkFn(link.slackID, link.kind, link.account); err != nil {
+			t.Fatalf("link %s: %v", link.account, err)
+		}
+	}
+
+	cfg := Config{TeamMembers: []string{"Alice Smith", "Bob Lee", "U_EVE"}, Location: time.UTC}
+	merged := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	mrs := []GitLabMR{
+		// Matched by name, attributed by the link.
+		{Title: "Add cache", Author: "ASmith", AuthorName: "Alice Smith", WebURL: "mr/1", State: "merged", MergedAt: merged},
+		// Admitted by a self-service link of a user team_members lists by Slack ID.
+		{Title: "Rotate keys", Author: "e-vance", AuthorName: "E. Vance", WebURL: "mr/5", State: "merged", MergedAt: merged},
+		// A self-service link of someone the team doesn't list admits nothing.
+		{Title: "Exfiltrate", Author: "mallory-x", AuthorName: "Mallory", WebURL: "mr/4", State: "merged", MergedAt: merged},
+	}
+	for _, mr := range mrs {
+		if _, err := ImportMR(cfg, db, mr); err != nil {
+			t.Fatalf("ImportMR %s: %v", mr.WebURL, err)
+		}
+	}
+	prs := []GitHubPR{
+		{Title: "Fix flaky test", Author: "bob-dev", AuthorName: "Bob Lee", HTMLURL: "pr/2", State: "merged", MergedAt: merged},
+		{Title: "Docs", Author: "carol", AuthorName: "Bob Lee", HTMLURL: "pr/3", State: "open", UpdatedAt: merged},
+		// Differently named authors admitted by manager-linked accounts.
+		{Title: "Speed up CI", Author: "dk-99", AuthorName: "DK", HTMLURL: "pr/6", State: "merged", MergedAt: merged},
+		{Title: "Fix typo", Author: "fz", AuthorName: "fz", AuthorEmail: "Frank@Example.com", HTMLURL: "pr/7", State: "merged", MergedAt: merged},
+	}
+	for _, pr := range prs {
+		if _, err := ImportPR(cfg, db, pr); err != nil {
+			t.Fatalf("ImportPR %s: %v", pr.HTMLURL, err)
+		}
+	}
+	if exists, _ := db.SourceRefExists("mr/4"); exists {
+		t.Fatal("expected the MR of an unlisted, self-linked author to be skipped")
+	}
+
+	// Linking later backfills items already tracked without an author ID
+	// but keeps the author ID of attributed ones.
+	if err := db.LinkIdentity("U_CAROL", IdentityGitHub, "carol"); err != nil {
+		t.Fatalf("LinkIdentity carol: %v", err)
+	}
+	if err := db.ReassignIdentity("U_BOB2", IdentityGitHub, "bob-dev"); err != nil {
+		t.Fatalf("ReassignIdentity: %v", err)
+	}
+	for _, pr := range prs[:2] {
+		if result, err := ImportPR(cfg, db, pr); err != nil || result.AlreadyTracked != 1 {
+			t.Fatalf("ImportPR %s again: %+v err=%v", pr.HTMLURL, result, err)
+		}
+	}
+
+	want := map[string]string{"mr/1": "U_ALICE", "mr/5": "U_EVE", "pr/2": "U_BOB", "pr/3": "U_CAROL", "pr/6": "U_DANA", "pr/7": "U_FRANK"}
+	for ref, authorID := range want {
+		item, err := db.GetWorkItemBySourceRef(ref)
+		if err != nil || item.AuthorID != authorID {
+			t.Errorf("%s: author_id=%q want %q err=%v", ref, item.AuthorID, authorID, err)
+		}
+	}
+}
+
+func TestLinkAdmits(t *testing.T) {
+	single := Config{TeamMembers: []string{"Alice Smith", "U_ALICE"}, ManagerSlackIDs: []string{"U_BOSS"}}
+	multi := Config{TeamMembers: []string{"Alice Smith"}, Teams: []config.Team{{ID: "web"}}}
+	tests := []struct {
+		cfg  Config
+		link Link
+		want bool
+	}{
+		{single, Link{}, false},
+		{single, Link{SlackID: "U_ALICE"}, true},
+		{single, Link{SlackID: "U_BOSS"}, true},
+		{single, Link{SlackID: "U_DANA"}, false},
+		{single, Link{SlackID: "U_DANA", Confirmed: true}, true},
+		{multi, Link{SlackID: "U_DANA", Confirmed: true}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.link.Admits(tt.cfg); got != tt.want {
+			t.Errorf("%+v.Admits(multi=%t) = %t, want %t", tt.link, tt.cfg.MultiTeam(), got, tt.want)
+		}
+	}
+}
@@ -1,22 +1,32 @@
 package fetch
 
 import (
-	"database/sql"
 	"regexp"
 	"reportbot/internal/config"
 	"reportbot/internal/domain"
 	gh "reportbot/internal/integrations/github"
 	gl "reportbot/internal/integrations/gitlab"
-	"reportbot/internal/storage/sqlite"
+	"reportbot/internal/storage"
 	"strings"
 	"time"
 )
 
 type Config = config.Config
 type WorkItem = domain.WorkItem
+type Store = storage.Store
 type GitLabMR = domain.GitLabMR
 type GitHubPR = domain.GitHubPR
 
+const (
+	IdentityGitLab = domain.IdentityGitLab
+	IdentityGitHub = domain.IdentityGitHub
+	IdentityEmail  = domain.IdentityEmail
+)
+
+func NormalizeAccount(account string) string { return domain.NormalizeAccount(account) }
+
+func ListIdentities(db Store) ([]domain.Identity, error) { return db.ListIdentities() }
+
 func ReportWeekRange(cfg Config, now time.Time) (time.Time, time.Time) {
 	return domain.ReportWeekRange(cfg, now)
 }
@@ -29,18 +39,49 @@ func FetchGitHubPRs(cfg Config, from, to time.Time) ([]GitHubPR, error) {
 	return gh.FetchGitHubPRs(cfg, from, to)
 }
 
-func SourceRefExists(db *sql.DB, sourceRef string) (bool, error) {
-	return sqlite.SourceRefExists(db, sourceRef)
+func FetchClosedMRs(cfg Config, from, to time.Time) ([]GitLabMR, error) {
+	return gl.FetchClosedMRs(cfg, from, to)
+}
+
+func FetchClosedGitHubPRs(cfg Config, from, to time.Time) ([]GitHubPR, error) {
+	return gh.FetchClosedGitHubPRs(cfg, from, to)
+}
+
+func SourceRefExists(db Store, sourceRef string) (bool, error) {
+	return db.SourceRefExists(sourceRef)
+}
+
+func GetWorkItemBySourceRef(db Store, sourceRef string) (WorkItem, error) {
+	return db.GetWorkItemBySourceRef(sourceRef)
+}
+
+// UpdateWorkItemFromSource records the changes without an actor, like
+// InsertWorkItems.
+func UpdateWorkItemFromSource(db Store, id int64, description, status string, reportedAt time.Time) error {
+	return db.UpdateWorkItemFromSource(id, description, status, reportedAt, "")
+}
+
+func UpdateWorkItemAuthorID(db Store, id int64, authorID string) error {
+	return db.UpdateWorkItemAuthorID(id, authorID)
 }
 
-func InsertWorkItems(db *sql.DB, items []WorkItem) (int, error) {
-	return sqlite.InsertWorkItems(db, items)
+// InsertWorkItems stores fetched items; their created events have no actor
+// because the bot imported them.
+func InsertWorkItems(db Store, items []WorkItem) (int, error) {
+	return db.InsertWorkItems(items, "")
 }
 
+// statusClosedUnmerged flags items whose MR/PR was closed without merging.
+// It is a free-text status, so reports show it as is.
+const statusClosedUnmerged = "closed, not merged"
+
 func mapMRStatus(mr GitLabMR) string {
 	if mr.State == "merged" {
 		return "done"
 	}
+	if mr.State == "closed" {
+		return statusClosedUnmerged
+	}
 	if mr.State == "opened" {
 		return "in progress"
 	}
@@ -54,6 +95,9 @@ func mrReportedAt(mr GitLabMR, loc *time.Location) time.Time {
 	if !mr.MergedAt.IsZero() {
 		return mr.MergedAt.In(loc)
 	}
+	if mr.State == "closed" && !mr.ClosedAt.IsZero() {
+		return mr.ClosedAt.In(loc)
+	}
 	if !mr.CreatedAt.IsZero() {
 		return mr.CreatedAt.In(loc)
 	}
@@ -64,6 +108,9 @@ func mapPRStatus(pr GitHubPR) string {
 	if pr.State == "merged" {
 		return "done"
 	}
+	if pr.State == "closed" {
+		return statusClosedUnmerged
+	}
 	if pr.State == "open" {
 		return "in progress"
 	}
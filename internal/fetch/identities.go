@@ -0,0 +1,79 @@
+package fetch
+
+import (
+	"log"
+	"strings"
+)
+
+// Link is the Slack user an account is linked to. Confirmed links were
+// made by a manager; the others are self-service and unverified.
+type Link struct {
+	SlackID   string
+	Confirmed bool
+}
+
+// Identities maps the GitLab usernames, GitHub logins and emails Slack users
+// linked with /whoami to their links. Keys are "kind:account", where kind is
+// the item source ("gitlab" or "github") or "email".
+type Identities map[string]Link
+
+// LoadIdentities reads every linked account. On error it logs and returns
+// no identities, so items are still imported and matched by name.
+func LoadIdentities(db Store) Identities {
+	list, err := ListIdentities(db)
+	if err != nil {
+		log.Printf("auto-fetch identities error: %v", err)
+		return Identities{}
+	}
+	ids := make(Identities, len(list))
+	for _, id := range list {
+		for _, kind := range []string{IdentityGitLab, IdentityGitHub, IdentityEmail} {
+			if account := id.Account(kind); account != "" {
+				ids[kind+":"+account] = Link{SlackID: id.SlackID, Confirmed: id.Confirmed(kind)}
+			}
+		}
+	}
+	return ids
+}
+
+// Author returns the link of an MR/PR author: by their username on source,
+// or else by their email. It is the zero Link when neither is linked.
+func (ids Identities) Author(source, username, email string) Link {
+	if link := ids.lookup(source, username); link.SlackID != "" {
+		return link
+	}
+	return ids.lookup(IdentityEmail, email)
+}
+
+func (ids Identities) lookup(kind, account string) Link {
+	account = NormalizeAccount(account)
+	if account == "" {
+		return Link{}
+	}
+	return ids[kind+":"+account]
+}
+
+// Admits reports whether the link vouches for its author being on cfg's
+// team: the linked Slack user is listed in team_members by Slack ID or
+// manages the team, or a manager confirmed the link. Confirmation alone
+// doesn't say which team the user is on, so in a teams deployment the
+// team has to list them.
+func (l Link) Admits(cfg Config) bool {
+	if l.SlackID == "" {
+		return false
+	}
+	if listsSlackID(cfg.TeamMembers, l.SlackID) || cfg.IsManagerID(l.SlackID) {
+		return true
+	}
+	return l.Confirmed && !cfg.MultiTeam()
+}
+
+// listsSlackID reports whether team_members names slackID directly.
+func listsSlackID(members []string, slackID string) bool {
+	for _, m := range members {
+		if strings.TrimSpace(m) == slackID {
+			return true
+		}
+	}
+	return false
+}
@@ -9,4 +9,6 @@ import (
 type Config = config.Config
 type GitHubPR = domain.GitHubPR
 
+const GitHubFetchAPISearch = config.GitHubFetchAPISearch
+
 var externalHTTPClient = httpx.ExternalHTTPClient()
@@ -41,7 +41,19 @@ type githubPRLinks struct {
 	MergedAt string `json:"merged_at"`
 }
 
+// FetchGitHubPRs returns the PRs merged in [from, to) and the open PRs
+// updated in it, through the API selected by github_fetch_api.
 func FetchGitHubPRs(cfg Config, from, to time.Time) ([]GitHubPR, error) {
+	if cfg.GitHubFetchAPI == GitHubFetchAPISearch {
+		return fetchGitHubPRsSearch(cfg, from, to)
+	}
+	return fetchGitHubPRsGraphQL(cfg, from, to)
+}
+
+// fetchGitHubPRsSearch uses the REST search API, which has neither merge
+// times nor author names: merge time is approximated by close time and the
+// author name is the login.
+func fetchGitHubPRsSearch(cfg Config, from, to time.Time) ([]GitHubPR, error) {
 	fromStr := from.Format("2006-01-02")
 	toStr := to.Format("2006-01-02")
 	scope := buildScopeQualifier(cfg)
@@ -52,7 +64,7 @@ func FetchGitHubPRs(cfg Config, from, to time.Time) ([]GitHubPR, error) {
 	// including next-week items on date-boundary searches.
 	mergedQuery := strings.TrimSpace(fmt.Sprintf("type:pr is:merged merged:>=%s merged:<%s %s", fromStr, toStr, scope))
 	log.Printf("github fetch merged query=%s", mergedQuery)
-	mergedItems, err := searchGitHubPRs(cfg.GitHubToken, mergedQuery)
+	mergedItems, err := searchGitHubPRs(cfg, mergedQuery)
 	if err != nil {
 		return nil, fmt.Errorf("searching merged PRs: %w", err)
 	}
@@ -76,7 +88,7 @@ func FetchGitHubPRs(cfg Config, from, to time.Time) ([]GitHubPR, error) {
 	// Query 2: Open PRs updated since the start of the range.
 	openQuery := strings.TrimSpace(fmt.Sprintf("type:pr is:open updated:>=%s %s", fromStr, scope))
 	log.Printf("github fetch open query=%s", openQuery)
-	openItems, err := searchGitHubPRs(cfg.GitHubToken, openQuery)
+	openItems, err := searchGitHubPRs(cfg, openQuery)
 	if err != nil {
 		return nil, fmt.Errorf("searching open PRs: %w", err)
 	}
@@ -103,19 +115,44 @@ func FetchGitHubPRs(cfg Config, from, to time.Time) ([]GitHubPR, error) {
 	return allPRs, nil
 }
 
-func searchGitHubPRs(token, query string) ([]githubPRItem, error) {
+// FetchClosedGitHubPRs returns the PRs closed without merging in [from, to).
+// Fetch uses them to flag items that were imported while the PR was open.
+func FetchClosedGitHubPRs(cfg Config, from, to time.Time) ([]GitHubPR, error) {
+	if cfg.GitHubFetchAPI != GitHubFetchAPISearch {
+		return fetchClosedGitHubPRsGraphQL(cfg, from, to)
+	}
+	query := strings.TrimSpace(fmt.Sprintf("type:pr is:closed is:unmerged closed:>=%s closed:<%s %s",
+		from.Format("2006-01-02"), to.Format("2006-01-02"), buildScopeQualifier(cfg)))
+	log.Printf("github fetch closed query=%s", query)
+	items, err := searchGitHubPRs(cfg, query)
+	if err != nil {
+		return nil, fmt.Errorf("searching closed PRs: %w", err)
+	}
+	var closed []GitHubPR
+	for _, item := range items {
+		pr := convertGitHubItem(item, "closed")
+		if pr.ClosedAt.IsZero() || pr.ClosedAt.Before(from) || !pr.ClosedAt.Before(to) {
+			continue
+		}
+		closed = append(closed, pr)
+	}
+	log.Printf("github fetch closed total=%d", len(closed))
+	return closed, nil
+}
+
+func searchGitHubPRs(cfg Config, query string) ([]githubPRItem, error) {
 	var all []githubPRItem
 	page := 1
 
 	for {
-		apiURL := fmt.Sprintf("https://api.github.com/search/issues?q=%s&per_page=100&page=%d",
-			url.QueryEscape(query), page)
+		apiURL := fmt.Sprintf("%s/search/issues?q=%s&per_page=100&page=%d",
+			restBaseURL(cfg), url.QueryEscape(query), page)
 
 		req, err := http.NewRequest("GET", apiURL, nil)
 		if err != nil {
 			return nil, fmt.Errorf("creating request: %w", err)
 		}
-		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Authorization", "Bearer "+cfg.GitHubToken)
 		req.Header.Set("Accept", "application/vnd.github+json")
 
 		resp, err := externalHTTPClient.Do(req)
@@ -185,12 +222,16 @@ func convertGitHubItem(item githubPRItem, derivedState string) GitHubPR {
 }
 
 func extractRepoFullName(repoURL string) string {
-	// repoURL is like "https://api.github.com/repos/org/repo-name"
+	// repoURL is like "https://api.github.com/repos/org/repo-name", or
+	// "https://github.example.com/api/v3/repos/org/repo-name" on GHES
 	u, err := url.Parse(repoURL)
 	if err != nil {
 		return ""
 	}
 	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) >= 5 && parts[0] == "api" && parts[1] == "v3" {
+		parts = parts[2:]
+	}
 	// Expected: ["repos", "org", "repo-name"]
 	if len(parts) >= 3 && parts[0] == "repos" {
 		return parts[1] + "/" + parts[2]
@@ -198,6 +239,33 @@ func extractRepoFullName(repoURL string) string {
 	return ""
 }
 
+// restBaseURL is the REST API root: api.github.com, or /api/v3 on a GitHub
+// Enterprise Server set by github_base_url.
+func restBaseURL(cfg Config) string {
+	if cfg.GitHubBaseURL == "" {
+		return "https://api.github.com"
+	}
+	return enterpriseRoot(cfg.GitHubBaseURL) + "/api/v3"
+}
+
+// graphQLURL is the GraphQL endpoint matching restBaseURL.
+func graphQLURL(cfg Config) string {
+	if cfg.GitHubBaseURL == "" {
+		return "https://api.github.com/graphql"
+	}
+	return enterpriseRoot(cfg.GitHubBaseURL) + "/api/graphql"
+}
+
+// enterpriseRoot accepts github_base_url with or without the API path, as
+// GHES docs and clients use both forms.
+func enterpriseRoot(baseURL string) string {
+	root := strings.TrimRight(baseURL, "/")
+	for _, suffix := range []string{"/api/v3", "/api/graphql", "/api"} {
+		root = strings.TrimSuffix(root, suffix)
+	}
+	return root
+}
+
 func buildScopeQualifier(cfg Config) string {
 	if len(cfg.GitHubRepos) > 0 {
 		var parts []string
@@ -1,6 +1,10 @@
 package github
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reportbot/internal/httpx"
 	"testing"
 	"time"
 )
@@ -13,6 +17,7 @@ func TestExtractRepoFullName(t *testing.T) {
 		{"https://api.github.com/repos/myorg/myrepo", "myorg/myrepo"},
 		{"https://api.github.com/repos/acme/widget-service", "acme/widget-service"},
 		{"https://api.github.com/repos/a/b/extra", "a/b"},
+		{"https://github.example.com/api/v3/repos/acme/widget-service", "acme/widget-service"},
 		{"", ""},
 		{"not-a-url", ""},
 		{"https://api.github.com/users/foo", ""},
@@ -62,6 +67,76 @@ func TestBuildScopeQualifier(t *testing.T) {
 	}
 }
 
+func TestGitHubBaseURLs(t *testing.T) {
+	tests := []struct {
+		baseURL     string
+		wantREST    string
+		wantGraphQL string
+	}{
+		{"", "https://api.github.com", "https://api.github.com/graphql"},
+		{"https://github.example.com", "https://github.example.com/api/v3", "https://github.example.com/api/graphql"},
+		{"https://github.example.com/api/v3/", "https://github.example.com/api/v3", "https://github.example.com/api/graphql"},
+		{"https://github.example.com/api/graphql", "https://github.example.com/api/v3", "https://github.example.com/api/graphql"},
+	}
+	for _, tt := range tests {
+		cfg := Config{GitHubBaseURL: tt.baseURL}
+		if got := restBaseURL(cfg); got != tt.wantREST {
+			t.Errorf("restBaseURL(%q) = %q, want %q", tt.baseURL, got, tt.wantREST)
+		}
+		if got := graphQLURL(cfg); got != tt.wantGraphQL {
+			t.Errorf("graphQLURL(%q) = %q, want %q", tt.baseURL, got, tt.wantGraphQL)
+		}
+	}
+}
+
+func TestFetchGitHubPRsFromEnterpriseServer(t *testing.T) {
+	// A GHES instance with a self-signed certificate, reachable once
+	// tls_skip_verify is applied to the shared client.
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/search/issues" {
+			http.NotFound(w, r)
+			return
+		}
+		resp := githubSearchResponse{}
+		if r.URL.Query().Get("q") == "type:pr is:merged merged:>=2026-03-02 merged:<2026-03-09 org:acme" {
+			resp.Items = []githubPRItem{{
+				Title:         "Pin base image",
+				HTMLURL:       "https://github.example.com/acme/infra/pull/8",
+				State:         "closed",
+				User:          githubUser{Login: "alice"},
+				PullRequest:   &githubPRLinks{},
+				CreatedAt:     "2026-03-02T09:00:00Z",
+				UpdatedAt:     "2026-03-03T10:00:00Z",
+				ClosedAt:      "2026-03-03T10:00:00Z",
+				RepositoryURL: "https://github.example.com/api/v3/repos/acme/infra",
+			}}
+		}
+		resp.TotalCount = len(resp.Items)
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	client := httpx.ExternalHTTPClient()
+	origTransport, origTimeout := client.Transport, client.Timeout
+	defer func() { client.Transport, client.Timeout = origTransport, origTimeout }()
+
+	cfg := Config{GitHubToken: "ghp-test", GitHubOrg: "acme", GitHubBaseURL: srv.URL, GitHubFetchAPI: GitHubFetchAPISearch}
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+
+	if _, err := FetchGitHubPRs(cfg, from, to); err == nil {
+		t.Fatal("expected a certificate error before tls_skip_verify is applied")
+	}
+	httpx.ConfigureExternalHTTPClient(0, true)
+	prs, err := FetchGitHubPRs(cfg, from, to)
+	if err != nil {
+		t.Fatalf("FetchGitHubPRs: %v", err)
+	}
+	if len(prs) != 1 || prs[0].RepoFullName != "acme/infra" || prs[0].State != "merged" {
+		t.Fatalf("unexpected PRs: %+v", prs)
+	}
+}
+
 func TestConvertGitHubItem(t *testing.T) {
 	t.Run("merged PR", func(t *testing.T) {
 		item := githubPRItem{
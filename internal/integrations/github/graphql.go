@@ -0,0 +1,285 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// searchResultCap is the most results GitHub search returns for one
+	// query, however it is paginated.
+	searchResultCap = 1000
+	// minSplitRange stops range splitting; a busier hour than 1000 PRs is
+	// fetched as far as the cap allows.
+	minSplitRange   = time.Hour
+	graphQLPageSize = 50
+)
+
+// One search page with everything fetch needs per PR. The author's name and
+// public email are only available on User authors (not bots).
+const prSearchQuery = `query($q: String!, $first: Int!, $after: String) {
+  search(query: $q, type: ISSUE, first: $first, after: $after) {
+    issueCount
+    pageInfo { hasNextPage endCursor }
+    nodes {
+      ... on PullRequest {
+        url
+        title
+        state
+        createdAt
+        updatedAt
+        closedAt
+        mergedAt
+        author { login ... on User { name email } }
+        repository { nameWithOwner }
+        labels(first: 20) { nodes { name } }
+        closingIssuesReferences(first: 10) { nodes { number repository { nameWithOwner } } }
+      }
+    }
+  }
+}`
+
+type graphQLPR struct {
+	URL       string `json:"url"`
+	Title     string `json:"title"`
+	State     string `json:"state"` // OPEN, CLOSED or MERGED
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+	ClosedAt  string `json:"closedAt"`
+	MergedAt  string `json:"mergedAt"`
+	Author    *struct {
+		Login string `json:"login"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	} `json:"author"`
+	Repository struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"repository"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	ClosingIssuesReferences struct {
+		Nodes []struct {
+			Number     int `json:"number"`
+			Repository struct {
+				NameWithOwner string `json:"nameWithOwner"`
+			} `json:"repository"`
+		} `json:"nodes"`
+	} `json:"closingIssuesReferences"`
+}
+
+type graphQLSearchResponse struct {
+	Data *struct {
+		Search struct {
+			IssueCount int `json:"issueCount"`
+			PageInfo   struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Nodes []graphQLPR `json:"nodes"`
+		} `json:"search"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// fetchGitHubPRsGraphQL is FetchGitHubPRs over the GraphQL API: merged PRs
+// in [from, to) and open PRs updated in it, with real merge times and
+// author names.
+func fetchGitHubPRsGraphQL(cfg Config, from, to time.Time) ([]GitHubPR, error) {
+	scope := buildScopeQualifier(cfg)
+
+	merged, err := searchPRsGraphQL(cfg, func(from, to time.Time) string {
+		return rangeQuery("type:pr is:merged", "merged", from, to, scope)
+	}, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("searching merged PRs: %w", err)
+	}
+	open, err := searchPRsGraphQL(cfg, func(from, to time.Time) string {
+		return rangeQuery("type:pr is:open", "updated", from, to, scope)
+	}, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("searching open PRs: %w", err)
+	}
+
+	var allPRs []GitHubPR
+	mergedCount, openCount := 0, 0
+	for _, pr := range merged {
+		if pr.State == "merged" && inRange(pr.MergedAt, from, to) {
+			allPRs = append(allPRs, pr)
+			mergedCount++
+		}
+	}
+	for _, pr := range open {
+		if pr.State == "open" && inRange(pr.UpdatedAt, from, to) {
+			allPRs = append(allPRs, pr)
+			openCount++
+		}
+	}
+	log.Printf("github graphql fetch done total=%d (merged=%d open=%d)", len(allPRs), mergedCount, openCount)
+	return allPRs, nil
+}
+
+// fetchClosedGitHubPRsGraphQL is FetchClosedGitHubPRs over the GraphQL API.
+func fetchClosedGitHubPRsGraphQL(cfg Config, from, to time.Time) ([]GitHubPR, error) {
+	scope := buildScopeQualifier(cfg)
+	prs, err := searchPRsGraphQL(cfg, func(from, to time.Time) string {
+		return rangeQuery("type:pr is:closed is:unmerged", "closed", from, to, scope)
+	}, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("searching closed PRs: %w", err)
+	}
+	var closed []GitHubPR
+	for _, pr := range prs {
+		if pr.State == "closed" && inRange(pr.ClosedAt, from, to) {
+			closed = append(closed, pr)
+		}
+	}
+	log.Printf("github graphql fetch closed total=%d", len(closed))
+	return closed, nil
+}
+
+// rangeQuery restricts a search to field in [from, to) with timestamps, so
+// ranges can be split finer than a day.
+func rangeQuery(base, field string, from, to time.Time, scope string) string {
+	const layout = "2006-01-02T15:04:05Z"
+	return strings.TrimSpace(fmt.Sprintf("%s %s:>=%s %s:<%s %s",
+		base, field, from.UTC().Format(layout), field, to.UTC().Format(layout), scope))
+}
+
+func inRange(t, from, to time.Time) bool {
+	return !t.IsZero() && !t.Before(from) && t.Before(to)
+}
+
+// searchPRsGraphQL runs the search built by query for [from, to). GitHub
+// search returns at most 1000 results per query, so a range with more is
+// split in half and each half searched on its own.
+func searchPRsGraphQL(cfg Config, query func(from, to time.Time) string, from, to time.Time) ([]GitHubPR, error) {
+	q := query(from, to)
+	log.Printf("github graphql search query=%s", q)
+
+	var prs []GitHubPR
+	cursor := ""
+	for {
+		page, err := graphQLSearchPage(cfg, q, cursor)
+		if err != nil {
+			return nil, err
+		}
+		search := page.Data.Search
+		if cursor == "" && search.IssueCount > searchResultCap {
+			if to.Sub(from) > minSplitRange {
+				mid := from.Add(to.Sub(from) / 2).Truncate(time.Second)
+				log.Printf("github graphql search split results=%d at %s", search.IssueCount, mid.UTC().Format(time.RFC3339))
+				first, err := searchPRsGraphQL(cfg, query, from, mid)
+				if err != nil {
+					return nil, err
+				}
+				second, err := searchPRsGraphQL(cfg, query, mid, to)
+				if err != nil {
+					return nil, err
+				}
+				return append(first, second...), nil
+			}
+			log.Printf("github graphql search results=%d exceed the %d cap within %s; the rest are skipped", search.IssueCount, searchResultCap, to.Sub(from))
+		}
+		for _, node := range search.Nodes {
+			if node.URL != "" {
+				prs = append(prs, convertGraphQLPR(node))
+			}
+		}
+		if !search.PageInfo.HasNextPage || search.PageInfo.EndCursor == "" {
+			break
+		}
+		cursor = search.PageInfo.EndCursor
+	}
+	return prs, nil
+}
+
+func graphQLSearchPage(cfg Config, query, cursor string) (*graphQLSearchResponse, error) {
+	variables := map[string]any{"q": query, "first": graphQLPageSize}
+	if cursor != "" {
+		variables["after"] = cursor
+	}
+	payload, err := json.Marshal(map[string]any{"query": prSearchQuery, "variables": variables})
+	if err != nil {
+		return nil, fmt.Errorf("encoding query: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", graphQLURL(cfg), bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+cfg.GitHubToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := externalHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("GitHub GraphQL API returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result graphQLSearchResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		var msgs []string
+		for _, e := range result.Errors {
+			msgs = append(msgs, e.Message)
+		}
+		return nil, fmt.Errorf("GitHub GraphQL API errors: %s", strings.Join(msgs, "; "))
+	}
+	if result.Data == nil {
+		return nil, fmt.Errorf("GitHub GraphQL API returned no data")
+	}
+	return &result, nil
+}
+
+func convertGraphQLPR(node graphQLPR) GitHubPR {
+	createdAt, _ := time.Parse(time.RFC3339, node.CreatedAt)
+	updatedAt, _ := time.Parse(time.RFC3339, node.UpdatedAt)
+	closedAt, _ := time.Parse(time.RFC3339, node.ClosedAt)
+	mergedAt, _ := time.Parse(time.RFC3339, node.MergedAt)
+
+	pr := GitHubPR{
+		Title:        node.Title,
+		HTMLURL:      node.URL,
+		MergedAt:     mergedAt,
+		UpdatedAt:    updatedAt,
+		CreatedAt:    createdAt,
+		ClosedAt:     closedAt,
+		State:        strings.ToLower(node.State),
+		RepoFullName: node.Repository.NameWithOwner,
+	}
+	if node.Author != nil {
+		pr.Author = node.Author.Login
+		pr.AuthorName = strings.TrimSpace(node.Author.Name)
+		pr.AuthorEmail = strings.TrimSpace(node.Author.Email)
+	}
+	if pr.AuthorName == "" {
+		pr.AuthorName = pr.Author
+	}
+	for _, l := range node.Labels.Nodes {
+		pr.Labels = append(pr.Labels, l.Name)
+	}
+	for _, issue := range node.ClosingIssuesReferences.Nodes {
+		pr.LinkedIssues = append(pr.LinkedIssues, fmt.Sprintf("%s#%d", issue.Repository.NameWithOwner, issue.Number))
+	}
+	return pr
+}
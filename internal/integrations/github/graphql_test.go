@@ -0,0 +1,153 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type graphQLRequest struct {
+	Query     string `json:"query"`
+	Variables struct {
+		Q     string `json:"q"`
+		After string `json:"after"`
+	} `json:"variables"`
+}
+
+// withMockGraphQL serves a GitHub Enterprise GraphQL endpoint with respond
+// and records the search queries it was sent. The returned config points at
+// it.
+func withMockGraphQL(t *testing.T, respond func(req graphQLRequest) string) (Config, *[]string) {
+	t.Helper()
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/graphql" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer gho-test" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		queries = append(queries, req.Variables.Q)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, respond(req))
+	}))
+	t.Cleanup(srv.Close)
+	return Config{GitHubToken: "gho-test", GitHubOrg: "acme", GitHubBaseURL: srv.URL}, &queries
+}
+
+func searchPage(issueCount int, endCursor string, nodes ...string) string {
+	return fmt.Sprintf(`{"data":{"search":{"issueCount":%d,"pageInfo":{"hasNextPage":%t,"endCursor":%q},"nodes":[%s]}}}`,
+		issueCount, endCursor != "", endCursor, strings.Join(nodes, ","))
+}
+
+func prNode(url, state, mergedAt, updatedAt string) string {
+	return fmt.Sprintf(`{"url":%q,"title":"PR %s","state":%q,"createdAt":"2026-03-01T09:00:00Z","updatedAt":%q,"closedAt":%q,"mergedAt":%q,
+		"author":{"login":"asmith","name":"Alice Smith"},"repository":{"nameWithOwner":"acme/api"},"labels":{"nodes":[]},
+		"closingIssuesReferences":{"nodes":[]}}`,
+		url, url, state, updatedAt, mergedAt, mergedAt)
+}
+
+func TestConvertGraphQLPR(t *testing.T) {
+	var node graphQLPR
+	raw := `{"url":"https://github.com/acme/api/pull/7","title":"Add retries","state":"MERGED",
+		"createdAt":"2026-03-02T09:00:00Z","updatedAt":"2026-03-03T11:00:00Z","closedAt":"2026-03-03T10:00:00Z","mergedAt":"2026-03-03T10:00:00Z",
+		"author":{"login":"asmith","name":"Alice Smith","email":"alice@example.com"},"repository":{"nameWithOwner":"acme/api"},
+		"labels":{"nodes":[{"name":"backend"}]},
+		"closingIssuesReferences":{"nodes":[{"number":12,"repository":{"nameWithOwner":"acme/api"}}]}}`
+	if err := json.Unmarshal([]byte(raw), &node); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	pr := convertGraphQLPR(node)
+	if pr.State != "merged" || pr.Author != "asmith" || pr.AuthorName != "Alice Smith" || pr.AuthorEmail != "alice@example.com" || pr.RepoFullName != "acme/api" {
+		t.Fatalf("unexpected PR: %+v", pr)
+	}
+	if !pr.MergedAt.Equal(time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("MergedAt = %v", pr.MergedAt)
+	}
+	if strings.Join(pr.LinkedIssues, ",") != "acme/api#12" || strings.Join(pr.Labels, ",") != "backend" {
+		t.Errorf("LinkedIssues = %v, Labels = %v", pr.LinkedIssues, pr.Labels)
+	}
+
+	// Bots have no profile name; fall back to the login.
+	node.Author.Name = ""
+	if got := convertGraphQLPR(node).AuthorName; got != "asmith" {
+		t.Errorf("AuthorName without a profile name = %q", got)
+	}
+}
+
+func TestFetchGitHubPRsGraphQLPaginates(t *testing.T) {
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	cfg, queries := withMockGraphQL(t, func(req graphQLRequest) string {
+		switch {
+		case strings.Contains(req.Variables.Q, "is:merged") && req.Variables.After == "":
+			return searchPage(2, "page2", prNode("https://github.com/acme/api/pull/1", "MERGED", "2026-03-03T10:00:00Z", "2026-03-03T10:00:00Z"))
+		case strings.Contains(req.Variables.Q, "is:merged"):
+			// Merged after the range: search matched on an earlier
+			// timestamp, the in-range check drops it.
+			return searchPage(2, "", prNode("https://github.com/acme/api/pull/2", "MERGED", "2026-03-10T10:00:00Z", "2026-03-10T10:00:00Z"))
+		default:
+			return searchPage(1, "", prNode("https://github.com/acme/api/pull/3", "OPEN", "", "2026-03-04T08:00:00Z"))
+		}
+	})
+
+	prs, err := FetchGitHubPRs(cfg, from, to)
+	if err != nil {
+		t.Fatalf("FetchGitHubPRs: %v", err)
+	}
+	if len(prs) != 2 || prs[0].HTMLURL != "https://github.com/acme/api/pull/1" || prs[1].State != "open" {
+		t.Fatalf("unexpected PRs: %+v", prs)
+	}
+	wantMerged := "type:pr is:merged merged:>=2026-03-02T00:00:00Z merged:<2026-03-09T00:00:00Z org:acme"
+	if len(*queries) != 3 || (*queries)[0] != wantMerged || (*queries)[1] != wantMerged {
+		t.Fatalf("unexpected queries: %q", *queries)
+	}
+}
+
+func TestSearchPRsGraphQLSplitsRangeAtResultCap(t *testing.T) {
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	full := rangeQuery("type:pr is:merged", "merged", from, to, "")
+	cfg, queries := withMockGraphQL(t, func(req graphQLRequest) string {
+		if req.Variables.Q == full {
+			return searchPage(searchResultCap+1, "")
+		}
+		// Each half returns one PR merged at its start.
+		start := strings.TrimPrefix(strings.Fields(req.Variables.Q)[2], "merged:>=")
+		return searchPage(1, "", prNode("https://github.com/acme/api/pull/"+start, "MERGED", start, start))
+	})
+
+	prs, err := searchPRsGraphQL(cfg, func(from, to time.Time) string {
+		return rangeQuery("type:pr is:merged", "merged", from, to, "")
+	}, from, to)
+	if err != nil {
+		t.Fatalf("searchPRsGraphQL: %v", err)
+	}
+	if len(*queries) != 3 {
+		t.Fatalf("expected the full range and two halves, got %q", *queries)
+	}
+	if len(prs) != 2 || !prs[1].MergedAt.Equal(from.Add(12*time.Hour)) {
+		t.Fatalf("unexpected PRs: %+v", prs)
+	}
+}
+
+func TestGraphQLSearchPageReturnsAPIErrors(t *testing.T) {
+	cfg, _ := withMockGraphQL(t, func(graphQLRequest) string {
+		return `{"data":null,"errors":[{"message":"Bad credentials"}]}`
+	})
+	_, err := graphQLSearchPage(cfg, "type:pr", "")
+	if err == nil || !strings.Contains(err.Error(), "Bad credentials") {
+		t.Fatalf("expected the GraphQL error, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PullRequestEvent is the X-GitHub-Event header of pull_request events.
+const PullRequestEvent = "pull_request"
+
+type pullRequestEventPayload struct {
+	Action      string `json:"action"`
+	PullRequest *struct {
+		Title     string        `json:"title"`
+		HTMLURL   string        `json:"html_url"`
+		State     string        `json:"state"` // "open" or "closed"
+		Merged    bool          `json:"merged"`
+		CreatedAt string        `json:"created_at"`
+		UpdatedAt string        `json:"updated_at"`
+		ClosedAt  string        `json:"closed_at"`
+		MergedAt  string        `json:"merged_at"`
+		User      githubUser    `json:"user"`
+		Labels    []githubLabel `json:"labels"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// VerifyWebhookSignature reports whether signature, the X-Hub-Signature-256
+// header, is the HMAC-SHA256 of body under secret.
+func VerifyWebhookSignature(body []byte, signature, secret string) bool {
+	if secret == "" {
+		return false
+	}
+	sig, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// ParsePullRequestEvent parses a pull_request event body. The PR's state is
+// derived as the fetcher does: "merged", "closed" (without merging) or
+// "open".
+func ParsePullRequestEvent(body []byte) (GitHubPR, string, error) {
+	var p pullRequestEventPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return GitHubPR{}, "", fmt.Errorf("parsing pull_request event: %w", err)
+	}
+	if p.PullRequest == nil || p.PullRequest.HTMLURL == "" {
+		return GitHubPR{}, "", fmt.Errorf("not a pull_request event")
+	}
+	raw := p.PullRequest
+
+	state := "open"
+	if raw.Merged {
+		state = "merged"
+	} else if raw.State == "closed" {
+		state = "closed"
+	}
+	createdAt, _ := time.Parse(time.RFC3339, raw.CreatedAt)
+	updatedAt, _ := time.Parse(time.RFC3339, raw.UpdatedAt)
+	closedAt, _ := time.Parse(time.RFC3339, raw.ClosedAt)
+	mergedAt, _ := time.Parse(time.RFC3339, raw.MergedAt)
+
+	var labels []string
+	for _, l := range raw.Labels {
+		labels = append(labels, l.Name)
+	}
+
+	return GitHubPR{
+		Title:        raw.Title,
+		Author:       raw.User.Login,
+		AuthorName:   raw.User.Login, // match the fetcher, which only has the login
+		HTMLURL:      raw.HTMLURL,
+		MergedAt:     mergedAt,
+		UpdatedAt:    updatedAt,
+		CreatedAt:    createdAt,
+		ClosedAt:     closedAt,
+		State:        state,
+		Labels:       labels,
+		RepoFullName: p.Repository.FullName,
+	}, p.Action, nil
+}
+
+// RepoInScope reports whether repo ("owner/name") is one the fetcher
+// searches: one of github_repos or, without them, a repo of github_org.
+func RepoInScope(cfg Config, repo string) bool {
+	if len(cfg.GitHubRepos) > 0 {
+		for _, r := range cfg.GitHubRepos {
+			if strings.EqualFold(strings.TrimSpace(r), repo) {
+				return true
+			}
+		}
+		return false
+	}
+	if cfg.GitHubOrg != "" {
+		owner, _, _ := strings.Cut(repo, "/")
+		return strings.EqualFold(owner, cfg.GitHubOrg)
+	}
+	return true
+}
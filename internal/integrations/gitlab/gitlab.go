@@ -19,6 +19,7 @@ type gitlabMRResponse struct {
 	MergedAt    string `json:"merged_at"`
 	UpdatedAt   string `json:"updated_at"`
 	CreatedAt   string `json:"created_at"`
+	ClosedAt    string `json:"closed_at"`
 	State       string `json:"state"`
 	Author      struct {
 		Username string `json:"username"`
@@ -33,18 +34,72 @@ type gitlabMRResponse struct {
 var markdownHeadingRe = regexp.MustCompile(`^\s*#{1,6}\s+\S`)
 
 func FetchMRs(cfg Config, from, to time.Time) ([]GitLabMR, error) {
+	log.Printf("gitlab fetch start group=%s since=%s", cfg.GitLabGroupID, from.Format("2006-01-02T15:04:05Z"))
+	mrs, err := listGroupMRs(cfg, "all", from)
+	if err != nil {
+		return nil, err
+	}
+
+	var allMRs []GitLabMR
+	for _, mr := range mrs {
+		switch mr.State {
+		case "merged":
+			if mr.MergedAt.Before(from) || !mr.MergedAt.Before(to) {
+				continue
+			}
+		case "opened":
+			// For open MRs, include ones updated during the week window.
+			if mr.UpdatedAt.IsZero() || mr.UpdatedAt.Before(from) || !mr.UpdatedAt.Before(to) {
+				continue
+			}
+		default:
+			continue
+		}
+		allMRs = append(allMRs, mr)
+	}
+
+	log.Printf("gitlab fetch done total=%d", len(allMRs))
+	return allMRs, nil
+}
+
+// FetchClosedMRs returns the MRs closed without merging in [from, to). Fetch
+// uses them to flag items that were imported while the MR was open.
+func FetchClosedMRs(cfg Config, from, to time.Time) ([]GitLabMR, error) {
+	mrs, err := listGroupMRs(cfg, "closed", from)
+	if err != nil {
+		return nil, err
+	}
+
+	var closed []GitLabMR
+	for _, mr := range mrs {
+		closedAt := mr.ClosedAt
+		if closedAt.IsZero() {
+			closedAt = mr.UpdatedAt
+		}
+		if mr.State != "closed" || closedAt.IsZero() || closedAt.Before(from) || !closedAt.Before(to) {
+			continue
+		}
+		closed = append(closed, mr)
+	}
+
+	log.Printf("gitlab fetch closed total=%d", len(closed))
+	return closed, nil
+}
+
+// listGroupMRs pages through the group's MRs in state (all, opened, closed
+// or merged) updated since from.
+func listGroupMRs(cfg Config, state string, from time.Time) ([]GitLabMR, error) {
 	since := from.Format("2006-01-02T15:04:05Z")
 	groupID := url.PathEscape(cfg.GitLabGroupID)
 	ticketFieldLabel := strings.TrimSpace(cfg.GitLabRefTicketLabel)
 
 	var allMRs []GitLabMR
 	page := 1
-	log.Printf("gitlab fetch start group=%s since=%s", cfg.GitLabGroupID, since)
 
 	for {
-		apiURL := fmt.Sprintf("%s/api/v4/groups/%s/merge_requests?state=all&updated_after=%s&per_page=100&page=%d",
-			strings.TrimRight(cfg.GitLabURL, "/"), groupID, since, page)
-		log.Printf("gitlab fetch page=%d", page)
+		apiURL := fmt.Sprintf("%s/api/v4/groups/%s/merge_requests?state=%s&updated_after=%s&per_page=100&page=%d",
+			strings.TrimRight(cfg.GitLabURL, "/"), groupID, state, since, page)
+		log.Printf("gitlab fetch state=%s page=%d", state, page)
 
 		req, err := http.NewRequest("GET", apiURL, nil)
 		if err != nil {
@@ -73,51 +128,7 @@ func FetchMRs(cfg Config, from, to time.Time) ([]GitLabMR, error) {
 		}
 
 		for _, mr := range mrs {
-			mergedAt, err := time.Parse(time.RFC3339, mr.MergedAt)
-			if err != nil {
-				mergedAt = time.Time{}
-			}
-
-			updatedAt, err := time.Parse(time.RFC3339, mr.UpdatedAt)
-			if err != nil {
-				updatedAt = time.Time{}
-			}
-
-			createdAt, err := time.Parse(time.RFC3339, mr.CreatedAt)
-			if err != nil {
-				createdAt = time.Time{}
-			}
-
-			state := strings.ToLower(strings.TrimSpace(mr.State))
-			switch state {
-			case "merged":
-				if mergedAt.Before(from) || !mergedAt.Before(to) {
-					continue
-				}
-			case "opened":
-				// For open MRs, include ones updated during the week window.
-				if updatedAt.IsZero() || updatedAt.Before(from) || !updatedAt.Before(to) {
-					continue
-				}
-			default:
-				continue
-			}
-
-			projectPath := extractProjectPath(mr.WebURL)
-
-			allMRs = append(allMRs, GitLabMR{
-				Title:       mr.Title,
-				Author:      mr.Author.Username,
-				AuthorName:  mr.Author.Name,
-				WebURL:      mr.WebURL,
-				TicketIDs:   parseTicketIDsFromDescription(mr.Description, ticketFieldLabel),
-				MergedAt:    mergedAt,
-				UpdatedAt:   updatedAt,
-				CreatedAt:   createdAt,
-				State:       state,
-				Labels:      mr.Labels,
-				ProjectPath: projectPath,
-			})
+			allMRs = append(allMRs, convertGitLabMR(mr, ticketFieldLabel))
 		}
 
 		if len(mrs) < 100 {
@@ -125,11 +136,32 @@ func FetchMRs(cfg Config, from, to time.Time) ([]GitLabMR, error) {
 		}
 		page++
 	}
-
-	log.Printf("gitlab fetch done total=%d", len(allMRs))
 	return allMRs, nil
 }
 
+func convertGitLabMR(mr gitlabMRResponse, ticketFieldLabel string) GitLabMR {
+	// Unparseable or missing timestamps stay zero.
+	mergedAt, _ := time.Parse(time.RFC3339, mr.MergedAt)
+	updatedAt, _ := time.Parse(time.RFC3339, mr.UpdatedAt)
+	createdAt, _ := time.Parse(time.RFC3339, mr.CreatedAt)
+	closedAt, _ := time.Parse(time.RFC3339, mr.ClosedAt)
+
+	return GitLabMR{
+		Title:       mr.Title,
+		Author:      mr.Author.Username,
+		AuthorName:  mr.Author.Name,
+		WebURL:      mr.WebURL,
+		TicketIDs:   parseTicketIDsFromDescription(mr.Description, ticketFieldLabel),
+		MergedAt:    mergedAt,
+		UpdatedAt:   updatedAt,
+		CreatedAt:   createdAt,
+		ClosedAt:    closedAt,
+		State:       strings.ToLower(strings.TrimSpace(mr.State)),
+		Labels:      mr.Labels,
+		ProjectPath: extractProjectPath(mr.WebURL),
+	}
+}
+
 func parseTicketIDsFromDescription(description, fieldLabel string) string {
 	description = strings.TrimSpace(description)
 	fieldLabel = strings.TrimSpace(fieldLabel)
@@ -261,3 +261,51 @@ Improve beacon pipeline cache key stability
 		t.Fatalf("unexpected ticket IDs: %q", mrs[0].TicketIDs)
 	}
 }
+
+func TestFetchClosedMRsFiltersByCloseTime(t *testing.T) {
+	from := time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	createdAt := from.Add(-72 * time.Hour).Format(time.RFC3339)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("state"); got != "closed" {
+			t.Fatalf("unexpected state query: %q", got)
+		}
+		payload := []map[string]any{
+			{
+				"title":      "Closed in range",
+				"web_url":    "https://gitlab.example.com/group/proj/-/merge_requests/10",
+				"updated_at": to.Add(time.Hour).Format(time.RFC3339),
+				"closed_at":  from.Add(24 * time.Hour).Format(time.RFC3339),
+				"created_at": createdAt,
+				"state":      "closed",
+				"author":     map[string]any{"username": "alice", "name": "Alice"},
+			},
+			{
+				"title":      "Closed before range",
+				"web_url":    "https://gitlab.example.com/group/proj/-/merge_requests/11",
+				"updated_at": from.Add(time.Hour).Format(time.RFC3339),
+				"closed_at":  from.Add(-time.Hour).Format(time.RFC3339),
+				"created_at": createdAt,
+				"state":      "closed",
+				"author":     map[string]any{"username": "bob", "name": "Bob"},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(payload)
+	}))
+	defer server.Close()
+
+	cfg := Config{
+		GitLabURL:     server.URL,
+		GitLabToken:   "glpat-test",
+		GitLabGroupID: "my-group",
+	}
+	mrs, err := FetchClosedMRs(cfg, from, to)
+	if err != nil {
+		t.Fatalf("FetchClosedMRs failed: %v", err)
+	}
+	if len(mrs) != 1 || mrs[0].Title != "Closed in range" || mrs[0].State != "closed" || mrs[0].ClosedAt.IsZero() {
+		t.Fatalf("unexpected closed MRs: %+v", mrs)
+	}
+}
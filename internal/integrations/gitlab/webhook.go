@@ -0,0 +1,150 @@
+package gitlab
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MergeRequestHookEvent is the X-Gitlab-Event header of merge request hooks.
+const MergeRequestHookEvent = "Merge Request Hook"
+
+// MergeRequestHook is a parsed merge request webhook. The payload names the
+// user who triggered the event, not the MR author, so MR.Author and
+// MR.AuthorName are only set when the two are the same user; otherwise
+// AuthorID identifies the author for FetchUser. MR.AuthorEmail is that
+// user's email or, as GitLab usually redacts it, the email of the last
+// commit's author.
+type MergeRequestHook struct {
+	MR       GitLabMR
+	AuthorID int64
+	Action   string
+}
+
+type mergeRequestHookPayload struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		ID       int64  `json:"id"`
+		Name     string `json:"name"`
+		Username string `json:"username"`
+		Email    string `json:"email"`
+	} `json:"user"`
+	ObjectAttributes struct {
+		AuthorID    int64  `json:"author_id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		URL         string `json:"url"`
+		State       string `json:"state"`
+		Action      string `json:"action"`
+		CreatedAt   string `json:"created_at"`
+		UpdatedAt   string `json:"updated_at"`
+		MergedAt    string `json:"merged_at"`
+		ClosedAt    string `json:"closed_at"`
+		LastCommit  struct {
+			Author struct {
+				Email string `json:"email"`
+			} `json:"author"`
+		} `json:"last_commit"`
+	} `json:"object_attributes"`
+	Labels []struct {
+		Title string `json:"title"`
+	} `json:"labels"`
+}
+
+// VerifyWebhookToken reports whether token, the X-Gitlab-Token header, is
+// the configured secret.
+func VerifyWebhookToken(token, secret string) bool {
+	return secret != "" && subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+}
+
+// ParseMergeRequestHook parses a merge request hook body. Merged and closed
+// MRs without merged_at or closed_at take the time from updated_at, which
+// the merge or close sets.
+func ParseMergeRequestHook(body []byte, ticketFieldLabel string) (MergeRequestHook, error) {
+	var p mergeRequestHookPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return MergeRequestHook{}, fmt.Errorf("parsing merge request hook: %w", err)
+	}
+	attrs := p.ObjectAttributes
+	if p.ObjectKind != "merge_request" || attrs.URL == "" {
+		return MergeRequestHook{}, fmt.Errorf("not a merge request hook (object_kind=%q)", p.ObjectKind)
+	}
+
+	mr := GitLabMR{
+		Title:       attrs.Title,
+		WebURL:      attrs.URL,
+		TicketIDs:   parseTicketIDsFromDescription(attrs.Description, strings.TrimSpace(ticketFieldLabel)),
+		CreatedAt:   parseHookTime(attrs.CreatedAt),
+		UpdatedAt:   parseHookTime(attrs.UpdatedAt),
+		MergedAt:    parseHookTime(attrs.MergedAt),
+		ClosedAt:    parseHookTime(attrs.ClosedAt),
+		State:       strings.ToLower(strings.TrimSpace(attrs.State)),
+		ProjectPath: extractProjectPath(attrs.URL),
+	}
+	if mr.State == "merged" && mr.MergedAt.IsZero() {
+		mr.MergedAt = mr.UpdatedAt
+	}
+	if mr.State == "closed" && mr.ClosedAt.IsZero() {
+		mr.ClosedAt = mr.UpdatedAt
+	}
+	for _, l := range p.Labels {
+		mr.Labels = append(mr.Labels, l.Title)
+	}
+	if p.User.ID != 0 && p.User.ID == attrs.AuthorID {
+		mr.Author = p.User.Username
+		mr.AuthorName = p.User.Name
+		if strings.Contains(p.User.Email, "@") {
+			mr.AuthorEmail = p.User.Email
+		}
+	}
+	if mr.AuthorEmail == "" {
+		mr.AuthorEmail = strings.TrimSpace(attrs.LastCommit.Author.Email)
+	}
+	return MergeRequestHook{MR: mr, AuthorID: attrs.AuthorID, Action: attrs.Action}, nil
+}
+
+// parseHookTime accepts both timestamp formats GitLab has used in webhooks.
+func parseHookTime(s string) time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05 MST", "2006-01-02 15:04:05 -0700"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// FetchUser returns the username and display name of a GitLab user.
+func FetchUser(cfg Config, id int64) (string, string, error) {
+	apiURL := fmt.Sprintf("%s/api/v4/users/%d", strings.TrimRight(cfg.GitLabURL, "/"), id)
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", cfg.GitLabToken)
+
+	resp, err := externalHTTPClient.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("fetching user: %w", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return "", "", fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return "", "", fmt.Errorf("GitLab API returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var user struct {
+		Username string `json:"username"`
+		Name     string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &user); err != nil {
+		return "", "", fmt.Errorf("parsing response: %w", err)
+	}
+	return user.Username, user.Name, nil
+}
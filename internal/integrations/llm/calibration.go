@@ -0,0 +1,130 @@
+package llm
+
+import "strings"
+
+// Confidence calibration maps the model's raw confidence onto the observed
+// rate of decisions that managers did not correct, so llm_confidence_threshold
+// means "at least this share of such decisions were right".
+const (
+	calibrationBins        = 10
+	calibrationMinSamples  = 30
+	calibrationPriorWeight = 5.0
+)
+
+type ConfidenceCalibration struct {
+	centers   []float64
+	values    []float64
+	Samples   int
+	Corrected int
+}
+
+// BuildConfidenceCalibration bins samples by raw confidence and estimates
+// per-bin accuracy, shrunk toward the raw value when a bin has few samples
+// and forced monotone. Returns nil when there is too little history.
+func BuildConfidenceCalibration(samples []ConfidenceSample) *ConfidenceCalibration {
+	var counts, correct [calibrationBins]float64
+	total, corrected := 0, 0
+	for _, s := range samples {
+		if s.RawConfidence <= 0 || s.RawConfidence > 1 {
+			continue
+		}
+		bin := int(s.RawConfidence * calibrationBins)
+		if bin >= calibrationBins {
+			bin = calibrationBins - 1
+		}
+		counts[bin]++
+		total++
+		if s.Corrected {
+			corrected++
+		} else {
+			correct[bin]++
+		}
+	}
+	if total < calibrationMinSamples || corrected == 0 {
+		return nil
+	}
+
+	c := &ConfidenceCalibration{Samples: total, Corrected: corrected}
+	weights := make([]float64, calibrationBins)
+	for i := 0; i < calibrationBins; i++ {
+		center := (float64(i) + 0.5) / calibrationBins
+		c.centers = append(c.centers, center)
+		c.values = append(c.values, (correct[i]+calibrationPriorWeight*center)/(counts[i]+calibrationPriorWeight))
+		weights[i] = counts[i] + calibrationPriorWeight
+	}
+	poolAdjacentViolators(c.values, weights)
+	return c
+}
+
+// poolAdjacentViolators makes values non-decreasing in place by merging
+// neighbouring blocks that violate the order into their weighted mean.
+func poolAdjacentViolators(values, weights []float64) {
+	type block struct {
+		sum, weight float64
+		n           int
+	}
+	var blocks []block
+	for i := range values {
+		blocks = append(blocks, block{sum: values[i] * weights[i], weight: weights[i], n: 1})
+		for len(blocks) > 1 {
+			last, prev := blocks[len(blocks)-1], blocks[len(blocks)-2]
+			if prev.sum/prev.weight <= last.sum/last.weight {
+				break
+			}
+			blocks = blocks[:len(blocks)-2]
+			blocks = append(blocks, block{sum: prev.sum + last.sum, weight: prev.weight + last.weight, n: prev.n + last.n})
+		}
+	}
+	i := 0
+	for _, b := range blocks {
+		mean := b.sum / b.weight
+		for j := 0; j < b.n; j++ {
+			values[i] = mean
+			i++
+		}
+	}
+}
+
+// Calibrate maps a raw confidence to a calibrated one by interpolating
+// between bin centers. A nil calibration returns raw unchanged.
+func (c *ConfidenceCalibration) Calibrate(raw float64) float64 {
+	if c == nil || len(c.centers) == 0 {
+		return raw
+	}
+	if raw <= c.centers[0] {
+		return c.values[0]
+	}
+	last := len(c.centers) - 1
+	if raw >= c.centers[last] {
+		return c.values[last]
+	}
+	for i := 1; i <= last; i++ {
+		if raw <= c.centers[i] {
+			frac := (raw - c.centers[i-1]) / (c.centers[i] - c.centers[i-1])
+			return c.values[i-1] + frac*(c.values[i]-c.values[i-1])
+		}
+	}
+	return c.values[last]
+}
+
+// CalibrateDecisions replaces Confidence with the calibrated value for
+// decisions whose confidence came from the model. Glossary, heuristic and
+// Undetermined decisions keep their fixed values. Returns how many changed.
+func CalibrateDecisions(decisions map[int64]LLMSectionDecision, c *ConfidenceCalibration) int {
+	if c == nil {
+		return 0
+	}
+	changed := 0
+	for id, d := range decisions {
+		if d.RawConfidence <= 0 || strings.TrimSpace(d.SectionID) == "UND" {
+			continue
+		}
+		if d.ConfidenceSource != confidenceSourceModel && d.ConfidenceSource != confidenceSourceLogprob {
+			continue
+		}
+		d.Confidence = c.Calibrate(d.RawConfidence)
+		decisions[id] = d
+		changed++
+	}
+	return changed
+}
@@ -0,0 +1,70 @@
+package llm
+
+import "testing"
+
+func calibrationSamples(raw float64, total, corrected int) []ConfidenceSample {
+	var out []ConfidenceSample
+	for i := 0; i < total; i++ {
+		out = append(out, ConfidenceSample{RawConfidence: raw, Corrected: i < corrected})
+	}
+	return out
+}
+
+func TestBuildConfidenceCalibration_NeedsEnoughHistory(t *testing.T) {
+	if c := BuildConfidenceCalibration(calibrationSamples(0.9, 10, 2)); c != nil {
+		t.Fatalf("expected nil calibration for too few samples")
+	}
+	if c := BuildConfidenceCalibration(calibrationSamples(0.9, 50, 0)); c != nil {
+		t.Fatalf("expected nil calibration without any corrections")
+	}
+	var nilCal *ConfidenceCalibration
+	if got := nilCal.Calibrate(0.42); got != 0.42 {
+		t.Fatalf("nil calibration should be identity, got %v", got)
+	}
+}
+
+func TestBuildConfidenceCalibration_PullsOverconfidentScoresDown(t *testing.T) {
+	// The model says 0.95 but managers corrected 45% of those decisions,
+	// and 80% of its 0.45 decisions.
+	samples := append(calibrationSamples(0.95, 100, 45), calibrationSamples(0.45, 100, 80)...)
+	c := BuildConfidenceCalibration(samples)
+	if c == nil {
+		t.Fatal("expected calibration")
+	}
+	if c.Samples != 200 || c.Corrected != 125 {
+		t.Fatalf("unexpected sample counts: %d/%d", c.Samples, c.Corrected)
+	}
+	high := c.Calibrate(0.95)
+	if high > 0.70 {
+		t.Fatalf("expected overconfident 0.95 to calibrate below threshold, got %.2f", high)
+	}
+	// Monotone: a higher raw score never calibrates lower.
+	prev := 0.0
+	for raw := 0.05; raw <= 1.0; raw += 0.05 {
+		got := c.Calibrate(raw)
+		if got+1e-9 < prev {
+			t.Fatalf("calibration not monotone at %.2f: %.3f < %.3f", raw, got, prev)
+		}
+		prev = got
+	}
+}
+
+func TestCalibrateDecisions_OnlyModelSourced(t *testing.T) {
+	samples := append(calibrationSamples(0.95, 100, 60), calibrationSamples(0.35, 40, 30)...)
+	c := BuildConfidenceCalibration(samples)
+	decisions := map[int64]LLMSectionDecision{
+		1: {SectionID: "S0_0", Confidence: 0.95, RawConfidence: 0.95, ConfidenceSource: confidenceSourceModel},
+		2: {SectionID: "S0_0", Confidence: 0.99, ConfidenceSource: confidenceSourceGlossary},
+		3: {SectionID: "S0_0", Confidence: 0.90, ConfidenceSource: confidenceSourceHeuristic},
+		4: {SectionID: "UND", Confidence: 0.30, RawConfidence: 0.30, ConfidenceSource: confidenceSourceModel},
+	}
+	if n := CalibrateDecisions(decisions, c); n != 1 {
+		t.Fatalf("expected one calibrated decision, got %d", n)
+	}
+	if decisions[1].Confidence >= 0.95 {
+		t.Fatalf("expected model confidence to be calibrated down, got %v", decisions[1].Confidence)
+	}
+	if decisions[2].Confidence != 0.99 || decisions[3].Confidence != 0.90 || decisions[4].Confidence != 0.30 {
+		t.Fatalf("non-model decisions should be untouched: %+v", decisions)
+	}
+}
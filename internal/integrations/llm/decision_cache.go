@@ -0,0 +1,119 @@
+package llm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reportbot/internal/domain"
+	"strings"
+)
+
+type ClassificationRecord = domain.ClassificationRecord
+
+// DecisionCache looks up earlier classification decisions by cache key so
+// unchanged items are not re-sent to the LLM. Implementations return the
+// most recent uncorrected decision for each key they know.
+type DecisionCache interface {
+	LookupClassifications(keys []string) (map[string]ClassificationRecord, error)
+}
+
+var decisionCaches teamStores[DecisionCache]
+
+// SetDecisionCache installs the cache consulted before classifying teamID's
+// items. Without one, every item is classified by the LLM.
+func SetDecisionCache(teamID string, cache DecisionCache) {
+	decisionCaches.set(teamID, cache)
+}
+
+// classificationFingerprint hashes everything besides the item itself that
+// shapes a decision: provider and model, the template section set, the
+// classification guide and the glossary. Changing any of them invalidates
+// every cached decision.
+func classificationFingerprint(provider Provider, options []sectionOption, guidance string, glossary *LLMGlossary) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "provider=%s\nmodel=%s\n", provider.Name(), provider.Model())
+	for _, opt := range options {
+		fmt.Fprintf(h, "section=%s|%s\n", opt.ID, opt.Label)
+	}
+	fmt.Fprintf(h, "guide=%s\n", guidance)
+	if glossary != nil {
+		data, _ := json.Marshal(glossary)
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// decisionCacheKey combines the fingerprint with the item's normalized
+// description and status. Whitespace and case differences do not matter.
+func decisionCacheKey(fingerprint string, item WorkItem) string {
+	description := strings.ToLower(strings.Join(strings.Fields(item.Description), " "))
+	sum := sha256.Sum256([]byte(fingerprint + "\n" + normalizeStatus(item.Status) + "\n" + description))
+	return hex.EncodeToString(sum[:])
+}
+
+// decisionFromRecord rebuilds a decision from classification history. The
+// stored confidence was already calibrated when it was first recorded.
+// DuplicateOf is left empty: it names a K<n> position in the existing items
+// of the run that produced it, which the fingerprint does not cover.
+func decisionFromRecord(rec ClassificationRecord) LLMSectionDecision {
+	var alternatives []string
+	for _, alt := range strings.Split(rec.AlternativeSectionIDs, ",") {
+		if alt = strings.TrimSpace(alt); alt != "" {
+			alternatives = append(alternatives, alt)
+		}
+	}
+	return LLMSectionDecision{
+		SectionID:        rec.SectionID,
+		NormalizedStatus: rec.NormalizedStatus,
+		TicketIDs:        rec.TicketIDs,
+		Confidence:       rec.Confidence,
+		RawConfidence:    rec.RawConfidence,
+		ConfidenceSource: confidenceSourceCache,
+		Alternatives:     alternatives,
+		CacheKey:         rec.CacheKey,
+		CachedFrom:       rec.WorkItemID,
+	}
+}
+
+// lookupCachedDecisions splits items into cache hits, returned as decisions,
+// and misses that still need the LLM. keys maps every item ID to its key.
+func lookupCachedDecisions(teamID string, items []WorkItem, fingerprint string, options []sectionOption) (hits map[int64]LLMSectionDecision, misses []WorkItem, keys map[int64]string) {
+	keys = make(map[int64]string, len(items))
+	var keyList []string
+	for _, item := range items {
+		key := decisionCacheKey(fingerprint, item)
+		keys[item.ID] = key
+		keyList = append(keyList, key)
+	}
+	hits = make(map[int64]LLMSectionDecision)
+
+	cache := decisionCaches.get(teamID)
+	if cache == nil {
+		return hits, items, keys
+	}
+	records, err := cache.LookupClassifications(keyList)
+	if err != nil {
+		log.Printf("llm decision cache lookup error (non-fatal): %v", err)
+		return hits, items, keys
+	}
+
+	validSections := make(map[string]bool, len(options)+1)
+	validSections["UND"] = true
+	for _, opt := range options {
+		validSections[opt.ID] = true
+	}
+	for _, item := range items {
+		rec, ok := records[keys[item.ID]]
+		// A decision that merged the item into an existing one depends on
+		// that report's existing items, so it is re-classified against
+		// the current ones instead.
+		if !ok || !validSections[strings.TrimSpace(rec.SectionID)] || strings.TrimSpace(rec.DuplicateOf) != "" {
+			misses = append(misses, item)
+			continue
+		}
+		hits[item.ID] = decisionFromRecord(rec)
+	}
+	return hits, misses, keys
+}
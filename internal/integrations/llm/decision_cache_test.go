@@ -0,0 +1,162 @@
+package llm
+
+import (
+	"testing"
+)
+
+type memoryDecisionCache struct {
+	records map[string]ClassificationRecord
+}
+
+func (c *memoryDecisionCache) LookupClassifications(keys []string) (map[string]ClassificationRecord, error) {
+	out := make(map[string]ClassificationRecord)
+	for _, k := range keys {
+		if r, ok := c.records[k]; ok {
+			out[k] = r
+		}
+	}
+	return out, nil
+}
+
+func TestDecisionCacheKeyNormalizesDescription(t *testing.T) {
+	fp := classificationFingerprint(&scriptedProvider{}, []SectionOption{{ID: "S0_0", Label: "Infra"}}, "guide", nil)
+	a := decisionCacheKey(fp, WorkItem{Description: "Fix  DB timeout ", Status: "done"})
+	b := decisionCacheKey(fp, WorkItem{Description: "fix db timeout", Status: "Done"})
+	if a != b {
+		t.Fatal("expected whitespace/case-insensitive keys")
+	}
+	if a == decisionCacheKey(fp, WorkItem{Description: "fix db timeout", Status: "in progress"}) {
+		t.Fatal("expected status change to change the key")
+	}
+
+	otherSections := classificationFingerprint(&scriptedProvider{}, []SectionOption{{ID: "S0_0", Label: "Platform"}}, "guide", nil)
+	otherGuide := classificationFingerprint(&scriptedProvider{}, []SectionOption{{ID: "S0_0", Label: "Infra"}}, "guide v2", nil)
+	otherGlossary := classificationFingerprint(&scriptedProvider{}, []SectionOption{{ID: "S0_0", Label: "Infra"}}, "guide",
+		&LLMGlossary{Terms: []GlossaryTerm{{Phrase: "db", Section: "Infra"}}})
+	for name, other := range map[string]string{"sections": otherSections, "guide": otherGuide, "glossary": otherGlossary} {
+		if other == fp {
+			t.Fatalf("expected %s change to change the fingerprint", name)
+		}
+	}
+}
+
+func TestCategorizeReusesCachedDecisions(t *testing.T) {
+	provider := &scriptedProvider{steps: []func() (string, LLMUsage, error){
+		func() (string, LLMUsage, error) {
+			return `[{"id":2,"section_id":"S1_0","normalized_status":"done","ticket_ids":[],"duplicate_of":"","confidence":0.9,"alternative_section_ids":[]}]`,
+				LLMUsage{InputTokens: 100, OutputTokens: 10}, nil
+		},
+	}}
+	options := []SectionOption{{ID: "S0_0", Label: "Infra"}, {ID: "S1_0", Label: "Auth"}}
+	cfg := Config{LLMBatchSize: 10}
+	items := []WorkItem{
+		{ID: 1, Description: "Fix DB timeout", Status: "done"},
+		{ID: 2, Description: "Add SSO login", Status: "done"},
+	}
+
+	fp := classificationFingerprint(provider, options, "", nil)
+	cache := &memoryDecisionCache{records: map[string]ClassificationRecord{
+		decisionCacheKey(fp, items[0]): {WorkItemID: 1, SectionID: "S0_0", Confidence: 0.88, RawConfidence: 0.91, NormalizedStatus: "done", AlternativeSectionIDs: "S1_0"},
+	}}
+	SetDecisionCache("", cache)
+	t.Cleanup(func() { SetDecisionCache("", nil) })
+
+	decisions, usage, err := CategorizeItemsToSectionsWithProvider(provider, cfg, items, options, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CategorizeItemsToSectionsWithProvider: %v", err)
+	}
+	if provider.calls != 1 {
+		t.Fatalf("expected a single LLM call for the miss, got %d", provider.calls)
+	}
+	if usage.DecisionCacheHits != 1 || usage.DecisionCacheMisses != 1 {
+		t.Fatalf("unexpected cache counters: %+v", usage)
+	}
+	hit := decisions[1]
+	if hit.SectionID != "S0_0" || hit.Confidence != 0.88 || hit.CachedFrom != 1 || hit.ConfidenceSource != confidenceSourceCache {
+		t.Fatalf("unexpected cached decision: %+v", hit)
+	}
+	if len(hit.Alternatives) != 1 || hit.Alternatives[0] != "S1_0" {
+		t.Fatalf("expected alternatives restored, got %+v", hit.Alternatives)
+	}
+	miss := decisions[2]
+	if miss.SectionID != "S1_0" || miss.CachedFrom != 0 || miss.CacheKey != decisionCacheKey(fp, items[1]) {
+		t.Fatalf("unexpected fresh decision: %+v", miss)
+	}
+
+	// All hits: no LLM call at all.
+	cache.records[miss.CacheKey] = ClassificationRecord{WorkItemID: 2, SectionID: "S1_0", Confidence: 0.9}
+	_, usage, err = CategorizeItemsToSectionsWithProvider(provider, cfg, items, options, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CategorizeItemsToSectionsWithProvider (cached): %v", err)
+	}
+	if provider.calls != 1 || usage.DecisionCacheHits != 2 || usage.TotalTokens() != 0 {
+		t.Fatalf("expected fully cached run, calls=%d usage=%+v", provider.calls, usage)
+	}
+}
+
+func TestCachedDecisionForRemovedSectionIsAMiss(t *testing.T) {
+	provider := &scriptedProvider{}
+	options := []SectionOption{{ID: "S0_0", Label: "Infra"}}
+	item := WorkItem{ID: 1, Description: "Fix DB timeout", Status: "done"}
+	fp := classificationFingerprint(provider, options, "", nil)
+	SetDecisionCache("", &memoryDecisionCache{records: map[string]ClassificationRecord{
+		decisionCacheKey(fp, item): {WorkItemID: 1, SectionID: "S9_0"},
+	}})
+	t.Cleanup(func() { SetDecisionCache("", nil) })
+
+	hits, misses, _ := lookupCachedDecisions("", []WorkItem{item}, fp, options)
+	if len(hits) != 0 || len(misses) != 1 {
+		t.Fatalf("expected stale section to miss, hits=%v misses=%v", hits, misses)
+	}
+}
+
+func TestCachedDuplicateDecisionIsReclassified(t *testing.T) {
+	provider := &scriptedProvider{steps: []func() (string, LLMUsage, error){
+		func() (string, LLMUsage, error) {
+			return `[{"id":1,"section_id":"S0_0","normalized_status":"done","ticket_ids":[],"duplicate_of":"","confidence":0.9,"alternative_section_ids":[]}]`, LLMUsage{}, nil
+		},
+	}}
+	options := []SectionOption{{ID: "S0_0", Label: "Infra"}}
+	item := WorkItem{ID: 1, Description: "Fix DB timeout", Status: "done"}
+	fp := classificationFingerprint(provider, options, "", nil)
+	// K1 was the matching DB item when this was recorded; in this report
+	// K1 is an unrelated item.
+	SetDecisionCache("", &memoryDecisionCache{records: map[string]ClassificationRecord{
+		decisionCacheKey(fp, item): {WorkItemID: 1, SectionID: "S0_0", DuplicateOf: "K1", Confidence: 0.9},
+	}})
+	t.Cleanup(func() { SetDecisionCache("", nil) })
+	existing := []ExistingItemContext{{Key: "K1", SectionID: "S0_0", Description: "Rotate TLS certificates", Status: "done"}}
+
+	decisions, usage, err := CategorizeItemsToSectionsWithProvider(provider, Config{LLMBatchSize: 10}, []WorkItem{item}, options, existing, nil, nil)
+	if err != nil {
+		t.Fatalf("CategorizeItemsToSectionsWithProvider: %v", err)
+	}
+	if provider.calls != 1 || usage.DecisionCacheHits != 0 {
+		t.Fatalf("expected the cached duplicate to be re-classified, calls=%d usage=%+v", provider.calls, usage)
+	}
+	if got := decisions[1]; got.DuplicateOf != "" || got.CachedFrom != 0 {
+		t.Fatalf("expected a fresh decision without the stale duplicate key, got %+v", got)
+	}
+
+	if dec := decisionFromRecord(ClassificationRecord{SectionID: "S0_0", DuplicateOf: "K1"}); dec.DuplicateOf != "" {
+		t.Fatalf("decisionFromRecord should not carry DuplicateOf, got %q", dec.DuplicateOf)
+	}
+}
+
+func TestDecisionCacheIsPerTeam(t *testing.T) {
+	provider := &scriptedProvider{}
+	options := []SectionOption{{ID: "S0_0", Label: "Infra"}}
+	item := WorkItem{ID: 1, Description: "Fix DB timeout", Status: "done"}
+	fp := classificationFingerprint(provider, options, "", nil)
+	SetDecisionCache("platform", &memoryDecisionCache{records: map[string]ClassificationRecord{
+		decisionCacheKey(fp, item): {WorkItemID: 1, SectionID: "S0_0"},
+	}})
+	t.Cleanup(func() { SetDecisionCache("platform", nil) })
+
+	if hits, _, _ := lookupCachedDecisions("platform", []WorkItem{item}, fp, options); len(hits) != 1 {
+		t.Fatalf("expected a hit in the team's own cache, got %v", hits)
+	}
+	if hits, misses, _ := lookupCachedDecisions("mobile", []WorkItem{item}, fp, options); len(hits) != 0 || len(misses) != 1 {
+		t.Fatalf("expected another team's cache to be ignored, hits=%v misses=%v", hits, misses)
+	}
+}
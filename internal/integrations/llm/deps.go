@@ -9,6 +9,8 @@ import (
 type Config = config.Config
 type WorkItem = domain.WorkItem
 type ClassificationCorrection = domain.ClassificationCorrection
+type ConfidenceSample = domain.ConfidenceSample
+type RedactionRule = config.RedactionRule
 
 type SectionOption struct {
 	ID         string
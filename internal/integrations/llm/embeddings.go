@@ -0,0 +1,227 @@
+package llm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"reportbot/internal/domain"
+	"strings"
+)
+
+// --- OpenAI-compatible /embeddings API (OpenAI, Ollama /v1, vLLM, ...) ---
+
+type Embedding = domain.Embedding
+
+// EmbeddingStore caches work item embeddings between runs so only new or
+// edited descriptions are sent to the embeddings endpoint.
+type EmbeddingStore interface {
+	LoadEmbeddings(model string, workItemIDs []int64) (map[int64]Embedding, error)
+	SaveEmbeddings(embeddings []Embedding) error
+}
+
+var embeddingStores teamStores[EmbeddingStore]
+
+// SetEmbeddingStore installs the cache used for teamID's example embeddings.
+// Without a store, vectors are recomputed on every classification run.
+func SetEmbeddingStore(teamID string, store EmbeddingStore) {
+	embeddingStores.set(teamID, store)
+}
+
+const embeddingRequestBatchSize = 96
+
+type openAIEmbeddingsRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type openAIEmbeddingsResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Usage struct {
+		PromptTokens int64 `json:"prompt_tokens"`
+	} `json:"usage"`
+}
+
+// callEmbeddings returns one vector per text, in order.
+func callEmbeddings(cfg Config, texts []string) ([][]float32, LLMUsage, error) {
+	out := make([][]float32, 0, len(texts))
+	var usage LLMUsage
+	for start := 0; start < len(texts); start += embeddingRequestBatchSize {
+		end := start + embeddingRequestBatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+		vectors, batchUsage, err := doEmbeddingsRequest(cfg.EmbeddingBaseURL, cfg.EmbeddingAPIKey, cfg.EmbeddingModel, texts[start:end])
+		usage.Add(batchUsage)
+		if err != nil {
+			return nil, usage, err
+		}
+		out = append(out, vectors...)
+	}
+	return out, usage, nil
+}
+
+func doEmbeddingsRequest(baseURL, apiKey, model string, texts []string) ([][]float32, LLMUsage, error) {
+	bodyBytes, err := json.Marshal(openAIEmbeddingsRequest{Model: model, Input: texts})
+	if err != nil {
+		return nil, LLMUsage{}, fmt.Errorf("marshaling embeddings request: %w", err)
+	}
+	req, err := http.NewRequest("POST", strings.TrimRight(baseURL, "/")+"/embeddings", bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, LLMUsage{}, fmt.Errorf("creating embeddings request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := externalHTTPClient.Do(req)
+	if err != nil {
+		return nil, LLMUsage{}, fmt.Errorf("embeddings API error: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, LLMUsage{}, fmt.Errorf("reading embeddings body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, LLMUsage{}, newAPIStatusError("Embeddings API", resp, respBody)
+	}
+
+	var parsed openAIEmbeddingsResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, LLMUsage{}, fmt.Errorf("parsing embeddings payload: %w", err)
+	}
+	usage := LLMUsage{InputTokens: parsed.Usage.PromptTokens}
+	if len(parsed.Data) != len(texts) {
+		return nil, usage, fmt.Errorf("embeddings API returned %d vectors for %d inputs", len(parsed.Data), len(texts))
+	}
+	vectors := make([][]float32, len(texts))
+	for _, d := range parsed.Data {
+		if d.Index < 0 || d.Index >= len(texts) || len(d.Embedding) == 0 {
+			return nil, usage, fmt.Errorf("embeddings API returned invalid entry at index %d", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	return vectors, usage, nil
+}
+
+func embeddingTextHash(text string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(text)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// exampleVectors are the embeddings used for hybrid example ranking:
+// history is aligned with the historical items, items is keyed by work item
+// ID. Entries are nil when an item could not be embedded.
+type exampleVectors struct {
+	history [][]float32
+	items   map[int64][]float32
+}
+
+// loadExampleVectors embeds the batch items and historical examples, reusing
+// cached vectors whose description is unchanged. Text is redacted before it
+// is sent; the cache is keyed on the original description.
+func loadExampleVectors(cfg Config, items []WorkItem, historical []historicalItem, redact *redactor) (*exampleVectors, LLMUsage, error) {
+	texts := make(map[int64]string)
+	for _, h := range historical {
+		if h.WorkItemID > 0 {
+			texts[h.WorkItemID] = h.Description
+		}
+	}
+	for _, item := range items {
+		if item.ID > 0 {
+			texts[item.ID] = item.Description
+		}
+	}
+	ids := make([]int64, 0, len(texts))
+	for id := range texts {
+		ids = append(ids, id)
+	}
+
+	vectors := make(map[int64][]float32, len(ids))
+	store := embeddingStores.get(cfg.TeamID)
+	if store != nil {
+		cached, err := store.LoadEmbeddings(cfg.EmbeddingModel, ids)
+		if err != nil {
+			log.Printf("llm embeddings cache load error (non-fatal): %v", err)
+		}
+		for id, e := range cached {
+			if e.TextHash == embeddingTextHash(texts[id]) {
+				vectors[id] = e.Vector
+			}
+		}
+	}
+
+	var missingIDs []int64
+	var missingTexts []string
+	for _, id := range ids {
+		if _, ok := vectors[id]; !ok {
+			missingIDs = append(missingIDs, id)
+			missingTexts = append(missingTexts, texts[id])
+		}
+	}
+	sendTexts := make([]string, len(missingTexts))
+	for i, text := range missingTexts {
+		sendTexts[i] = redact.Redact(text)
+	}
+
+	var usage LLMUsage
+	if len(missingTexts) > 0 {
+		embedded, embedUsage, err := callEmbeddings(cfg, sendTexts)
+		usage = embedUsage
+		recordUsage(cfg, "embeddings", cfg.EmbeddingModel, usagePurposeEmbeddings, usage)
+		if err != nil {
+			return nil, usage, err
+		}
+		fresh := make([]Embedding, len(missingIDs))
+		for i, id := range missingIDs {
+			vectors[id] = embedded[i]
+			fresh[i] = Embedding{WorkItemID: id, Model: cfg.EmbeddingModel, TextHash: embeddingTextHash(missingTexts[i]), Vector: embedded[i]}
+		}
+		if store != nil {
+			if err := store.SaveEmbeddings(fresh); err != nil {
+				log.Printf("llm embeddings cache save error (non-fatal): %v", err)
+			}
+		}
+	}
+	log.Printf("llm embeddings model=%s items=%d cached=%d embedded=%d tokens=%d", cfg.EmbeddingModel, len(ids), len(ids)-len(missingIDs), len(missingIDs), usage.InputTokens)
+
+	out := &exampleVectors{
+		history: make([][]float32, len(historical)),
+		items:   make(map[int64][]float32, len(items)),
+	}
+	for i, h := range historical {
+		out.history[i] = vectors[h.WorkItemID]
+	}
+	for _, item := range items {
+		if v, ok := vectors[item.ID]; ok {
+			out.items[item.ID] = v
+		}
+	}
+	return out, usage, nil
+}
+
+func denseCosineSim(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,152 @@
+package llm
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeEmbeddingVectors maps known descriptions to fixed vectors; anything
+// else embeds to a vector orthogonal to all of them.
+var fakeEmbeddingVectors = map[string][]float32{
+	"Speed up checkout page":              {1, 0, 0},
+	"Reduce latency of payment flow":      {0.95, 0.05, 0},
+	"Fix checkout button colour":          {0, 1, 0},
+	"Rotate TLS certificates for gateway": {0, 0, 1},
+}
+
+func newFakeEmbeddingsServer(t *testing.T, requests *[][]string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/embeddings" {
+			http.NotFound(w, r)
+			return
+		}
+		var req openAIEmbeddingsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		*requests = append(*requests, req.Input)
+		var data []map[string]any
+		for i, text := range req.Input {
+			vec, ok := fakeEmbeddingVectors[text]
+			if !ok {
+				vec = []float32{0, 0, 0.01}
+			}
+			data = append(data, map[string]any{"index": i, "embedding": vec})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data":  data,
+			"usage": map[string]int{"prompt_tokens": 5 * len(req.Input)},
+		})
+	}))
+}
+
+type memoryEmbeddingStore struct {
+	saved map[int64]Embedding
+}
+
+func (s *memoryEmbeddingStore) LoadEmbeddings(model string, ids []int64) (map[int64]Embedding, error) {
+	out := make(map[int64]Embedding)
+	for _, id := range ids {
+		if e, ok := s.saved[id]; ok && e.Model == model {
+			out[id] = e
+		}
+	}
+	return out, nil
+}
+
+func (s *memoryEmbeddingStore) SaveEmbeddings(embeddings []Embedding) error {
+	for _, e := range embeddings {
+		s.saved[e.WorkItemID] = e
+	}
+	return nil
+}
+
+func TestLoadExampleVectorsCachesUnchangedDescriptions(t *testing.T) {
+	var requests [][]string
+	server := newFakeEmbeddingsServer(t, &requests)
+	defer server.Close()
+
+	store := &memoryEmbeddingStore{saved: map[int64]Embedding{}}
+	SetEmbeddingStore("", store)
+	t.Cleanup(func() { SetEmbeddingStore("", nil) })
+
+	cfg := Config{EmbeddingModel: "text-embedding-3-small", EmbeddingBaseURL: server.URL + "/v1"}
+	historical := []historicalItem{
+		{WorkItemID: 1, Description: "Reduce latency of payment flow", SectionID: "S0_0"},
+		{WorkItemID: 2, Description: "Fix checkout button colour", SectionID: "S1_0"},
+	}
+	items := []WorkItem{{ID: 10, Description: "Speed up checkout page"}}
+
+	vectors, usage, err := loadExampleVectors(cfg, items, historical, nil)
+	if err != nil {
+		t.Fatalf("loadExampleVectors: %v", err)
+	}
+	if len(requests) != 1 || len(requests[0]) != 3 {
+		t.Fatalf("expected one request with 3 inputs, got %v", requests)
+	}
+	if usage.InputTokens != 15 {
+		t.Fatalf("expected embedding usage to be reported, got %+v", usage)
+	}
+	if len(vectors.history) != 2 || vectors.history[0] == nil || vectors.items[10] == nil {
+		t.Fatalf("expected vectors for all items, got %+v", vectors)
+	}
+	if len(store.saved) != 3 {
+		t.Fatalf("expected 3 cached vectors, got %d", len(store.saved))
+	}
+
+	// Second run: only the edited description is re-embedded.
+	items[0].Description = "Rotate TLS certificates for gateway"
+	if _, _, err := loadExampleVectors(cfg, items, historical, nil); err != nil {
+		t.Fatalf("loadExampleVectors (cached): %v", err)
+	}
+	if len(requests) != 2 || len(requests[1]) != 1 || requests[1][0] != "Rotate TLS certificates for gateway" {
+		t.Fatalf("expected only the edited item to be re-embedded, got %v", requests)
+	}
+}
+
+func TestLoadExampleVectorsReportsAPIErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"model not found"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	cfg := Config{EmbeddingModel: "missing", EmbeddingBaseURL: server.URL}
+	_, _, err := loadExampleVectors(cfg, []WorkItem{{ID: 1, Description: "x"}}, nil, nil)
+	if err == nil {
+		t.Fatal("expected error from failing embeddings endpoint")
+	}
+}
+
+func TestTopKForBatchHybridMatchesParaphrases(t *testing.T) {
+	items := []historicalItem{
+		{WorkItemID: 1, Description: "Reduce latency of payment flow", SectionID: "S0_0"},
+		{WorkItemID: 2, Description: "Fix checkout button colour", SectionID: "S1_0"},
+		{WorkItemID: 3, Description: "Rotate TLS certificates for gateway", SectionID: "S2_0"},
+	}
+	query := "Speed up checkout page"
+
+	lexical := buildTFIDFIndex(items)
+	if got := lexical.topKForBatch([]string{query}, 1); len(got) != 1 || got[0].SectionID != "S1_0" {
+		t.Fatalf("expected TF-IDF to match on the shared token, got %+v", got)
+	}
+
+	hybrid := buildTFIDFIndex(items)
+	hybrid.withEmbeddings([][]float32{
+		fakeEmbeddingVectors[items[0].Description],
+		fakeEmbeddingVectors[items[1].Description],
+		fakeEmbeddingVectors[items[2].Description],
+	}, 0.7)
+	got := hybrid.topKForBatchHybrid([]string{query}, [][]float32{fakeEmbeddingVectors[query]}, 1)
+	if len(got) != 1 || got[0].SectionID != "S0_0" {
+		t.Fatalf("expected embedding similarity to surface the paraphrase, got %+v", got)
+	}
+
+	// A query without a vector falls back to TF-IDF.
+	got = hybrid.topKForBatchHybrid([]string{query}, [][]float32{nil}, 1)
+	if len(got) != 1 || got[0].SectionID != "S1_0" {
+		t.Fatalf("expected TF-IDF fallback without query vector, got %+v", got)
+	}
+}
@@ -22,6 +22,11 @@ type sectionClassifiedItem struct {
 	NormalizedStatus string          `json:"normalized_status"`
 	TicketIDs        json.RawMessage `json:"ticket_ids"`
 	DuplicateOf      string          `json:"duplicate_of"`
+	Confidence       *float64        `json:"confidence"`
+	Alternatives     []string        `json:"alternative_section_ids"`
+	// LogprobConfidence is added by providers that can derive the section
+	// probability from token logprobs; models never emit it themselves.
+	LogprobConfidence *float64 `json:"logprob_confidence"`
 }
 
 type ExistingItemContext struct {
@@ -39,13 +44,44 @@ type LLMSectionDecision struct {
 	TicketIDs        string
 	DuplicateOf      string
 	Confidence       float64
+	// RawConfidence is the uncalibrated probability reported by the model
+	// (or derived from logprobs). Zero when the model gave none or when a
+	// glossary rule decided the section instead.
+	RawConfidence    float64
+	ConfidenceSource string
+	// Alternatives are other plausible section IDs, most likely first.
+	Alternatives []string
+	// CacheKey identifies the inputs this decision was made from; see
+	// decisionCacheKey. CachedFrom is the work item whose stored decision
+	// was reused, or 0 when the LLM decided this time.
+	CacheKey   string
+	CachedFrom int64
 }
 
+// Confidence sources recorded on LLMSectionDecision.
+const (
+	confidenceSourceGlossary  = "glossary"
+	confidenceSourceModel     = "model"
+	confidenceSourceLogprob   = "logprob"
+	confidenceSourceHeuristic = "heuristic"
+	confidenceSourceInvalid   = "invalid"
+	confidenceSourceCache     = "cache"
+)
+
+const maxAlternativeSections = 3
+
 type LLMUsage struct {
 	InputTokens              int64
 	OutputTokens             int64
 	CacheCreationInputTokens int64
 	CacheReadInputTokens     int64
+	// DegradedBatches counts classification batches that still failed after
+	// retries; their items are left undecided and land in Undetermined.
+	DegradedBatches int
+	// DecisionCacheHits and DecisionCacheMisses count items whose decision
+	// was reused from classification history versus sent to the LLM.
+	DecisionCacheHits   int
+	DecisionCacheMisses int
 }
 
 func (u LLMUsage) TotalTokens() int64 {
@@ -57,6 +93,9 @@ func (u *LLMUsage) Add(other LLMUsage) {
 	u.OutputTokens += other.OutputTokens
 	u.CacheCreationInputTokens += other.CacheCreationInputTokens
 	u.CacheReadInputTokens += other.CacheReadInputTokens
+	u.DegradedBatches += other.DegradedBatches
+	u.DecisionCacheHits += other.DecisionCacheHits
+	u.DecisionCacheMisses += other.DecisionCacheMisses
 }
 
 const defaultAnthropicModel = "claude-sonnet-4-5-20250929"
@@ -85,6 +124,27 @@ func CategorizeItemsToSections(
 	if len(items) == 0 {
 		return nil, LLMUsage{}, nil
 	}
+	provider, err := NewProvider(cfg)
+	if err != nil {
+		return nil, LLMUsage{}, err
+	}
+	return CategorizeItemsToSectionsWithProvider(provider, cfg, items, options, existing, corrections, historicalItems)
+}
+
+// CategorizeItemsToSectionsWithProvider is CategorizeItemsToSections with an
+// explicit provider, for callers that replay or fake LLM responses.
+func CategorizeItemsToSectionsWithProvider(
+	provider Provider,
+	cfg Config,
+	items []WorkItem,
+	options []sectionOption,
+	existing []existingItemContext,
+	corrections []ClassificationCorrection,
+	historicalItems []historicalItem,
+) (map[int64]LLMSectionDecision, LLMUsage, error) {
+	if len(items) == 0 {
+		return nil, LLMUsage{}, nil
+	}
 
 	batchSize := cfg.LLMBatchSize
 	if batchSize < 1 {
@@ -97,10 +157,39 @@ func CategorizeItemsToSections(
 	glossarySectionMap := resolveGlossarySectionMap(glossary, options)
 	templateGuidance := loadTemplateGuidance(cfg.LLMGuidePath)
 
+	// Reuse earlier decisions for items whose description, section set,
+	// guide and glossary are unchanged; only the rest go to the LLM.
+	allItems := items
+	fingerprint := classificationFingerprint(provider, options, templateGuidance, glossary)
+	cached, items, cacheKeys := lookupCachedDecisions(cfg.TeamID, allItems, fingerprint, options)
+	log.Printf("llm decision cache hits=%d misses=%d", len(cached), len(items))
+	if len(items) == 0 {
+		return cached, LLMUsage{DecisionCacheHits: len(cached)}, nil
+	}
+
+	// Redact item-derived text before anything is sent to a provider.
+	redact, err := newRedactor(cfg)
+	if err != nil {
+		return nil, LLMUsage{}, err
+	}
+	existing = redact.redactExisting(existing)
+	corrections = redact.redactCorrections(corrections)
+
 	// Build TF-IDF index for example selection.
 	var tfidfIdx *tfidfIndex
+	var vectors *exampleVectors
 	if len(historicalItems) > 0 {
 		tfidfIdx = buildTFIDFIndex(historicalItems)
+		if cfg.EmbeddingsConfigured() {
+			// Hybrid ranking is best-effort; TF-IDF alone still works.
+			loaded, _, err := loadExampleVectors(cfg, items, historicalItems, redact)
+			if err != nil {
+				log.Printf("llm embeddings unavailable, using TF-IDF only: %v", err)
+			} else {
+				vectors = loaded
+				tfidfIdx.withEmbeddings(vectors.history, cfg.EmbeddingWeight)
+			}
+		}
 	}
 
 	// Pre-slice all batches.
@@ -128,50 +217,30 @@ func CategorizeItemsToSections(
 		go func(idx int, batch []WorkItem) {
 			defer wg.Done()
 			defer func() { <-sem }()
-			// Select relevant examples for this batch via TF-IDF.
+			// Select relevant examples for this batch via TF-IDF, blended
+			// with embedding similarity when vectors are available.
 			var batchExamples []historicalItem
 			if tfidfIdx != nil {
 				var queries []string
+				var queryVecs [][]float32
 				for _, item := range batch {
 					queries = append(queries, item.Description)
+					if vectors != nil {
+						queryVecs = append(queryVecs, vectors.items[item.ID])
+					}
 				}
 				exampleCount := cfg.LLMExampleCount
 				if exampleCount < 1 {
 					exampleCount = 20
 				}
-				batchExamples = tfidfIdx.topKForBatch(queries, exampleCount)
-			}
-			systemPrompt, userPrompt := buildSectionPrompts(cfg, options, batch, existing, templateGuidance, corrections, batchExamples)
-
-			var responseText string
-			var usage LLMUsage
-			var callErr error
-
-			switch cfg.LLMProvider {
-			case "openai":
-				model := cfg.LLMModel
-				if model == "" {
-					model = defaultOpenAIModel
-				}
-				log.Printf("llm section-classify provider=openai model=%s items=%d sections=%d batch=%d", model, len(batch), len(options), idx)
-				responseText, usage, callErr = callOpenAISectionStructured(cfg.OpenAIAPIKey, cfg.OpenAIBaseURL, model, systemPrompt, userPrompt, options)
-			default:
-				model := cfg.LLMModel
-				if model == "" {
-					model = defaultAnthropicModel
-				}
-				log.Printf("llm section-classify provider=anthropic model=%s items=%d sections=%d batch=%d", model, len(batch), len(options), idx)
-				responseText, usage, callErr = callAnthropic(cfg.AnthropicAPIKey, model, systemPrompt, userPrompt)
+				batchExamples = redact.redactHistorical(tfidfIdx.topKForBatchHybrid(queries, queryVecs, exampleCount))
 			}
+			systemPrompt, userPrompt := buildSectionPrompts(cfg, options, redact.redactItems(batch), existing, templateGuidance, corrections, batchExamples)
 
-			if callErr != nil {
-				results[idx] = batchResult{usage: usage, err: callErr}
-				return
-			}
-
-			parsed, parseErr := parseSectionClassifiedResponse(responseText)
-			if parseErr != nil {
-				results[idx] = batchResult{usage: usage, err: parseErr}
+			log.Printf("llm section-classify provider=%s model=%s items=%d sections=%d batch=%d", provider.Name(), provider.Model(), len(batch), len(options), idx)
+			parsed, usage, batchErr := classifyBatchWithRetry(provider, idx, systemPrompt, userPrompt, options)
+			if batchErr != nil {
+				results[idx] = batchResult{usage: usage, err: batchErr}
 				return
 			}
 			glossaryOverrides := applyGlossaryOverrides(batch, parsed, glossary, glossarySectionMap)
@@ -181,22 +250,52 @@ func CategorizeItemsToSections(
 	}
 	wg.Wait()
 
-	all := make(map[int64]LLMSectionDecision, len(items))
-	totalUsage := LLMUsage{}
-	for _, r := range results {
+	all := make(map[int64]LLMSectionDecision, len(allItems))
+	totalUsage := LLMUsage{DecisionCacheHits: len(cached), DecisionCacheMisses: len(items)}
+	var rejected error
+	for idx, r := range results {
 		totalUsage.Add(r.usage)
+		recordUsage(cfg, provider.Name(), provider.Model(), usagePurposeClassify, r.usage)
+		if r.err != nil && rejectedRequest(r.err) {
+			// A bad key or malformed request fails every batch the same way;
+			// report it rather than routing the whole report to Undetermined.
+			if rejected == nil {
+				rejected = fmt.Errorf("llm section-classify batch=%d: %w", idx, r.err)
+			}
+			continue
+		}
 		if r.err != nil {
-			return nil, totalUsage, r.err
+			// Leave the batch's items without a decision so the report builder
+			// routes them to Undetermined instead of failing the whole report.
+			log.Printf("llm section-classify batch=%d degraded items=%d: %v", idx, len(batches[idx]), r.err)
+			totalUsage.DegradedBatches++
+			continue
 		}
 		for id, decision := range r.decisions {
+			decision.CacheKey = cacheKeys[id]
 			all[id] = decision
 		}
 	}
+	if rejected != nil {
+		return nil, totalUsage, rejected
+	}
+	redact.restoreDecisions(all)
+	for id, decision := range cached {
+		all[id] = decision
+	}
 
 	// Generator-Critic loop: second LLM pass to catch misclassifications.
-	if cfg.LLMCriticEnabled && len(all) > 0 {
-		flagged, criticUsage, err := runCriticPass(cfg, items, all, options)
+	runCritic := cfg.LLMCriticEnabled && len(all) > 0
+	if runCritic {
+		if err := CheckOptionalPassBudget(cfg); err != nil {
+			log.Printf("llm critic skipped: %v", err)
+			runCritic = false
+		}
+	}
+	if runCritic {
+		flagged, criticUsage, err := runCriticPass(provider, redact.redactItems(allItems), all, options)
 		totalUsage.Add(criticUsage)
+		recordUsage(cfg, provider.Name(), provider.Model(), usagePurposeCritic, criticUsage)
 		if err != nil {
 			log.Printf("llm critic error (non-fatal): %v", err)
 		} else {
@@ -218,6 +317,7 @@ func CategorizeItemsToSections(
 		}
 	}
 
+	redact.logCounts("classify")
 	return all, totalUsage, nil
 }
 
@@ -295,10 +395,12 @@ Also:
 - choose normalized_status from: done, in testing, in progress, other
 - extract ticket IDs if present (e.g. [1247202] or bare ticket numbers); return them as an array of strings
 - if this item is the same underlying work as an existing item, set duplicate_of to that existing key (Kxx); otherwise empty string
+- set confidence to your probability (0.0-1.0) that section_id is correct; be honest, low values are useful
+- list up to 2 other plausible section IDs in alternative_section_ids, most likely first (empty array if none)
 %s%s
 
 Respond with JSON only (no markdown):
-[{"id": 1, "section_id": "S0_2", "normalized_status": "in progress", "ticket_ids": ["1247202"], "duplicate_of": "K3"}, ...]`, sectionLines.String(), templateBlock, correctionsNote)
+[{"id": 1, "section_id": "S0_2", "normalized_status": "in progress", "ticket_ids": ["1247202"], "duplicate_of": "K3", "confidence": 0.82, "alternative_section_ids": ["S1_0"]}, ...]`, sectionLines.String(), templateBlock, correctionsNote)
 
 	correctionsBlock := ""
 	if len(corrections) > 0 {
@@ -390,12 +492,22 @@ parsed:
 	decisions := make(map[int64]LLMSectionDecision)
 	for _, c := range classified {
 		ticketIDs := parseTicketIDsField(c.TicketIDs)
-		decisions[c.ID] = LLMSectionDecision{
+		decision := LLMSectionDecision{
 			SectionID:        strings.TrimSpace(c.SectionID),
 			NormalizedStatus: normalizeStatus(strings.TrimSpace(c.NormalizedStatus)),
 			TicketIDs:        ticketIDs,
 			DuplicateOf:      strings.TrimSpace(c.DuplicateOf),
+			Alternatives:     c.Alternatives,
 		}
+		switch {
+		case c.LogprobConfidence != nil && *c.LogprobConfidence > 0:
+			decision.RawConfidence = clampConfidence(*c.LogprobConfidence)
+			decision.ConfidenceSource = confidenceSourceLogprob
+		case c.Confidence != nil && *c.Confidence > 0:
+			decision.RawConfidence = clampConfidence(*c.Confidence)
+			decision.ConfidenceSource = confidenceSourceModel
+		}
+		decisions[c.ID] = decision
 	}
 	return decisions, nil
 }
@@ -531,31 +643,86 @@ func assignLocalConfidence(decisions map[int64]LLMSectionDecision, options []sec
 	}
 }
 
+// withDerivedConfidence sets Confidence from the model-reported value when
+// there is one, falling back to fixed buckets for models that omit it.
+// Glossary overrides and invalid section IDs always use fixed values.
 func withDerivedConfidence(decision LLMSectionDecision, validSections map[string]bool, glossaryOverride bool) LLMSectionDecision {
 	sectionID := strings.TrimSpace(decision.SectionID)
 	if strings.EqualFold(sectionID, "UND") {
 		sectionID = "UND"
 		decision.SectionID = sectionID
 	}
+	decision.Alternatives = normalizeAlternatives(decision.Alternatives, sectionID, validSections)
+	raw := decision.RawConfidence
 	switch {
 	case glossaryOverride:
 		decision.Confidence = 0.99
+		decision.RawConfidence = 0
+		decision.ConfidenceSource = confidenceSourceGlossary
 	case sectionID == "" || !validSections[sectionID]:
 		decision.Confidence = 0.20
-	case strings.EqualFold(sectionID, "UND"):
+		decision.RawConfidence = 0
+		decision.ConfidenceSource = confidenceSourceInvalid
+	case sectionID == "UND":
 		decision.Confidence = 0.40
+		if raw <= 0 {
+			decision.ConfidenceSource = confidenceSourceHeuristic
+		} else if raw < decision.Confidence {
+			decision.Confidence = raw
+		}
+	case raw > 0:
+		decision.Confidence = raw
 	case strings.TrimSpace(decision.DuplicateOf) != "":
 		decision.Confidence = 0.95
+		decision.ConfidenceSource = confidenceSourceHeuristic
 	default:
 		decision.Confidence = 0.90
+		decision.ConfidenceSource = confidenceSourceHeuristic
 	}
 	return decision
 }
 
+func normalizeAlternatives(alternatives []string, chosen string, validSections map[string]bool) []string {
+	var out []string
+	seen := map[string]bool{chosen: true, "UND": true}
+	for _, alt := range alternatives {
+		alt = strings.TrimSpace(alt)
+		if alt == "" || seen[alt] || !validSections[alt] {
+			continue
+		}
+		seen[alt] = true
+		out = append(out, alt)
+		if len(out) == maxAlternativeSections {
+			break
+		}
+	}
+	return out
+}
+
+func clampConfidence(v float64) float64 {
+	switch {
+	case v < 0:
+		return 0
+	case v > 1:
+		return 1
+	}
+	return v
+}
+
 // --- Anthropic ---
 
-func callAnthropic(apiKey, model, systemPrompt, userPrompt string) (string, LLMUsage, error) {
-	client := anthropic.NewClient(option.WithAPIKey(apiKey))
+// newAnthropicClient disables the SDK's own retries: classifyBatchWithRetry
+// owns the retry policy, and stacking both multiplies attempts per batch.
+func newAnthropicClient(apiKey, baseURL string) anthropic.Client {
+	opts := []option.RequestOption{option.WithAPIKey(apiKey), option.WithMaxRetries(0)}
+	if baseURL != "" {
+		opts = append(opts, option.WithBaseURL(baseURL))
+	}
+	return anthropic.NewClient(opts...)
+}
+
+func callAnthropic(apiKey, baseURL, model, systemPrompt, userPrompt string) (string, LLMUsage, error) {
+	client := newAnthropicClient(apiKey, baseURL)
 
 	message, err := client.Messages.New(context.Background(), anthropic.MessageNewParams{
 		Model:     anthropic.Model(model),
@@ -594,6 +761,7 @@ type openAIResponsesRequest struct {
 	Input       string                    `json:"input"`
 	Temperature float64                   `json:"temperature,omitempty"`
 	Text        *openAIResponsesTextParam `json:"text,omitempty"`
+	Include     []string                  `json:"include,omitempty"`
 }
 
 type openAIResponsesTextParam struct {
@@ -627,14 +795,7 @@ type openAIResponsesResponse struct {
 }
 
 func buildSectionJSONSchema(options []sectionOption) map[string]any {
-	sections := make([]string, 0, len(options)+1)
-	sections = append(sections, "UND")
-	for _, option := range options {
-		id := strings.TrimSpace(option.ID)
-		if id != "" {
-			sections = append(sections, id)
-		}
-	}
+	sections := sectionIDEnum(options)
 	return map[string]any{
 		"type": "array",
 		"items": map[string]any{
@@ -661,13 +822,23 @@ func buildSectionJSONSchema(options []sectionOption) map[string]any {
 				"duplicate_of": map[string]any{
 					"type": "string",
 				},
+				"confidence": map[string]any{
+					"type": "number",
+				},
+				"alternative_section_ids": map[string]any{
+					"type": "array",
+					"items": map[string]any{
+						"type": "string",
+						"enum": sections,
+					},
+				},
 			},
-			"required": []string{"id", "section_id", "normalized_status", "ticket_ids", "duplicate_of"},
+			"required": []string{"id", "section_id", "normalized_status", "ticket_ids", "duplicate_of", "confidence", "alternative_section_ids"},
 		},
 	}
 }
 
-func callOpenAISectionStructured(apiKey, baseURL, model, systemPrompt, userPrompt string, options []sectionOption) (string, LLMUsage, error) {
+func callOpenAISectionStructured(apiKey, baseURL, model, systemPrompt, userPrompt string, options []sectionOption, withLogprobs bool) (string, LLMUsage, error) {
 	reqBody := openAIResponsesRequest{
 		Model: model,
 		Input: buildResponsesInput(systemPrompt, userPrompt),
@@ -680,11 +851,17 @@ func callOpenAISectionStructured(apiKey, baseURL, model, systemPrompt, userPromp
 			},
 		},
 	}
-	responseText, usage, err := doOpenAIResponsesRequest(apiKey, baseURL, reqBody)
+	if withLogprobs {
+		reqBody.Include = []string{openAILogprobsInclude}
+	}
+	responseText, usage, rawBody, err := doOpenAIResponsesRequestRaw(apiKey, baseURL, reqBody)
 	if err != nil {
 		return "", usage, err
 	}
 	log.Printf("llm openai responses size=%d tokens_in=%d tokens_out=%d", len(responseText), usage.InputTokens, usage.OutputTokens)
+	if withLogprobs {
+		responseText = annotateLogprobConfidence(responseText, extractResponsesOutputLogprobs(rawBody, responseText))
+	}
 	return responseText, usage, nil
 }
 
@@ -702,14 +879,21 @@ func buildResponsesInput(systemPrompt, userPrompt string) string {
 }
 
 func doOpenAIResponsesRequest(apiKey, baseURL string, reqBody openAIResponsesRequest) (string, LLMUsage, error) {
+	responseText, usage, _, err := doOpenAIResponsesRequestRaw(apiKey, baseURL, reqBody)
+	return responseText, usage, err
+}
+
+// doOpenAIResponsesRequestRaw also returns the response body so callers can
+// read optional fields such as token logprobs.
+func doOpenAIResponsesRequestRaw(apiKey, baseURL string, reqBody openAIResponsesRequest) (string, LLMUsage, []byte, error) {
 	bodyBytes, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", LLMUsage{}, fmt.Errorf("marshaling responses request: %w", err)
+		return "", LLMUsage{}, nil, fmt.Errorf("marshaling responses request: %w", err)
 	}
 
 	req, err := http.NewRequest("POST", strings.TrimRight(baseURL, "/")+"/responses", bytes.NewReader(bodyBytes))
 	if err != nil {
-		return "", LLMUsage{}, fmt.Errorf("creating responses request: %w", err)
+		return "", LLMUsage{}, nil, fmt.Errorf("creating responses request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+apiKey)
@@ -717,22 +901,19 @@ func doOpenAIResponsesRequest(apiKey, baseURL string, reqBody openAIResponsesReq
 	resp, err := externalHTTPClient.Do(req)
 	if err != nil {
 		log.Printf("llm openai responses error: %v", err)
-		return "", LLMUsage{}, fmt.Errorf("OpenAI Responses API error: %w", err)
+		return "", LLMUsage{}, nil, fmt.Errorf("OpenAI Responses API error: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", LLMUsage{}, fmt.Errorf("reading responses body: %w", err)
+		return "", LLMUsage{}, nil, fmt.Errorf("reading responses body: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		truncated := string(respBody)
-		if len(truncated) > 512 {
-			truncated = truncated[:512] + "..."
-		}
-		log.Printf("llm openai responses HTTP %d: %s", resp.StatusCode, truncated)
-		return "", LLMUsage{}, fmt.Errorf("OpenAI Responses API HTTP %d: %s", resp.StatusCode, truncated)
+		statusErr := newAPIStatusError("OpenAI Responses API", resp, respBody)
+		log.Printf("llm openai responses HTTP %d: %s", resp.StatusCode, statusErr.Body)
+		return "", LLMUsage{}, nil, statusErr
 	}
 
 	var responsesResp openAIResponsesResponse
@@ -742,23 +923,23 @@ func doOpenAIResponsesRequest(apiKey, baseURL string, reqBody openAIResponsesReq
 			truncated = truncated[:512] + "..."
 		}
 		log.Printf("llm openai responses invalid JSON: %s", truncated)
-		return "", LLMUsage{}, fmt.Errorf("parsing OpenAI Responses payload: %w (body: %s)", err, truncated)
+		return "", LLMUsage{}, nil, fmt.Errorf("parsing OpenAI Responses payload: %w (body: %s)", err, truncated)
 	}
 	if responsesResp.Error != nil {
 		log.Printf("llm openai responses api error: %s", responsesResp.Error.Message)
-		return "", LLMUsage{}, fmt.Errorf("OpenAI Responses API error: %s", responsesResp.Error.Message)
+		return "", LLMUsage{}, nil, fmt.Errorf("OpenAI Responses API error: %s", responsesResp.Error.Message)
 	}
 
 	responseText, err := extractResponsesOutputText(responsesResp)
 	if err != nil {
-		return "", LLMUsage{}, err
+		return "", LLMUsage{}, nil, err
 	}
 	usage := LLMUsage{}
 	if responsesResp.Usage != nil {
 		usage.InputTokens = responsesResp.Usage.InputTokens
 		usage.OutputTokens = responsesResp.Usage.OutputTokens
 	}
-	return responseText, usage, nil
+	return responseText, usage, respBody, nil
 }
 
 func extractResponsesOutputText(resp openAIResponsesResponse) (string, error) {
@@ -800,6 +981,26 @@ func callOpenAI(apiKey, baseURL, model, systemPrompt, userPrompt string) (string
 	return responseText, usage, nil
 }
 
+func callOpenAIStructured(apiKey, baseURL, model, systemPrompt, userPrompt string, output StructuredOutput) (string, LLMUsage, error) {
+	responseText, usage, err := doOpenAIResponsesRequest(apiKey, baseURL, openAIResponsesRequest{
+		Model: model,
+		Input: buildResponsesInput(systemPrompt, userPrompt),
+		Text: &openAIResponsesTextParam{
+			Format: openAIResponsesFormatParam{
+				Type:   "json_schema",
+				Name:   output.Name,
+				Strict: true,
+				Schema: output.Schema,
+			},
+		},
+	})
+	if err != nil {
+		return "", usage, err
+	}
+	log.Printf("llm openai responses schema=%s size=%d tokens_in=%d tokens_out=%d", output.Name, len(responseText), usage.InputTokens, usage.OutputTokens)
+	return responseText, usage, nil
+}
+
 // --- Generator-Critic Loop ---
 
 type criticFlagged struct {
@@ -808,7 +1009,7 @@ type criticFlagged struct {
 	SuggestedSectionID string `json:"suggested_section_id"`
 }
 
-func runCriticPass(cfg Config, items []WorkItem, decisions map[int64]LLMSectionDecision, options []sectionOption) ([]criticFlagged, LLMUsage, error) {
+func runCriticPass(provider Provider, items []WorkItem, decisions map[int64]LLMSectionDecision, options []sectionOption) ([]criticFlagged, LLMUsage, error) {
 	var sectionLines strings.Builder
 	for _, opt := range options {
 		sectionLines.WriteString(fmt.Sprintf("- %s: %s\n", opt.ID, opt.Label))
@@ -834,26 +1035,12 @@ Respond with JSON only (no markdown):
 
 	userPrompt := "Review these classifications:\n" + itemLines.String()
 
-	var responseText string
-	var usage LLMUsage
-	var err error
-
-	switch cfg.LLMProvider {
-	case "openai":
-		model := cfg.LLMModel
-		if model == "" {
-			model = defaultOpenAIModel
-		}
-		log.Printf("llm critic provider=openai model=%s items=%d", model, len(items))
-		responseText, usage, err = callOpenAI(cfg.OpenAIAPIKey, cfg.OpenAIBaseURL, model, systemPrompt, userPrompt)
-	default:
-		model := cfg.LLMModel
-		if model == "" {
-			model = defaultAnthropicModel
-		}
-		log.Printf("llm critic provider=anthropic model=%s items=%d", model, len(items))
-		responseText, usage, err = callAnthropic(cfg.AnthropicAPIKey, model, systemPrompt, userPrompt)
-	}
+	log.Printf("llm critic provider=%s model=%s items=%d", provider.Name(), provider.Model(), len(items))
+	responseText, usage, err := completeStructured(provider, systemPrompt, userPrompt, StructuredOutput{
+		Name:        "report_misclassifications",
+		Description: "Report the items whose section assignment is wrong; an empty array when all are correct.",
+		Schema:      buildCriticJSONSchema(options),
+	})
 	if err != nil {
 		return nil, usage, err
 	}
@@ -907,6 +1094,12 @@ func analyzeCorrections(cfg Config, corrections []ClassificationCorrection, opti
 		sectionLines.WriteString(fmt.Sprintf("- %s: %s\n", opt.ID, opt.Label))
 	}
 
+	redact, err := newRedactor(cfg)
+	if err != nil {
+		return nil, LLMUsage{}, err
+	}
+	corrections = redact.redactCorrections(corrections)
+
 	var corrLines strings.Builder
 	for _, c := range corrections {
 		desc := strings.TrimSpace(c.Description)
@@ -943,26 +1136,17 @@ Respond with JSON only (no markdown):
 
 	userPrompt := "Recent classification corrections:\n" + corrLines.String()
 
-	var responseText string
-	var usage LLMUsage
-	var err error
-
-	switch cfg.LLMProvider {
-	case "openai":
-		model := cfg.LLMModel
-		if model == "" {
-			model = defaultOpenAIModel
-		}
-		log.Printf("llm retrospective provider=openai model=%s corrections=%d", model, len(corrections))
-		responseText, usage, err = callOpenAI(cfg.OpenAIAPIKey, cfg.OpenAIBaseURL, model, systemPrompt, userPrompt)
-	default:
-		model := cfg.LLMModel
-		if model == "" {
-			model = defaultAnthropicModel
-		}
-		log.Printf("llm retrospective provider=anthropic model=%s corrections=%d", model, len(corrections))
-		responseText, usage, err = callAnthropic(cfg.AnthropicAPIKey, model, systemPrompt, userPrompt)
+	provider, err := NewProvider(cfg)
+	if err != nil {
+		return nil, LLMUsage{}, err
 	}
+	log.Printf("llm retrospective provider=%s model=%s corrections=%d", provider.Name(), provider.Model(), len(corrections))
+	responseText, usage, err := completeStructured(provider, systemPrompt, userPrompt, StructuredOutput{
+		Name:        "suggest_improvements",
+		Description: "Suggest glossary terms or guide updates for repeated correction patterns.",
+		Schema:      buildRetroJSONSchema(),
+	})
+	recordUsage(cfg, provider.Name(), provider.Model(), usagePurposeRetrospect, usage)
 	if err != nil {
 		return nil, usage, err
 	}
@@ -981,5 +1165,130 @@ Respond with JSON only (no markdown):
 	if len(suggestions) > 5 {
 		suggestions = suggestions[:5]
 	}
+	for i := range suggestions {
+		suggestions[i].Title = redact.Restore(suggestions[i].Title)
+		suggestions[i].Reasoning = redact.Restore(suggestions[i].Reasoning)
+		suggestions[i].Phrase = redact.Restore(suggestions[i].Phrase)
+		suggestions[i].GuideText = redact.Restore(suggestions[i].GuideText)
+	}
+	redact.logCounts("retrospect")
 	return suggestions, usage, nil
 }
+
+// --- Roll-up Summary ---
+
+// summarizeTeamReport asks for a short plain-text paragraph summarizing one
+// team's weekly report, used under the team's heading in a department
+// roll-up.
+func summarizeTeamReport(cfg Config, teamName, report string) (string, LLMUsage, error) {
+	if strings.TrimSpace(report) == "" {
+		return "", LLMUsage{}, nil
+	}
+	redact, err := newRedactor(cfg)
+	if err != nil {
+		return "", LLMUsage{}, err
+	}
+
+	systemPrompt := `You write the summary paragraph for one team in a department's weekly status email.
+Summarize the team report below in 2-4 sentences for a director: main accomplishments first, then notable work still in progress.
+Only mention work that appears in the report. Do not list every item or name individual people.
+Reply with the paragraph only: plain text, no headings, bullet points or markdown.`
+	userPrompt := fmt.Sprintf("Team: %s\n\nReport:\n%s", teamName, redact.Redact(report))
+
+	provider, err := NewProvider(cfg)
+	if err != nil {
+		return "", LLMUsage{}, err
+	}
+	log.Printf("llm rollup summary provider=%s model=%s team=%s", provider.Name(), provider.Model(), teamName)
+	responseText, usage, err := provider.Complete(systemPrompt, userPrompt)
+	recordUsage(cfg, provider.Name(), provider.Model(), usagePurposeRollup, usage)
+	if err != nil {
+		return "", usage, err
+	}
+	summary := strings.Join(strings.Fields(redact.Restore(responseText)), " ")
+	redact.logCounts("rollup")
+	return summary, usage, nil
+}
+
+// --- Period Theme Summary ---
+
+// PeriodCategory is one category of a monthly or quarterly report with the
+// item lines its theme summary is written from.
+type PeriodCategory struct {
+	Name  string
+	Items []string
+}
+
+type periodThemeSummary struct {
+	Category string `json:"category"`
+	Summary  string `json:"summary"`
+}
+
+// summarizePeriodThemes asks for a short narrative of the themes of each
+// category of a monthly or quarterly report, in one request. It returns the
+// summaries keyed by category name; categories the model skipped are
+// missing from the map.
+func summarizePeriodThemes(cfg Config, periodLabel string, categories []PeriodCategory) (map[string]string, LLMUsage, error) {
+	if len(categories) == 0 {
+		return nil, LLMUsage{}, nil
+	}
+	redact, err := newRedactor(cfg)
+	if err != nil {
+		return nil, LLMUsage{}, err
+	}
+
+	names := make([]string, 0, len(categories))
+	var body strings.Builder
+	for _, c := range categories {
+		names = append(names, c.Name)
+		body.WriteString(fmt.Sprintf("## %s\n", c.Name))
+		for _, item := range c.Items {
+			body.WriteString("- " + redact.Redact(item) + "\n")
+		}
+		body.WriteString("\n")
+	}
+
+	systemPrompt := `You write the narrative part of a team's monthly or quarterly status report.
+For every category below, write 2-3 sentences describing the themes of the work: what was delivered, what is still in progress, and how items relate.
+Only mention work that appears in the items. Do not list every item or name individual people.
+Use plain text without markdown.
+
+Respond with JSON only (no markdown):
+[{"category": "<category name exactly as given>", "summary": "..."}, ...]`
+	userPrompt := fmt.Sprintf("Period: %s\n\n%s", periodLabel, body.String())
+
+	provider, err := NewProvider(cfg)
+	if err != nil {
+		return nil, LLMUsage{}, err
+	}
+	log.Printf("llm period summary provider=%s model=%s period=%q categories=%d", provider.Name(), provider.Model(), periodLabel, len(categories))
+	responseText, usage, err := completeStructured(provider, systemPrompt, userPrompt, StructuredOutput{
+		Name:        "summarize_period_themes",
+		Description: "Record a theme summary for each report category.",
+		Schema:      buildPeriodThemesJSONSchema(names),
+	})
+	recordUsage(cfg, provider.Name(), provider.Model(), usagePurposePeriod, usage)
+	if err != nil {
+		return nil, usage, err
+	}
+
+	responseText = strings.TrimSpace(responseText)
+	responseText = strings.TrimPrefix(responseText, "```json")
+	responseText = strings.TrimPrefix(responseText, "```")
+	responseText = strings.TrimSuffix(responseText, "```")
+	responseText = strings.TrimSpace(responseText)
+
+	var summaries []periodThemeSummary
+	if err := json.Unmarshal([]byte(responseText), &summaries); err != nil {
+		return nil, usage, fmt.Errorf("parsing period summary response: %w (response: %s)", err, responseText)
+	}
+	out := make(map[string]string, len(summaries))
+	for _, s := range summaries {
+		summary := strings.Join(strings.Fields(redact.Restore(s.Summary)), " ")
+		if summary != "" {
+			out[s.Category] = summary
+		}
+	}
+	redact.logCounts("period summary")
+	return out, usage, nil
+}
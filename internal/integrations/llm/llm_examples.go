@@ -17,6 +17,10 @@ type tfidfIndex struct {
 	idf   []float64
 	docs  []sparseVec
 	items []historicalItem
+	// vectors holds optional dense embeddings aligned with items; when set,
+	// ranking blends TF-IDF and embedding cosine by embeddingWeight.
+	vectors         [][]float32
+	embeddingWeight float64
 }
 
 func tokenize(s string) []string {
@@ -115,11 +119,29 @@ func (idx *tfidfIndex) queryVec(query string) sparseVec {
 
 // topKIndices returns the indices of the top-K most similar items to query.
 func (idx *tfidfIndex) topKIndices(query string, k int) []int {
+	return idx.rankIndices(query, nil, k)
+}
+
+// withEmbeddings enables hybrid ranking. vectors must be aligned with the
+// indexed items; nil entries fall back to TF-IDF alone.
+func (idx *tfidfIndex) withEmbeddings(vectors [][]float32, weight float64) {
+	if len(vectors) != len(idx.items) {
+		return
+	}
+	idx.vectors = vectors
+	idx.embeddingWeight = weight
+}
+
+// rankIndices scores every item against query. When the index and qvec both
+// carry embeddings the score is a weighted blend of TF-IDF and embedding
+// cosine, so paraphrases without shared tokens can still match.
+func (idx *tfidfIndex) rankIndices(query string, qvec []float32, k int) []int {
 	if len(idx.items) == 0 || k <= 0 {
 		return nil
 	}
-	qvec := idx.queryVec(query)
-	if len(qvec) == 0 {
+	tvec := idx.queryVec(query)
+	hybrid := len(qvec) > 0 && idx.vectors != nil
+	if len(tvec) == 0 && !hybrid {
 		return nil
 	}
 
@@ -129,7 +151,14 @@ func (idx *tfidfIndex) topKIndices(query string, k int) []int {
 	}
 	var results []scored
 	for i, dvec := range idx.docs {
-		sim := cosineSim(qvec, dvec)
+		sim := cosineSim(tvec, dvec)
+		if hybrid && idx.vectors[i] != nil {
+			dense := denseCosineSim(qvec, idx.vectors[i])
+			if dense < 0 {
+				dense = 0
+			}
+			sim = (1-idx.embeddingWeight)*sim + idx.embeddingWeight*dense
+		}
 		if sim > 0 {
 			results = append(results, scored{i, sim})
 		}
@@ -157,13 +186,23 @@ func (idx *tfidfIndex) topK(query string, k int) []historicalItem {
 }
 
 func (idx *tfidfIndex) topKForBatch(queries []string, k int) []historicalItem {
+	return idx.topKForBatchHybrid(queries, nil, k)
+}
+
+// topKForBatchHybrid is topKForBatch with optional query embeddings aligned
+// with queries; a nil slice or nil entry ranks that query by TF-IDF only.
+func (idx *tfidfIndex) topKForBatchHybrid(queries []string, queryVecs [][]float32, k int) []historicalItem {
 	if len(idx.items) == 0 || k <= 0 {
 		return nil
 	}
 	seen := make(map[int]bool)
 	var out []historicalItem
-	for _, q := range queries {
-		for _, docIdx := range idx.topKIndices(q, k) {
+	for i, q := range queries {
+		var qvec []float32
+		if i < len(queryVecs) {
+			qvec = queryVecs[i]
+		}
+		for _, docIdx := range idx.rankIndices(q, qvec, k) {
 			if !seen[docIdx] {
 				seen[docIdx] = true
 				out = append(out, idx.items[docIdx])
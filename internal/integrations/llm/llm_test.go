@@ -331,3 +331,29 @@ That should be correct.`
 		t.Errorf("expected UND, got %q", decisions[237].SectionID)
 	}
 }
+
+func TestParseSectionClassifiedResponse_ModelConfidenceAndAlternatives(t *testing.T) {
+	response := `[
+		{"id": 1, "section_id": "S0_0", "normalized_status": "done", "ticket_ids": [], "duplicate_of": "", "confidence": 0.55, "alternative_section_ids": ["S1_0", "S0_0", "NOPE", "S1_0"]},
+		{"id": 2, "section_id": "S0_0", "normalized_status": "done", "ticket_ids": [], "duplicate_of": ""},
+		{"id": 3, "section_id": "UND", "normalized_status": "done", "ticket_ids": [], "duplicate_of": "", "confidence": 0.8}
+	]`
+	decisions, err := parseSectionClassifiedResponse(response)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	assignLocalConfidence(decisions, []sectionOption{{ID: "S0_0"}, {ID: "S1_0"}}, nil)
+
+	if d := decisions[1]; d.Confidence != 0.55 || d.RawConfidence != 0.55 || d.ConfidenceSource != confidenceSourceModel {
+		t.Fatalf("expected model-reported confidence, got %+v", d)
+	}
+	if alts := decisions[1].Alternatives; len(alts) != 1 || alts[0] != "S1_0" {
+		t.Fatalf("expected alternatives filtered to valid, distinct, non-chosen IDs, got %v", alts)
+	}
+	if d := decisions[2]; d.Confidence != 0.90 || d.ConfidenceSource != confidenceSourceHeuristic {
+		t.Fatalf("expected heuristic fallback without model confidence, got %+v", d)
+	}
+	if d := decisions[3]; d.Confidence != 0.40 {
+		t.Fatalf("expected UND confidence capped at 0.40, got %+v", d)
+	}
+}
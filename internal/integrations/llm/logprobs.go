@@ -0,0 +1,117 @@
+package llm
+
+import (
+	"encoding/json"
+	"log"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// openAILogprobsInclude asks the Responses API to return per-token logprobs
+// for the output text. Endpoints that do not support it simply omit them.
+const openAILogprobsInclude = "message.output_text.logprobs"
+
+type openAITokenLogprob struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
+}
+
+type openAIResponsesLogprobsPayload struct {
+	Output []struct {
+		Type    string `json:"type"`
+		Content []struct {
+			Type     string               `json:"type"`
+			Text     string               `json:"text"`
+			Logprobs []openAITokenLogprob `json:"logprobs"`
+		} `json:"content"`
+	} `json:"output"`
+}
+
+var sectionIDValueRe = regexp.MustCompile(`"section_id"\s*:\s*"([^"]*)"`)
+
+// extractResponsesOutputLogprobs returns the token logprobs of the output
+// content whose text is outputText, or nil when the endpoint sent none.
+func extractResponsesOutputLogprobs(body []byte, outputText string) []openAITokenLogprob {
+	var payload openAIResponsesLogprobsPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil
+	}
+	for _, output := range payload.Output {
+		if output.Type == "reasoning" {
+			continue
+		}
+		for _, content := range output.Content {
+			if content.Text == outputText && len(content.Logprobs) > 0 {
+				return content.Logprobs
+			}
+		}
+	}
+	return nil
+}
+
+// sectionLogprobConfidences returns, for each "section_id" value in text in
+// order, the joint probability of the tokens spelling that value. Entries are
+// zero when the tokens cannot be aligned with the text.
+func sectionLogprobConfidences(text string, tokens []openAITokenLogprob) []float64 {
+	var joined strings.Builder
+	starts := make([]int, len(tokens))
+	for i, tok := range tokens {
+		starts[i] = joined.Len()
+		joined.WriteString(tok.Token)
+	}
+	if joined.String() != text {
+		return nil
+	}
+
+	matches := sectionIDValueRe.FindAllStringSubmatchIndex(text, -1)
+	out := make([]float64, len(matches))
+	for i, m := range matches {
+		valueStart, valueEnd := m[2], m[3]
+		if valueEnd <= valueStart {
+			continue
+		}
+		sum := 0.0
+		found := false
+		for t, tok := range tokens {
+			tokStart, tokEnd := starts[t], starts[t]+len(tok.Token)
+			if tokEnd <= valueStart || tokStart >= valueEnd {
+				continue
+			}
+			sum += tok.Logprob
+			found = true
+		}
+		if found {
+			out[i] = math.Exp(sum)
+		}
+	}
+	return out
+}
+
+// annotateLogprobConfidence adds a logprob_confidence field to each decision
+// in a classification response. The i-th section_id value belongs to the
+// i-th array element, which the strict JSON schema guarantees.
+func annotateLogprobConfidence(text string, tokens []openAITokenLogprob) string {
+	if len(tokens) == 0 {
+		return text
+	}
+	confidences := sectionLogprobConfidences(text, tokens)
+	if len(confidences) == 0 {
+		log.Printf("llm openai logprobs could not be aligned with output text; using model-reported confidence")
+		return text
+	}
+	var items []map[string]any
+	if err := json.Unmarshal([]byte(text), &items); err != nil || len(items) != len(confidences) {
+		return text
+	}
+	for i := range items {
+		if confidences[i] > 0 {
+			items[i]["logprob_confidence"] = confidences[i]
+		}
+	}
+	annotated, err := json.Marshal(items)
+	if err != nil {
+		return text
+	}
+	return string(annotated)
+}
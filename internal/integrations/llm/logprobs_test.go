@@ -0,0 +1,80 @@
+package llm
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func logprobTokens(parts ...string) []openAITokenLogprob {
+	out := make([]openAITokenLogprob, 0, len(parts))
+	for _, p := range parts {
+		out = append(out, openAITokenLogprob{Token: p, Logprob: -0.01})
+	}
+	return out
+}
+
+func TestSectionLogprobConfidences(t *testing.T) {
+	text := `[{"id":1,"section_id":"S0_0"},{"id":2,"section_id":"S1_0"}]`
+	tokens := logprobTokens(`[{"id":1,"section_id":"`, `S0`, `_0`, `"},{"id":2,"section_id":"`, `S1_0`, `"}]`)
+	tokens[1].Logprob = math.Log(0.5)
+	tokens[2].Logprob = 0
+	tokens[4].Logprob = math.Log(0.9)
+
+	got := sectionLogprobConfidences(text, tokens)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 confidences, got %v", got)
+	}
+	if math.Abs(got[0]-0.5) > 1e-9 || math.Abs(got[1]-0.9) > 1e-9 {
+		t.Fatalf("unexpected confidences: %v", got)
+	}
+
+	if got := sectionLogprobConfidences(text, logprobTokens("mismatch")); got != nil {
+		t.Fatalf("expected nil when tokens do not spell the text, got %v", got)
+	}
+}
+
+func TestCallOpenAISectionStructured_UsesLogprobConfidence(t *testing.T) {
+	text := `[{"id":7,"section_id":"S0_0","normalized_status":"done","ticket_ids":[],"duplicate_of":"","confidence":0.95,"alternative_section_ids":["S1_0"]}]`
+	var gotInclude []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openAIResponsesRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		gotInclude = req.Include
+		tokens := []openAITokenLogprob{
+			{Token: `[{"id":7,"section_id":"`, Logprob: 0},
+			{Token: `S0_0`, Logprob: math.Log(0.6)},
+			{Token: text[len(`[{"id":7,"section_id":"S0_0`):], Logprob: 0},
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"output": []map[string]any{{
+				"type": "message",
+				"content": []map[string]any{{
+					"type": "output_text", "text": text, "logprobs": tokens,
+				}},
+			}},
+		})
+	}))
+	defer server.Close()
+
+	out, _, err := callOpenAISectionStructured("sk", server.URL, "m", "sys", "user", []sectionOption{{ID: "S0_0"}, {ID: "S1_0"}}, true)
+	if err != nil {
+		t.Fatalf("callOpenAISectionStructured: %v", err)
+	}
+	if len(gotInclude) != 1 || gotInclude[0] != openAILogprobsInclude {
+		t.Fatalf("expected logprobs include, got %v", gotInclude)
+	}
+	decisions, err := parseSectionClassifiedResponse(out)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	d := decisions[7]
+	if d.ConfidenceSource != confidenceSourceLogprob || math.Abs(d.RawConfidence-0.6) > 1e-9 {
+		t.Fatalf("expected logprob confidence 0.6, got %+v", d)
+	}
+	if len(d.Alternatives) != 1 || d.Alternatives[0] != "S1_0" {
+		t.Fatalf("expected alternatives to survive annotation, got %+v", d.Alternatives)
+	}
+}
@@ -0,0 +1,138 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// --- Ollama / llama.cpp-style local chat API ---
+
+const (
+	defaultOllamaModel   = "llama3.1:8b"
+	defaultOllamaBaseURL = "http://localhost:11434"
+)
+
+type ollamaChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ollamaChatRequest struct {
+	Model    string              `json:"model"`
+	Messages []ollamaChatMessage `json:"messages"`
+	Stream   bool                `json:"stream"`
+	Format   any                 `json:"format,omitempty"`
+	Options  map[string]any      `json:"options,omitempty"`
+}
+
+type ollamaChatResponse struct {
+	Model           string            `json:"model"`
+	Message         ollamaChatMessage `json:"message"`
+	Done            bool              `json:"done"`
+	PromptEvalCount int64             `json:"prompt_eval_count"`
+	EvalCount       int64             `json:"eval_count"`
+	Error           string            `json:"error"`
+}
+
+type ollamaProvider struct {
+	baseURL string
+	model   string
+}
+
+func newOllamaProvider(cfg Config) (Provider, error) {
+	model := cfg.LLMModel
+	if model == "" {
+		model = defaultOllamaModel
+	}
+	baseURL := strings.TrimRight(cfg.OllamaBaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultOllamaBaseURL
+	}
+	return ollamaProvider{baseURL: baseURL, model: model}, nil
+}
+
+func (p ollamaProvider) Name() string  { return "ollama" }
+func (p ollamaProvider) Model() string { return p.model }
+
+// ClassifySections constrains the reply with the same JSON schema used for
+// the OpenAI responses API; Ollama enforces it via the "format" field.
+func (p ollamaProvider) ClassifySections(systemPrompt, userPrompt string, options []SectionOption) (string, LLMUsage, error) {
+	return callOllamaChat(p.baseURL, p.model, systemPrompt, userPrompt, buildSectionJSONSchema(options))
+}
+
+func (p ollamaProvider) Complete(systemPrompt, userPrompt string) (string, LLMUsage, error) {
+	return callOllamaChat(p.baseURL, p.model, systemPrompt, userPrompt, nil)
+}
+
+func (p ollamaProvider) CompleteStructured(systemPrompt, userPrompt string, output StructuredOutput) (string, LLMUsage, error) {
+	return callOllamaChat(p.baseURL, p.model, systemPrompt, userPrompt, output.Schema)
+}
+
+func callOllamaChat(baseURL, model, systemPrompt, userPrompt string, format any) (string, LLMUsage, error) {
+	reqBody := ollamaChatRequest{
+		Model:  model,
+		Stream: false,
+		Format: format,
+		// Deterministic output keeps classification stable between runs.
+		Options: map[string]any{"temperature": 0},
+	}
+	if strings.TrimSpace(systemPrompt) != "" {
+		reqBody.Messages = append(reqBody.Messages, ollamaChatMessage{Role: "system", Content: systemPrompt})
+	}
+	reqBody.Messages = append(reqBody.Messages, ollamaChatMessage{Role: "user", Content: userPrompt})
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", LLMUsage{}, fmt.Errorf("marshaling ollama request: %w", err)
+	}
+	req, err := http.NewRequest("POST", strings.TrimRight(baseURL, "/")+"/api/chat", bytes.NewReader(bodyBytes))
+	if err != nil {
+		return "", LLMUsage{}, fmt.Errorf("creating ollama request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := externalHTTPClient.Do(req)
+	if err != nil {
+		log.Printf("llm ollama error: %v", err)
+		return "", LLMUsage{}, fmt.Errorf("Ollama API error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", LLMUsage{}, fmt.Errorf("reading ollama body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		statusErr := newAPIStatusError("Ollama API", resp, respBody)
+		log.Printf("llm ollama HTTP %d: %s", resp.StatusCode, statusErr.Body)
+		return "", LLMUsage{}, statusErr
+	}
+
+	var chatResp ollamaChatResponse
+	if err := json.Unmarshal(respBody, &chatResp); err != nil {
+		truncated := string(respBody)
+		if len(truncated) > 512 {
+			truncated = truncated[:512] + "..."
+		}
+		return "", LLMUsage{}, fmt.Errorf("parsing Ollama payload: %w (body: %s)", err, truncated)
+	}
+	if chatResp.Error != "" {
+		return "", LLMUsage{}, fmt.Errorf("Ollama API error: %s", chatResp.Error)
+	}
+
+	usage := LLMUsage{
+		InputTokens:  chatResp.PromptEvalCount,
+		OutputTokens: chatResp.EvalCount,
+	}
+	text := chatResp.Message.Content
+	if strings.TrimSpace(text) == "" {
+		return "", usage, fmt.Errorf("no content in Ollama response")
+	}
+	log.Printf("llm ollama response size=%d tokens_in=%d tokens_out=%d", len(text), usage.InputTokens, usage.OutputTokens)
+	return text, usage, nil
+}
@@ -0,0 +1,97 @@
+package llm
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOllamaProvider_ClassifySendsSchemaAndReportsUsage(t *testing.T) {
+	var got ollamaChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"model":             got.Model,
+			"message":           map[string]string{"role": "assistant", "content": `[{"id":1,"section_id":"S0_0","normalized_status":"done","ticket_ids":[],"duplicate_of":""}]`},
+			"done":              true,
+			"prompt_eval_count": 120,
+			"eval_count":        30,
+		})
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(Config{LLMProvider: "ollama", OllamaBaseURL: server.URL + "/"})
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	if provider.Model() != defaultOllamaModel {
+		t.Fatalf("expected default model, got %q", provider.Model())
+	}
+
+	text, usage, err := provider.ClassifySections("system", "user", []SectionOption{{ID: "S0_0", Label: "Infra"}})
+	if err != nil {
+		t.Fatalf("ClassifySections: %v", err)
+	}
+	if _, err := parseSectionClassifiedResponse(text); err != nil {
+		t.Fatalf("unexpected response text %q: %v", text, err)
+	}
+	if usage.InputTokens != 120 || usage.OutputTokens != 30 {
+		t.Fatalf("unexpected usage: %+v", usage)
+	}
+	if got.Stream {
+		t.Fatal("expected non-streaming request")
+	}
+	if len(got.Messages) != 2 || got.Messages[0].Role != "system" || got.Messages[1].Role != "user" {
+		t.Fatalf("unexpected messages: %+v", got.Messages)
+	}
+	schema, ok := got.Format.(map[string]any)
+	if !ok || schema["type"] != "array" {
+		t.Fatalf("expected JSON schema format, got %#v", got.Format)
+	}
+}
+
+func TestOllamaProvider_CompleteOmitsFormat(t *testing.T) {
+	var raw map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&raw)
+		_, _ = w.Write([]byte(`{"message":{"role":"assistant","content":"[]"},"done":true}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(Config{LLMProvider: "ollama", OllamaBaseURL: server.URL, LLMModel: "qwen2.5"})
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	text, _, err := provider.Complete("", "review")
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if text != "[]" {
+		t.Fatalf("unexpected text %q", text)
+	}
+	if _, ok := raw["format"]; ok {
+		t.Fatalf("free-form completion should not send format: %#v", raw["format"])
+	}
+	if raw["model"] != "qwen2.5" {
+		t.Fatalf("expected configured model, got %v", raw["model"])
+	}
+}
+
+func TestOllamaProvider_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"model not found"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	provider, _ := NewProvider(Config{LLMProvider: "ollama", OllamaBaseURL: server.URL})
+	if _, _, err := provider.Complete("", "x"); err == nil {
+		t.Fatal("expected error for HTTP 404")
+	}
+}
@@ -0,0 +1,161 @@
+package llm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Provider is an LLM backend. Implementations are registered by name with
+// RegisterProvider and selected at runtime through cfg.LLMProvider, so call
+// sites never switch on the provider name themselves.
+type Provider interface {
+	// Name returns the registered provider name, e.g. "anthropic".
+	Name() string
+	// Model returns the model used for requests, with provider defaults applied.
+	Model() string
+	// ClassifySections returns the raw JSON array of section decisions for one
+	// batch. Implementations should constrain output to the section IDs in
+	// options when the backend supports schema-constrained output.
+	ClassifySections(systemPrompt, userPrompt string, options []SectionOption) (string, LLMUsage, error)
+	// Complete runs a free-form completion (critic pass, retrospective).
+	Complete(systemPrompt, userPrompt string) (string, LLMUsage, error)
+}
+
+// ProviderFactory builds a Provider from config.
+type ProviderFactory func(cfg Config) (Provider, error)
+
+const defaultProviderName = "anthropic"
+
+var providerRegistry = struct {
+	sync.RWMutex
+	factories map[string]ProviderFactory
+}{factories: make(map[string]ProviderFactory)}
+
+// RegisterProvider makes a provider available under name. Registering the
+// same name twice replaces the earlier factory.
+func RegisterProvider(name string, factory ProviderFactory) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || factory == nil {
+		return
+	}
+	providerRegistry.Lock()
+	providerRegistry.factories[name] = factory
+	providerRegistry.Unlock()
+}
+
+// UnregisterProvider removes name from the registry, so tests can drop the
+// fakes they register.
+func UnregisterProvider(name string) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	providerRegistry.Lock()
+	delete(providerRegistry.factories, name)
+	providerRegistry.Unlock()
+}
+
+// RegisteredProviders returns the sorted names of all registered providers.
+func RegisteredProviders() []string {
+	providerRegistry.RLock()
+	defer providerRegistry.RUnlock()
+	names := make([]string, 0, len(providerRegistry.factories))
+	for name := range providerRegistry.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewProvider builds the provider selected by cfg.LLMProvider. Factories
+// apply their own defaults and reject missing settings, so startup validates
+// llm_provider by calling NewProvider rather than switching on known names.
+func NewProvider(cfg Config) (Provider, error) {
+	name := strings.ToLower(strings.TrimSpace(cfg.LLMProvider))
+	if name == "" {
+		name = defaultProviderName
+	}
+	providerRegistry.RLock()
+	factory, ok := providerRegistry.factories[name]
+	providerRegistry.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown llm_provider %q (registered: %s)", cfg.LLMProvider, strings.Join(RegisteredProviders(), ", "))
+	}
+	return factory(cfg)
+}
+
+func init() {
+	RegisterProvider("anthropic", newAnthropicProvider)
+	RegisterProvider("openai", newOpenAIProvider)
+	RegisterProvider("ollama", newOllamaProvider)
+}
+
+// --- Anthropic ---
+
+type anthropicProvider struct {
+	apiKey  string
+	model   string
+	baseURL string // empty uses the SDK default; set by tests
+}
+
+func newAnthropicProvider(cfg Config) (Provider, error) {
+	model := cfg.LLMModel
+	if model == "" {
+		model = defaultAnthropicModel
+	}
+	if cfg.AnthropicAPIKey == "" {
+		return nil, fmt.Errorf("anthropic_api_key is required when llm_provider=anthropic")
+	}
+	return anthropicProvider{apiKey: cfg.AnthropicAPIKey, model: model}, nil
+}
+
+func (p anthropicProvider) Name() string  { return "anthropic" }
+func (p anthropicProvider) Model() string { return p.model }
+
+// ClassifySections answers through a forced tool call whose input schema is
+// the section enum, so the reply never needs to be scraped from text.
+func (p anthropicProvider) ClassifySections(systemPrompt, userPrompt string, options []SectionOption) (string, LLMUsage, error) {
+	return callAnthropicTool(p.apiKey, p.baseURL, p.model, systemPrompt, userPrompt, sectionOutput(options))
+}
+
+func (p anthropicProvider) Complete(systemPrompt, userPrompt string) (string, LLMUsage, error) {
+	return callAnthropic(p.apiKey, p.baseURL, p.model, systemPrompt, userPrompt)
+}
+
+func (p anthropicProvider) CompleteStructured(systemPrompt, userPrompt string, output StructuredOutput) (string, LLMUsage, error) {
+	return callAnthropicTool(p.apiKey, p.baseURL, p.model, systemPrompt, userPrompt, output)
+}
+
+// --- OpenAI / OpenAI-compatible ---
+
+type openAIProvider struct {
+	apiKey   string
+	baseURL  string
+	model    string
+	logprobs bool
+}
+
+func newOpenAIProvider(cfg Config) (Provider, error) {
+	model := cfg.LLMModel
+	if model == "" {
+		model = defaultOpenAIModel
+	}
+	if cfg.OpenAIAPIKey == "" {
+		return nil, fmt.Errorf("openai_api_key is required when llm_provider=openai")
+	}
+	return openAIProvider{apiKey: cfg.OpenAIAPIKey, baseURL: cfg.OpenAIBaseURL, model: model, logprobs: cfg.OpenAILogprobs}, nil
+}
+
+func (p openAIProvider) Name() string  { return "openai" }
+func (p openAIProvider) Model() string { return p.model }
+
+func (p openAIProvider) ClassifySections(systemPrompt, userPrompt string, options []SectionOption) (string, LLMUsage, error) {
+	return callOpenAISectionStructured(p.apiKey, p.baseURL, p.model, systemPrompt, userPrompt, options, p.logprobs)
+}
+
+func (p openAIProvider) Complete(systemPrompt, userPrompt string) (string, LLMUsage, error) {
+	return callOpenAI(p.apiKey, p.baseURL, p.model, systemPrompt, userPrompt)
+}
+
+func (p openAIProvider) CompleteStructured(systemPrompt, userPrompt string, output StructuredOutput) (string, LLMUsage, error) {
+	return callOpenAIStructured(p.apiKey, p.baseURL, p.model, systemPrompt, userPrompt, output)
+}
@@ -0,0 +1,139 @@
+package llm
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+type fakeProvider struct {
+	mu             sync.Mutex
+	classifyText   string
+	completeText   string
+	usage          LLMUsage
+	classifyCalls  int
+	completeCalls  int
+	lastSystem     string
+	lastUserPrompt string
+}
+
+func (f *fakeProvider) Name() string  { return "fake" }
+func (f *fakeProvider) Model() string { return "fake-model" }
+
+func (f *fakeProvider) ClassifySections(systemPrompt, userPrompt string, _ []SectionOption) (string, LLMUsage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.classifyCalls++
+	f.lastSystem = systemPrompt
+	f.lastUserPrompt = userPrompt
+	return f.classifyText, f.usage, nil
+}
+
+func (f *fakeProvider) Complete(systemPrompt, userPrompt string) (string, LLMUsage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.completeCalls++
+	f.lastSystem = systemPrompt
+	f.lastUserPrompt = userPrompt
+	return f.completeText, f.usage, nil
+}
+
+func TestNewProvider_BuiltinsAndDefault(t *testing.T) {
+	p, err := NewProvider(Config{AnthropicAPIKey: "sk-ant"})
+	if err != nil {
+		t.Fatalf("NewProvider default: %v", err)
+	}
+	if p.Name() != "anthropic" || p.Model() != defaultAnthropicModel {
+		t.Fatalf("expected default anthropic provider, got %s/%s", p.Name(), p.Model())
+	}
+
+	p, err = NewProvider(Config{LLMProvider: "OpenAI", OpenAIAPIKey: "sk", LLMModel: "custom"})
+	if err != nil {
+		t.Fatalf("NewProvider openai: %v", err)
+	}
+	if p.Name() != "openai" || p.Model() != "custom" {
+		t.Fatalf("expected openai/custom, got %s/%s", p.Name(), p.Model())
+	}
+
+	p, err = NewProvider(Config{LLMProvider: "ollama"})
+	if err != nil {
+		t.Fatalf("NewProvider ollama: %v", err)
+	}
+	if got := p.(ollamaProvider).baseURL; got != defaultOllamaBaseURL {
+		t.Fatalf("expected default Ollama base URL, got %q", got)
+	}
+
+	if _, err := NewProvider(Config{LLMProvider: "nope"}); err == nil {
+		t.Fatal("expected error for unknown provider")
+	}
+	if _, err := NewProvider(Config{LLMProvider: "anthropic"}); err == nil || !strings.Contains(err.Error(), "anthropic_api_key") {
+		t.Fatalf("expected missing anthropic key error, got %v", err)
+	}
+	if _, err := NewProvider(Config{LLMProvider: "openai"}); err == nil || !strings.Contains(err.Error(), "openai_api_key") {
+		t.Fatalf("expected missing openai key error, got %v", err)
+	}
+}
+
+func TestNewProvider_AcceptsRegisteredCustomProvider(t *testing.T) {
+	RegisterProvider("fake-custom", func(Config) (Provider, error) { return &fakeProvider{}, nil })
+	t.Cleanup(func() { UnregisterProvider("fake-custom") })
+
+	if _, err := NewProvider(Config{LLMProvider: "fake-custom"}); err != nil {
+		t.Fatalf("expected registered provider to validate, got %v", err)
+	}
+	UnregisterProvider("fake-custom")
+	_, err := NewProvider(Config{LLMProvider: "fake-custom"})
+	if err == nil || !strings.Contains(err.Error(), "registered: anthropic, ollama, openai") {
+		t.Fatalf("expected unknown provider error listing the registry, got %v", err)
+	}
+}
+
+func TestCategorizeItemsToSections_UsesRegisteredProvider(t *testing.T) {
+	fake := &fakeProvider{
+		classifyText: `[{"id": 1, "section_id": "S0_0", "normalized_status": "done", "ticket_ids": [], "duplicate_of": ""}]`,
+		usage:        LLMUsage{InputTokens: 10, OutputTokens: 5},
+	}
+	RegisterProvider("fake-classify", func(Config) (Provider, error) { return fake, nil })
+	t.Cleanup(func() { UnregisterProvider("fake-classify") })
+
+	cfg := Config{LLMProvider: "fake-classify", LLMBatchSize: 10}
+	options := []sectionOption{{ID: "S0_0", Label: "Infra"}}
+	items := []WorkItem{{ID: 1, Description: "Upgrade cluster", Status: "done"}}
+
+	decisions, usage, err := CategorizeItemsToSections(cfg, items, options, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CategorizeItemsToSections: %v", err)
+	}
+	if fake.classifyCalls != 1 {
+		t.Fatalf("expected one classify call, got %d", fake.classifyCalls)
+	}
+	if decisions[1].SectionID != "S0_0" {
+		t.Fatalf("expected S0_0, got %q", decisions[1].SectionID)
+	}
+	if usage.TotalTokens() != 15 {
+		t.Fatalf("expected usage to be reported, got %d", usage.TotalTokens())
+	}
+	if !strings.Contains(fake.lastUserPrompt, "ID:1 - Upgrade cluster") {
+		t.Fatalf("expected item in prompt, got %s", fake.lastUserPrompt)
+	}
+}
+
+func TestCategorizeItemsToSections_CriticUsesProviderCompletion(t *testing.T) {
+	fake := &fakeProvider{
+		classifyText: `[{"id": 1, "section_id": "S0_0", "normalized_status": "done", "ticket_ids": [], "duplicate_of": ""}]`,
+		completeText: `[{"id": 1, "reason": "database work", "suggested_section_id": "S1_0"}]`,
+	}
+	options := []sectionOption{{ID: "S0_0", Label: "Infra"}, {ID: "S1_0", Label: "Database"}}
+	items := []WorkItem{{ID: 1, Description: "Tune postgres", Status: "done"}}
+
+	decisions, _, err := CategorizeItemsToSectionsWithProvider(fake, Config{LLMCriticEnabled: true}, items, options, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CategorizeItemsToSectionsWithProvider: %v", err)
+	}
+	if fake.completeCalls != 1 {
+		t.Fatalf("expected one critic completion, got %d", fake.completeCalls)
+	}
+	if decisions[1].SectionID != "S1_0" {
+		t.Fatalf("expected critic to move item to S1_0, got %q", decisions[1].SectionID)
+	}
+}
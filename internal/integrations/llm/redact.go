@@ -0,0 +1,271 @@
+package llm
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Built-in redaction rules applied when llm_redaction_enabled is set. They
+// run before configured rules and the customer-name list.
+var builtinRedactionRules = []struct {
+	name    string
+	pattern string
+}{
+	{"SECRET", `(?i)\bbearer\s+[A-Za-z0-9._~+/\-]{16,}=*|\b(?:glpat-[A-Za-z0-9_\-]{20,}|gh[pousr]_[A-Za-z0-9]{30,}|xox[abposr]-[A-Za-z0-9\-]{10,}|sk-[A-Za-z0-9_\-]{20,}|AKIA[0-9A-Z]{16})\b`},
+	{"EMAIL", `\b[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}\b`},
+	{"IP", `\b(?:\d{1,3}\.){3}\d{1,3}(?:/\d{1,2})?\b`},
+	{"HOST", `(?i)\b(?:[a-z0-9](?:[a-z0-9\-]*[a-z0-9])?\.)+(?:internal|corp|local|lan|intra)\b`},
+}
+
+type redactionRule struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// placeholderRe matches text shaped like a placeholder. Only placeholders the
+// redactor issued are protected from later rules; see Redact.
+var placeholderRe = regexp.MustCompile(`\[[A-Z0-9_]+_\d+\]`)
+
+// redactor replaces sensitive substrings with placeholders such as [IP_1]
+// and maps them back afterwards. The same value always gets the same
+// placeholder within one redactor, so the model can still tell that two
+// items mention the same customer. Safe for concurrent use by batches.
+type redactor struct {
+	mu      sync.Mutex
+	rules   []redactionRule
+	forward map[string]string // rule name + normalized value -> placeholder
+	reverse map[string]string // placeholder -> original value
+	next    map[string]int
+	counts  map[string]int
+}
+
+// newRedactor builds the redactor for cfg, or returns nil when redaction is
+// disabled. A nil redactor passes text through unchanged.
+func newRedactor(cfg Config) (*redactor, error) {
+	if !cfg.LLMRedactionEnabled {
+		return nil, nil
+	}
+	r := &redactor{
+		forward: make(map[string]string),
+		reverse: make(map[string]string),
+		next:    make(map[string]int),
+		counts:  make(map[string]int),
+	}
+	for _, b := range builtinRedactionRules {
+		r.rules = append(r.rules, redactionRule{name: b.name, re: regexp.MustCompile(b.pattern)})
+	}
+	for _, c := range cfg.LLMRedactionRules {
+		re, err := regexp.Compile(c.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("redaction rule %q: %w", c.Name, err)
+		}
+		r.rules = append(r.rules, redactionRule{name: placeholderName(c.Name), re: re})
+	}
+	if strings.TrimSpace(cfg.LLMRedactionNamesPath) != "" {
+		names, err := loadRedactionNames(cfg.LLMRedactionNamesPath)
+		if err != nil {
+			return nil, err
+		}
+		if re := customerNamesPattern(names); re != nil {
+			r.rules = append(r.rules, redactionRule{name: "CUSTOMER", re: re})
+		}
+	}
+	return r, nil
+}
+
+func placeholderName(name string) string {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	return strings.Map(func(c rune) rune {
+		if (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			return c
+		}
+		return '_'
+	}, name)
+}
+
+// loadRedactionNames reads one name per line; blank lines and lines
+// starting with # are ignored.
+func loadRedactionNames(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read redaction names: %w", err)
+	}
+	defer f.Close()
+	var names []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		names = append(names, line)
+	}
+	return names, scanner.Err()
+}
+
+// customerNamesPattern matches any listed name as a whole word, ignoring
+// case. Longer names come first so "Acme Bank" wins over "Acme".
+func customerNamesPattern(names []string) *regexp.Regexp {
+	if len(names) == 0 {
+		return nil
+	}
+	sorted := append([]string(nil), names...)
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	quoted := make([]string, len(sorted))
+	for i, n := range sorted {
+		quoted[i] = regexp.QuoteMeta(n)
+	}
+	return regexp.MustCompile(`(?i)(?:^|\b)(?:` + strings.Join(quoted, "|") + `)(?:\b|$)`)
+}
+
+// Redact replaces every rule match in s with its placeholder. Rules run in
+// order, and each one only sees the text between placeholders inserted so
+// far, so a later rule (say `\d+`) cannot rewrite "[IP_1]" into a nested
+// placeholder that Restore could not map back.
+func (r *redactor) Redact(s string) string {
+	if r == nil || s == "" {
+		return s
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rule := range r.rules {
+		var b strings.Builder
+		last := 0
+		for _, loc := range placeholderRe.FindAllStringIndex(s, -1) {
+			if _, issued := r.reverse[s[loc[0]:loc[1]]]; !issued {
+				continue
+			}
+			b.WriteString(r.replaceLocked(rule, s[last:loc[0]]))
+			b.WriteString(s[loc[0]:loc[1]])
+			last = loc[1]
+		}
+		b.WriteString(r.replaceLocked(rule, s[last:]))
+		s = b.String()
+	}
+	return s
+}
+
+// replaceLocked replaces rule's matches in s; the caller holds r.mu.
+func (r *redactor) replaceLocked(rule redactionRule, s string) string {
+	if s == "" {
+		return s
+	}
+	return rule.re.ReplaceAllStringFunc(s, func(match string) string {
+		key := rule.name + "\x00" + strings.ToLower(match)
+		placeholder, ok := r.forward[key]
+		if !ok {
+			r.next[rule.name]++
+			placeholder = fmt.Sprintf("[%s_%d]", rule.name, r.next[rule.name])
+			r.forward[key] = placeholder
+			r.reverse[placeholder] = match
+		}
+		r.counts[rule.name]++
+		return placeholder
+	})
+}
+
+// Restore maps placeholders in s back to the original values.
+func (r *redactor) Restore(s string) string {
+	if r == nil || s == "" || !strings.Contains(s, "[") {
+		return s
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.reverse) == 0 {
+		return s
+	}
+	pairs := make([]string, 0, 2*len(r.reverse))
+	for placeholder, original := range r.reverse {
+		pairs = append(pairs, placeholder, original)
+	}
+	return strings.NewReplacer(pairs...).Replace(s)
+}
+
+// logCounts writes one line per run with how many matches each rule
+// replaced; values themselves are never logged.
+func (r *redactor) logCounts(scope string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	total := 0
+	names := make([]string, 0, len(r.counts))
+	for name, n := range r.counts {
+		total += n
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s=%d", strings.ToLower(name), r.counts[name])
+	}
+	log.Printf("llm redaction scope=%s replaced=%d distinct=%d %s", scope, total, len(r.reverse), strings.Join(parts, " "))
+}
+
+func (r *redactor) redactItems(items []WorkItem) []WorkItem {
+	if r == nil {
+		return items
+	}
+	out := make([]WorkItem, len(items))
+	for i, item := range items {
+		item.Description = r.Redact(item.Description)
+		out[i] = item
+	}
+	return out
+}
+
+func (r *redactor) redactExisting(existing []existingItemContext) []existingItemContext {
+	if r == nil {
+		return existing
+	}
+	out := make([]existingItemContext, len(existing))
+	for i, e := range existing {
+		e.Description = r.Redact(e.Description)
+		out[i] = e
+	}
+	return out
+}
+
+func (r *redactor) redactCorrections(corrections []ClassificationCorrection) []ClassificationCorrection {
+	if r == nil {
+		return corrections
+	}
+	out := make([]ClassificationCorrection, len(corrections))
+	for i, c := range corrections {
+		c.Description = r.Redact(c.Description)
+		out[i] = c
+	}
+	return out
+}
+
+func (r *redactor) redactHistorical(items []historicalItem) []historicalItem {
+	if r == nil {
+		return items
+	}
+	out := make([]historicalItem, len(items))
+	for i, h := range items {
+		h.Description = r.Redact(h.Description)
+		out[i] = h
+	}
+	return out
+}
+
+// restoreDecisions maps placeholders back in the free-text fields the model
+// copies from its input.
+func (r *redactor) restoreDecisions(decisions map[int64]LLMSectionDecision) {
+	if r == nil {
+		return
+	}
+	for id, d := range decisions {
+		d.DuplicateOf = r.Restore(d.DuplicateOf)
+		d.TicketIDs = r.Restore(d.TicketIDs)
+		decisions[id] = d
+	}
+}
@@ -0,0 +1,139 @@
+package llm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRedactorPlaceholdersAreStableAndReversible(t *testing.T) {
+	red, err := newRedactor(Config{
+		LLMRedactionEnabled: true,
+		LLMRedactionRules:   []RedactionRule{{Name: "hostname", Pattern: `\bprod-db-\d+\b`}},
+	})
+	if err != nil {
+		t.Fatalf("newRedactor: %v", err)
+	}
+	in := "JIRA-123: ask alice@example.com about 10.0.4.17 and prod-db-3; Alice@Example.com again"
+	out := red.Redact(in)
+	for _, leaked := range []string{"alice@", "10.0.4.17", "prod-db-3"} {
+		if strings.Contains(strings.ToLower(out), leaked) {
+			t.Fatalf("expected %q redacted, got %q", leaked, out)
+		}
+	}
+	if !strings.Contains(out, "JIRA-123") {
+		t.Fatalf("expected ticket ID kept, got %q", out)
+	}
+	if strings.Count(out, "[EMAIL_1]") != 2 || !strings.Contains(out, "[IP_1]") || !strings.Contains(out, "[HOSTNAME_1]") {
+		t.Fatalf("unexpected placeholders: %q", out)
+	}
+	if got := red.Restore("[IP_1] and [HOSTNAME_1]"); got != "10.0.4.17 and prod-db-3" {
+		t.Fatalf("unexpected restore: %q", got)
+	}
+}
+
+func TestRedactorSkipsEarlierPlaceholders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "customers.txt")
+	if err := os.WriteFile(path, []byte("Build\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	red, err := newRedactor(Config{
+		LLMRedactionEnabled: true,
+		// Both rules match inside placeholders issued before them.
+		LLMRedactionRules: []RedactionRule{
+			{Name: "build", Pattern: `\d+`},
+			{Name: "tag", Pattern: `\[[A-Z]+_`},
+		},
+		LLMRedactionNamesPath: path,
+	})
+	if err != nil {
+		t.Fatalf("newRedactor: %v", err)
+	}
+	in := "Build 42 on 10.0.4.17 mailed to ops@example.com, see [DRAFT_ notes"
+	out := red.Redact(in)
+	if want := "[CUSTOMER_1] [BUILD_1] on [IP_1] mailed to [EMAIL_1], see [TAG_1] notes"; out != want {
+		t.Fatalf("unexpected redaction:\n got %q\nwant %q", out, want)
+	}
+	if got := red.Restore(out); got != in {
+		t.Fatalf("round trip lost text:\n got %q\nwant %q", got, in)
+	}
+	if again := red.Redact(out); again != out {
+		t.Fatalf("expected redacted text to be left alone, got %q", again)
+	}
+}
+
+func TestRedactorDisabledIsNil(t *testing.T) {
+	red, err := newRedactor(Config{})
+	if err != nil || red != nil {
+		t.Fatalf("expected nil redactor when disabled, got %v, %v", red, err)
+	}
+	if got := red.Redact("alice@example.com"); got != "alice@example.com" {
+		t.Fatalf("expected passthrough, got %q", got)
+	}
+}
+
+func TestRedactorCustomerNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "customers.txt")
+	if err := os.WriteFile(path, []byte("# customers\nAcme\nAcme Bank\n\nGlobex\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	red, err := newRedactor(Config{LLMRedactionEnabled: true, LLMRedactionNamesPath: path})
+	if err != nil {
+		t.Fatalf("newRedactor: %v", err)
+	}
+	out := red.Redact("Migrate acme bank ledger for Globex, unlike Acmeish")
+	if out != "Migrate [CUSTOMER_1] ledger for [CUSTOMER_2], unlike Acmeish" {
+		t.Fatalf("unexpected redaction: %q", out)
+	}
+	if got := red.Restore(out); got != "Migrate acme bank ledger for Globex, unlike Acmeish" {
+		t.Fatalf("unexpected restore: %q", got)
+	}
+
+	if _, err := newRedactor(Config{LLMRedactionEnabled: true, LLMRedactionNamesPath: filepath.Join(t.TempDir(), "missing")}); err == nil {
+		t.Fatal("expected error for missing names file")
+	}
+}
+
+type capturingProvider struct {
+	scriptedProvider
+	promptMu sync.Mutex
+	prompts  []string
+}
+
+func (p *capturingProvider) ClassifySections(system, user string, options []SectionOption) (string, LLMUsage, error) {
+	p.promptMu.Lock()
+	p.prompts = append(p.prompts, system+"\n"+user)
+	p.promptMu.Unlock()
+	return p.scriptedProvider.ClassifySections(system, user, options)
+}
+
+func TestCategorizeRedactsPromptsAndRestoresDecisions(t *testing.T) {
+	provider := &capturingProvider{scriptedProvider: scriptedProvider{steps: []func() (string, LLMUsage, error){
+		func() (string, LLMUsage, error) {
+			return `[{"id":1,"section_id":"S0_0","normalized_status":"done","ticket_ids":[],"duplicate_of":"Rotate keys on [IP_1]","confidence":0.9,"alternative_section_ids":[]}]`,
+				LLMUsage{}, nil
+		},
+	}}}
+	options := []SectionOption{{ID: "S0_0", Label: "Infra"}}
+	cfg := Config{LLMBatchSize: 10, LLMRedactionEnabled: true}
+	items := []WorkItem{{ID: 1, Description: "Rotate keys on 192.168.1.20 for bob@corp.example.com", Status: "done"}}
+	existing := []existingItemContext{{Key: "K1", SectionID: "S0_0", Description: "Rotate keys on 192.168.1.20"}}
+
+	decisions, _, err := CategorizeItemsToSectionsWithProvider(provider, cfg, items, options, existing, nil, nil)
+	if err != nil {
+		t.Fatalf("CategorizeItemsToSectionsWithProvider: %v", err)
+	}
+	if len(provider.prompts) != 1 {
+		t.Fatalf("expected one prompt, got %d", len(provider.prompts))
+	}
+	for _, leaked := range []string{"192.168.1.20", "bob@"} {
+		if strings.Contains(provider.prompts[0], leaked) {
+			t.Fatalf("prompt leaked %q:\n%s", leaked, provider.prompts[0])
+		}
+	}
+	if got := decisions[1].DuplicateOf; got != "Rotate keys on 192.168.1.20" {
+		t.Fatalf("expected duplicate_of restored, got %q", got)
+	}
+}
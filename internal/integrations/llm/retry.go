@@ -0,0 +1,159 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// Per-batch retry policy for section classification. Delays double on each
+// attempt up to llmRetryMaxDelay; a server-provided Retry-After wins.
+const llmBatchMaxAttempts = 3
+
+var (
+	llmRetryBaseDelay = 2 * time.Second
+	llmRetryMaxDelay  = 60 * time.Second
+	retrySleep        = time.Sleep
+)
+
+// apiStatusError is a non-2xx reply from an HTTP LLM backend. It keeps the
+// status and Retry-After so the batch retry loop can decide how to back off.
+type apiStatusError struct {
+	API        string
+	StatusCode int
+	RetryAfter time.Duration
+	Body       string
+}
+
+func (e *apiStatusError) Error() string {
+	return fmt.Sprintf("%s HTTP %d: %s", e.API, e.StatusCode, e.Body)
+}
+
+func newAPIStatusError(api string, resp *http.Response, body []byte) *apiStatusError {
+	truncated := string(body)
+	if len(truncated) > 512 {
+		truncated = truncated[:512] + "..."
+	}
+	return &apiStatusError{
+		API:        api,
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		Body:       truncated,
+	}
+}
+
+// parseRetryAfter accepts both forms allowed by RFC 9110: delay-seconds and
+// an HTTP-date. Unparseable or past values yield zero.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := at.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusRequestTimeout || code >= 500
+}
+
+// retryDecision reports whether err is worth retrying and any delay the
+// server asked for. Transport errors, timeouts and malformed model output are
+// retried; client errors such as 400/401/403 are not.
+func retryDecision(err error) (bool, time.Duration) {
+	var statusErr *apiStatusError
+	if errors.As(err, &statusErr) {
+		return retryableStatus(statusErr.StatusCode), statusErr.RetryAfter
+	}
+	var anthropicErr *anthropic.Error
+	if errors.As(err, &anthropicErr) {
+		var retryAfter time.Duration
+		if anthropicErr.Response != nil {
+			retryAfter = parseRetryAfter(anthropicErr.Response.Header.Get("Retry-After"), time.Now())
+		}
+		return retryableStatus(anthropicErr.StatusCode), retryAfter
+	}
+	return true, 0
+}
+
+// rejectedRequest reports whether err means the LLM is misconfigured (bad
+// credentials, no access, unknown model or endpoint), so every batch will
+// fail the same way. Other client errors, such as a 400 for one batch's
+// oversized prompt, are specific to that batch.
+func rejectedRequest(err error) bool {
+	var statusErr *apiStatusError
+	var anthropicErr *anthropic.Error
+	switch {
+	case errors.As(err, &statusErr):
+		return configStatus(statusErr.StatusCode)
+	case errors.As(err, &anthropicErr):
+		return configStatus(anthropicErr.StatusCode)
+	}
+	return false
+}
+
+func configStatus(code int) bool {
+	return code == http.StatusUnauthorized || code == http.StatusForbidden || code == http.StatusNotFound
+}
+
+func backoffDelay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		if retryAfter > llmRetryMaxDelay {
+			return llmRetryMaxDelay
+		}
+		return retryAfter
+	}
+	delay := llmRetryBaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= llmRetryMaxDelay {
+			return llmRetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// classifyBatchWithRetry calls the provider and parses its reply, retrying
+// both API failures and unparseable responses. Usage from failed attempts is
+// still returned since those tokens were billed.
+func classifyBatchWithRetry(provider Provider, batchIdx int, systemPrompt, userPrompt string, options []sectionOption) (map[int64]LLMSectionDecision, LLMUsage, error) {
+	total := LLMUsage{}
+	var lastErr error
+	for attempt := 1; attempt <= llmBatchMaxAttempts; attempt++ {
+		responseText, usage, err := provider.ClassifySections(systemPrompt, userPrompt, options)
+		total.Add(usage)
+		if err == nil {
+			parsed, parseErr := parseSectionClassifiedResponse(responseText)
+			if parseErr == nil {
+				return parsed, total, nil
+			}
+			err = parseErr
+		}
+		lastErr = err
+
+		retry, retryAfter := retryDecision(err)
+		if !retry || attempt == llmBatchMaxAttempts {
+			break
+		}
+		delay := backoffDelay(attempt, retryAfter)
+		log.Printf("llm section-classify batch=%d attempt=%d/%d failed, retrying in %s: %v", batchIdx, attempt, llmBatchMaxAttempts, delay, err)
+		retrySleep(delay)
+	}
+	return nil, total, lastErr
+}
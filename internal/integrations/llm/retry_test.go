@@ -0,0 +1,269 @@
+package llm
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type scriptedProvider struct {
+	mu    sync.Mutex
+	calls int
+	steps []func() (string, LLMUsage, error)
+}
+
+func (p *scriptedProvider) Name() string  { return "scripted" }
+func (p *scriptedProvider) Model() string { return "scripted-model" }
+
+func (p *scriptedProvider) ClassifySections(_, _ string, _ []SectionOption) (string, LLMUsage, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	step := p.steps[p.calls%len(p.steps)]
+	p.calls++
+	return step()
+}
+
+func (p *scriptedProvider) Complete(_, _ string) (string, LLMUsage, error) {
+	return "[]", LLMUsage{}, nil
+}
+
+func stubRetrySleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var mu sync.Mutex
+	var slept []time.Duration
+	orig := retrySleep
+	retrySleep = func(d time.Duration) {
+		mu.Lock()
+		slept = append(slept, d)
+		mu.Unlock()
+	}
+	t.Cleanup(func() { retrySleep = orig })
+	return &slept
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	if got := parseRetryAfter("7", now); got != 7*time.Second {
+		t.Fatalf("expected 7s, got %s", got)
+	}
+	if got := parseRetryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now); got != 30*time.Second {
+		t.Fatalf("expected 30s from HTTP date, got %s", got)
+	}
+	for _, v := range []string{"", "soon", "-3", now.Add(-time.Minute).Format(http.TimeFormat)} {
+		if got := parseRetryAfter(v, now); got != 0 {
+			t.Fatalf("expected 0 for %q, got %s", v, got)
+		}
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	if got := backoffDelay(1, 0); got != llmRetryBaseDelay {
+		t.Fatalf("attempt 1: got %s", got)
+	}
+	if got := backoffDelay(3, 0); got != 4*llmRetryBaseDelay {
+		t.Fatalf("attempt 3: got %s", got)
+	}
+	if got := backoffDelay(1, 5*time.Second); got != 5*time.Second {
+		t.Fatalf("retry-after should win, got %s", got)
+	}
+	if got := backoffDelay(20, 0); got != llmRetryMaxDelay {
+		t.Fatalf("expected cap, got %s", got)
+	}
+}
+
+func TestClassifyBatchWithRetry_HonoursRetryAfterThenSucceeds(t *testing.T) {
+	slept := stubRetrySleep(t)
+	provider := &scriptedProvider{steps: []func() (string, LLMUsage, error){
+		func() (string, LLMUsage, error) {
+			return "", LLMUsage{}, &apiStatusError{API: "test", StatusCode: http.StatusTooManyRequests, RetryAfter: 3 * time.Second}
+		},
+		func() (string, LLMUsage, error) {
+			return "not json", LLMUsage{InputTokens: 4}, nil
+		},
+		func() (string, LLMUsage, error) {
+			return `[{"id":1,"section_id":"S0_0","normalized_status":"done","ticket_ids":[],"duplicate_of":""}]`, LLMUsage{InputTokens: 10}, nil
+		},
+	}}
+
+	decisions, usage, err := classifyBatchWithRetry(provider, 0, "sys", "user", nil)
+	if err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if provider.calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", provider.calls)
+	}
+	if decisions[1].SectionID != "S0_0" {
+		t.Fatalf("unexpected decisions: %+v", decisions)
+	}
+	if usage.InputTokens != 14 {
+		t.Fatalf("expected usage from all attempts, got %d", usage.InputTokens)
+	}
+	if len(*slept) != 2 || (*slept)[0] != 3*time.Second || (*slept)[1] != 2*llmRetryBaseDelay {
+		t.Fatalf("unexpected backoff delays: %v", *slept)
+	}
+}
+
+func TestClassifyBatchWithRetry_DoesNotRetryClientErrors(t *testing.T) {
+	slept := stubRetrySleep(t)
+	provider := &scriptedProvider{steps: []func() (string, LLMUsage, error){
+		func() (string, LLMUsage, error) {
+			return "", LLMUsage{}, &apiStatusError{API: "test", StatusCode: http.StatusUnauthorized}
+		},
+	}}
+	_, _, err := classifyBatchWithRetry(provider, 0, "sys", "user", nil)
+	var statusErr *apiStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 error, got %v", err)
+	}
+	if provider.calls != 1 || len(*slept) != 0 {
+		t.Fatalf("expected no retries, calls=%d sleeps=%d", provider.calls, len(*slept))
+	}
+}
+
+func TestCategorizeItemsToSections_DegradesFailedBatch(t *testing.T) {
+	stubRetrySleep(t)
+	provider := &batchRoutingProvider{}
+	options := []sectionOption{{ID: "S0_0", Label: "Infra"}}
+	items := []WorkItem{
+		{ID: 1, Description: "Upgrade cluster", Status: "done"},
+		{ID: 2, Description: "Broken batch item", Status: "done"},
+	}
+
+	decisions, usage, err := CategorizeItemsToSectionsWithProvider(provider, Config{LLMBatchSize: 1}, items, options, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("expected degraded success, got %v", err)
+	}
+	if usage.DegradedBatches != 1 {
+		t.Fatalf("expected 1 degraded batch, got %d", usage.DegradedBatches)
+	}
+	if decisions[1].SectionID != "S0_0" {
+		t.Fatalf("expected healthy batch to classify, got %+v", decisions[1])
+	}
+	if _, ok := decisions[2]; ok {
+		t.Fatalf("expected failed batch item to be left undecided, got %+v", decisions[2])
+	}
+}
+
+// batchRoutingProvider succeeds for item 1 and always returns 503 for item 2.
+type batchRoutingProvider struct{}
+
+func (batchRoutingProvider) Name() string  { return "routing" }
+func (batchRoutingProvider) Model() string { return "routing-model" }
+
+func (batchRoutingProvider) ClassifySections(_, userPrompt string, _ []SectionOption) (string, LLMUsage, error) {
+	if strings.Contains(userPrompt, "ID:2 ") {
+		return "", LLMUsage{}, &apiStatusError{API: "test", StatusCode: http.StatusServiceUnavailable}
+	}
+	return `[{"id":1,"section_id":"S0_0","normalized_status":"done","ticket_ids":[],"duplicate_of":""}]`, LLMUsage{}, nil
+}
+
+func (batchRoutingProvider) Complete(_, _ string) (string, LLMUsage, error) {
+	return "[]", LLMUsage{}, nil
+}
+
+func TestDoOpenAIResponsesRequest_ExposesRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "12")
+		http.Error(w, `{"error":{"message":"rate limited"}}`, http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	_, _, err := doOpenAIResponsesRequest("sk-test", server.URL, openAIResponsesRequest{Model: "m", Input: "x"})
+	retry, retryAfter := retryDecision(err)
+	if !retry || retryAfter != 12*time.Second {
+		t.Fatalf("expected retryable 429 with 12s Retry-After, got retry=%t after=%s err=%v", retry, retryAfter, err)
+	}
+}
+
+func TestCategorizeItemsToSections_FailsOnRejectedRequest(t *testing.T) {
+	cases := []struct {
+		status int
+		body   string
+	}{
+		{http.StatusUnauthorized, "invalid api key"},
+		{http.StatusForbidden, "model access denied"},
+		{http.StatusNotFound, "model: claude-nope not found"},
+	}
+	for _, c := range cases {
+		t.Run(http.StatusText(c.status), func(t *testing.T) {
+			slept := stubRetrySleep(t)
+			provider := &scriptedProvider{steps: []func() (string, LLMUsage, error){
+				func() (string, LLMUsage, error) {
+					return "", LLMUsage{InputTokens: 3}, &apiStatusError{API: "test", StatusCode: c.status, Body: c.body}
+				},
+			}}
+			options := []sectionOption{{ID: "S0_0", Label: "Infra"}}
+			items := []WorkItem{{ID: 1, Description: "Upgrade cluster", Status: "done"}}
+
+			decisions, usage, err := CategorizeItemsToSectionsWithProvider(provider, Config{LLMBatchSize: 1}, items, options, nil, nil, nil)
+			var statusErr *apiStatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != c.status {
+				t.Fatalf("expected the %d to fail classification, got decisions=%+v err=%v", c.status, decisions, err)
+			}
+			if usage.InputTokens != 3 || provider.calls != 1 || len(*slept) != 0 {
+				t.Fatalf("expected one billed attempt and no retries, usage=%+v calls=%d sleeps=%d", usage, provider.calls, len(*slept))
+			}
+		})
+	}
+}
+
+func TestCategorizeItemsToSections_DegradesBatchSpecificClientErrors(t *testing.T) {
+	cases := []struct {
+		status int
+		body   string
+	}{
+		{http.StatusBadRequest, "prompt is too long: 210000 tokens > 200000 maximum"},
+		{http.StatusRequestEntityTooLarge, "request too large"},
+		{http.StatusUnprocessableEntity, "invalid schema for this batch"},
+	}
+	for _, c := range cases {
+		t.Run(http.StatusText(c.status), func(t *testing.T) {
+			slept := stubRetrySleep(t)
+			provider := &scriptedProvider{steps: []func() (string, LLMUsage, error){
+				func() (string, LLMUsage, error) {
+					return "", LLMUsage{}, &apiStatusError{API: "test", StatusCode: c.status, Body: c.body}
+				},
+			}}
+			options := []sectionOption{{ID: "S0_0", Label: "Infra"}}
+			items := []WorkItem{{ID: 1, Description: "Upgrade cluster", Status: "done"}}
+
+			decisions, usage, err := CategorizeItemsToSectionsWithProvider(provider, Config{LLMBatchSize: 1}, items, options, nil, nil, nil)
+			if err != nil {
+				t.Fatalf("expected the %d to degrade the batch, got %v", c.status, err)
+			}
+			if _, ok := decisions[1]; ok || usage.DegradedBatches != 1 {
+				t.Fatalf("expected the item left for Undetermined, decisions=%+v degraded=%d", decisions, usage.DegradedBatches)
+			}
+			if provider.calls != 1 || len(*slept) != 0 {
+				t.Fatalf("expected no retries of a client error, calls=%d sleeps=%d", provider.calls, len(*slept))
+			}
+		})
+	}
+}
+
+func TestAnthropicClientDoesNotRetry(t *testing.T) {
+	var mu sync.Mutex
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits++
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"type":"error","error":{"type":"overloaded_error","message":"overloaded"}}`))
+	}))
+	defer server.Close()
+
+	provider := anthropicProvider{apiKey: "test", model: "claude-test", baseURL: server.URL}
+	_, _, err := provider.ClassifySections("sys", "user", nil)
+	if retry, _ := retryDecision(err); err == nil || !retry {
+		t.Fatalf("expected a retryable 503, got %v", err)
+	}
+	if hits != 1 {
+		t.Fatalf("expected a single request with SDK retries disabled, got %d", hits)
+	}
+}
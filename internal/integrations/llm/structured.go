@@ -0,0 +1,170 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// StructuredOutput names the JSON schema a reply must conform to. Schema is
+// the shape the caller parses; providers wrap it as their API requires.
+type StructuredOutput struct {
+	Name        string
+	Description string
+	Schema      map[string]any
+}
+
+// StructuredCompleter is implemented by providers that can constrain a
+// free-form completion to a JSON schema. The critic pass and retrospective
+// use it when available and fall back to Complete otherwise.
+type StructuredCompleter interface {
+	CompleteStructured(systemPrompt, userPrompt string, output StructuredOutput) (string, LLMUsage, error)
+}
+
+func completeStructured(provider Provider, systemPrompt, userPrompt string, output StructuredOutput) (string, LLMUsage, error) {
+	if sc, ok := provider.(StructuredCompleter); ok {
+		return sc.CompleteStructured(systemPrompt, userPrompt, output)
+	}
+	return provider.Complete(systemPrompt, userPrompt)
+}
+
+func sectionOutput(options []sectionOption) StructuredOutput {
+	return StructuredOutput{
+		Name:        "section_classification_batch",
+		Description: "Record the section classification of every work item in the batch.",
+		Schema:      buildSectionJSONSchema(options),
+	}
+}
+
+func sectionIDEnum(options []sectionOption) []string {
+	sections := make([]string, 0, len(options)+1)
+	sections = append(sections, "UND")
+	for _, option := range options {
+		if id := strings.TrimSpace(option.ID); id != "" {
+			sections = append(sections, id)
+		}
+	}
+	return sections
+}
+
+func buildCriticJSONSchema(options []sectionOption) map[string]any {
+	return map[string]any{
+		"type": "array",
+		"items": map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"id":     map[string]any{"type": "integer"},
+				"reason": map[string]any{"type": "string"},
+				"suggested_section_id": map[string]any{
+					"type": "string",
+					"enum": sectionIDEnum(options),
+				},
+			},
+			"required": []string{"id", "reason", "suggested_section_id"},
+		},
+	}
+}
+
+func buildRetroJSONSchema() map[string]any {
+	return map[string]any{
+		"type": "array",
+		"items": map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"title":     map[string]any{"type": "string"},
+				"reasoning": map[string]any{"type": "string"},
+				"action": map[string]any{
+					"type": "string",
+					"enum": []string{"glossary_term", "guide_update"},
+				},
+				"phrase":     map[string]any{"type": "string"},
+				"section":    map[string]any{"type": "string"},
+				"guide_text": map[string]any{"type": "string"},
+			},
+			"required": []string{"title", "reasoning", "action", "phrase", "section", "guide_text"},
+		},
+	}
+}
+
+func buildPeriodThemesJSONSchema(categories []string) map[string]any {
+	return map[string]any{
+		"type": "array",
+		"items": map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"category": map[string]any{"type": "string", "enum": categories},
+				"summary":  map[string]any{"type": "string"},
+			},
+			"required": []string{"category", "summary"},
+		},
+	}
+}
+
+// anthropicToolInputKey wraps array schemas: tool input must be an object.
+const anthropicToolInputKey = "items"
+
+// callAnthropicTool forces the model to answer through a single tool whose
+// input schema is output.Schema, so the reply is validated JSON rather than
+// text to scrape. It returns the JSON the caller asked for.
+func callAnthropicTool(apiKey, baseURL, model, systemPrompt, userPrompt string, output StructuredOutput) (string, LLMUsage, error) {
+	client := newAnthropicClient(apiKey, baseURL)
+
+	schema := anthropic.ToolInputSchemaParam{
+		Properties: map[string]any{anthropicToolInputKey: output.Schema},
+		Required:   []string{anthropicToolInputKey},
+	}
+	tool := anthropic.ToolParam{Name: output.Name, InputSchema: schema}
+	if output.Description != "" {
+		tool.Description = anthropic.String(output.Description)
+	}
+
+	message, err := client.Messages.New(context.Background(), anthropic.MessageNewParams{
+		Model:     anthropic.Model(model),
+		MaxTokens: 4096,
+		System: []anthropic.TextBlockParam{
+			{Text: systemPrompt, CacheControl: anthropic.NewCacheControlEphemeralParam()},
+		},
+		Messages: []anthropic.MessageParam{
+			anthropic.NewUserMessage(anthropic.NewTextBlock(userPrompt)),
+		},
+		Tools:      []anthropic.ToolUnionParam{{OfTool: &tool}},
+		ToolChoice: anthropic.ToolChoiceParamOfTool(output.Name),
+	})
+	if err != nil {
+		log.Printf("llm anthropic error: %v", err)
+		return "", LLMUsage{}, fmt.Errorf("Anthropic API error: %w", err)
+	}
+	usage := LLMUsage{
+		InputTokens:              message.Usage.InputTokens,
+		OutputTokens:             message.Usage.OutputTokens,
+		CacheCreationInputTokens: message.Usage.CacheCreationInputTokens,
+		CacheReadInputTokens:     message.Usage.CacheReadInputTokens,
+	}
+	if message.StopReason == anthropic.StopReasonMaxTokens {
+		return "", usage, fmt.Errorf("Anthropic response truncated at max_tokens (tool %s)", output.Name)
+	}
+
+	for _, block := range message.Content {
+		if block.Type != "tool_use" || block.Name != output.Name {
+			continue
+		}
+		var input map[string]json.RawMessage
+		if err := json.Unmarshal(block.Input, &input); err != nil {
+			return "", usage, fmt.Errorf("parsing Anthropic tool input: %w", err)
+		}
+		result, ok := input[anthropicToolInputKey]
+		if !ok {
+			return "", usage, fmt.Errorf("Anthropic tool input missing %q", anthropicToolInputKey)
+		}
+		log.Printf("llm anthropic tool=%s size=%d tokens_in=%d tokens_out=%d cache_create=%d cache_read=%d", output.Name, len(result), usage.InputTokens, usage.OutputTokens, usage.CacheCreationInputTokens, usage.CacheReadInputTokens)
+		return string(result), usage, nil
+	}
+	return "", usage, fmt.Errorf("no %s tool call in Anthropic response", output.Name)
+}
@@ -0,0 +1,119 @@
+package llm
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newFakeAnthropicServer(t *testing.T, stopReason string, toolInput string, captured *map[string]any) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(captured); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "msg_1", "type": "message", "role": "assistant", "model": "claude-test",
+			"stop_reason": "` + stopReason + `",
+			"content": [
+				{"type": "text", "text": "Sure, here are the results:"},
+				{"type": "tool_use", "id": "toolu_1", "name": "section_classification_batch", "input": ` + toolInput + `}
+			],
+			"usage": {"input_tokens": 120, "output_tokens": 30}
+		}`))
+	}))
+}
+
+func TestAnthropicClassifySectionsUsesForcedTool(t *testing.T) {
+	var captured map[string]any
+	server := newFakeAnthropicServer(t, "tool_use",
+		`{"items": [{"id": 7, "section_id": "S0_0", "normalized_status": "done", "ticket_ids": ["123"], "duplicate_of": "", "confidence": 0.8, "alternative_section_ids": []}]}`,
+		&captured)
+	defer server.Close()
+
+	provider := anthropicProvider{apiKey: "test", model: "claude-test", baseURL: server.URL}
+	text, usage, err := provider.ClassifySections("sys", "user", []SectionOption{{ID: "S0_0", Label: "Infra"}})
+	if err != nil {
+		t.Fatalf("ClassifySections: %v", err)
+	}
+	if usage.InputTokens != 120 || usage.OutputTokens != 30 {
+		t.Fatalf("unexpected usage: %+v", usage)
+	}
+	decisions, err := parseSectionClassifiedResponse(text)
+	if err != nil {
+		t.Fatalf("tool output should parse as-is: %v (%s)", err, text)
+	}
+	if decisions[7].SectionID != "S0_0" || decisions[7].TicketIDs != "123" {
+		t.Fatalf("unexpected decision: %+v", decisions[7])
+	}
+
+	choice, _ := captured["tool_choice"].(map[string]any)
+	if choice["type"] != "tool" || choice["name"] != "section_classification_batch" {
+		t.Fatalf("expected forced tool choice, got %#v", captured["tool_choice"])
+	}
+	tools, _ := captured["tools"].([]any)
+	if len(tools) != 1 {
+		t.Fatalf("expected one tool, got %#v", captured["tools"])
+	}
+	schema, _ := json.Marshal(tools[0].(map[string]any)["input_schema"])
+	if !strings.Contains(string(schema), `"enum":["UND","S0_0"]`) {
+		t.Fatalf("expected section enum in tool schema, got %s", schema)
+	}
+}
+
+func TestAnthropicToolCallReportsTruncation(t *testing.T) {
+	var captured map[string]any
+	server := newFakeAnthropicServer(t, "max_tokens", `{"items": [{"id": 7}]}`, &captured)
+	defer server.Close()
+
+	provider := anthropicProvider{apiKey: "test", model: "claude-test", baseURL: server.URL}
+	_, usage, err := provider.ClassifySections("sys", "user", nil)
+	if err == nil || !strings.Contains(err.Error(), "max_tokens") {
+		t.Fatalf("expected truncation error, got %v", err)
+	}
+	if usage.OutputTokens != 30 {
+		t.Fatalf("expected usage on truncation, got %+v", usage)
+	}
+}
+
+type structuredFakeProvider struct {
+	fakeProvider
+	outputs []StructuredOutput
+	reply   string
+}
+
+func (f *structuredFakeProvider) CompleteStructured(_, _ string, output StructuredOutput) (string, LLMUsage, error) {
+	f.outputs = append(f.outputs, output)
+	return f.reply, LLMUsage{}, nil
+}
+
+func TestCriticUsesStructuredOutputWhenAvailable(t *testing.T) {
+	provider := &structuredFakeProvider{reply: `[{"id": 1, "reason": "auth work", "suggested_section_id": "S1_0"}]`}
+	options := []SectionOption{{ID: "S0_0", Label: "Infra"}, {ID: "S1_0", Label: "Auth"}}
+	items := []WorkItem{{ID: 1, Description: "Add SSO login"}}
+	decisions := map[int64]LLMSectionDecision{1: {SectionID: "S0_0"}}
+
+	flagged, _, err := runCriticPass(provider, items, decisions, options)
+	if err != nil {
+		t.Fatalf("runCriticPass: %v", err)
+	}
+	if len(flagged) != 1 || flagged[0].SuggestedSectionID != "S1_0" {
+		t.Fatalf("unexpected flagged: %+v", flagged)
+	}
+	if provider.completeCalls != 0 || len(provider.outputs) != 1 || provider.outputs[0].Name != "report_misclassifications" {
+		t.Fatalf("expected structured call, complete=%d outputs=%+v", provider.completeCalls, provider.outputs)
+	}
+
+	// Providers without structured output still go through Complete.
+	plain := &fakeProvider{completeText: "[]"}
+	if _, _, err := runCriticPass(plain, items, decisions, options); err != nil || plain.completeCalls != 1 {
+		t.Fatalf("expected fallback to Complete, err=%v calls=%d", err, plain.completeCalls)
+	}
+}
@@ -0,0 +1,31 @@
+package llm
+
+import "sync"
+
+// teamStores holds one store per team ID, so a team's cached decisions,
+// spend and embeddings never come from another team's data. A single-team
+// deployment registers its store under "".
+type teamStores[T any] struct {
+	mu     sync.RWMutex
+	byTeam map[string]T
+}
+
+// set installs store for teamID; a nil store removes it.
+func (r *teamStores[T]) set(teamID string, store T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if any(store) == nil {
+		delete(r.byTeam, teamID)
+		return
+	}
+	if r.byTeam == nil {
+		r.byTeam = make(map[string]T)
+	}
+	r.byTeam[teamID] = store
+}
+
+func (r *teamStores[T]) get(teamID string) T {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.byTeam[teamID]
+}
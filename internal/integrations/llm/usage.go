@@ -0,0 +1,126 @@
+package llm
+
+import (
+	"fmt"
+	"log"
+	"reportbot/internal/domain"
+	"time"
+)
+
+type LLMUsageRecord = domain.LLMUsageRecord
+
+// Purposes recorded in the llm_usage table.
+const (
+	usagePurposeClassify   = "classify"
+	usagePurposeCritic     = "critic"
+	usagePurposeRetrospect = "retrospect"
+	usagePurposeEmbeddings = "embeddings"
+	usagePurposeRollup     = "rollup"
+	usagePurposePeriod     = "period_summary"
+)
+
+// UsageLedger persists LLM usage and reports spend for budget checks.
+type UsageLedger interface {
+	RecordLLMUsage(rec LLMUsageRecord) error
+	LLMSpendSince(since time.Time) (float64, error)
+}
+
+var usageLedgers teamStores[UsageLedger]
+
+// SetUsageLedger installs the ledger teamID's LLM calls are recorded in and
+// its budgets are checked against. Without one, usage is only logged and
+// budgets are not enforced.
+func SetUsageLedger(teamID string, ledger UsageLedger) {
+	usageLedgers.set(teamID, ledger)
+}
+
+// UsageCostUSD prices usage with the configured per-model rates. Models
+// without a price cost nothing.
+func UsageCostUSD(cfg Config, model string, usage LLMUsage) float64 {
+	price, ok := cfg.LLMPrices[model]
+	if !ok {
+		return 0
+	}
+	cached := price.CachedInputPerMTok
+	if cached == 0 {
+		cached = price.InputPerMTok
+	}
+	return (float64(usage.InputTokens+usage.CacheCreationInputTokens)*price.InputPerMTok +
+		float64(usage.OutputTokens)*price.OutputPerMTok +
+		float64(usage.CacheReadInputTokens)*cached) / 1e6
+}
+
+// recordUsage writes one llm_usage row. Failures are logged, never returned:
+// accounting must not break report generation.
+func recordUsage(cfg Config, providerName, model, purpose string, usage LLMUsage) {
+	ledger := usageLedgers.get(cfg.TeamID)
+	if ledger == nil {
+		return
+	}
+	if usage.InputTokens+usage.OutputTokens+usage.CacheCreationInputTokens+usage.CacheReadInputTokens == 0 {
+		return
+	}
+	rec := LLMUsageRecord{
+		Provider:                 providerName,
+		Model:                    model,
+		Purpose:                  purpose,
+		InputTokens:              usage.InputTokens,
+		OutputTokens:             usage.OutputTokens,
+		CacheCreationInputTokens: usage.CacheCreationInputTokens,
+		CacheReadInputTokens:     usage.CacheReadInputTokens,
+		CostUSD:                  UsageCostUSD(cfg, model, usage),
+		CreatedAt:                time.Now().UTC(),
+	}
+	if err := ledger.RecordLLMUsage(rec); err != nil {
+		log.Printf("llm usage record error (non-fatal) purpose=%s: %v", purpose, err)
+	}
+}
+
+// BudgetPeriodStarts returns the start of the week (Monday 00:00) and of
+// the month containing now, in the configured timezone.
+func BudgetPeriodStarts(cfg Config, now time.Time) (week, month time.Time) {
+	loc := cfg.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	now = now.In(loc)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset), time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+}
+
+// CheckOptionalPassBudget returns an error when this week's or this month's
+// recorded spend has reached its budget. Optional passes (critic,
+// retrospect) call it first; weekly classification itself always runs.
+func CheckOptionalPassBudget(cfg Config) error {
+	if cfg.LLMBudgetWeeklyUSD <= 0 && cfg.LLMBudgetMonthlyUSD <= 0 {
+		return nil
+	}
+	ledger := usageLedgers.get(cfg.TeamID)
+	if ledger == nil {
+		return nil
+	}
+	week, month := BudgetPeriodStarts(cfg, time.Now())
+	checks := []struct {
+		name   string
+		since  time.Time
+		budget float64
+	}{
+		{"weekly", week, cfg.LLMBudgetWeeklyUSD},
+		{"monthly", month, cfg.LLMBudgetMonthlyUSD},
+	}
+	for _, c := range checks {
+		if c.budget <= 0 {
+			continue
+		}
+		spend, err := ledger.LLMSpendSince(c.since)
+		if err != nil {
+			log.Printf("llm budget check error (non-fatal): %v", err)
+			return nil
+		}
+		if spend >= c.budget {
+			return fmt.Errorf("%s LLM budget exhausted: $%.2f spent of $%.2f", c.name, spend, c.budget)
+		}
+	}
+	return nil
+}
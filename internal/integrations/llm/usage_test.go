@@ -0,0 +1,110 @@
+package llm
+
+import (
+	"reportbot/internal/config"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memoryUsageLedger struct {
+	mu      sync.Mutex
+	records []LLMUsageRecord
+	spend   float64
+}
+
+func (l *memoryUsageLedger) RecordLLMUsage(rec LLMUsageRecord) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, rec)
+	return nil
+}
+
+func (l *memoryUsageLedger) LLMSpendSince(time.Time) (float64, error) {
+	return l.spend, nil
+}
+
+func TestUsageCostUSD(t *testing.T) {
+	cfg := Config{LLMPrices: map[string]config.LLMPrice{
+		"m": {InputPerMTok: 3, OutputPerMTok: 15, CachedInputPerMTok: 0.3},
+		"n": {InputPerMTok: 1, OutputPerMTok: 2},
+	}}
+	got := UsageCostUSD(cfg, "m", LLMUsage{InputTokens: 1_000_000, OutputTokens: 100_000, CacheReadInputTokens: 1_000_000})
+	if diff := got - (3 + 1.5 + 0.3); diff > 1e-9 || diff < -1e-9 {
+		t.Fatalf("unexpected cost: %v", got)
+	}
+	if got := UsageCostUSD(cfg, "n", LLMUsage{CacheReadInputTokens: 1_000_000}); got != 1 {
+		t.Fatalf("expected cached tokens at input price when unset, got %v", got)
+	}
+	if got := UsageCostUSD(cfg, "unknown", LLMUsage{InputTokens: 1_000_000}); got != 0 {
+		t.Fatalf("expected unpriced model to cost 0, got %v", got)
+	}
+}
+
+func TestBudgetPeriodStarts(t *testing.T) {
+	now := time.Date(2026, 3, 5, 15, 0, 0, 0, time.UTC) // Thursday
+	week, month := BudgetPeriodStarts(Config{Location: time.UTC}, now)
+	if !week.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected week start: %s", week)
+	}
+	if !month.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected month start: %s", month)
+	}
+	sunday := time.Date(2026, 3, 8, 23, 0, 0, 0, time.UTC)
+	if week, _ := BudgetPeriodStarts(Config{Location: time.UTC}, sunday); !week.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected Sunday to belong to the week starting Monday, got %s", week)
+	}
+}
+
+func TestCheckOptionalPassBudget(t *testing.T) {
+	ledger := &memoryUsageLedger{spend: 4}
+	SetUsageLedger("", ledger)
+	t.Cleanup(func() { SetUsageLedger("", nil) })
+
+	if err := CheckOptionalPassBudget(Config{}); err != nil {
+		t.Fatalf("expected no budget to allow, got %v", err)
+	}
+	if err := CheckOptionalPassBudget(Config{LLMBudgetWeeklyUSD: 5}); err != nil {
+		t.Fatalf("expected spend under budget to allow, got %v", err)
+	}
+	ledger.spend = 5
+	err := CheckOptionalPassBudget(Config{LLMBudgetMonthlyUSD: 5})
+	if err == nil || !strings.Contains(err.Error(), "monthly") {
+		t.Fatalf("expected monthly budget error, got %v", err)
+	}
+}
+
+func TestCategorizeRecordsUsageAndSkipsCriticOverBudget(t *testing.T) {
+	ledger := &memoryUsageLedger{spend: 10}
+	SetUsageLedger("", ledger)
+	t.Cleanup(func() { SetUsageLedger("", nil) })
+
+	provider := &scriptedProvider{steps: []func() (string, LLMUsage, error){
+		func() (string, LLMUsage, error) {
+			return `[{"id":1,"section_id":"S0_0","normalized_status":"done","ticket_ids":[],"duplicate_of":"","confidence":0.9,"alternative_section_ids":[]}]`,
+				LLMUsage{InputTokens: 1000, OutputTokens: 100}, nil
+		},
+	}}
+	cfg := Config{
+		LLMBatchSize:       10,
+		LLMCriticEnabled:   true,
+		LLMBudgetWeeklyUSD: 5,
+		LLMPrices:          map[string]config.LLMPrice{"scripted-model": {InputPerMTok: 1000, OutputPerMTok: 1000}},
+	}
+	items := []WorkItem{{ID: 1, Description: "Fix DB timeout", Status: "done"}}
+	_, _, err := CategorizeItemsToSectionsWithProvider(provider, cfg, items, []SectionOption{{ID: "S0_0", Label: "Infra"}}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CategorizeItemsToSectionsWithProvider: %v", err)
+	}
+	if len(ledger.records) != 1 {
+		t.Fatalf("expected only the classify call to be recorded (critic skipped), got %+v", ledger.records)
+	}
+	rec := ledger.records[0]
+	if rec.Purpose != usagePurposeClassify || rec.Provider != "scripted" || rec.Model != "scripted-model" || rec.InputTokens != 1000 {
+		t.Fatalf("unexpected usage record: %+v", rec)
+	}
+	if rec.CostUSD != 1.1 {
+		t.Fatalf("unexpected cost: %v", rec.CostUSD)
+	}
+}
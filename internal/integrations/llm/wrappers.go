@@ -3,3 +3,11 @@ package llm
 func AnalyzeCorrections(cfg Config, corrections []ClassificationCorrection, options []SectionOption) ([]RetroSuggestion, LLMUsage, error) {
 	return analyzeCorrections(cfg, corrections, options)
 }
+
+func SummarizeTeamReport(cfg Config, teamName, report string) (string, LLMUsage, error) {
+	return summarizeTeamReport(cfg, teamName, report)
+}
+
+func SummarizePeriodThemes(cfg Config, periodLabel string, categories []PeriodCategory) (map[string]string, LLMUsage, error) {
+	return summarizePeriodThemes(cfg, periodLabel, categories)
+}
@@ -1,14 +1,14 @@
 package slackbot
 
 import (
-	"database/sql"
+	"reportbot/internal/backup"
 	"reportbot/internal/config"
 	"reportbot/internal/domain"
 	"reportbot/internal/fetch"
 	llm "reportbot/internal/integrations/llm"
 	"reportbot/internal/nudge"
 	"reportbot/internal/report"
-	"reportbot/internal/storage/sqlite"
+	"reportbot/internal/storage"
 	"time"
 
 	"github.com/slack-go/slack"
@@ -16,15 +16,21 @@ import (
 
 type Config = config.Config
 type WorkItem = domain.WorkItem
+type WorkItemEvent = domain.WorkItemEvent
+type SearchQuery = domain.SearchQuery
+type Store = storage.Store
 type GitLabMR = domain.GitLabMR
 type GitHubPR = domain.GitHubPR
 type ClassificationRecord = domain.ClassificationRecord
 type ClassificationCorrection = domain.ClassificationCorrection
 type ClassificationStats = domain.ClassificationStats
+type LLMUsageSummary = domain.LLMUsageSummary
 type sectionOption = llm.SectionOption
 type BuildResult = report.BuildResult
 type LLMSectionDecision = llm.LLMSectionDecision
 type RenderedNudge = nudge.RenderedNudge
+type BackupResult = backup.Result
+type Identity = domain.Identity
 
 type loadStatus int
 
@@ -40,6 +46,20 @@ const (
 	actionNudgePageNext = nudge.ActionPageNext
 )
 
+const (
+	WorkItemEventCreated         = domain.WorkItemEventCreated
+	WorkItemEventEdited          = domain.WorkItemEventEdited
+	WorkItemEventStatusChanged   = domain.WorkItemEventStatusChanged
+	WorkItemEventCategoryChanged = domain.WorkItemEventCategoryChanged
+	WorkItemEventDeleted         = domain.WorkItemEventDeleted
+	WorkItemEventRestored        = domain.WorkItemEventRestored
+	WorkItemEventNudgeDone       = domain.WorkItemEventNudgeDone
+)
+
+func ForTeam(db Store, teamID string) Store {
+	return storage.ForTeam(db, teamID)
+}
+
 func ReportWeekRange(cfg Config, now time.Time) (time.Time, time.Time) {
 	return domain.ReportWeekRange(cfg, now)
 }
@@ -48,7 +68,7 @@ func FridayOfWeek(monday time.Time) time.Time {
 	return domain.FridayOfWeek(monday)
 }
 
-func FetchAndImportMRs(cfg Config, db *sql.DB) (fetch.FetchResult, error) {
+func FetchAndImportMRs(cfg Config, db Store) (fetch.FetchResult, error) {
 	return fetch.FetchAndImportMRs(cfg, db)
 }
 
@@ -56,8 +76,8 @@ func FormatFetchSummary(result fetch.FetchResult) string {
 	return fetch.FormatFetchSummary(result)
 }
 
-func BuildReportsFromLast(cfg Config, items []WorkItem, reportDate time.Time, corrections []ClassificationCorrection, historicalItems []domain.HistoricalItem) (BuildResult, error) {
-	return report.BuildReportsFromLast(cfg, items, reportDate, corrections, historicalItems)
+func BuildReportsFromLast(cfg Config, items []WorkItem, reportDate time.Time, corrections []ClassificationCorrection, historicalItems []domain.HistoricalItem, confidenceSamples []domain.ConfidenceSample) (BuildResult, error) {
+	return report.BuildReportsFromLast(cfg, items, reportDate, corrections, historicalItems, confidenceSamples)
 }
 
 func WriteEmailDraftFile(body, outputDir string, reportDate time.Time, subjectPrefix string) (string, error) {
@@ -76,6 +96,38 @@ func renderBossMarkdown(t *report.ReportTemplate) string {
 	return report.RenderBossMarkdown(t)
 }
 
+func RollupSources(cfg Config) []report.RollupSource {
+	return report.RollupSources(cfg)
+}
+
+func BuildRollup(cfg Config, sources []report.RollupSource, reportDate time.Time, summarize bool) (report.Rollup, error) {
+	return report.BuildRollup(cfg, sources, reportDate, summarize)
+}
+
+func WriteRollupFiles(r report.Rollup, outputDir string) (string, string, error) {
+	return report.WriteRollupFiles(r, outputDir)
+}
+
+func FormatRollupSummary(r report.Rollup) string {
+	return report.FormatRollupSummary(r)
+}
+
+func PeriodContaining(kind string, t time.Time) (report.Period, error) {
+	return report.PeriodContaining(kind, t)
+}
+
+func BuildPeriodReport(cfg Config, p report.Period, items []WorkItem, sections map[int64]string, summarize bool) (report.PeriodResult, error) {
+	return report.BuildPeriodReport(cfg, p, items, sections, summarize)
+}
+
+func RenderPeriodMarkdown(teamName string, res report.PeriodResult) string {
+	return report.RenderPeriodMarkdown(teamName, res)
+}
+
+func WritePeriodReportFile(content, outputDir, teamName string, p report.Period) (string, error) {
+	return report.WritePeriodReportFile(content, outputDir, teamName, p)
+}
+
 func loadTemplateForGeneration(outputDir, teamName string, reportDate time.Time) (*report.ReportTemplate, loadStatus, error) {
 	t, err := report.LoadTemplateForGeneration(outputDir, teamName, reportDate)
 	if err != nil {
@@ -104,86 +156,160 @@ func synthesizeName(name string) string {
 	return report.SynthesizeName(name)
 }
 
-func InsertWorkItem(db *sql.DB, item WorkItem) error {
-	return sqlite.InsertWorkItem(db, item)
+func InsertWorkItem(db Store, item WorkItem, actorID string) error {
+	return db.InsertWorkItem(item, actorID)
+}
+
+func InsertWorkItems(db Store, items []WorkItem, actorID string) (int, error) {
+	return db.InsertWorkItems(items, actorID)
+}
+
+func GetSlackItemsByAuthorAndDateRange(db Store, author string, from, to time.Time) ([]WorkItem, error) {
+	return db.GetSlackItemsByAuthorAndDateRange(author, from, to)
+}
+
+func GetLabeledWorkItems(db Store, since time.Time, limit int) ([]domain.LabeledWorkItem, error) {
+	return db.GetLabeledWorkItems(since, limit)
+}
+
+func GetItemsByDateRange(db Store, from, to time.Time) ([]WorkItem, error) {
+	return db.GetItemsByDateRange(from, to)
 }
 
-func InsertWorkItems(db *sql.DB, items []WorkItem) (int, error) {
-	return sqlite.InsertWorkItems(db, items)
+func GetRecentCorrections(db Store, since time.Time, limit int) ([]ClassificationCorrection, error) {
+	return db.GetRecentCorrections(since, limit)
 }
 
-func GetSlackItemsByAuthorAndDateRange(db *sql.DB, author string, from, to time.Time) ([]WorkItem, error) {
-	return sqlite.GetSlackItemsByAuthorAndDateRange(db, author, from, to)
+func GetClassifiedItemsWithSections(db Store, since time.Time, limit int) ([]domain.HistoricalItem, error) {
+	return db.GetClassifiedItemsWithSections(since, limit)
 }
 
-func GetItemsByDateRange(db *sql.DB, from, to time.Time) ([]WorkItem, error) {
-	return sqlite.GetItemsByDateRange(db, from, to)
+func GetConfidenceSamples(db Store, since time.Time, limit int) ([]domain.ConfidenceSample, error) {
+	return db.GetConfidenceSamples(since, limit)
 }
 
-func GetRecentCorrections(db *sql.DB, since time.Time, limit int) ([]ClassificationCorrection, error) {
-	return sqlite.GetRecentCorrections(db, since, limit)
+func InsertClassificationHistory(db Store, records []ClassificationRecord) error {
+	return db.InsertClassificationHistory(records)
 }
 
-func GetClassifiedItemsWithSections(db *sql.DB, since time.Time, limit int) ([]domain.HistoricalItem, error) {
-	return sqlite.GetClassifiedItemsWithSections(db, since, limit)
+func GetSlackAuthorIDsByDateRange(db Store, from, to time.Time) (map[string]bool, error) {
+	return db.GetSlackAuthorIDsByDateRange(from, to)
 }
 
-func InsertClassificationHistory(db *sql.DB, records []ClassificationRecord) error {
-	return sqlite.InsertClassificationHistory(db, records)
+func GetWorkItemByID(db Store, id int64) (WorkItem, error) {
+	return db.GetWorkItemByID(id)
 }
 
-func GetSlackAuthorIDsByDateRange(db *sql.DB, from, to time.Time) (map[string]bool, error) {
-	return sqlite.GetSlackAuthorIDsByDateRange(db, from, to)
+func UpdateWorkItemTextAndStatus(db Store, id int64, description, status, actorID string) error {
+	return db.UpdateWorkItemTextAndStatus(id, description, status, actorID)
 }
 
-func GetWorkItemByID(db *sql.DB, id int64) (WorkItem, error) {
-	return sqlite.GetWorkItemByID(db, id)
+func UpdateWorkItemStatus(db Store, id int64, status, actorID string) error {
+	return db.UpdateWorkItemStatus(id, status, actorID)
 }
 
-func UpdateWorkItemTextAndStatus(db *sql.DB, id int64, description, status string) error {
-	return sqlite.UpdateWorkItemTextAndStatus(db, id, description, status)
+func MarkWorkItemDoneFromNudge(db Store, id int64, actorID string) error {
+	return db.MarkWorkItemDoneFromNudge(id, actorID)
 }
 
-func UpdateWorkItemStatus(db *sql.DB, id int64, status string) error {
-	return sqlite.UpdateWorkItemStatus(db, id, status)
+func UpdateWorkItemCategory(db Store, id int64, category, actorID string) error {
+	return db.UpdateWorkItemCategory(id, category, actorID)
 }
 
-func UpdateWorkItemCategory(db *sql.DB, id int64, category string) error {
-	return sqlite.UpdateWorkItemCategory(db, id, category)
+func DeleteWorkItemByID(db Store, id int64, actorID string) error {
+	return db.DeleteWorkItemByID(id, actorID)
 }
 
-func DeleteWorkItemByID(db *sql.DB, id int64) error {
-	return sqlite.DeleteWorkItemByID(db, id)
+func RestoreWorkItem(db Store, id int64, actorID string) error {
+	return db.RestoreWorkItem(id, actorID)
 }
 
-func GetClassificationStats(db *sql.DB, since time.Time) (ClassificationStats, error) {
-	return sqlite.GetClassificationStats(db, since)
+func GetWorkItemEvents(db Store, workItemID int64) ([]WorkItemEvent, error) {
+	return db.GetWorkItemEvents(workItemID)
 }
 
-func GetCorrectionsBySection(db *sql.DB, since time.Time) ([]sqlite.SectionCorrectionStat, error) {
-	return sqlite.GetCorrectionsBySection(db, since)
+func GetDeletedItemsByDateRange(db Store, from, to time.Time) ([]WorkItem, error) {
+	return db.GetDeletedItemsByDateRange(from, to)
 }
 
-func GetWeeklyClassificationTrend(db *sql.DB, since time.Time) ([]sqlite.WeeklyTrend, error) {
-	return sqlite.GetWeeklyClassificationTrend(db, since)
+func RunBackup(cfg Config, db Store, now time.Time) (BackupResult, error) {
+	return backup.Run(cfg, db, now)
 }
 
-func GetLatestClassification(db *sql.DB, workItemID int64) (ClassificationRecord, error) {
-	return sqlite.GetLatestClassification(db, workItemID)
+func FormatBackupResult(r BackupResult) string {
+	return backup.FormatResult(r)
 }
 
-func InsertClassificationCorrection(db *sql.DB, c ClassificationCorrection) error {
-	return sqlite.InsertClassificationCorrection(db, c)
+const (
+	IdentityGitLab = domain.IdentityGitLab
+	IdentityGitHub = domain.IdentityGitHub
+	IdentityEmail  = domain.IdentityEmail
+)
+
+var ErrIdentityTaken = domain.ErrIdentityTaken
+
+func NormalizeAccount(account string) string { return domain.NormalizeAccount(account) }
+
+func GetIdentity(db Store, slackID string) (Identity, error) {
+	return db.GetIdentity(slackID)
+}
+
+func LinkIdentity(db Store, slackID, kind, account string) error {
+	return db.LinkIdentity(slackID, kind, account)
+}
+
+func ReassignIdentity(db Store, slackID, kind, account string) error {
+	return db.ReassignIdentity(slackID, kind, account)
+}
+
+func SearchWorkItems(db Store, q SearchQuery) ([]WorkItem, error) {
+	return db.SearchWorkItems(q)
+}
+
+func GetClassificationStats(db Store, since time.Time) (ClassificationStats, error) {
+	return db.GetClassificationStats(since)
+}
+
+func GetCorrectionsBySection(db Store, since time.Time) ([]domain.SectionCorrectionStat, error) {
+	return db.GetCorrectionsBySection(since)
 }
 
-func CountCorrectionsByPhrase(db *sql.DB, description, correctedSectionID string) (int, error) {
-	return sqlite.CountCorrectionsByPhrase(db, description, correctedSectionID)
+func GetWeeklyClassificationTrend(db Store, since time.Time) ([]domain.WeeklyTrend, error) {
+	return db.GetWeeklyClassificationTrend(since)
+}
+
+func GetLatestClassification(db Store, workItemID int64) (ClassificationRecord, error) {
+	return db.GetLatestClassification(workItemID)
+}
+
+func InsertClassificationCorrection(db Store, c ClassificationCorrection) error {
+	return db.InsertClassificationCorrection(c)
+}
+
+func CountCorrectionsByPhrase(db Store, description, correctedSectionID string) (int, error) {
+	return db.CountCorrectionsByPhrase(description, correctedSectionID)
 }
 
 func analyzeCorrections(cfg Config, corrections []ClassificationCorrection, options []sectionOption) ([]llm.RetroSuggestion, llm.LLMUsage, error) {
 	return llm.AnalyzeCorrections(cfg, corrections, options)
 }
 
+func checkOptionalPassBudget(cfg Config) error {
+	return llm.CheckOptionalPassBudget(cfg)
+}
+
+func budgetPeriodStarts(cfg Config, now time.Time) (time.Time, time.Time) {
+	return llm.BudgetPeriodStarts(cfg, now)
+}
+
+func GetLLMSpendSince(db Store, since time.Time) (float64, error) {
+	return db.LLMSpendSince(since)
+}
+
+func GetLLMUsageSummary(db Store, since time.Time) ([]LLMUsageSummary, error) {
+	return db.GetLLMUsageSummary(since)
+}
+
 func AppendGlossaryTerm(path, phrase, section string) error {
 	return llm.AppendGlossaryTerm(path, phrase, section)
 }
@@ -192,11 +318,11 @@ func extractGlossaryPhrase(description string) string {
 	return llm.ExtractGlossaryPhrase(description)
 }
 
-func sendNudges(api *slack.Client, db *sql.DB, cfg Config, memberIDs []string, reportChannelID string) {
+func sendNudges(api *slack.Client, db Store, cfg Config, memberIDs []string, reportChannelID string) {
 	nudge.SendNudges(api, db, cfg, memberIDs, reportChannelID)
 }
 
-func RenderNudgeForUser(api *slack.Client, db *sql.DB, cfg Config, userID, reportChannelID string, now time.Time, page int, updated bool) (RenderedNudge, error) {
+func RenderNudgeForUser(api *slack.Client, db Store, cfg Config, userID, reportChannelID string, now time.Time, page int, updated bool) (RenderedNudge, error) {
 	return nudge.RenderNudgeForUser(api, db, cfg, userID, reportChannelID, now, page, updated)
 }
 
@@ -113,6 +113,8 @@ func withMockGitHubAPI(t *testing.T) {
 					RepositoryURL: "https://api.github.com/repos/acme/repo-b",
 				},
 			}
+		case strings.Contains(q, "is:closed is:unmerged"):
+			// No PR was closed without merging.
 		default:
 			t.Fatalf("unexpected search query: %q", q)
 		}
@@ -489,10 +491,11 @@ func TestFunctional_FetchAndImportGitHub_EndToEnd(t *testing.T) {
 	db := newTestDB(t)
 
 	cfg := Config{
-		GitHubToken: "gho-test",
-		GitHubOrg:   "acme",
-		TeamMembers: []string{"alice", "bob"},
-		Location:    time.UTC,
+		GitHubToken:    "gho-test",
+		GitHubOrg:      "acme",
+		GitHubFetchAPI: "search", // the mock serves the REST search API
+		TeamMembers:    []string{"alice", "bob"},
+		Location:       time.UTC,
 	}
 
 	first, err := FetchAndImportMRs(cfg, db)
@@ -529,6 +532,7 @@ func TestFunctional_HandleFetchMRs_WithSlackAndGitHub(t *testing.T) {
 	cfg := Config{
 		GitHubToken:     "gho-test",
 		GitHubOrg:       "acme",
+		GitHubFetchAPI:  "search",
 		TeamMembers:     []string{"alice", "bob"},
 		ManagerSlackIDs: []string{"U_MANAGER"},
 		Location:        time.UTC,
@@ -128,7 +128,7 @@ func TestHandleBlockActions_NudgeDoneUpdatesStatusAndRefreshesMessage(t *testing
 		Source:      "slack",
 		Status:      "in progress",
 		ReportedAt:  now,
-	}); err != nil {
+	}, ""); err != nil {
 		t.Fatalf("insert work item: %v", err)
 	}
 	items, err := GetItemsByDateRange(db, now.Add(-time.Hour), now.Add(time.Hour))
@@ -176,7 +176,7 @@ func TestHandleBlockActions_NudgeMoreUpdatesStatus(t *testing.T) {
 		Source:      "slack",
 		Status:      "in progress",
 		ReportedAt:  now,
-	}); err != nil {
+	}, ""); err != nil {
 		t.Fatalf("insert work item: %v", err)
 	}
 	items, err := GetItemsByDateRange(db, now.Add(-time.Hour), now.Add(time.Hour))
@@ -230,7 +230,7 @@ func TestHandleBlockActions_NudgePageNextRefreshesWithoutMutation(t *testing.T)
 			Source:      "slack",
 			Status:      "in progress",
 			ReportedAt:  now.Add(time.Duration(i) * time.Minute),
-		}); err != nil {
+		}, ""); err != nil {
 			t.Fatalf("insert work item %d: %v", i, err)
 		}
 	}
@@ -283,7 +283,7 @@ func TestHandleBlockActions_NudgeDoneClampsPageAfterLastItemRemoved(t *testing.T
 			Source:      "slack",
 			Status:      "in progress",
 			ReportedAt:  now.Add(time.Duration(i) * time.Minute),
-		}); err != nil {
+		}, ""); err != nil {
 			t.Fatalf("insert work item %d: %v", i, err)
 		}
 	}
@@ -333,7 +333,7 @@ func TestHandleBlockActions_NudgeUnauthorizedUserCannotUpdate(t *testing.T) {
 		Source:      "slack",
 		Status:      "in progress",
 		ReportedAt:  now,
-	}); err != nil {
+	}, ""); err != nil {
 		t.Fatalf("insert work item: %v", err)
 	}
 	items, err := GetItemsByDateRange(db, now.Add(-time.Hour), now.Add(time.Hour))
@@ -1,7 +1,6 @@
 package slackbot
 
 import (
-	"database/sql"
 	"fmt"
 	"log"
 	"os"
@@ -52,7 +51,7 @@ const (
 	nudgeMetaPrefix           = "nudge:"
 )
 
-func StartSlackBot(cfg Config, db *sql.DB, api *slack.Client) error {
+func StartSlackBot(cfg Config, db Store, api *slack.Client) error {
 	client := socketmode.New(api)
 
 	go func() {
@@ -88,7 +87,16 @@ func StartSlackBot(cfg Config, db *sql.DB, api *slack.Client) error {
 	return client.Run()
 }
 
-func handleSlashCommand(client *socketmode.Client, api *slack.Client, db *sql.DB, cfg Config, cmd slack.SlashCommand) {
+func handleSlashCommand(client *socketmode.Client, api *slack.Client, db Store, cfg Config, cmd slack.SlashCommand) {
+	teamCfg, teamDB, ok := resolveTeam(api, cfg, db, cmd.ChannelID, cmd.UserID)
+	// Help and linked accounts are the same for every team.
+	if !ok && cmd.Command != "/help" && cmd.Command != "/whoami" {
+		postEphemeral(api, cmd, unknownTeamMessage(cfg))
+		log.Printf("%s no team for channel=%s user=%s", cmd.Command, cmd.ChannelID, cmd.UserID)
+		return
+	}
+	cfg, db = teamCfg, teamDB
+
 	switch cmd.Command {
 	case "/report":
 		handleReport(api, db, cfg, cmd)
@@ -110,6 +118,14 @@ func handleSlashCommand(client *socketmode.Client, api *slack.Client, db *sql.DB
 		handleRetrospective(api, db, cfg, cmd)
 	case "/stats":
 		handleReportStats(api, db, cfg, cmd)
+	case "/history":
+		handleItemHistory(api, db, cfg, cmd)
+	case "/search":
+		handleSearch(api, db, cfg, cmd)
+	case "/backup":
+		handleBackup(api, db, cfg, cmd)
+	case "/whoami":
+		handleWhoami(api, db, cfg, cmd)
 	case "/help":
 		handleHelp(api, cfg, cmd)
 	}
@@ -128,6 +144,10 @@ func handleEventsAPI(api *slack.Client, cfg Config, event slackevents.EventsAPIE
 func handleMemberJoined(api *slack.Client, cfg Config, ev *slackevents.MemberJoinedChannelEvent) {
 	log.Printf("member-joined user=%s channel=%s", ev.User, ev.Channel)
 
+	cfg, ok := cfg.TeamForChannel(ev.Channel)
+	if !ok {
+		return
+	}
 	teamName := cfg.TeamName
 	if teamName == "" {
 		teamName = "the team"
@@ -151,7 +171,7 @@ func handleMemberJoined(api *slack.Client, cfg Config, ev *slackevents.MemberJoi
 	}
 }
 
-func handleReport(api *slack.Client, db *sql.DB, cfg Config, cmd slack.SlashCommand) {
+func handleReport(api *slack.Client, db Store, cfg Config, cmd slack.SlashCommand) {
 	text := strings.TrimSpace(cmd.Text)
 	if text == "" {
 		postEphemeral(api, cmd, "Usage: /report <description> (status)\nExample: /report [ticket_id] Add pagination to user list API (done)\nMultiline (separate items with newlines, e.g. Shift+Enter): /report Item A (in progress)\\nItem B (done)")
@@ -204,13 +224,13 @@ func handleReport(api *slack.Client, db *sql.DB, cfg Config, cmd slack.SlashComm
 		items[i].AuthorID = authorID
 	}
 	if len(items) == 1 {
-		if err := InsertWorkItem(db, items[0]); err != nil {
+		if err := InsertWorkItem(db, items[0], cmd.UserID); err != nil {
 			postEphemeral(api, cmd, fmt.Sprintf("Error saving item: %v", err))
 			log.Printf("report insert error user=%s: %v", cmd.UserID, err)
 			return
 		}
 	} else {
-		if _, err := InsertWorkItems(db, items); err != nil {
+		if _, err := InsertWorkItems(db, items, cmd.UserID); err != nil {
 			postEphemeral(api, cmd, fmt.Sprintf("Error saving items: %v", err))
 			log.Printf("report batch insert error user=%s: %v", cmd.UserID, err)
 			return
@@ -354,7 +374,7 @@ func parseReportItems(reportText, author string, loc *time.Location) ([]WorkItem
 	return items, nil
 }
 
-func handleFetchMRs(api *slack.Client, db *sql.DB, cfg Config, cmd slack.SlashCommand) {
+func handleFetchMRs(api *slack.Client, db Store, cfg Config, cmd slack.SlashCommand) {
 	isManager, err := isManagerUser(api, cfg, cmd.UserID)
 	if err != nil {
 		postEphemeral(api, cmd, fmt.Sprintf("Error checking permissions: %v", err))
@@ -394,10 +414,11 @@ func handleFetchMRs(api *slack.Client, db *sql.DB, cfg Config, cmd slack.SlashCo
 
 	msg := FormatFetchSummary(result)
 	postEphemeral(api, cmd, msg)
-	log.Printf("fetch inserted=%d alreadyTracked=%d skippedNonTeam=%d", result.Inserted, result.AlreadyTracked, result.SkippedNonTeam)
+	log.Printf("fetch inserted=%d alreadyTracked=%d updated=%d closed=%d skippedNonTeam=%d",
+		result.Inserted, result.AlreadyTracked, result.Updated, result.Closed, result.SkippedNonTeam)
 }
 
-func handleTestNudge(api *slack.Client, db *sql.DB, cfg Config, cmd slack.SlashCommand) {
+func handleTestNudge(api *slack.Client, db Store, cfg Config, cmd slack.SlashCommand) {
 	targetID := cmd.UserID
 	targetLabel := "yourself"
 	rawTarget := strings.TrimSpace(cmd.Text)
@@ -493,9 +514,9 @@ func parseGenerateReportArgs(text string) (mode string, sendPrivate bool, err er
 	fields := strings.Fields(strings.ToLower(strings.TrimSpace(text)))
 	for _, f := range fields {
 		switch f {
-		case "team", "boss", "post":
+		case "team", "boss", "post", "rollup", "month", "quarter":
 			if modeSet && mode != f {
-				return "", false, fmt.Errorf("Usage: /generate-report [team|boss|post] [private]\nExamples: /generate-report team, /generate-report boss private, /generate-report post, /gen post private")
+				return "", false, fmt.Errorf("Usage: /generate-report [team|boss|post|rollup|month|quarter] [private]\nExamples: /generate-report team, /generate-report boss private, /generate-report post, /gen post private, /gen month")
 			}
 			mode = f
 			modeSet = true
@@ -504,13 +525,13 @@ func parseGenerateReportArgs(text string) (mode string, sendPrivate bool, err er
 		case "channel":
 			sendPrivate = false
 		default:
-			return "", false, fmt.Errorf("Usage: /generate-report [team|boss|post] [private]\nExamples: /generate-report team, /generate-report boss private, /generate-report post, /gen post private")
+			return "", false, fmt.Errorf("Usage: /generate-report [team|boss|post|rollup|month|quarter] [private]\nExamples: /generate-report team, /generate-report boss private, /generate-report post, /gen post private, /gen month")
 		}
 	}
 	return mode, sendPrivate, nil
 }
 
-func handleGenerateReport(api *slack.Client, db *sql.DB, cfg Config, cmd slack.SlashCommand) {
+func handleGenerateReport(api *slack.Client, db Store, cfg Config, cmd slack.SlashCommand) {
 	isManager, err := isManagerUser(api, cfg, cmd.UserID)
 	if err != nil {
 		postEphemeral(api, cmd, fmt.Sprintf("Error checking permissions: %v", err))
@@ -544,6 +565,16 @@ func handleGenerateReport(api *slack.Client, db *sql.DB, cfg Config, cmd slack.S
 		return
 	}
 
+	if mode == "rollup" {
+		postRollupReport(api, cfg, cmd, friday, sendPrivate)
+		return
+	}
+
+	if mode == "month" || mode == "quarter" {
+		handlePeriodReport(api, db, cfg, cmd, mode, monday, sendPrivate)
+		return
+	}
+
 	// Boss mode shortcut: derive from existing team report if available.
 	if mode == "boss" {
 		filePath, bossReport, err := deriveBossReportFromTeamReport(cfg.ReportOutputDir, cfg.TeamName, friday)
@@ -640,7 +671,13 @@ func handleGenerateReport(api *slack.Client, db *sql.DB, cfg Config, cmd slack.S
 		log.Printf("generate-report historical items load error (non-fatal): %v", histErr)
 	}
 
-	result, err := BuildReportsFromLast(cfg, items, monday, corrections, historicalItems)
+	// Load past raw confidences and their correction outcomes for calibration.
+	confidenceSamples, sampleErr := GetConfidenceSamples(db, monday.AddDate(0, 0, -182), 2000)
+	if sampleErr != nil {
+		log.Printf("generate-report confidence samples load error (non-fatal): %v", sampleErr)
+	}
+
+	result, err := BuildReportsFromLast(cfg, items, monday, corrections, historicalItems, confidenceSamples)
 	if err != nil {
 		postEphemeral(api, cmd, fmt.Sprintf("Error building report: %v", err))
 		log.Printf("report build error: %v", err)
@@ -657,16 +694,23 @@ func handleGenerateReport(api *slack.Client, db *sql.DB, cfg Config, cmd slack.S
 		}
 		var records []ClassificationRecord
 		for itemID, dec := range result.Decisions {
+			if dec.CachedFrom == itemID {
+				// Reused this item's own earlier decision; already recorded.
+				continue
+			}
 			records = append(records, ClassificationRecord{
-				WorkItemID:       itemID,
-				SectionID:        dec.SectionID,
-				SectionLabel:     optionLabels[dec.SectionID],
-				Confidence:       dec.Confidence,
-				NormalizedStatus: dec.NormalizedStatus,
-				TicketIDs:        dec.TicketIDs,
-				DuplicateOf:      dec.DuplicateOf,
-				LLMProvider:      cfg.LLMProvider,
-				LLMModel:         cfg.LLMModel,
+				WorkItemID:            itemID,
+				SectionID:             dec.SectionID,
+				SectionLabel:          optionLabels[dec.SectionID],
+				Confidence:            dec.Confidence,
+				NormalizedStatus:      dec.NormalizedStatus,
+				TicketIDs:             dec.TicketIDs,
+				DuplicateOf:           dec.DuplicateOf,
+				LLMProvider:           cfg.LLMProvider,
+				LLMModel:              cfg.LLMModel,
+				RawConfidence:         dec.RawConfidence,
+				AlternativeSectionIDs: strings.Join(dec.Alternatives, ","),
+				CacheKey:              dec.CacheKey,
 			})
 		}
 		if err := InsertClassificationHistory(db, records); err != nil {
@@ -708,7 +752,8 @@ func handleGenerateReport(api *slack.Client, db *sql.DB, cfg Config, cmd slack.S
 		return
 	}
 
-	tokenUsedText := formatTokenCount(llmUsage.TotalTokens())
+	tokenUsedText := formatTokenCount(llmUsage.TotalTokens()) + formatDecisionCacheStats(llmUsage.DecisionCacheHits, llmUsage.DecisionCacheMisses)
+	degradedText := formatDegradedBatches(llmUsage.DegradedBatches)
 
 	uploadChannel := cmd.ChannelID
 	if sendPrivate {
@@ -728,7 +773,7 @@ func handleGenerateReport(api *slack.Client, db *sql.DB, cfg Config, cmd slack.S
 		Filename:       filepath.Base(filePath),
 		Channel:        uploadChannel,
 		Title:          fileTitle,
-		InitialComment: fmt.Sprintf("Generated report for reporting week containing %s (mode: %s, tokens used: %s)", friday.Format("2006-01-02"), mode, tokenUsedText),
+		InitialComment: fmt.Sprintf("Generated report for reporting week containing %s (mode: %s, tokens used: %s)%s", friday.Format("2006-01-02"), mode, tokenUsedText, degradedText),
 	})
 	if err != nil {
 		log.Printf("Error uploading report file: %v", err)
@@ -736,12 +781,12 @@ func handleGenerateReport(api *slack.Client, db *sql.DB, cfg Config, cmd slack.S
 		return
 	}
 
-	msg := fmt.Sprintf("Report generated with %d items (mode: %s, tokens used: %s)", len(items), mode, tokenUsedText)
+	msg := fmt.Sprintf("Report generated with %d items (mode: %s, tokens used: %s)%s", len(items), mode, tokenUsedText, degradedText)
 	if filePath != "" {
 		msg += fmt.Sprintf("\nSaved to: %s", filePath)
 	}
 	postEphemeral(api, cmd, msg)
-	log.Printf("generate-report done items=%d", len(items))
+	log.Printf("generate-report done items=%d degraded_batches=%d cache_hits=%d cache_misses=%d", len(items), llmUsage.DegradedBatches, llmUsage.DecisionCacheHits, llmUsage.DecisionCacheMisses)
 
 	// Uncertainty sampling: send messages for low-confidence items.
 	sendUncertaintyMessages(api, cfg, cmd, result, items)
@@ -855,6 +900,27 @@ func sanitizeReportFilenamePart(s string) string {
 	return sanitized
 }
 
+// formatDegradedBatches returns a summary suffix for LLM batches that failed
+// after retries, or "" when every batch classified cleanly.
+func formatDegradedBatches(count int) string {
+	if count <= 0 {
+		return ""
+	}
+	noun := "batches"
+	if count == 1 {
+		noun = "batch"
+	}
+	return fmt.Sprintf("\nWarning: %d LLM %s failed after retries; those items were placed in Undetermined.", count, noun)
+}
+
+// formatDecisionCacheStats is appended to the token count in /gen replies.
+func formatDecisionCacheStats(hits, misses int) string {
+	if hits+misses == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", cache hits: %d, misses: %d", hits, misses)
+}
+
 func formatTokenCount(tokens int64) string {
 	if tokens < 1000 {
 		return fmt.Sprintf("%d", tokens)
@@ -868,7 +934,7 @@ func formatTokenCount(tokens int64) string {
 	return fmt.Sprintf("%d.%dk", whole, decimal)
 }
 
-func handleListItems(api *slack.Client, db *sql.DB, cfg Config, cmd slack.SlashCommand) {
+func handleListItems(api *slack.Client, db Store, cfg Config, cmd slack.SlashCommand) {
 	scope, err := parseListScope(cmd.Text)
 	if err != nil {
 		postEphemeral(api, cmd, err.Error())
@@ -877,7 +943,7 @@ func handleListItems(api *slack.Client, db *sql.DB, cfg Config, cmd slack.SlashC
 	renderListItems(api, db, cfg, cmd.ChannelID, cmd.UserID, 0, scope)
 }
 
-func renderListItems(api *slack.Client, db *sql.DB, cfg Config, channelID, userID string, page int, scope string) {
+func renderListItems(api *slack.Client, db Store, cfg Config, channelID, userID string, page int, scope string) {
 	scope = normalizeListScope(scope)
 	monday, nextMonday := ReportWeekRange(cfg, time.Now().In(cfg.Location))
 	items, err := GetItemsByDateRange(db, monday, nextMonday)
@@ -960,18 +1026,7 @@ func renderListItems(api *slack.Client, db *sql.DB, cfg Config, channelID, userI
 
 	for idx, item := range items[start:end] {
 		lineNumber := start + idx + 1
-		source := ""
-		switch item.Source {
-		case "gitlab":
-			source = " [GitLab]"
-		case "github":
-			source = " [GitHub]"
-		}
-		category := ""
-		if item.Category != "" {
-			category = fmt.Sprintf(" _%s_", item.Category)
-		}
-		text := formatListItemText(lineNumber, item, source, category)
+		text := formatListItemText(lineNumber, item, listItemSource(item), listItemCategory(item))
 		if canManageItem(item, isManager, userID, user) {
 			editOpt := slack.NewOptionBlockObject(
 				fmt.Sprintf("edit:%s:%d", scope, item.ID),
@@ -983,7 +1038,15 @@ func renderListItems(api *slack.Client, db *sql.DB, cfg Config, channelID, userI
 				slack.NewTextBlockObject(slack.PlainTextType, "Delete", false, false),
 				nil,
 			)
-			menu := slack.NewOverflowBlockElement(actionRowMenu, editOpt, deleteOpt)
+			options := []*slack.OptionBlockObject{editOpt, deleteOpt}
+			if isManager {
+				options = append(options, slack.NewOptionBlockObject(
+					fmt.Sprintf("history:%s:%d", scope, item.ID),
+					slack.NewTextBlockObject(slack.PlainTextType, "History", false, false),
+					nil,
+				))
+			}
+			menu := slack.NewOverflowBlockElement(actionRowMenu, options...)
 			blocks = append(blocks, slack.NewSectionBlock(
 				slack.NewTextBlockObject(slack.MarkdownType, text, false, false),
 				nil,
@@ -1028,7 +1091,7 @@ func renderListItems(api *slack.Client, db *sql.DB, cfg Config, channelID, userI
 	log.Printf("list-items count=%d page=%d", len(items), page)
 }
 
-func handleListMissing(api *slack.Client, db *sql.DB, cfg Config, cmd slack.SlashCommand) {
+func handleListMissing(api *slack.Client, db Store, cfg Config, cmd slack.SlashCommand) {
 	isManager, err := isManagerUser(api, cfg, cmd.UserID)
 	if err != nil {
 		postEphemeral(api, cmd, fmt.Sprintf("Error checking permissions: %v", err))
@@ -1220,7 +1283,16 @@ func postEphemeralTo(api *slack.Client, channelID, userID, text string) {
 	}
 }
 
-func handleInteraction(api *slack.Client, db *sql.DB, cfg Config, cb slack.InteractionCallback) {
+func handleInteraction(api *slack.Client, db Store, cfg Config, cb slack.InteractionCallback) {
+	channelID := interactionChannelID(cb)
+	cfg, db, ok := resolveTeam(api, cfg, db, channelID, cb.User.ID)
+	if !ok {
+		if channelID != "" {
+			postEphemeralTo(api, channelID, cb.User.ID, unknownTeamMessage(cfg))
+		}
+		log.Printf("interaction no team for channel=%s user=%s", channelID, cb.User.ID)
+		return
+	}
 	switch cb.Type {
 	case slack.InteractionTypeBlockActions:
 		handleBlockActions(api, db, cfg, cb)
@@ -1229,7 +1301,7 @@ func handleInteraction(api *slack.Client, db *sql.DB, cfg Config, cb slack.Inter
 	}
 }
 
-func handleBlockActions(api *slack.Client, db *sql.DB, cfg Config, cb slack.InteractionCallback) {
+func handleBlockActions(api *slack.Client, db Store, cfg Config, cb slack.InteractionCallback) {
 	if len(cb.ActionCallback.BlockActions) == 0 {
 		return
 	}
@@ -1244,6 +1316,9 @@ func handleBlockActions(api *slack.Client, db *sql.DB, cfg Config, cb slack.Inte
 	case actionPagePrev, actionPageNext:
 		scope, page := parseListPageValue(act.Value)
 		renderListItems(api, db, cfg, channelID, userID, page, scope)
+	case actionSearchPrev, actionSearchNext:
+		page, query := parseSearchPageValue(act.Value)
+		renderSearchResults(api, db, cfg, channelID, userID, query, page)
 	case actionDeleteItem:
 		itemID, err := strconv.ParseInt(strings.TrimSpace(act.Value), 10, 64)
 		if err != nil {
@@ -1317,6 +1392,15 @@ func handleBlockActions(api *slack.Client, db *sql.DB, cfg Config, cb slack.Inte
 			openDeleteModal(api, db, cfg, cb.TriggerID, channelID, userID, itemID, scope)
 			return
 		}
+		if strings.HasPrefix(val, "history:") {
+			_, itemID, ok := parseListRowAction(val, "history")
+			if !ok {
+				postEphemeralTo(api, channelID, userID, "Invalid item id.")
+				return
+			}
+			showItemHistory(api, db, cfg, channelID, userID, itemID)
+			return
+		}
 	}
 
 	// Support dynamically suffixed uncertainty action IDs.
@@ -1335,7 +1419,7 @@ func handleBlockActions(api *slack.Client, db *sql.DB, cfg Config, cb slack.Inte
 	}
 }
 
-func handleViewSubmission(api *slack.Client, db *sql.DB, cfg Config, cb slack.InteractionCallback) {
+func handleViewSubmission(api *slack.Client, db Store, cfg Config, cb slack.InteractionCallback) {
 	if cb.View.CallbackID == modalNudgeConfirmCallback {
 		handleNudgeConfirm(api, db, cfg, cb)
 		return
@@ -1415,7 +1499,7 @@ func handleViewSubmission(api *slack.Client, db *sql.DB, cfg Config, cb slack.In
 		return
 	}
 
-	if err := UpdateWorkItemTextAndStatus(db, itemID, description, status); err != nil {
+	if err := UpdateWorkItemTextAndStatus(db, itemID, description, status, userID); err != nil {
 		log.Printf("edit modal update error id=%d: %v", itemID, err)
 		return
 	}
@@ -1426,7 +1510,7 @@ func handleViewSubmission(api *slack.Client, db *sql.DB, cfg Config, cb slack.In
 			newCategoryID := strings.TrimSpace(catAction.SelectedOption.Value)
 			if newCategoryID != "" && newCategoryID != noCategoryChangeValue && newCategoryID != item.Category {
 				recordCategoryCorrection(db, cfg, item, newCategoryID, userID)
-				if err := UpdateWorkItemCategory(db, itemID, newCategoryID); err != nil {
+				if err := UpdateWorkItemCategory(db, itemID, newCategoryID, userID); err != nil {
 					log.Printf("edit modal category update error id=%d: %v", itemID, err)
 				}
 			}
@@ -1442,7 +1526,7 @@ func handleViewSubmission(api *slack.Client, db *sql.DB, cfg Config, cb slack.In
 	renderListItems(api, db, cfg, channelID, userID, 0, scope)
 }
 
-func deleteItemAction(api *slack.Client, db *sql.DB, cfg Config, channelID, userID string, itemID int64, scope string) {
+func deleteItemAction(api *slack.Client, db Store, cfg Config, channelID, userID string, itemID int64, scope string) {
 	item, err := GetWorkItemByID(db, itemID)
 	if err != nil {
 		postEphemeralTo(api, channelID, userID, "Item not found.")
@@ -1461,14 +1545,14 @@ func deleteItemAction(api *slack.Client, db *sql.DB, cfg Config, channelID, user
 		return
 	}
 
-	if err := DeleteWorkItemByID(db, itemID); err != nil {
+	if err := DeleteWorkItemByID(db, itemID, userID); err != nil {
 		postEphemeralTo(api, channelID, userID, fmt.Sprintf("Delete failed: %v", err))
 		return
 	}
 	renderListItems(api, db, cfg, channelID, userID, 0, scope)
 }
 
-func openEditModal(api *slack.Client, db *sql.DB, cfg Config, triggerID, channelID, userID string, itemID int64, scope string) {
+func openEditModal(api *slack.Client, db Store, cfg Config, triggerID, channelID, userID string, itemID int64, scope string) {
 	item, err := GetWorkItemByID(db, itemID)
 	if err != nil {
 		postEphemeralTo(api, channelID, userID, "Item not found.")
@@ -1617,7 +1701,7 @@ func openEditModal(api *slack.Client, db *sql.DB, cfg Config, triggerID, channel
 	}
 }
 
-func openDeleteModal(api *slack.Client, db *sql.DB, cfg Config, triggerID, channelID, userID string, itemID int64, scope string) {
+func openDeleteModal(api *slack.Client, db Store, cfg Config, triggerID, channelID, userID string, itemID int64, scope string) {
 	item, err := GetWorkItemByID(db, itemID)
 	if err != nil {
 		postEphemeralTo(api, channelID, userID, "Item not found.")
@@ -1676,6 +1760,23 @@ func formatItemDescriptionForList(item WorkItem) string {
 	return fmt.Sprintf("[%s] %s", tickets, description)
 }
 
+func listItemSource(item WorkItem) string {
+	switch item.Source {
+	case "gitlab":
+		return " [GitLab]"
+	case "github":
+		return " [GitHub]"
+	}
+	return ""
+}
+
+func listItemCategory(item WorkItem) string {
+	if item.Category == "" {
+		return ""
+	}
+	return fmt.Sprintf(" _%s_", item.Category)
+}
+
 func formatListItemText(lineNumber int, item WorkItem, source, category string) string {
 	return fmt.Sprintf("%d. *%s*: %s (%s)%s%s",
 		lineNumber, item.Author, formatItemDescriptionForList(item), item.Status, source, category)
@@ -1865,7 +1966,7 @@ func openNudgeConfirmModal(api *slack.Client, cfg Config, triggerID, channelID,
 	}
 }
 
-func handleNudgeConfirm(api *slack.Client, db *sql.DB, cfg Config, cb slack.InteractionCallback) {
+func handleNudgeConfirm(api *slack.Client, db Store, cfg Config, cb slack.InteractionCallback) {
 	userID := cb.User.ID
 	if !cfg.IsManagerID(userID) {
 		log.Printf("nudge confirm denied user=%s (not manager)", userID)
@@ -1902,7 +2003,7 @@ func handleNudgeConfirm(api *slack.Client, db *sql.DB, cfg Config, cb slack.Inte
 	log.Printf("nudge sent from /check user=%s count=%d", userID, len(targetIDs))
 }
 
-func handleNudgeDoneAction(api *slack.Client, db *sql.DB, cfg Config, cb slack.InteractionCallback, act *slack.BlockAction) {
+func handleNudgeDoneAction(api *slack.Client, db Store, cfg Config, cb slack.InteractionCallback, act *slack.BlockAction) {
 	targetUserID, itemID, page, ok := parseNudgeDonePayload(act.Value)
 	if !ok {
 		log.Printf("nudge done invalid payload=%q", act.Value)
@@ -1915,14 +2016,14 @@ func handleNudgeDoneAction(api *slack.Client, db *sql.DB, cfg Config, cb slack.I
 		log.Printf("nudge done denied item=%d target=%s", itemID, targetUserID)
 		return
 	}
-	if err := UpdateWorkItemStatus(db, itemID, "done"); err != nil {
+	if err := MarkWorkItemDoneFromNudge(db, itemID, cb.User.ID); err != nil {
 		log.Printf("nudge done update error item=%d: %v", itemID, err)
 		return
 	}
 	refreshNudgeMessage(api, db, cfg, cb, targetUserID, page, true)
 }
 
-func handleNudgeMoreAction(api *slack.Client, db *sql.DB, cfg Config, cb slack.InteractionCallback, act *slack.BlockAction) {
+func handleNudgeMoreAction(api *slack.Client, db Store, cfg Config, cb slack.InteractionCallback, act *slack.BlockAction) {
 	val := strings.TrimSpace(act.Value)
 	if strings.TrimSpace(act.SelectedOption.Value) != "" {
 		val = strings.TrimSpace(act.SelectedOption.Value)
@@ -1939,14 +2040,14 @@ func handleNudgeMoreAction(api *slack.Client, db *sql.DB, cfg Config, cb slack.I
 		log.Printf("nudge status denied item=%d target=%s", itemID, targetUserID)
 		return
 	}
-	if err := UpdateWorkItemStatus(db, itemID, status); err != nil {
+	if err := UpdateWorkItemStatus(db, itemID, status, cb.User.ID); err != nil {
 		log.Printf("nudge status update error item=%d status=%q: %v", itemID, status, err)
 		return
 	}
 	refreshNudgeMessage(api, db, cfg, cb, targetUserID, page, true)
 }
 
-func handleNudgePageAction(api *slack.Client, db *sql.DB, cfg Config, cb slack.InteractionCallback, act *slack.BlockAction) {
+func handleNudgePageAction(api *slack.Client, db Store, cfg Config, cb slack.InteractionCallback, act *slack.BlockAction) {
 	targetUserID, page, ok := parseNudgePagePayload(act.Value)
 	if !ok {
 		log.Printf("nudge page invalid payload=%q", act.Value)
@@ -1970,7 +2071,7 @@ func authorizeNudgeAction(api *slack.Client, cb slack.InteractionCallback, targe
 	return true
 }
 
-func canActOnNudgeItem(api *slack.Client, db *sql.DB, cfg Config, itemID int64, targetUserID string) bool {
+func canActOnNudgeItem(api *slack.Client, db Store, cfg Config, itemID int64, targetUserID string) bool {
 	item, err := GetWorkItemByID(db, itemID)
 	if err != nil {
 		log.Printf("canActOnNudgeItem: failed to get work item id=%d: %v", itemID, err)
@@ -2002,7 +2103,7 @@ func canActOnNudgeItem(api *slack.Client, db *sql.DB, cfg Config, itemID int64,
 	return false
 }
 
-func refreshNudgeMessage(api *slack.Client, db *sql.DB, cfg Config, cb slack.InteractionCallback, targetUserID string, page int, updated bool) {
+func refreshNudgeMessage(api *slack.Client, db Store, cfg Config, cb slack.InteractionCallback, targetUserID string, page int, updated bool) {
 	channelID := nudgeActionChannelID(cb)
 	messageTS := strings.TrimSpace(cb.Container.MessageTs)
 	if messageTS == "" {
@@ -2087,7 +2188,7 @@ func parseNudgePagePayload(raw string) (targetUserID string, page int, ok bool)
 	return strings.TrimSpace(parts[1]), page, true
 }
 
-func handleReportStats(api *slack.Client, db *sql.DB, cfg Config, cmd slack.SlashCommand) {
+func handleReportStats(api *slack.Client, db Store, cfg Config, cmd slack.SlashCommand) {
 	isManager, err := isManagerUser(api, cfg, cmd.UserID)
 	if err != nil {
 		postEphemeral(api, cmd, fmt.Sprintf("Error checking permissions: %v", err))
@@ -2185,10 +2286,55 @@ func handleReportStats(api *slack.Client, db *sql.DB, cfg Config, cmd slack.Slas
 		}
 	}
 
+	// LLM spend.
+	weekStart, monthStart := budgetPeriodStarts(cfg, time.Now())
+	weekSpend, weekErr := GetLLMSpendSince(db, weekStart)
+	monthUsage, monthErr := GetLLMUsageSummary(db, monthStart)
+	if weekErr != nil || monthErr != nil {
+		log.Printf("stats llm usage error (non-fatal): week=%v month=%v", weekErr, monthErr)
+	} else {
+		sb.WriteString(formatLLMSpend(cfg, weekSpend, monthUsage))
+	}
+
 	postEphemeral(api, cmd, sb.String())
 	log.Printf("stats sent user=%s", cmd.UserID)
 }
 
+// formatLLMSpend renders the /stats spend section: this week's and this
+// month's cost against any configured budget, then the month by model.
+func formatLLMSpend(cfg Config, weekSpend float64, monthUsage []LLMUsageSummary) string {
+	var monthSpend float64
+	var monthTokens int64
+	for _, u := range monthUsage {
+		monthSpend += u.CostUSD
+		monthTokens += u.InputTokens + u.OutputTokens
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n*LLM Spend*\n")
+	sb.WriteString(fmt.Sprintf("- This week: %s\n", formatSpendAgainstBudget(weekSpend, cfg.LLMBudgetWeeklyUSD)))
+	sb.WriteString(fmt.Sprintf("- This month: %s (tokens: %s)\n", formatSpendAgainstBudget(monthSpend, cfg.LLMBudgetMonthlyUSD), formatTokenCount(monthTokens)))
+	for _, u := range monthUsage {
+		sb.WriteString(fmt.Sprintf("  - %s/%s %s: %d calls, %s tokens, $%.2f\n",
+			u.Provider, u.Model, u.Purpose, u.Calls, formatTokenCount(u.InputTokens+u.OutputTokens), u.CostUSD))
+	}
+	if len(cfg.LLMPrices) == 0 && monthTokens > 0 {
+		sb.WriteString("- No llm_prices configured; costs show as $0.00\n")
+	}
+	return sb.String()
+}
+
+func formatSpendAgainstBudget(spend, budget float64) string {
+	if budget <= 0 {
+		return fmt.Sprintf("$%.2f", spend)
+	}
+	text := fmt.Sprintf("$%.2f of $%.2f budget", spend, budget)
+	if spend >= budget {
+		text += " (exhausted: critic and /retrospect paused)"
+	}
+	return text
+}
+
 func handleHelp(api *slack.Client, cfg Config, cmd slack.SlashCommand) {
 	isManager, err := isManagerUser(api, cfg, cmd.UserID)
 	if err != nil {
@@ -2212,7 +2358,9 @@ func handleHelp(api *slack.Client, cfg Config, cmd slack.SlashCommand) {
 		">(in progress)```",
 		"",
 		"`/list` — List your items for this week (`/list all` for the team).",
+		"`/search <query> [author:x] [since:2026-01-01] [status:done]` — Search all reported items.",
 		"`/nudge` — Send yourself a test nudge DM.",
+		"`/whoami [link gitlab:<username>|github:<login>|email:<address>]` — Link your Git accounts so fetched MRs/PRs are attributed to you.",
 		"`/help` — Show this help.",
 	}
 
@@ -2228,6 +2376,9 @@ func handleHelp(api *slack.Client, cfg Config, cmd slack.SlashCommand) {
 			"`/nudge <member>` — Send a test nudge DM to one member.",
 			"`/retrospect` — Analyze recent corrections and suggest improvements.",
 			"`/stats` — Show classification accuracy dashboard.",
+			"`/history <item-id>` — Show who changed an item and when (`/history deleted`, `/history restore <item-id>`).",
+			"`/backup` — Back up the database now and report size and duration.",
+			"`/whoami [link|unlink] @user ...` — Show or change a member's linked Git accounts; linking confirms the account and moves it from anyone who claimed it.",
 		)
 	}
 
@@ -2364,7 +2515,7 @@ func loadSectionOptionsForModal(cfg Config) []sectionOption {
 	return templateOptions(template)
 }
 
-func recordCategoryCorrection(db *sql.DB, cfg Config, item WorkItem, newCategoryID, userID string) {
+func recordCategoryCorrection(db Store, cfg Config, item WorkItem, newCategoryID, userID string) {
 	originalSectionID := item.Category
 	originalLabel := ""
 
@@ -2403,7 +2554,7 @@ func recordCategoryCorrection(db *sql.DB, cfg Config, item WorkItem, newCategory
 	tryAutoGrowGlossary(db, cfg, item.Description, newCategoryID, correctedLabel)
 }
 
-func tryAutoGrowGlossary(db *sql.DB, cfg Config, description, sectionID, sectionLabel string) {
+func tryAutoGrowGlossary(db Store, cfg Config, description, sectionID, sectionLabel string) {
 	if strings.TrimSpace(cfg.LLMGlossaryPath) == "" {
 		return
 	}
@@ -2481,14 +2632,11 @@ func sendUncertaintyMessages(api *slack.Client, cfg Config, cmd slack.SlashComma
 		}
 		headerText := fmt.Sprintf("Uncertain classification (%.0f%% confidence)\n_%s_\nBest guess: %s", u.confidence*100, desc, bestGuess)
 
-		// Build section buttons (up to 4 most common sections).
+		// Build section buttons: the model's best guess and alternatives
+		// first, then the most common sections, up to 4 in total.
 		var buttons []slack.BlockElement
-		limit := 4
-		if len(result.Options) < limit {
-			limit = len(result.Options)
-		}
-		for i := 0; i < limit; i++ {
-			opt := result.Options[i]
+		candidates := uncertaintyButtonOptions(result.Options, u.decision, 4)
+		for i, opt := range candidates {
 			label := opt.Label
 			if strings.TrimSpace(label) == "" {
 				continue
@@ -2532,7 +2680,35 @@ func sendUncertaintyMessages(api *slack.Client, cfg Config, cmd slack.SlashComma
 	log.Printf("uncertainty messages sent count=%d attempted=%d", sent, len(uncertain))
 }
 
-func handleUncertaintySelect(api *slack.Client, db *sql.DB, cfg Config, cb slack.InteractionCallback, act *slack.BlockAction) {
+// uncertaintyButtonOptions orders section choices for an uncertain item:
+// the chosen section, then the model's alternatives, then remaining options
+// in template order.
+func uncertaintyButtonOptions(options []sectionOption, decision LLMSectionDecision, limit int) []sectionOption {
+	byID := make(map[string]sectionOption, len(options))
+	for _, opt := range options {
+		byID[opt.ID] = opt
+	}
+	var out []sectionOption
+	seen := map[string]bool{}
+	add := func(id string) {
+		opt, ok := byID[id]
+		if !ok || seen[id] || len(out) >= limit {
+			return
+		}
+		seen[id] = true
+		out = append(out, opt)
+	}
+	add(decision.SectionID)
+	for _, id := range decision.Alternatives {
+		add(id)
+	}
+	for _, opt := range options {
+		add(opt.ID)
+	}
+	return out
+}
+
+func handleUncertaintySelect(api *slack.Client, db Store, cfg Config, cb slack.InteractionCallback, act *slack.BlockAction) {
 	channelID := cb.Channel.ID
 	if channelID == "" {
 		channelID = cb.Container.ChannelID
@@ -2559,7 +2735,7 @@ func handleUncertaintySelect(api *slack.Client, db *sql.DB, cfg Config, cb slack
 	}
 
 	recordCategoryCorrection(db, cfg, item, sectionID, userID)
-	if err := UpdateWorkItemCategory(db, itemID, sectionID); err != nil {
+	if err := UpdateWorkItemCategory(db, itemID, sectionID, userID); err != nil {
 		log.Printf("uncertainty category update error id=%d: %v", itemID, err)
 	}
 
@@ -2568,7 +2744,7 @@ func handleUncertaintySelect(api *slack.Client, db *sql.DB, cfg Config, cb slack
 
 // --- Retrospective ---
 
-func handleRetrospective(api *slack.Client, db *sql.DB, cfg Config, cmd slack.SlashCommand) {
+func handleRetrospective(api *slack.Client, db Store, cfg Config, cmd slack.SlashCommand) {
 	isManager, err := isManagerUser(api, cfg, cmd.UserID)
 	if err != nil {
 		postEphemeral(api, cmd, fmt.Sprintf("Error checking permissions: %v", err))
@@ -2594,6 +2770,12 @@ func handleRetrospective(api *slack.Client, db *sql.DB, cfg Config, cmd slack.Sl
 		return
 	}
 
+	if err := checkOptionalPassBudget(cfg); err != nil {
+		postEphemeral(api, cmd, fmt.Sprintf("Retrospective skipped: %v.", err))
+		log.Printf("retrospective skipped: %v", err)
+		return
+	}
+
 	postEphemeral(api, cmd, fmt.Sprintf("Analyzing %d corrections from the last 4 weeks...", len(corrections)))
 
 	sectionOpts := loadSectionOptionsForModal(cfg)
@@ -2663,7 +2845,7 @@ func handleRetrospective(api *slack.Client, db *sql.DB, cfg Config, cmd slack.Sl
 	log.Printf("retrospective done suggestions=%d tokens=%d", len(suggestions), usage.TotalTokens())
 }
 
-func handleRetroApply(api *slack.Client, db *sql.DB, cfg Config, cb slack.InteractionCallback, act *slack.BlockAction) {
+func handleRetroApply(api *slack.Client, db Store, cfg Config, cb slack.InteractionCallback, act *slack.BlockAction) {
 	channelID := cb.Channel.ID
 	if channelID == "" {
 		channelID = cb.Container.ChannelID
@@ -0,0 +1,34 @@
+package slackbot
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+func handleBackup(api *slack.Client, db Store, cfg Config, cmd slack.SlashCommand) {
+	isManager, err := isManagerUser(api, cfg, cmd.UserID)
+	if err != nil {
+		postEphemeral(api, cmd, fmt.Sprintf("Error checking permissions: %v", err))
+		log.Printf("backup auth error user=%s: %v", cmd.UserID, err)
+		return
+	}
+	if !isManager {
+		postEphemeral(api, cmd, "Sorry, only managers can use this command.")
+		log.Printf("backup denied user=%s", cmd.UserID)
+		return
+	}
+
+	postEphemeral(api, cmd, "Backing up the database...")
+	result, err := RunBackup(cfg, db, time.Now().In(cfg.Location))
+	if err != nil {
+		postEphemeral(api, cmd, fmt.Sprintf("Backup failed: %v", err))
+		log.Printf("backup error user=%s: %v", cmd.UserID, err)
+		return
+	}
+	log.Printf("backup user=%s path=%s size=%d duration=%s removed=%d",
+		cmd.UserID, result.Path, result.Size, result.Duration, len(result.Removed))
+	postEphemeral(api, cmd, FormatBackupResult(result))
+}
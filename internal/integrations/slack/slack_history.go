@@ -0,0 +1,174 @@
+package slackbot
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+const historyUsage = "Usage: `/history <item-id>`, `/history restore <item-id>` or `/history deleted`"
+
+// parseHistoryArgs splits /history text into an action (show, restore or
+// deleted) and an item ID.
+func parseHistoryArgs(text string) (string, int64, error) {
+	fields := strings.Fields(strings.ToLower(strings.TrimSpace(text)))
+	parseID := func(raw string) (int64, error) {
+		id, err := strconv.ParseInt(strings.TrimPrefix(raw, "#"), 10, 64)
+		if err != nil || id <= 0 {
+			return 0, fmt.Errorf("Invalid item id %q. %s", raw, historyUsage)
+		}
+		return id, nil
+	}
+	switch {
+	case len(fields) == 1 && fields[0] == "deleted":
+		return "deleted", 0, nil
+	case len(fields) == 1:
+		id, err := parseID(fields[0])
+		return "show", id, err
+	case len(fields) == 2 && fields[0] == "restore":
+		id, err := parseID(fields[1])
+		return "restore", id, err
+	default:
+		return "", 0, fmt.Errorf("%s", historyUsage)
+	}
+}
+
+func handleItemHistory(api *slack.Client, db Store, cfg Config, cmd slack.SlashCommand) {
+	isManager, err := isManagerUser(api, cfg, cmd.UserID)
+	if err != nil {
+		postEphemeral(api, cmd, fmt.Sprintf("Error checking permissions: %v", err))
+		log.Printf("history auth error user=%s: %v", cmd.UserID, err)
+		return
+	}
+	if !isManager {
+		postEphemeral(api, cmd, "Sorry, only managers can use this command.")
+		log.Printf("history denied user=%s", cmd.UserID)
+		return
+	}
+
+	action, itemID, err := parseHistoryArgs(cmd.Text)
+	if err != nil {
+		postEphemeral(api, cmd, err.Error())
+		return
+	}
+
+	switch action {
+	case "deleted":
+		monday, nextMonday := ReportWeekRange(cfg, time.Now().In(cfg.Location))
+		items, err := GetDeletedItemsByDateRange(db, monday, nextMonday)
+		if err != nil {
+			postEphemeral(api, cmd, fmt.Sprintf("Error loading deleted items: %v", err))
+			log.Printf("history deleted error user=%s: %v", cmd.UserID, err)
+			return
+		}
+		postEphemeral(api, cmd, formatDeletedItems(items))
+	case "restore":
+		if err := RestoreWorkItem(db, itemID, cmd.UserID); err != nil {
+			postEphemeral(api, cmd, fmt.Sprintf("Restore failed: %v", err))
+			log.Printf("history restore error user=%s item=%d: %v", cmd.UserID, itemID, err)
+			return
+		}
+		postEphemeral(api, cmd, fmt.Sprintf("Item %d restored.", itemID))
+		log.Printf("history restore user=%s item=%d", cmd.UserID, itemID)
+	default:
+		text, err := loadItemHistoryText(db, cfg, itemID)
+		if err != nil {
+			postEphemeral(api, cmd, err.Error())
+			return
+		}
+		postEphemeral(api, cmd, text)
+	}
+}
+
+// showItemHistory answers the History option of a /list row.
+func showItemHistory(api *slack.Client, db Store, cfg Config, channelID, userID string, itemID int64) {
+	if isManager, _ := isManagerUser(api, cfg, userID); !isManager {
+		postEphemeralTo(api, channelID, userID, "Sorry, only managers can view item history.")
+		return
+	}
+	text, err := loadItemHistoryText(db, cfg, itemID)
+	if err != nil {
+		postEphemeralTo(api, channelID, userID, err.Error())
+		return
+	}
+	postEphemeralTo(api, channelID, userID, text)
+}
+
+func loadItemHistoryText(db Store, cfg Config, itemID int64) (string, error) {
+	events, err := GetWorkItemEvents(db, itemID)
+	if err != nil {
+		log.Printf("history load error item=%d: %v", itemID, err)
+		return "", fmt.Errorf("Error loading history: %v", err)
+	}
+	if len(events) == 0 {
+		return "", fmt.Errorf("No history recorded for item %d.", itemID)
+	}
+	return formatItemHistory(itemID, events, cfg.Location), nil
+}
+
+// formatItemHistory renders an item's events oldest first. The item counts
+// as deleted when its latest delete is not followed by a restore.
+func formatItemHistory(itemID int64, events []WorkItemEvent, loc *time.Location) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+	deleted := false
+	var lines []string
+	for _, e := range events {
+		actor := "ReportBot"
+		if e.ActorID != "" {
+			actor = fmt.Sprintf("<@%s>", e.ActorID)
+		}
+		var what string
+		switch e.EventType {
+		case WorkItemEventCreated:
+			what = fmt.Sprintf("created by %s: %q", actor, e.NewValue)
+		case WorkItemEventEdited:
+			what = fmt.Sprintf("edited by %s: %q → %q", actor, e.OldValue, e.NewValue)
+		case WorkItemEventStatusChanged:
+			what = fmt.Sprintf("status changed by %s: %s → %s", actor, historyValue(e.OldValue), historyValue(e.NewValue))
+		case WorkItemEventCategoryChanged:
+			what = fmt.Sprintf("category changed by %s: %s → %s", actor, historyValue(e.OldValue), historyValue(e.NewValue))
+		case WorkItemEventNudgeDone:
+			what = fmt.Sprintf("marked done from nudge by %s (was %s)", actor, historyValue(e.OldValue))
+		case WorkItemEventDeleted:
+			what = fmt.Sprintf("deleted by %s", actor)
+			deleted = true
+		case WorkItemEventRestored:
+			what = fmt.Sprintf("restored by %s", actor)
+			deleted = false
+		default:
+			what = fmt.Sprintf("%s by %s: %s → %s", e.EventType, actor, historyValue(e.OldValue), historyValue(e.NewValue))
+		}
+		lines = append(lines, fmt.Sprintf("• %s — %s", e.CreatedAt.In(loc).Format("Mon Jan 2 15:04"), what))
+	}
+
+	header := fmt.Sprintf("*History of item %d*", itemID)
+	if deleted {
+		header += fmt.Sprintf(" (deleted; `/history restore %d` to restore)", itemID)
+	}
+	return header + "\n" + strings.Join(lines, "\n")
+}
+
+func historyValue(v string) string {
+	if strings.TrimSpace(v) == "" {
+		return "_none_"
+	}
+	return "`" + v + "`"
+}
+
+func formatDeletedItems(items []WorkItem) string {
+	if len(items) == 0 {
+		return "No items were deleted this week."
+	}
+	lines := []string{fmt.Sprintf("*Deleted this week (%d)*", len(items))}
+	for _, item := range items {
+		lines = append(lines, fmt.Sprintf("• `%d` *%s*: %s (%s)", item.ID, item.Author, item.Description, item.Status))
+	}
+	lines = append(lines, "Restore with `/history restore <item-id>`.")
+	return strings.Join(lines, "\n")
+}
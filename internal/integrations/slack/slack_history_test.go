@@ -0,0 +1,58 @@
+package slackbot
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseHistoryArgs(t *testing.T) {
+	cases := []struct {
+		text   string
+		action string
+		id     int64
+		ok     bool
+	}{
+		{"42", "show", 42, true},
+		{"#42", "show", 42, true},
+		{"restore 7", "restore", 7, true},
+		{"DELETED", "deleted", 0, true},
+		{"", "", 0, false},
+		{"restore", "", 0, false},
+		{"abc", "", 0, false},
+		{"restore -1", "", 0, false},
+	}
+	for _, c := range cases {
+		action, id, err := parseHistoryArgs(c.text)
+		if (err == nil) != c.ok || (c.ok && (action != c.action || id != c.id)) {
+			t.Fatalf("parseHistoryArgs(%q) = %q, %d, %v", c.text, action, id, err)
+		}
+	}
+}
+
+func TestFormatItemHistory(t *testing.T) {
+	at := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	events := []WorkItemEvent{
+		{EventType: WorkItemEventCreated, NewValue: "Fix login", ActorID: "U1", CreatedAt: at},
+		{EventType: WorkItemEventStatusChanged, OldValue: "in progress", NewValue: "done", CreatedAt: at},
+		{EventType: WorkItemEventCategoryChanged, NewValue: "S0_0", ActorID: "UMGR", CreatedAt: at},
+		{EventType: WorkItemEventDeleted, OldValue: "Fix login", ActorID: "UMGR", CreatedAt: at},
+	}
+	got := formatItemHistory(5, events, time.UTC)
+	for _, want := range []string{
+		"*History of item 5* (deleted; `/history restore 5` to restore)",
+		"Mon Mar 2 09:30 — created by <@U1>: \"Fix login\"",
+		"status changed by ReportBot: `in progress` → `done`",
+		"category changed by <@UMGR>: _none_ → `S0_0`",
+		"deleted by <@UMGR>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in:\n%s", want, got)
+		}
+	}
+
+	events = append(events, WorkItemEvent{EventType: WorkItemEventRestored, ActorID: "UMGR", CreatedAt: at})
+	if got := formatItemHistory(5, events, time.UTC); strings.Contains(got, "(deleted") {
+		t.Fatalf("expected restored item not to be marked deleted:\n%s", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"reportbot/internal/config"
 	"strings"
 	"testing"
 	"time"
@@ -64,6 +65,9 @@ func TestParseGenerateReportArgs(t *testing.T) {
 		{name: "post private", input: "post private", wantMode: "post", wantPrivate: true},
 		{name: "private only", input: "private", wantMode: "team", wantPrivate: true},
 		{name: "boss channel", input: "boss channel", wantMode: "boss", wantPrivate: false},
+		{name: "rollup private", input: "rollup private", wantMode: "rollup", wantPrivate: true},
+		{name: "quarter", input: "quarter", wantMode: "quarter", wantPrivate: false},
+		{name: "conflicting periods", input: "month quarter", wantErr: true},
 		{name: "conflicting modes", input: "post boss", wantErr: true},
 		{name: "unknown token", input: "boss now", wantErr: true},
 	}
@@ -491,3 +495,57 @@ func TestFindLatestTeamReportFile_NoMatch(t *testing.T) {
 		t.Fatal("expected error when no matching team report exists")
 	}
 }
+
+func TestFormatDegradedBatches(t *testing.T) {
+	if got := formatDegradedBatches(0); got != "" {
+		t.Fatalf("expected empty suffix, got %q", got)
+	}
+	if got := formatDegradedBatches(1); !strings.Contains(got, "1 LLM batch failed") {
+		t.Fatalf("unexpected singular text: %q", got)
+	}
+	if got := formatDegradedBatches(3); !strings.Contains(got, "3 LLM batches failed") || !strings.Contains(got, "Undetermined") {
+		t.Fatalf("unexpected plural text: %q", got)
+	}
+}
+
+func TestUncertaintyButtonOptions_PrefersAlternatives(t *testing.T) {
+	options := []sectionOption{{ID: "S0_0", Label: "A"}, {ID: "S1_0", Label: "B"}, {ID: "S2_0", Label: "C"}, {ID: "S3_0", Label: "D"}, {ID: "S4_0", Label: "E"}}
+	decision := LLMSectionDecision{SectionID: "S3_0", Alternatives: []string{"S4_0", "S3_0"}}
+
+	got := uncertaintyButtonOptions(options, decision, 4)
+	var ids []string
+	for _, opt := range got {
+		ids = append(ids, opt.ID)
+	}
+	if strings.Join(ids, ",") != "S3_0,S4_0,S0_0,S1_0" {
+		t.Fatalf("unexpected button order: %v", ids)
+	}
+}
+
+func TestFormatDecisionCacheStats(t *testing.T) {
+	if got := formatDecisionCacheStats(0, 0); got != "" {
+		t.Fatalf("expected empty suffix without classification, got %q", got)
+	}
+	if got := formatDecisionCacheStats(40, 5); got != ", cache hits: 40, misses: 5" {
+		t.Fatalf("unexpected cache stats text: %q", got)
+	}
+}
+
+func TestFormatLLMSpend(t *testing.T) {
+	cfg := Config{LLMBudgetWeeklyUSD: 2, LLMBudgetMonthlyUSD: 10, LLMPrices: map[string]config.LLMPrice{"m": {}}}
+	got := formatLLMSpend(cfg, 2.5, []LLMUsageSummary{
+		{Provider: "openai", Model: "m", Purpose: "classify", Calls: 3, InputTokens: 1500, OutputTokens: 500, CostUSD: 3.25},
+	})
+	for _, want := range []string{
+		"This week: $2.50 of $2.00 budget (exhausted",
+		"This month: $3.25 of $10.00 budget (tokens: 2k)",
+		"openai/m classify: 3 calls, 2k tokens, $3.25",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "No llm_prices") {
+		t.Fatalf("did not expect missing-prices hint: %s", got)
+	}
+}
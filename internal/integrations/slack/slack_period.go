@@ -0,0 +1,102 @@
+package slackbot
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// periodLabelLimit caps the classified items loaded to place a period's
+// work items in sections; a quarter of a large team stays well below it.
+const periodLabelLimit = 20000
+
+// handlePeriodReport handles /generate-report month|quarter: aggregate the
+// work items of the month or quarter containing the current report week's
+// Monday into one report grouped like the latest weekly report.
+func handlePeriodReport(api *slack.Client, db Store, cfg Config, cmd slack.SlashCommand, kind string, monday time.Time, sendPrivate bool) {
+	p, err := PeriodContaining(kind, monday)
+	if err != nil {
+		postEphemeral(api, cmd, err.Error())
+		return
+	}
+	items, err := GetItemsByDateRange(db, p.Start, p.End)
+	if err != nil {
+		postEphemeral(api, cmd, fmt.Sprintf("Error loading items: %v", err))
+		log.Printf("generate-report %s load error: %v", kind, err)
+		return
+	}
+	if len(items) == 0 {
+		postEphemeral(api, cmd, fmt.Sprintf("No work items found for %s.", p.Label()))
+		return
+	}
+
+	sections := make(map[int64]string)
+	labeled, err := GetLabeledWorkItems(db, p.Start, periodLabelLimit)
+	if err != nil {
+		log.Printf("generate-report %s labels load error (non-fatal): %v", kind, err)
+	}
+	for _, l := range labeled {
+		sections[l.Item.ID] = l.FinalLabel
+	}
+
+	res, err := BuildPeriodReport(cfg, p, items, sections, cfg.PeriodLLMSummary)
+	if err != nil {
+		postEphemeral(api, cmd, fmt.Sprintf("Error building report: %v", err))
+		log.Printf("generate-report %s build error: %v", kind, err)
+		return
+	}
+	filePath, err := WritePeriodReportFile(RenderPeriodMarkdown(cfg.TeamName, res), cfg.ReportOutputDir, cfg.TeamName, p)
+	if err != nil {
+		postEphemeral(api, cmd, fmt.Sprintf("Error writing report file: %v", err))
+		log.Printf("generate-report %s write error: %v", kind, err)
+		return
+	}
+
+	tokens := formatTokenCount(res.Usage.TotalTokens())
+	comment := fmt.Sprintf("%s report for %s: %d items in %d lines (tokens used: %s)", cfg.TeamName, p.Label(), res.Items, res.Lines, tokens)
+	if err := uploadReportFile(api, cmd, filePath, fmt.Sprintf("%s %s report", cfg.TeamName, p.Label()), comment, sendPrivate); err != nil {
+		log.Printf("generate-report %s upload error path=%s: %v", kind, filePath, err)
+		return
+	}
+	postEphemeral(api, cmd, fmt.Sprintf("%s report generated with %d items (tokens used: %s)\nSaved to: %s", p.Label(), res.Items, tokens, filePath))
+	log.Printf("generate-report done mode=%s period=%q items=%d lines=%d", kind, p.Label(), res.Items, res.Lines)
+}
+
+// uploadReportFile uploads a generated file to the command's channel, or
+// to the caller's DM when sendPrivate is set. Failures are reported to the
+// caller before they are returned.
+func uploadReportFile(api *slack.Client, cmd slack.SlashCommand, filePath, title, comment string, sendPrivate bool) error {
+	fi, err := os.Stat(filePath)
+	if err != nil || fi.Size() <= 0 {
+		postEphemeral(api, cmd, "Error: generated report file is empty.")
+		return fmt.Errorf("generated file is empty or inaccessible: %v", err)
+	}
+
+	uploadChannel := cmd.ChannelID
+	if sendPrivate {
+		ch, _, _, err := api.OpenConversation(&slack.OpenConversationParameters{Users: []string{cmd.UserID}})
+		if err != nil {
+			postEphemeral(api, cmd, "Error opening DM to send private report. Check bot permissions.")
+			return fmt.Errorf("open DM: %w", err)
+		}
+		uploadChannel = ch.ID
+	}
+
+	_, err = api.UploadFileV2(slack.UploadFileV2Parameters{
+		File:           filePath,
+		FileSize:       int(fi.Size()),
+		Filename:       filepath.Base(filePath),
+		Channel:        uploadChannel,
+		Title:          title,
+		InitialComment: comment,
+	})
+	if err != nil {
+		postEphemeral(api, cmd, "Error uploading report file to channel. Check bot permissions.")
+		return fmt.Errorf("upload: %w", err)
+	}
+	return nil
+}
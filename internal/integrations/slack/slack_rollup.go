@@ -0,0 +1,41 @@
+package slackbot
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// postRollupReport handles /generate-report rollup: combine the latest
+// report of every configured team into the department roll-up and upload
+// its .eml draft.
+func postRollupReport(api *slack.Client, cfg Config, cmd slack.SlashCommand, friday time.Time, sendPrivate bool) {
+	if !cfg.MultiTeam() {
+		postEphemeral(api, cmd, "The roll-up combines the reports of several teams. Configure teams in config.yaml, or run `reportbot rollup -team <name>=<dir>` for separate deployments.")
+		return
+	}
+
+	r, err := BuildRollup(cfg, RollupSources(cfg), friday, cfg.RollupLLMSummary)
+	if err != nil {
+		postEphemeral(api, cmd, fmt.Sprintf("Error building roll-up: %v", err))
+		log.Printf("rollup build error: %v", err)
+		return
+	}
+	mdPath, emlPath, err := WriteRollupFiles(r, cfg.RollupOutputDir)
+	if err != nil {
+		postEphemeral(api, cmd, fmt.Sprintf("Error writing roll-up: %v", err))
+		log.Printf("rollup write error: %v", err)
+		return
+	}
+	title := fmt.Sprintf("%s roll-up email draft", r.Title)
+	comment := fmt.Sprintf("%s (tokens used: %s)", FormatRollupSummary(r), formatTokenCount(r.Usage.TotalTokens()))
+	if err := uploadReportFile(api, cmd, emlPath, title, comment, sendPrivate); err != nil {
+		log.Printf("rollup upload error path=%s: %v", emlPath, err)
+		return
+	}
+
+	postEphemeral(api, cmd, fmt.Sprintf("Roll-up generated for %d teams\nSaved to: %s and %s", len(r.Teams), mdPath, emlPath))
+	log.Printf("rollup done teams=%d md=%s eml=%s", len(r.Teams), mdPath, emlPath)
+}
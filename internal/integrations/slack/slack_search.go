@@ -0,0 +1,205 @@
+package slackbot
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+const (
+	searchPageSize   = 10
+	actionSearchPrev = "search_page_prev"
+	actionSearchNext = "search_page_next"
+	searchUsage      = "Usage: `/search <query> [author:name] [since:2026-01-01] [status:done]`"
+	// Slack rejects button values longer than this.
+	maxButtonValueLen = 2000
+)
+
+var slackUserMentionRegex = regexp.MustCompile(`^<@([A-Z0-9]+)(?:\|[^>]*)?>$`)
+
+// parseSearchQuery turns /search text into a SearchQuery. Bare words are
+// matched against description, author, ticket IDs and source ref; double
+// quotes keep a phrase or filter value together.
+func parseSearchQuery(text string, loc *time.Location) (SearchQuery, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	var q SearchQuery
+	var terms []string
+	for _, tok := range splitSearchTokens(text) {
+		key, value, ok := strings.Cut(tok, ":")
+		if !ok || value == "" {
+			terms = append(terms, tok)
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "author":
+			if m := slackUserMentionRegex.FindStringSubmatch(value); m != nil {
+				q.AuthorID = m[1]
+			} else {
+				q.Author = strings.TrimPrefix(value, "@")
+			}
+		case "since":
+			since, err := time.ParseInLocation("2006-01-02", value, loc)
+			if err != nil {
+				return SearchQuery{}, fmt.Errorf("Invalid date %q, expected YYYY-MM-DD. %s", value, searchUsage)
+			}
+			q.Since = since
+		case "status":
+			q.Status = strings.NewReplacer("-", " ", "_", " ").Replace(strings.ToLower(value))
+		default:
+			terms = append(terms, tok)
+		}
+	}
+	q.Text = strings.Join(terms, " ")
+	if q.Text == "" && q.Author == "" && q.AuthorID == "" && q.Status == "" && q.Since.IsZero() {
+		return SearchQuery{}, fmt.Errorf("%s", searchUsage)
+	}
+	return q, nil
+}
+
+// splitSearchTokens splits on whitespace outside double quotes. Slack clients
+// often turn straight quotes into curly ones, so both are accepted.
+func splitSearchTokens(text string) []string {
+	var tokens []string
+	var cur strings.Builder
+	quoted := false
+	flush := func() {
+		if tok := strings.TrimSpace(cur.String()); tok != "" {
+			tokens = append(tokens, tok)
+		}
+		cur.Reset()
+	}
+	for _, r := range text {
+		switch {
+		case r == '"' || r == '“' || r == '”':
+			quoted = !quoted
+		case !quoted && (r == ' ' || r == '\t' || r == '\n'):
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+func handleSearch(api *slack.Client, db Store, cfg Config, cmd slack.SlashCommand) {
+	if _, err := parseSearchQuery(cmd.Text, cfg.Location); err != nil {
+		postEphemeral(api, cmd, err.Error())
+		return
+	}
+	renderSearchResults(api, db, cfg, cmd.ChannelID, cmd.UserID, strings.TrimSpace(cmd.Text), 0)
+}
+
+func renderSearchResults(api *slack.Client, db Store, cfg Config, channelID, userID, rawQuery string, page int) {
+	q, err := parseSearchQuery(rawQuery, cfg.Location)
+	if err != nil {
+		postEphemeralTo(api, channelID, userID, err.Error())
+		return
+	}
+	if page < 0 {
+		page = 0
+	}
+	// Fetch one extra row to know whether there is a next page.
+	q.Limit = searchPageSize + 1
+	q.Offset = page * searchPageSize
+	items, err := SearchWorkItems(db, q)
+	if err != nil {
+		postEphemeralTo(api, channelID, userID, fmt.Sprintf("Search failed: %v", err))
+		log.Printf("search error user=%s query=%q: %v", userID, rawQuery, err)
+		return
+	}
+	hasNext := len(items) > searchPageSize
+	if hasNext {
+		items = items[:searchPageSize]
+	}
+	log.Printf("search user=%s query=%q page=%d results=%d", userID, rawQuery, page, len(items))
+
+	blocks := buildSearchResultBlocks(rawQuery, page, items, hasNext, cfg.Location)
+	if _, err := api.PostEphemeral(channelID, userID, slack.MsgOptionBlocks(blocks...)); err != nil {
+		log.Printf("Error posting search blocks: %v", err)
+		postEphemeralTo(api, channelID, userID, "Error rendering search results.")
+	}
+}
+
+func buildSearchResultBlocks(rawQuery string, page int, items []WorkItem, hasNext bool, loc *time.Location) []slack.Block {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if len(items) == 0 {
+		text := fmt.Sprintf("No items match `%s`.", rawQuery)
+		if page > 0 {
+			text = fmt.Sprintf("No more items match `%s`.", rawQuery)
+		}
+		return []slack.Block{slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil,
+		)}
+	}
+
+	start := page * searchPageSize
+	blocks := []slack.Block{
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType,
+				fmt.Sprintf("*Search results for* `%s` (%d–%d)", rawQuery, start+1, start+len(items)),
+				false, false),
+			nil, nil,
+		),
+	}
+	for idx, item := range items {
+		blocks = append(blocks, slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType, formatSearchItemText(start+idx+1, item, loc), false, false),
+			nil, nil,
+		))
+	}
+
+	var nav []slack.BlockElement
+	if page > 0 {
+		nav = append(nav, slack.NewButtonBlockElement(
+			actionSearchPrev,
+			formatSearchPageValue(page-1, rawQuery),
+			slack.NewTextBlockObject(slack.PlainTextType, "Prev", false, false),
+		))
+	}
+	if hasNext {
+		nav = append(nav, slack.NewButtonBlockElement(
+			actionSearchNext,
+			formatSearchPageValue(page+1, rawQuery),
+			slack.NewTextBlockObject(slack.PlainTextType, "Next", false, false),
+		))
+	}
+	if len(nav) > 0 && len(formatSearchPageValue(page+1, rawQuery)) <= maxButtonValueLen {
+		blocks = append(blocks, slack.NewActionBlock("search_nav", nav...))
+	}
+	return blocks
+}
+
+// formatSearchItemText reuses the /list row format, links the source label to
+// the MR/PR and appends the date the item was reported.
+func formatSearchItemText(lineNumber int, item WorkItem, loc *time.Location) string {
+	source := listItemSource(item)
+	if source != "" && strings.HasPrefix(item.SourceRef, "http") {
+		source = fmt.Sprintf(" <%s|%s>", item.SourceRef, strings.TrimSpace(source))
+	}
+	return fmt.Sprintf("%s — %s",
+		formatListItemText(lineNumber, item, source, listItemCategory(item)),
+		item.ReportedAt.In(loc).Format("Jan 2, 2006"))
+}
+
+func formatSearchPageValue(page int, rawQuery string) string {
+	return fmt.Sprintf("%d|%s", page, rawQuery)
+}
+
+func parseSearchPageValue(raw string) (int, string) {
+	pageStr, query, _ := strings.Cut(raw, "|")
+	page, err := strconv.Atoi(strings.TrimSpace(pageStr))
+	if err != nil || page < 0 {
+		page = 0
+	}
+	return page, query
+}
@@ -0,0 +1,87 @@
+package slackbot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	q, err := parseSearchQuery(`“login flow” retry author:<@U123|alice> since:2026-01-01 status:in-progress`, time.UTC)
+	if err != nil {
+		t.Fatalf("parseSearchQuery: %v", err)
+	}
+	if q.Text != "login flow retry" {
+		t.Fatalf("Text = %q", q.Text)
+	}
+	if q.AuthorID != "U123" || q.Author != "" {
+		t.Fatalf("author = %q/%q", q.Author, q.AuthorID)
+	}
+	if !q.Since.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Since = %v", q.Since)
+	}
+	if q.Status != "in progress" {
+		t.Fatalf("Status = %q", q.Status)
+	}
+
+	q, err = parseSearchQuery(`author:"Bob Smith" JIRA-12 https://gitlab.example.com/x`, time.UTC)
+	if err != nil {
+		t.Fatalf("parseSearchQuery: %v", err)
+	}
+	if q.Author != "Bob Smith" || q.Text != "JIRA-12 https://gitlab.example.com/x" {
+		t.Fatalf("got author=%q text=%q", q.Author, q.Text)
+	}
+
+	for _, bad := range []string{"", "   ", "since:01/02/2026"} {
+		if _, err := parseSearchQuery(bad, time.UTC); err == nil {
+			t.Fatalf("parseSearchQuery(%q) expected error", bad)
+		}
+	}
+}
+
+func TestSearchPageValueRoundTrip(t *testing.T) {
+	page, query := parseSearchPageValue(formatSearchPageValue(3, "status:done a|b"))
+	if page != 3 || query != "status:done a|b" {
+		t.Fatalf("round trip = %d, %q", page, query)
+	}
+	if page, _ := parseSearchPageValue("x|foo"); page != 0 {
+		t.Fatalf("invalid page = %d, want 0", page)
+	}
+}
+
+func TestBuildSearchResultBlocks(t *testing.T) {
+	items := []WorkItem{
+		{Author: "Alice", Description: "Fix login", Status: "done", Source: "gitlab",
+			SourceRef: "https://gitlab.example.com/g/p/-/merge_requests/1", Category: "S0_0",
+			ReportedAt: time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)},
+		{Author: "Bob", Description: "Write docs", Status: "in progress",
+			ReportedAt: time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)},
+	}
+	blocks := buildSearchResultBlocks("login", 1, items, true, time.UTC)
+	// header + 2 rows + nav
+	if len(blocks) != 4 {
+		t.Fatalf("got %d blocks, want 4", len(blocks))
+	}
+	row := blocks[1].(*slack.SectionBlock).Text.Text
+	want := "11. *Alice*: Fix login (done) <https://gitlab.example.com/g/p/-/merge_requests/1|[GitLab]> _S0_0_ — Feb 3, 2026"
+	if row != want {
+		t.Fatalf("row = %q\nwant %q", row, want)
+	}
+	if row := blocks[2].(*slack.SectionBlock).Text.Text; !strings.HasPrefix(row, "12. *Bob*: Write docs (in progress) — ") {
+		t.Fatalf("row = %q", row)
+	}
+	nav := blocks[3].(*slack.ActionBlock).Elements.ElementSet
+	if len(nav) != 2 {
+		t.Fatalf("got %d nav buttons, want 2", len(nav))
+	}
+	if next := nav[1].(*slack.ButtonBlockElement); next.ActionID != actionSearchNext || next.Value != "2|login" {
+		t.Fatalf("next button = %s %q", next.ActionID, next.Value)
+	}
+
+	empty := buildSearchResultBlocks("nothing", 0, nil, false, time.UTC)
+	if len(empty) != 1 || !strings.Contains(empty[0].(*slack.SectionBlock).Text.Text, "No items match") {
+		t.Fatalf("unexpected empty result blocks: %#v", empty)
+	}
+}
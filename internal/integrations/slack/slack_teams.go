@@ -0,0 +1,108 @@
+package slackbot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// resolveTeam narrows cfg and db to the team a command or interaction
+// belongs to: the team owning channelID or, outside team channels (DMs,
+// nudge messages), the one team userID manages or is a member of. ok is
+// false when no single team matches. A single-team deployment always
+// resolves to cfg and db unchanged.
+func resolveTeam(api *slack.Client, cfg Config, db Store, channelID, userID string) (Config, Store, bool) {
+	if !cfg.MultiTeam() {
+		return cfg, db, true
+	}
+	teamCfg, ok := cfg.TeamForChannel(channelID)
+	if !ok {
+		teamCfg, ok = teamForUser(api, cfg, userID)
+	}
+	if !ok {
+		return cfg, db, false
+	}
+	return teamCfg, ForTeam(db, teamCfg.TeamID), true
+}
+
+// teamForUser returns the team userID belongs to when there is exactly one:
+// as a team manager, or as a member listed by Slack ID or by name.
+func teamForUser(api *slack.Client, cfg Config, userID string) (Config, bool) {
+	var user *slack.User
+	var matches []Config
+	for _, t := range cfg.Teams {
+		member := containsTrimmed(t.ManagerSlackIDs, userID) || containsTrimmed(t.Members, userID)
+		if !member && len(t.Members) > 0 && api != nil {
+			if user == nil {
+				users, err := getCachedUsers(api)
+				if err != nil {
+					log.Printf("resolve team: get users error: %v", err)
+					return cfg, false
+				}
+				for i := range users {
+					if users[i].ID == userID {
+						user = &users[i]
+						break
+					}
+				}
+				if user == nil {
+					return cfg, false
+				}
+			}
+			for _, name := range []string{user.RealName, user.Profile.DisplayName, user.Name} {
+				if anyNameMatches(t.Members, name) {
+					member = true
+					break
+				}
+			}
+		}
+		if member {
+			matches = append(matches, cfg.ForTeam(t))
+		}
+	}
+	if len(matches) != 1 {
+		return cfg, false
+	}
+	return matches[0], true
+}
+
+func containsTrimmed(vals []string, want string) bool {
+	for _, v := range vals {
+		if strings.TrimSpace(v) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// interactionChannelID is the channel an interaction came from. Modal
+// submissions carry it at the end of their private metadata.
+func interactionChannelID(cb slack.InteractionCallback) string {
+	if cb.Channel.ID != "" {
+		return cb.Channel.ID
+	}
+	if cb.Container.ChannelID != "" {
+		return cb.Container.ChannelID
+	}
+	meta := strings.TrimSpace(cb.View.PrivateMetadata)
+	if i := strings.LastIndex(meta, "|"); i >= 0 {
+		return strings.TrimSpace(meta[i+1:])
+	}
+	return ""
+}
+
+func unknownTeamMessage(cfg Config) string {
+	var channels []string
+	for _, t := range cfg.Teams {
+		if t.ReportChannelID != "" {
+			channels = append(channels, fmt.Sprintf("<#%s>", t.ReportChannelID))
+		}
+	}
+	msg := "This channel does not belong to a team. Run the command in your team's channel"
+	if len(channels) > 0 {
+		msg += " (" + strings.Join(channels, ", ") + ")"
+	}
+	return msg + "."
+}
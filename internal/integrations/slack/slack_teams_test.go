@@ -0,0 +1,59 @@
+package slackbot
+
+import (
+	"path/filepath"
+	"reportbot/internal/config"
+	"reportbot/internal/storage/sqlite"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestResolveTeam(t *testing.T) {
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "teams.db"))
+	if err != nil {
+		t.Fatalf("sqlite.Open: %v", err)
+	}
+	defer db.Close()
+
+	single := Config{TeamName: "Solo"}
+	if got, gotDB, ok := resolveTeam(nil, single, db, "CANY", "U1"); !ok || got.TeamName != "Solo" || gotDB != Store(db) {
+		t.Fatalf("single-team deployment must resolve unchanged, ok=%v cfg=%+v", ok, got)
+	}
+
+	cfg := Config{Teams: []config.Team{
+		{ID: "platform", ReportChannelID: "CPLAT", ManagerSlackIDs: []string{"UPLATMGR"}, Members: []string{"UALICE"}},
+		{ID: "mobile", ReportChannelID: "CMOB", Members: []string{"UBOB", "UALICE"}},
+	}}
+	cases := []struct {
+		channel, user, want string
+	}{
+		{"CMOB", "UALICE", "mobile"},
+		{"D123", "UPLATMGR", "platform"},
+		{"D123", "UBOB", "mobile"},
+		{"D123", "UALICE", ""}, // member of both teams
+		{"D123", "USTRANGER", ""},
+	}
+	for _, c := range cases {
+		got, _, ok := resolveTeam(nil, cfg, db, c.channel, c.user)
+		if ok != (c.want != "") || (ok && got.TeamID != c.want) {
+			t.Fatalf("resolveTeam(%s, %s) = %q ok=%v, want %q", c.channel, c.user, got.TeamID, ok, c.want)
+		}
+	}
+}
+
+func TestInteractionChannelID(t *testing.T) {
+	var cb slack.InteractionCallback
+	cb.View.PrivateMetadata = "42|CVIEW"
+	if got := interactionChannelID(cb); got != "CVIEW" {
+		t.Fatalf("expected channel from modal metadata, got %q", got)
+	}
+	cb.Container.ChannelID = "CCONTAINER"
+	if got := interactionChannelID(cb); got != "CCONTAINER" {
+		t.Fatalf("expected container channel, got %q", got)
+	}
+	cb.Channel.ID = "CCHANNEL"
+	if got := interactionChannelID(cb); got != "CCHANNEL" {
+		t.Fatalf("expected callback channel, got %q", got)
+	}
+}
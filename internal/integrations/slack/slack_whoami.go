@@ -0,0 +1,150 @@
+package slackbot
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+const whoamiUsage = "Usage: `/whoami` to show your linked accounts, `/whoami link gitlab:<username>` (or `github:<login>`, `email:<address>`), `/whoami unlink gitlab`. Managers can add `@user` after `link`/`unlink` to change someone else's accounts."
+
+// whoamiRequest is a parsed /whoami command. Action is "show", "link" or
+// "unlink"; Account is empty for unlink. User is the Slack ID of the
+// mentioned user a manager acts for, empty for the caller's own accounts.
+type whoamiRequest struct {
+	Action  string
+	User    string
+	Kind    string
+	Account string
+}
+
+// parseWhoami accepts "", "link <kind>:<account>" (or "link <kind>
+// <account>") and "unlink <kind>", each optionally naming a user as an
+// @-mention right after the action (or alone, to show their accounts).
+func parseWhoami(text string) (whoamiRequest, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return whoamiRequest{Action: "show"}, nil
+	}
+	if m := slackUserMentionRegex.FindStringSubmatch(fields[0]); m != nil && len(fields) == 1 {
+		return whoamiRequest{Action: "show", User: m[1]}, nil
+	}
+	action := strings.ToLower(fields[0])
+	args := fields[1:]
+	var user string
+	if len(args) > 0 {
+		if m := slackUserMentionRegex.FindStringSubmatch(args[0]); m != nil {
+			user = m[1]
+			args = args[1:]
+		}
+	}
+	if len(args) == 1 {
+		if kind, account, ok := strings.Cut(args[0], ":"); ok {
+			args = []string{kind, account}
+		}
+	}
+
+	switch {
+	case action == "link" && len(args) == 2:
+		req := whoamiRequest{Action: action, User: user, Kind: strings.ToLower(args[0]), Account: NormalizeAccount(args[1])}
+		if !isIdentityKind(req.Kind) {
+			return whoamiRequest{}, fmt.Errorf("Unknown account type `%s`. %s", args[0], whoamiUsage)
+		}
+		if req.Account == "" {
+			return whoamiRequest{}, fmt.Errorf("Missing %s account. %s", req.Kind, whoamiUsage)
+		}
+		if req.Kind == IdentityEmail && !strings.Contains(req.Account, "@") {
+			return whoamiRequest{}, fmt.Errorf("`%s` is not an email address.", args[1])
+		}
+		return req, nil
+	case action == "unlink" && len(args) == 1:
+		req := whoamiRequest{Action: action, User: user, Kind: strings.ToLower(args[0])}
+		if !isIdentityKind(req.Kind) {
+			return whoamiRequest{}, fmt.Errorf("Unknown account type `%s`. %s", args[0], whoamiUsage)
+		}
+		return req, nil
+	}
+	return whoamiRequest{}, fmt.Errorf("%s", whoamiUsage)
+}
+
+func isIdentityKind(kind string) bool {
+	return kind == IdentityGitLab || kind == IdentityGitHub || kind == IdentityEmail
+}
+
+// handleWhoami lets any user link their GitLab username, GitHub login and
+// email to their Slack account, so fetched MRs/PRs are attributed to them
+// even when their Git name differs from their Slack name. Self-service links
+// are not verified: an account someone else linked is refused, and the link
+// only admits MRs/PRs to the team when team_members lists the user's Slack
+// ID. Managers link for a user by naming them, which confirms the account
+// (and moves it from whoever claimed it), so it admits the user's MRs/PRs.
+func handleWhoami(api *slack.Client, db Store, cfg Config, cmd slack.SlashCommand) {
+	req, err := parseWhoami(cmd.Text)
+	if err != nil {
+		postEphemeral(api, cmd, err.Error())
+		return
+	}
+
+	target := cmd.UserID
+	if req.User != "" {
+		isManager, err := isManagerUser(api, cfg, cmd.UserID)
+		if err != nil || !isManager {
+			postEphemeral(api, cmd, "Only managers can view or change another user's accounts.")
+			log.Printf("whoami %s denied user=%s target=%s: not a manager", req.Action, cmd.UserID, req.User)
+			return
+		}
+		target = req.User
+	}
+
+	if req.Action != "show" {
+		link := LinkIdentity
+		if req.User != "" {
+			link = ReassignIdentity
+		}
+		err := link(db, target, req.Kind, req.Account)
+		if errors.Is(err, ErrIdentityTaken) {
+			postEphemeral(api, cmd, fmt.Sprintf("`%s:%s` is already linked to another Slack user. If it is yours, ask a manager to run `/whoami link <@%s> %s:%s`.",
+				req.Kind, req.Account, cmd.UserID, req.Kind, req.Account))
+			log.Printf("whoami %s denied user=%s kind=%s account=%s: taken", req.Action, cmd.UserID, req.Kind, req.Account)
+			return
+		}
+		if err != nil {
+			postEphemeral(api, cmd, fmt.Sprintf("Error saving the %s account: %v", req.Kind, err))
+			log.Printf("whoami %s error user=%s target=%s kind=%s: %v", req.Action, cmd.UserID, target, req.Kind, err)
+			return
+		}
+		log.Printf("whoami %s user=%s target=%s kind=%s account=%s", req.Action, cmd.UserID, target, req.Kind, req.Account)
+	}
+
+	identity, err := GetIdentity(db, target)
+	if err != nil && err != sql.ErrNoRows {
+		postEphemeral(api, cmd, fmt.Sprintf("Error loading linked accounts: %v", err))
+		log.Printf("whoami error user=%s target=%s: %v", cmd.UserID, target, err)
+		return
+	}
+	identity.SlackID = target
+	postEphemeral(api, cmd, formatIdentity(identity))
+}
+
+func formatIdentity(identity Identity) string {
+	lines := []string{fmt.Sprintf("*Linked accounts for <@%s>*", identity.SlackID)}
+	for _, kind := range []struct{ kind, label string }{
+		{IdentityGitLab, "GitLab"},
+		{IdentityGitHub, "GitHub"},
+		{IdentityEmail, "Email"},
+	} {
+		if account := identity.Account(kind.kind); account != "" && identity.Confirmed(kind.kind) {
+			lines = append(lines, fmt.Sprintf("• %s: `%s` (confirmed by a manager)", kind.label, account))
+		} else if account != "" {
+			lines = append(lines, fmt.Sprintf("• %s: `%s`", kind.label, account))
+		} else {
+			lines = append(lines, fmt.Sprintf("• %s: not linked", kind.label))
+		}
+	}
+	lines = append(lines, "", "MRs/PRs fetched from these accounts are recorded under this Slack user. "+whoamiUsage)
+	return strings.Join(lines, "\n")
+}
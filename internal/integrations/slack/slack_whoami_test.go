@@ -0,0 +1,43 @@
+package slackbot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseWhoami(t *testing.T) {
+	tests := []struct {
+		text string
+		want whoamiRequest
+	}{
+		{"", whoamiRequest{Action: "show"}},
+		{"link gitlab:JDoe", whoamiRequest{Action: "link", Kind: "gitlab", Account: "jdoe"}},
+		{"link GitHub @jdoe-gh", whoamiRequest{Action: "link", Kind: "github", Account: "jdoe-gh"}},
+		{"link email:Jane.Doe@example.com", whoamiRequest{Action: "link", Kind: "email", Account: "jane.doe@example.com"}},
+		{"unlink gitlab", whoamiRequest{Action: "unlink", Kind: "gitlab"}},
+		{"<@U2|jdoe>", whoamiRequest{Action: "show", User: "U2"}},
+		{"link <@U2|jdoe> gitlab:JDoe", whoamiRequest{Action: "link", User: "U2", Kind: "gitlab", Account: "jdoe"}},
+		{"unlink <@U2> github", whoamiRequest{Action: "unlink", User: "U2", Kind: "github"}},
+	}
+	for _, tt := range tests {
+		got, err := parseWhoami(tt.text)
+		if err != nil || got != tt.want {
+			t.Errorf("parseWhoami(%q) = %+v, %v; want %+v", tt.text, got, err, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"link", "link gitlab:", "link svn:jdoe", "link email:jdoe", "link <@U2>", "unlink", "unlink <@U2>", "forget gitlab"} {
+		if _, err := parseWhoami(bad); err == nil {
+			t.Errorf("parseWhoami(%q) expected error", bad)
+		}
+	}
+}
+
+func TestFormatIdentity(t *testing.T) {
+	got := formatIdentity(Identity{SlackID: "U1", GitLabUsername: "jdoe", GitHubLogin: "jdoe-gh", GitHubConfirmed: true})
+	for _, want := range []string{"<@U1>", "GitLab: `jdoe`\n", "GitHub: `jdoe-gh` (confirmed by a manager)", "Email: not linked"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatIdentity missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -1,16 +1,15 @@
 package slackbot
 
 import (
-	"database/sql"
 	"path/filepath"
 	sqlitedb "reportbot/internal/storage/sqlite"
 	"testing"
 )
 
-func newTestDB(t *testing.T) *sql.DB {
+func newTestDB(t *testing.T) Store {
 	t.Helper()
 	dbPath := filepath.Join(t.TempDir(), "slack-test.db")
-	db, err := sqlitedb.InitDB(dbPath)
+	db, err := sqlitedb.Open(dbPath)
 	if err != nil {
 		t.Fatalf("InitDB failed: %v", err)
 	}
@@ -1,10 +1,9 @@
 package nudge
 
 import (
-	"database/sql"
 	"reportbot/internal/config"
 	"reportbot/internal/domain"
-	"reportbot/internal/storage/sqlite"
+	"reportbot/internal/storage"
 	"strings"
 	"time"
 
@@ -13,13 +12,14 @@ import (
 
 type Config = config.Config
 type WorkItem = domain.WorkItem
+type Store = storage.Store
 
 func ReportWeekRange(cfg Config, now time.Time) (time.Time, time.Time) {
 	return domain.ReportWeekRange(cfg, now)
 }
 
-func GetItemsByDateRange(db *sql.DB, from, to time.Time) ([]WorkItem, error) {
-	return sqlite.GetItemsByDateRange(db, from, to)
+func GetItemsByDateRange(db Store, from, to time.Time) ([]WorkItem, error) {
+	return db.GetItemsByDateRange(from, to)
 }
 
 func resolveUserIDs(api *slack.Client, identifiers []string) ([]string, []string, error) {
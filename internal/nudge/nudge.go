@@ -1,7 +1,6 @@
 package nudge
 
 import (
-	"database/sql"
 	"fmt"
 	"log"
 	"regexp"
@@ -45,7 +44,7 @@ var dayMap = map[string]time.Weekday{
 	"saturday":  time.Saturday,
 }
 
-func StartNudgeScheduler(cfg Config, db *sql.DB, api *slack.Client) {
+func StartNudgeScheduler(cfg Config, db Store, api *slack.Client) {
 	if len(cfg.TeamMembers) == 0 {
 		log.Println("No team_members configured, nudge disabled")
 		return
@@ -74,7 +73,7 @@ func StartNudgeScheduler(cfg Config, db *sql.DB, api *slack.Client) {
 		hour, min = 10, 0
 	}
 
-	log.Printf("Nudge scheduled every %s at %02d:%02d for %d team members", weekday, hour, min, len(cfg.TeamMembers))
+	log.Printf("Nudge scheduled every %s at %02d:%02d for %d members of %s", weekday, hour, min, len(cfg.TeamMembers), cfg.TeamName)
 
 	go func() {
 		for {
@@ -101,7 +100,7 @@ func nextWeekday(now time.Time, day time.Weekday, hour, min int) time.Time {
 	return time.Date(now.Year(), now.Month(), now.Day()+int(daysUntil), hour, min, 0, 0, now.Location())
 }
 
-func sendNudges(api *slack.Client, db *sql.DB, cfg Config, memberIDs []string, reportChannelID string) {
+func sendNudges(api *slack.Client, db Store, cfg Config, memberIDs []string, reportChannelID string) {
 	for _, userID := range memberIDs {
 		channel, _, _, err := api.OpenConversation(&slack.OpenConversationParameters{
 			Users: []string{userID},
@@ -130,11 +129,11 @@ func sendNudges(api *slack.Client, db *sql.DB, cfg Config, memberIDs []string, r
 	}
 }
 
-func SendNudges(api *slack.Client, db *sql.DB, cfg Config, memberIDs []string, reportChannelID string) {
+func SendNudges(api *slack.Client, db Store, cfg Config, memberIDs []string, reportChannelID string) {
 	sendNudges(api, db, cfg, memberIDs, reportChannelID)
 }
 
-func RenderNudgeForUser(api *slack.Client, db *sql.DB, cfg Config, userID, reportChannelID string, now time.Time, page int, updated bool) (RenderedNudge, error) {
+func RenderNudgeForUser(api *slack.Client, db Store, cfg Config, userID, reportChannelID string, now time.Time, page int, updated bool) (RenderedNudge, error) {
 	now = now.In(cfg.Location)
 	monday, nextMonday := ReportWeekRange(cfg, now)
 	reminder := buildGenericNudgeText(reportChannelID, monday, nextMonday)
@@ -2,7 +2,6 @@ package nudge
 
 import (
 	"bytes"
-	"database/sql"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -22,10 +21,10 @@ func (fn roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
 	return fn(req)
 }
 
-func newRenderTestDB(t *testing.T) *sql.DB {
+func newRenderTestDB(t *testing.T) Store {
 	t.Helper()
 	dbPath := filepath.Join(t.TempDir(), "nudge-test.db")
-	db, err := sqlitedb.InitDB(dbPath)
+	db, err := sqlitedb.Open(dbPath)
 	if err != nil {
 		t.Fatalf("init db: %v", err)
 	}
@@ -122,14 +121,14 @@ func TestRenderNudgeForUser_GenericOnlyWhenNoActiveItems(t *testing.T) {
 	})
 
 	now := time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC)
-	if err := sqlitedb.InsertWorkItem(db, sqlitedb.WorkItem{
+	if err := db.InsertWorkItem(sqlitedb.WorkItem{
 		Description: "Already shipped",
 		Author:      "Member Display",
 		AuthorID:    "U_MEMBER",
 		Source:      "slack",
 		Status:      "done",
 		ReportedAt:  now,
-	}); err != nil {
+	}, ""); err != nil {
 		t.Fatalf("insert work item: %v", err)
 	}
 
@@ -163,7 +162,7 @@ func TestRenderNudgeForUser_PaginatesAndUsesAllSources(t *testing.T) {
 
 	insert := func(item sqlitedb.WorkItem) {
 		t.Helper()
-		if err := sqlitedb.InsertWorkItem(db, item); err != nil {
+		if err := db.InsertWorkItem(item, ""); err != nil {
 			t.Fatalf("insert work item: %v", err)
 		}
 	}
@@ -11,10 +11,13 @@ type Config = config.Config
 type WorkItem = domain.WorkItem
 type ClassificationCorrection = domain.ClassificationCorrection
 type historicalItem = domain.HistoricalItem
+type ConfidenceSample = domain.ConfidenceSample
+type ConfidenceCalibration = illm.ConfidenceCalibration
 type existingItemContext = illm.ExistingItemContext
 type LLMSectionDecision = illm.LLMSectionDecision
 type LLMUsage = illm.LLMUsage
 type sectionOption = illm.SectionOption
+type PeriodCategory = illm.PeriodCategory
 
 func CategorizeItemsToSections(
 	cfg Config,
@@ -27,6 +30,14 @@ func CategorizeItemsToSections(
 	return illm.CategorizeItemsToSections(cfg, items, options, existing, corrections, historicalItems)
 }
 
+func BuildConfidenceCalibration(samples []ConfidenceSample) *ConfidenceCalibration {
+	return illm.BuildConfidenceCalibration(samples)
+}
+
+func CalibrateDecisions(decisions map[int64]LLMSectionDecision, c *ConfidenceCalibration) int {
+	return illm.CalibrateDecisions(decisions, c)
+}
+
 func FridayOfWeek(monday time.Time) time.Time {
 	return domain.FridayOfWeek(monday)
 }
@@ -63,3 +74,15 @@ func NormalizeStatus(status string) string {
 func SynthesizeName(name string) string {
 	return synthesizeName(name)
 }
+
+func SummarizeTeamReport(cfg Config, teamName, report string) (string, LLMUsage, error) {
+	return illm.SummarizeTeamReport(cfg, teamName, report)
+}
+
+func CheckOptionalPassBudget(cfg Config) error {
+	return illm.CheckOptionalPassBudget(cfg)
+}
+
+func SummarizePeriodThemes(cfg Config, periodLabel string, categories []PeriodCategory) (map[string]string, LLMUsage, error) {
+	return illm.SummarizePeriodThemes(cfg, periodLabel, categories)
+}
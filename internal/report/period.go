@@ -0,0 +1,235 @@
+package report
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Period report kinds, as used in /generate-report month|quarter and in
+// the file names.
+const (
+	PeriodMonth   = "month"
+	PeriodQuarter = "quarter"
+)
+
+// Period is a calendar month or quarter: [Start, End).
+type Period struct {
+	Kind  string
+	Start time.Time
+	End   time.Time
+}
+
+// PeriodContaining returns the month or quarter that contains t.
+func PeriodContaining(kind string, t time.Time) (Period, error) {
+	switch kind {
+	case PeriodMonth:
+		start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		return Period{Kind: kind, Start: start, End: start.AddDate(0, 1, 0)}, nil
+	case PeriodQuarter:
+		month := time.Month((int(t.Month())-1)/3*3 + 1)
+		start := time.Date(t.Year(), month, 1, 0, 0, 0, 0, t.Location())
+		return Period{Kind: kind, Start: start, End: start.AddDate(0, 3, 0)}, nil
+	default:
+		return Period{}, fmt.Errorf("unknown period %q (want %s or %s)", kind, PeriodMonth, PeriodQuarter)
+	}
+}
+
+// Label is the human-readable period, e.g. "March 2026" or "Q1 2026".
+func (p Period) Label() string {
+	if p.Kind == PeriodQuarter {
+		return fmt.Sprintf("Q%d %d", (int(p.Start.Month())-1)/3+1, p.Start.Year())
+	}
+	return p.Start.Format("January 2006")
+}
+
+// fileTag distinguishes period reports from weekly <team>_YYYYMMDD.md
+// files, e.g. month_202603 or quarter_2026Q1.
+func (p Period) fileTag() string {
+	if p.Kind == PeriodQuarter {
+		return fmt.Sprintf("quarter_%dQ%d", p.Start.Year(), (int(p.Start.Month())-1)/3+1)
+	}
+	return "month_" + p.Start.Format("200601")
+}
+
+// PeriodResult is a monthly or quarterly report before rendering.
+type PeriodResult struct {
+	Period   Period
+	Template *ReportTemplate
+	// Items is the number of work items aggregated and Lines the number of
+	// report lines left after collapsing repeats of the same work.
+	Items int
+	Lines int
+	Usage LLMUsage
+}
+
+var summarizePeriodFn = func(cfg Config, periodLabel string, categories []PeriodCategory) (map[string]string, LLMUsage, error) {
+	return SummarizePeriodThemes(cfg, periodLabel, categories)
+}
+
+// BuildPeriodReport groups the work items of a period under the sections of
+// the team's most recent weekly report. sections maps a work item ID to the
+// section label it was last classified (or corrected) to; items without a
+// label, or whose section no longer exists, go to Undetermined. Repeated
+// reports of the same work, such as an item reported in progress one week
+// and done the next, collapse into one line with the latest status. With
+// summarize, an LLM pass writes a theme summary for each category.
+func BuildPeriodReport(cfg Config, p Period, items []WorkItem, sections map[int64]string, summarize bool) (PeriodResult, error) {
+	res := PeriodResult{Period: p, Items: len(items)}
+	t, err := periodSkeleton(cfg.ReportOutputDir, cfg.TeamName, p)
+	if err != nil {
+		return res, err
+	}
+
+	sectionByLabel := make(map[string][2]int)
+	for _, opt := range templateOptions(t) {
+		sectionByLabel[strings.ToLower(opt.Label)] = [2]int{opt.Category, opt.Subsection}
+	}
+	place := func(label string) (int, int, bool) {
+		label = strings.ToLower(strings.TrimSpace(label))
+		if pos, ok := sectionByLabel[label]; ok {
+			return pos[0], pos[1], true
+		}
+		// A subsection renamed or removed since: fall back to its category.
+		category, _, _ := strings.Cut(label, " > ")
+		for ci, cat := range t.Categories {
+			if strings.ToLower(strings.TrimSpace(cat.Name)) == category && len(cat.Subsections) > 0 {
+				return ci, 0, true
+			}
+		}
+		return 0, 0, false
+	}
+
+	for _, group := range collapsePeriodItems(items) {
+		latest := group[len(group)-1]
+		label := ""
+		for i := len(group) - 1; i >= 0 && label == ""; i-- {
+			label = sections[group[i].ID]
+		}
+		item := TemplateItem{
+			Author:      strings.TrimSpace(latest.Author),
+			Description: strings.TrimSpace(latest.Description),
+			TicketIDs:   strings.TrimSpace(latest.TicketIDs),
+			Status:      normalizeStatus(latest.Status),
+			ReportedAt:  group[0].ReportedAt,
+		}
+		if ci, si, ok := place(label); ok {
+			t.Categories[ci].Subsections[si].Items = append(t.Categories[ci].Subsections[si].Items, item)
+		} else {
+			undetermined, _ := ensureUndeterminedSection(t)
+			undetermined.Items = append(undetermined.Items, item)
+		}
+		res.Lines++
+	}
+	reorderTemplateItems(t)
+	res.Template = t
+
+	if summarize && res.Lines > 0 {
+		if err := CheckOptionalPassBudget(cfg); err != nil {
+			log.Printf("period summary skipped: %v", err)
+			return res, nil
+		}
+		summaries, usage, err := summarizePeriodFn(cfg, p.Label(), periodCategories(t))
+		res.Usage = usage
+		if err != nil {
+			log.Printf("period summary error (non-fatal): %v", err)
+			return res, nil
+		}
+		for ci := range t.Categories {
+			t.Categories[ci].Summary = summaries[t.Categories[ci].Name]
+		}
+	}
+	return res, nil
+}
+
+// periodSkeleton returns the sections of the newest weekly report dated
+// before the end of p, without their items.
+func periodSkeleton(outputDir, teamName string, p Period) (*ReportTemplate, error) {
+	path, _, err := latestReportOnOrBefore(outputDir, teamName, p.End.AddDate(0, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return &ReportTemplate{Categories: []TemplateCategory{{Name: "Undetermined", Subsections: []TemplateSubsection{{}}}}}, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading last report: %w", err)
+	}
+	t := cloneTemplate(parseTemplate(string(content)))
+	t.PrefixLines = nil
+	for ci := range t.Categories {
+		for si := range t.Categories[ci].Subsections {
+			t.Categories[ci].Subsections[si].Items = nil
+		}
+	}
+	return t, nil
+}
+
+// collapsePeriodItems groups items that report the same work: the same
+// MR/PR, or the same author and description. Each group is ordered by
+// report time and the groups by their first report.
+func collapsePeriodItems(items []WorkItem) [][]WorkItem {
+	sorted := append([]WorkItem(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].ReportedAt.Equal(sorted[j].ReportedAt) {
+			return sorted[i].ReportedAt.Before(sorted[j].ReportedAt)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	index := make(map[string]int)
+	var groups [][]WorkItem
+	for _, item := range sorted {
+		key := "ref\x00" + strings.TrimSpace(item.SourceRef)
+		if strings.TrimSpace(item.SourceRef) == "" {
+			key = "item\x00" + strings.ToLower(strings.TrimSpace(item.Author)) + "\x00" + itemIdentityKey(TemplateItem{Description: item.Description})
+		}
+		if i, ok := index[key]; ok {
+			groups[i] = append(groups[i], item)
+			continue
+		}
+		index[key] = len(groups)
+		groups = append(groups, []WorkItem{item})
+	}
+	return groups
+}
+
+func periodCategories(t *ReportTemplate) []PeriodCategory {
+	var out []PeriodCategory
+	for _, cat := range t.Categories {
+		if cat.MarkerLine != "" || !categoryHasItems(cat) {
+			continue
+		}
+		pc := PeriodCategory{Name: cat.Name}
+		for _, sub := range cat.Subsections {
+			for _, item := range sub.Items {
+				pc.Items = append(pc.Items, formatBossItem(item))
+			}
+		}
+		out = append(out, pc)
+	}
+	return out
+}
+
+// RenderPeriodMarkdown renders a period report in team format under a
+// "### <team> <period>" title.
+func RenderPeriodMarkdown(teamName string, res PeriodResult) string {
+	t := *res.Template
+	t.PrefixLines = []string{fmt.Sprintf("### %s %s", teamName, res.Period.Label())}
+	return renderTeamMarkdown(&t)
+}
+
+// WritePeriodReportFile writes a period report as <team>_month_YYYYMM.md or
+// <team>_quarter_YYYYQn.md, next to the weekly reports.
+func WritePeriodReportFile(content, outputDir, teamName string, p Period) (string, error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", err
+	}
+	filename := fmt.Sprintf("%s_%s.md", sanitizeFilename(teamName), p.fileTag())
+	path := filepath.Join(outputDir, filename)
+	return path, os.WriteFile(path, []byte(content), 0644)
+}
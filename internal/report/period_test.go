@@ -0,0 +1,121 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPeriodContaining(t *testing.T) {
+	day := time.Date(2026, 5, 14, 10, 0, 0, 0, time.UTC)
+	month, err := PeriodContaining(PeriodMonth, day)
+	if err != nil || month.Label() != "May 2026" || month.fileTag() != "month_202605" ||
+		!month.Start.Equal(time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)) || !month.End.Equal(time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected month %+v err=%v", month, err)
+	}
+	quarter, err := PeriodContaining(PeriodQuarter, day)
+	if err != nil || quarter.Label() != "Q2 2026" || quarter.fileTag() != "quarter_2026Q2" ||
+		!quarter.Start.Equal(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)) || !quarter.End.Equal(time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected quarter %+v err=%v", quarter, err)
+	}
+	if _, err := PeriodContaining("year", day); err == nil {
+		t.Fatal("expected error for unknown period")
+	}
+}
+
+func TestBuildPeriodReportCollapsesAndGroups(t *testing.T) {
+	dir := t.TempDir()
+	latest := `### TEAMX 20260327
+
+#### Backend
+
+- **API**
+  - **Pat One** - Old API item (done)
+- **Database**
+  - **Sam Two** - Old DB item (done)
+
+#### Frontend
+
+- **Lee Three** - Old UI item (done)
+`
+	if err := os.WriteFile(filepath.Join(dir, "TEAMX_20260327.md"), []byte(latest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// A report after the period must not define its structure.
+	if err := os.WriteFile(filepath.Join(dir, "TEAMX_20260403.md"), []byte("#### April Only\n\n- **Pat One** - x (done)\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 9, 0, 0, 0, time.UTC) }
+	items := []WorkItem{
+		{ID: 1, Author: "Pat One", Description: "Add pagination", Status: "in progress", ReportedAt: day(3)},
+		{ID: 2, Author: "Pat One", Description: "add pagination ", Status: "done", ReportedAt: day(17)},
+		{ID: 3, Author: "Sam Two", Description: "Index orders table", Status: "in progress", Source: "gitlab", SourceRef: "https://gitlab/mr/9", ReportedAt: day(5)},
+		{ID: 4, Author: "Sam Two", Description: "Index orders table (merged)", Status: "done", Source: "gitlab", SourceRef: "https://gitlab/mr/9", ReportedAt: day(12)},
+		{ID: 5, Author: "Lee Three", Description: "New settings page", Status: "in progress", ReportedAt: day(20)},
+		{ID: 6, Author: "Lee Three", Description: "Unclassified chore", Status: "done", ReportedAt: day(21)},
+		{ID: 7, Author: "Pat One", Description: "Cache warmup", Status: "done", ReportedAt: day(24)},
+	}
+	sections := map[int64]string{
+		1: "Backend > API",
+		3: "Backend > Database",
+		4: "Backend > Database",
+		5: "Frontend",
+		7: "Backend > Caching", // subsection since removed
+	}
+
+	orig := summarizePeriodFn
+	defer func() { summarizePeriodFn = orig }()
+	summarizePeriodFn = func(_ Config, periodLabel string, categories []PeriodCategory) (map[string]string, LLMUsage, error) {
+		if periodLabel != "March 2026" || len(categories) != 3 {
+			t.Fatalf("unexpected summary request %q %+v", periodLabel, categories)
+		}
+		return map[string]string{"Backend": "Backend theme."}, LLMUsage{InputTokens: 7}, nil
+	}
+
+	p, _ := PeriodContaining(PeriodMonth, day(30))
+	res, err := BuildPeriodReport(Config{ReportOutputDir: dir, TeamName: "TEAMX"}, p, items, sections, true)
+	if err != nil {
+		t.Fatalf("BuildPeriodReport: %v", err)
+	}
+	if res.Items != 7 || res.Lines != 5 || res.Usage.InputTokens != 7 {
+		t.Fatalf("unexpected result counts: %+v", res)
+	}
+
+	md := RenderPeriodMarkdown("TEAMX", res)
+	want := `### TEAMX March 2026
+
+#### Backend
+
+Backend theme.
+
+- **API**
+  - **Pat One** - Add pagination (done)
+  - **Pat One** - Cache warmup (done)
+
+- **Database**
+  - **Sam Two** - Index orders table (merged) (done)
+
+#### Frontend
+
+- **Lee Three** - New settings page (in progress)
+
+#### Undetermined
+
+- **Lee Three** - Unclassified chore (done)
+`
+	if md != want {
+		t.Fatalf("unexpected period report:\n%s\nwant:\n%s", md, want)
+	}
+
+	path, err := WritePeriodReportFile(md, dir, "TEAMX", p)
+	if err != nil || filepath.Base(path) != "TEAMX_month_202603.md" {
+		t.Fatalf("WritePeriodReportFile: %s %v", path, err)
+	}
+	// Period files must not be mistaken for weekly reports.
+	if got, _, _ := latestReportOnOrBefore(dir, "TEAMX", day(31)); !strings.HasSuffix(got, "TEAMX_20260327.md") {
+		t.Fatalf("latest weekly report = %s", got)
+	}
+}
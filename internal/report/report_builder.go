@@ -2,6 +2,7 @@ package report
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -20,6 +21,9 @@ type TemplateCategory struct {
 	Name        string
 	Subsections []TemplateSubsection
 	MarkerLine  string
+	// Summary is an optional paragraph rendered under the heading; only
+	// period reports set it.
+	Summary string
 }
 
 type TemplateSubsection struct {
@@ -56,10 +60,6 @@ var (
 	nameAliasParenRe  = regexp.MustCompile(`\([^)]*\)|（[^）]*）`)
 )
 
-var classifySectionsFn = func(cfg Config, items []WorkItem, options []sectionOption, existing []existingItemContext, corrections []ClassificationCorrection, historicalItems []historicalItem) (map[int64]LLMSectionDecision, LLMUsage, error) {
-	return CategorizeItemsToSections(cfg, items, options, existing, corrections, historicalItems)
-}
-
 type BuildResult struct {
 	Template  *ReportTemplate
 	Usage     LLMUsage
@@ -67,7 +67,7 @@ type BuildResult struct {
 	Options   []sectionOption
 }
 
-func BuildReportsFromLast(cfg Config, items []WorkItem, reportDate time.Time, corrections []ClassificationCorrection, historicalItems []historicalItem) (BuildResult, error) {
+func BuildReportsFromLast(cfg Config, items []WorkItem, reportDate time.Time, corrections []ClassificationCorrection, historicalItems []historicalItem, confidenceSamples []ConfidenceSample) (BuildResult, error) {
 	template, status, err := loadTemplateForGeneration(cfg.ReportOutputDir, cfg.TeamName, reportDate)
 	if err != nil {
 		return BuildResult{}, err
@@ -82,10 +82,17 @@ func BuildReportsFromLast(cfg Config, items []WorkItem, reportDate time.Time, co
 	llmUsage := LLMUsage{}
 	if len(options) > 0 && status != templateFirstEver {
 		existing := buildExistingItemContext(merged, options)
-		decisions, llmUsage, err = classifySectionsFn(cfg, items, options, existing, corrections, historicalItems)
+		// Classification uses the provider registered for cfg.LLMProvider.
+		decisions, llmUsage, err = CategorizeItemsToSections(cfg, items, options, existing, corrections, historicalItems)
 		if err != nil {
 			return BuildResult{Usage: llmUsage}, err
 		}
+		// Calibrate model confidence against past corrections so the
+		// threshold below reflects observed accuracy.
+		if calibration := BuildConfidenceCalibration(confidenceSamples); calibration != nil {
+			n := CalibrateDecisions(decisions, calibration)
+			log.Printf("report confidence calibrated decisions=%d samples=%d corrected=%d", n, calibration.Samples, calibration.Corrected)
+		}
 	}
 
 	confidenceThreshold := cfg.LLMConfidence
@@ -133,27 +140,42 @@ func loadTemplateForGeneration(outputDir, teamName string, reportDate time.Time)
 }
 
 func findLatestReportBefore(outputDir, teamName string, reportDate time.Time) (string, error) {
-	entries, err := os.ReadDir(outputDir)
+	files, err := listReportFiles(outputDir, teamName)
 	if err != nil {
-		return "", fmt.Errorf("reading report output dir: %w", err)
+		return "", err
 	}
+	current := FridayOfWeek(reportDate).Format("20060102")
+	for _, f := range files {
+		// Skip this week's own file so regenerating starts from last week.
+		if f.date.Format("20060102") == current || !f.date.Before(reportDate) {
+			continue
+		}
+		return f.path, nil
+	}
+	return "", fmt.Errorf("no prior report found in %s for team %s before %s", outputDir, teamName, reportDate.Format("20060102"))
+}
 
-	sanitized := sanitizeFilename(teamName)
-	prefix := sanitized + "_"
-	friday := FridayOfWeek(reportDate)
-	currentFile := fmt.Sprintf("%s_%s.md", sanitized, friday.Format("20060102"))
-	type candidate struct {
-		path string
-		date time.Time
+type reportFile struct {
+	path string
+	date time.Time
+}
+
+// listReportFiles returns teamName's <team>_YYYYMMDD.md reports in
+// outputDir, newest first.
+func listReportFiles(outputDir, teamName string) ([]reportFile, error) {
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading report output dir: %w", err)
 	}
-	var candidates []candidate
 
+	prefix := sanitizeFilename(teamName) + "_"
+	var files []reportFile
 	for _, entry := range entries {
 		if entry.IsDir() {
 			continue
 		}
 		name := entry.Name()
-		if name == currentFile || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".md") {
+		if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".md") {
 			continue
 		}
 		raw := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".md")
@@ -161,23 +183,16 @@ func findLatestReportBefore(outputDir, teamName string, reportDate time.Time) (s
 		if err != nil {
 			continue
 		}
-		if !d.Before(reportDate) {
-			continue
-		}
-		candidates = append(candidates, candidate{
+		files = append(files, reportFile{
 			path: filepath.Join(outputDir, name),
 			date: d,
 		})
 	}
 
-	if len(candidates) == 0 {
-		return "", fmt.Errorf("no prior report found in %s for team %s before %s", outputDir, teamName, reportDate.Format("20060102"))
-	}
-
-	sort.Slice(candidates, func(i, j int) bool {
-		return candidates[i].date.After(candidates[j].date)
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].date.After(files[j].date)
 	})
-	return candidates[0].path, nil
+	return files, nil
 }
 
 func parseTemplate(content string) *ReportTemplate {
@@ -622,6 +637,9 @@ func renderMarkdown(
 			continue
 		}
 		buf.WriteString(fmt.Sprintf("#### %s\n\n", categoryHeading(cat)))
+		if summary := strings.TrimSpace(cat.Summary); summary != "" {
+			buf.WriteString(summary + "\n\n")
+		}
 		for _, sub := range cat.Subsections {
 			if strings.TrimSpace(sub.HeaderLine) != "" {
 				buf.WriteString(strings.TrimSpace(sub.HeaderLine) + "\n")
@@ -1,8 +1,12 @@
 package report
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"regexp"
+	illm "reportbot/internal/integrations/llm"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -20,7 +24,7 @@ func TestBuildReportsFromLast_FirstEverFallback(t *testing.T) {
 		{ID: 2, Author: "Jordan Kim", Description: "Fix Y", Status: "done"},
 	}
 
-	result, err := BuildReportsFromLast(cfg, items, mustDate(t, "20260209"), nil, nil)
+	result, err := BuildReportsFromLast(cfg, items, mustDate(t, "20260209"), nil, nil, nil)
 	if err != nil {
 		t.Fatalf("BuildReportsFromLast failed: %v", err)
 	}
@@ -62,13 +66,7 @@ func TestBuildReportsFromLast_LoadsPriorReportWhenTeamNameNeedsSanitization(t *t
 		TeamName:        "Team/A",
 	}
 
-	orig := classifySectionsFn
-	classifySectionsFn = func(_ Config, _ []WorkItem, _ []sectionOption, _ []existingItemContext, _ []ClassificationCorrection, _ []historicalItem) (map[int64]LLMSectionDecision, LLMUsage, error) {
-		return map[int64]LLMSectionDecision{}, LLMUsage{}, nil
-	}
-	defer func() { classifySectionsFn = orig }()
-
-	result, err := BuildReportsFromLast(cfg, nil, mustDate(t, "20260209"), nil, nil)
+	result, err := BuildReportsFromLast(cfg, nil, mustDate(t, "20260209"), nil, nil, nil)
 	if err != nil {
 		t.Fatalf("BuildReportsFromLast failed: %v", err)
 	}
@@ -104,19 +102,9 @@ func TestBuildReportsFromLast_MergeSortAndDoneRemoval(t *testing.T) {
 		TeamName:        "TEAMX",
 	}
 
-	orig := classifySectionsFn
-	classifySectionsFn = func(_ Config, items []WorkItem, _ []sectionOption, _ []existingItemContext, _ []ClassificationCorrection, _ []historicalItem) (map[int64]LLMSectionDecision, LLMUsage, error) {
-		out := make(map[int64]LLMSectionDecision)
-		for _, item := range items {
-			out[item.ID] = LLMSectionDecision{
-				SectionID:        "S0_0",
-				NormalizedStatus: normalizeStatus(item.Status),
-				Confidence:       0.95,
-			}
-		}
-		return out, LLMUsage{}, nil
-	}
-	defer func() { classifySectionsFn = orig }()
+	cfg.LLMProvider = registerClassifier(t, func(item WorkItem, _ string) LLMSectionDecision {
+		return LLMSectionDecision{SectionID: "S0_0", NormalizedStatus: item.Status, Confidence: 0.95}
+	})
 
 	items := []WorkItem{
 		{ID: 11, Author: "Pat Two", Description: "Old done item", Status: "done"},
@@ -124,7 +112,7 @@ func TestBuildReportsFromLast_MergeSortAndDoneRemoval(t *testing.T) {
 		{ID: 13, Author: "Pat Four", Description: "New progress item", Status: "in progress"},
 	}
 
-	result, err := BuildReportsFromLast(cfg, items, mustDate(t, "20260209"), nil, nil)
+	result, err := BuildReportsFromLast(cfg, items, mustDate(t, "20260209"), nil, nil, nil)
 	if err != nil {
 		t.Fatalf("BuildReportsFromLast failed: %v", err)
 	}
@@ -173,43 +161,24 @@ func TestBuildReportsFromLast_LLMConfidenceAndDuplicate(t *testing.T) {
 		TeamName:        "TEAMX",
 	}
 
-	orig := classifySectionsFn
-	classifySectionsFn = func(_ Config, items []WorkItem, _ []sectionOption, existing []existingItemContext, _ []ClassificationCorrection, _ []historicalItem) (map[int64]LLMSectionDecision, LLMUsage, error) {
-		out := make(map[int64]LLMSectionDecision)
-		var dupKey string
-		for _, ex := range existing {
-			if strings.Contains(ex.Description, "Existing ongoing item") {
-				dupKey = ex.Key
-				break
-			}
+	existingKeyRe := regexp.MustCompile(`(?m)^- (K\d+) \|.*Existing ongoing item`)
+	cfg.LLMProvider = registerClassifier(t, func(item WorkItem, prompt string) LLMSectionDecision {
+		if item.ID == 22 {
+			return LLMSectionDecision{SectionID: "S0_0", NormalizedStatus: "done", Confidence: 0.40}
 		}
-		for _, item := range items {
-			switch item.ID {
-			case 21:
-				out[item.ID] = LLMSectionDecision{
-					SectionID:        "S0_0",
-					NormalizedStatus: "in test",
-					DuplicateOf:      dupKey,
-					Confidence:       0.95,
-				}
-			case 22:
-				out[item.ID] = LLMSectionDecision{
-					SectionID:        "S0_0",
-					NormalizedStatus: "done",
-					Confidence:       0.40,
-				}
-			}
+		var dupKey string
+		if m := existingKeyRe.FindStringSubmatch(prompt); m != nil {
+			dupKey = m[1]
 		}
-		return out, LLMUsage{}, nil
-	}
-	defer func() { classifySectionsFn = orig }()
+		return LLMSectionDecision{SectionID: "S0_0", NormalizedStatus: "in test", DuplicateOf: dupKey, Confidence: 0.95}
+	})
 
 	items := []WorkItem{
 		{ID: 21, Author: "Pat Two", Description: "Refined wording of existing ongoing item", Status: "in progress"},
 		{ID: 22, Author: "Pat Three", Description: "Low confidence placement", Status: "in progress"},
 	}
 
-	result, err := BuildReportsFromLast(cfg, items, mustDate(t, "20260209"), nil, nil)
+	result, err := BuildReportsFromLast(cfg, items, mustDate(t, "20260209"), nil, nil, nil)
 	if err != nil {
 		t.Fatalf("BuildReportsFromLast failed: %v", err)
 	}
@@ -249,26 +218,16 @@ func TestBuildReportsFromLast_PreservesFreeTextStatus(t *testing.T) {
 		TeamName:        "TEAMX",
 	}
 
-	orig := classifySectionsFn
-	classifySectionsFn = func(_ Config, items []WorkItem, _ []sectionOption, _ []existingItemContext, _ []ClassificationCorrection, _ []historicalItem) (map[int64]LLMSectionDecision, LLMUsage, error) {
-		out := make(map[int64]LLMSectionDecision, len(items))
-		for _, item := range items {
-			out[item.ID] = LLMSectionDecision{
-				SectionID:        "S0_0",
-				NormalizedStatus: "in progress",
-				Confidence:       0.95,
-			}
-		}
-		return out, LLMUsage{}, nil
-	}
-	defer func() { classifySectionsFn = orig }()
+	cfg.LLMProvider = registerClassifier(t, func(WorkItem, string) LLMSectionDecision {
+		return LLMSectionDecision{SectionID: "S0_0", NormalizedStatus: "in progress", Confidence: 0.95}
+	})
 
 	freeTextStatus := "resolved in session; root cause analysis in progress"
 	items := []WorkItem{
 		{ID: 41, Author: "Pat Two", Description: "Investigate customer database startup issue", Status: freeTextStatus},
 	}
 
-	result, err := BuildReportsFromLast(cfg, items, mustDate(t, "20260209"), nil, nil)
+	result, err := BuildReportsFromLast(cfg, items, mustDate(t, "20260209"), nil, nil, nil)
 	if err != nil {
 		t.Fatalf("BuildReportsFromLast failed: %v", err)
 	}
@@ -299,21 +258,11 @@ func TestBuildReportsFromLast_PreservesPrefixBlocks(t *testing.T) {
 		TeamName:        "TEAMX",
 	}
 
-	orig := classifySectionsFn
-	classifySectionsFn = func(_ Config, items []WorkItem, _ []sectionOption, _ []existingItemContext, _ []ClassificationCorrection, _ []historicalItem) (map[int64]LLMSectionDecision, LLMUsage, error) {
-		out := make(map[int64]LLMSectionDecision)
-		for _, item := range items {
-			out[item.ID] = LLMSectionDecision{
-				SectionID:        "S0_0",
-				NormalizedStatus: normalizeStatus(item.Status),
-				Confidence:       0.95,
-			}
-		}
-		return out, LLMUsage{}, nil
-	}
-	defer func() { classifySectionsFn = orig }()
+	cfg.LLMProvider = registerClassifier(t, func(item WorkItem, _ string) LLMSectionDecision {
+		return LLMSectionDecision{SectionID: "S0_0", NormalizedStatus: item.Status, Confidence: 0.95}
+	})
 
-	result, err := BuildReportsFromLast(cfg, []WorkItem{{ID: 31, Author: "Pat Two", Description: "New item", Status: "in progress"}}, mustDate(t, "20260209"), nil, nil)
+	result, err := BuildReportsFromLast(cfg, []WorkItem{{ID: 31, Author: "Pat Two", Description: "New item", Status: "in progress"}}, mustDate(t, "20260209"), nil, nil, nil)
 	if err != nil {
 		t.Fatalf("BuildReportsFromLast failed: %v", err)
 	}
@@ -453,21 +402,11 @@ func TestBuildReportsFromLast_PreservesMidTopHeading(t *testing.T) {
 		TeamName:        "TEAMX",
 	}
 
-	orig := classifySectionsFn
-	classifySectionsFn = func(_ Config, items []WorkItem, _ []sectionOption, _ []existingItemContext, _ []ClassificationCorrection, _ []historicalItem) (map[int64]LLMSectionDecision, LLMUsage, error) {
-		out := make(map[int64]LLMSectionDecision)
-		for _, item := range items {
-			out[item.ID] = LLMSectionDecision{
-				SectionID:        "S0_0",
-				NormalizedStatus: normalizeStatus(item.Status),
-				Confidence:       0.95,
-			}
-		}
-		return out, LLMUsage{}, nil
-	}
-	defer func() { classifySectionsFn = orig }()
+	cfg.LLMProvider = registerClassifier(t, func(item WorkItem, _ string) LLMSectionDecision {
+		return LLMSectionDecision{SectionID: "S0_0", NormalizedStatus: item.Status, Confidence: 0.95}
+	})
 
-	result, err := BuildReportsFromLast(cfg, []WorkItem{{ID: 99, Author: "Pat Five", Description: "New item", Status: "in progress"}}, mustDate(t, "20260209"), nil, nil)
+	result, err := BuildReportsFromLast(cfg, []WorkItem{{ID: 99, Author: "Pat Five", Description: "New item", Status: "in progress"}}, mustDate(t, "20260209"), nil, nil, nil)
 	if err != nil {
 		t.Fatalf("BuildReportsFromLast failed: %v", err)
 	}
@@ -639,6 +578,61 @@ func TestReorderTemplateItems_SupportCasesKeepsNewItemsAtBottom(t *testing.T) {
 	}
 }
 
+type stubSectionProvider struct {
+	response string
+}
+
+func (p stubSectionProvider) Name() string  { return "report-stub" }
+func (p stubSectionProvider) Model() string { return "stub" }
+
+func (p stubSectionProvider) ClassifySections(_, _ string, _ []illm.SectionOption) (string, LLMUsage, error) {
+	return p.response, LLMUsage{InputTokens: 3, OutputTokens: 2}, nil
+}
+
+func (p stubSectionProvider) Complete(_, _ string) (string, LLMUsage, error) {
+	return "[]", LLMUsage{}, nil
+}
+
+func TestBuildReportsFromLast_UsesRegisteredProvider(t *testing.T) {
+	dir := t.TempDir()
+	prev := `### TEAMX 20260202
+
+#### Top Focus
+
+- **Feature A**
+  - **Pat One** - Ongoing item (in progress)
+`
+	if err := os.WriteFile(filepath.Join(dir, "TEAMX_20260202.md"), []byte(prev), 0644); err != nil {
+		t.Fatalf("write previous report: %v", err)
+	}
+
+	illm.RegisterProvider("report-stub", func(Config) (illm.Provider, error) {
+		return stubSectionProvider{
+			response: `[{"id": 7, "section_id": "S0_0", "normalized_status": "done", "ticket_ids": [], "duplicate_of": ""}]`,
+		}, nil
+	})
+	t.Cleanup(func() { illm.UnregisterProvider("report-stub") })
+	cfg := Config{
+		ReportOutputDir: dir,
+		TeamName:        "TEAMX",
+		LLMProvider:     "report-stub",
+		LLMBatchSize:    10,
+	}
+	items := []WorkItem{{ID: 7, Author: "Pat One", Description: "Ship feature A", Status: "done"}}
+
+	result, err := BuildReportsFromLast(cfg, items, mustDate(t, "20260209"), nil, nil, nil)
+	if err != nil {
+		t.Fatalf("BuildReportsFromLast failed: %v", err)
+	}
+	if result.Usage.TotalTokens() != 5 {
+		t.Fatalf("expected stub provider usage, got %d", result.Usage.TotalTokens())
+	}
+	team := renderTeamMarkdown(result.Template)
+	if !strings.Contains(team, "**Pat One** - Ship feature A (done)") || strings.Contains(team, "#### Undetermined") {
+		t.Fatalf("expected stub provider decision to place item under Top Focus:\n%s", team)
+	}
+}
+
 func mustDate(t *testing.T, ymd string) time.Time {
 	t.Helper()
 	d, err := time.Parse("20060102", ymd)
@@ -647,3 +641,50 @@ func mustDate(t *testing.T, ymd string) time.Time {
 	}
 	return d
 }
+
+const testClassifierName = "report-test"
+
+var promptItemRe = regexp.MustCompile(`(?m)^ID:(\d+) - (.*) \(status: (.*)\)$`)
+
+// registerClassifier registers an LLM provider that answers each item in the
+// classification prompt with decide, so BuildReportsFromLast classifies
+// through the provider registry the same way it does in production. The
+// item passed to decide carries the ID, description and normalized status
+// from the prompt.
+func registerClassifier(t *testing.T, decide func(item WorkItem, prompt string) LLMSectionDecision) string {
+	t.Helper()
+	illm.RegisterProvider(testClassifierName, func(Config) (illm.Provider, error) {
+		return classifierProvider{decide: decide}, nil
+	})
+	t.Cleanup(func() { illm.UnregisterProvider(testClassifierName) })
+	return testClassifierName
+}
+
+type classifierProvider struct {
+	decide func(item WorkItem, prompt string) LLMSectionDecision
+}
+
+func (classifierProvider) Name() string  { return testClassifierName }
+func (classifierProvider) Model() string { return "scripted" }
+
+func (p classifierProvider) ClassifySections(_, userPrompt string, _ []sectionOption) (string, LLMUsage, error) {
+	out := []map[string]any{}
+	for _, m := range promptItemRe.FindAllStringSubmatch(userPrompt, -1) {
+		id, _ := strconv.ParseInt(m[1], 10, 64)
+		d := p.decide(WorkItem{ID: id, Description: m[2], Status: m[3]}, userPrompt)
+		out = append(out, map[string]any{
+			"id":                id,
+			"section_id":        d.SectionID,
+			"normalized_status": d.NormalizedStatus,
+			"ticket_ids":        []string{},
+			"duplicate_of":      d.DuplicateOf,
+			"confidence":        d.Confidence,
+		})
+	}
+	reply, err := json.Marshal(out)
+	return string(reply), LLMUsage{}, err
+}
+
+func (classifierProvider) Complete(_, _ string) (string, LLMUsage, error) {
+	return "[]", LLMUsage{}, nil
+}
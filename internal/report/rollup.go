@@ -0,0 +1,206 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// RollupSource is one team of a department roll-up: the name its reports
+// are filed under and the directory they are written to.
+type RollupSource struct {
+	TeamName  string
+	OutputDir string
+}
+
+// RollupTeam is one team's section of a roll-up.
+type RollupTeam struct {
+	Name string
+	// ReportDate is the date of the report the section was taken from; zero
+	// when the team has no report yet.
+	ReportDate time.Time
+	Template   *ReportTemplate
+	Summary    string
+	Done       int
+	InProgress int
+}
+
+// Rollup combines the latest report of several teams.
+type Rollup struct {
+	Title      string
+	ReportDate time.Time
+	Teams      []RollupTeam
+	Usage      LLMUsage
+}
+
+var summarizeTeamFn = func(cfg Config, teamName, report string) (string, LLMUsage, error) {
+	return SummarizeTeamReport(cfg, teamName, report)
+}
+
+// RollupSources returns the teams of a multi-team deployment, or the one
+// team of a single-team deployment, with their report output dirs.
+func RollupSources(cfg Config) []RollupSource {
+	var out []RollupSource
+	for _, tc := range cfg.TeamConfigs() {
+		out = append(out, RollupSource{TeamName: tc.TeamName, OutputDir: tc.ReportOutputDir})
+	}
+	return out
+}
+
+// BuildRollup loads the newest report of every source dated on or before
+// reportDate. With summarize, each team also gets an LLM-written summary
+// paragraph; summaries are skipped once the LLM budget is spent and a
+// failed summary is logged and left empty.
+func BuildRollup(cfg Config, sources []RollupSource, reportDate time.Time, summarize bool) (Rollup, error) {
+	r := Rollup{Title: cfg.RollupTitle, ReportDate: reportDate}
+	for _, src := range sources {
+		team := RollupTeam{Name: src.TeamName}
+		path, date, err := latestReportOnOrBefore(src.OutputDir, src.TeamName, reportDate)
+		if err != nil {
+			return r, fmt.Errorf("team %s: %w", src.TeamName, err)
+		}
+		if path != "" {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return r, fmt.Errorf("team %s: reading report: %w", src.TeamName, err)
+			}
+			t := parseTemplate(string(content))
+			stripCurrentTeamTitleFromPrefix(t, src.TeamName)
+			team.Template = t
+			team.ReportDate = date
+			team.Done, team.InProgress = countTemplateStatuses(t)
+		}
+		r.Teams = append(r.Teams, team)
+	}
+
+	if !summarize {
+		return r, nil
+	}
+	for i := range r.Teams {
+		team := &r.Teams[i]
+		if team.Template == nil {
+			continue
+		}
+		if err := CheckOptionalPassBudget(cfg); err != nil {
+			log.Printf("rollup summaries skipped: %v", err)
+			break
+		}
+		summary, usage, err := summarizeTeamFn(cfg, team.Name, renderBossMarkdown(team.Template))
+		r.Usage.Add(usage)
+		if err != nil {
+			log.Printf("rollup summary error team=%s (non-fatal): %v", team.Name, err)
+			continue
+		}
+		team.Summary = summary
+	}
+	return r, nil
+}
+
+// latestReportOnOrBefore returns the newest report of teamName in
+// outputDir dated no later than reportDate, or an empty path when there is
+// none (including when outputDir does not exist yet).
+func latestReportOnOrBefore(outputDir, teamName string, reportDate time.Time) (string, time.Time, error) {
+	files, err := listReportFiles(outputDir, teamName)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", time.Time{}, nil
+		}
+		return "", time.Time{}, err
+	}
+	last := reportDate.Format("20060102")
+	for _, f := range files {
+		if f.date.Format("20060102") <= last {
+			return f.path, f.date, nil
+		}
+	}
+	return "", time.Time{}, nil
+}
+
+// countTemplateStatuses counts done items and items still in progress or
+// in testing; free-text statuses count as neither.
+func countTemplateStatuses(t *ReportTemplate) (done, inProgress int) {
+	for _, cat := range t.Categories {
+		for _, sub := range cat.Subsections {
+			for _, item := range sub.Items {
+				switch statusBucket(statusForDisplay(item.Status)) {
+				case 0:
+					done++
+				case 1, 2:
+					inProgress++
+				}
+			}
+		}
+	}
+	return done, inProgress
+}
+
+// RenderRollupMarkdown renders the roll-up with one ### heading per team,
+// its counts and summary, and the team's report in boss format. The output
+// uses the same markdown subset as team reports, so it converts to EML with
+// WriteEmailDraftFile.
+func RenderRollupMarkdown(r Rollup) string {
+	var buf strings.Builder
+	totalDone, totalInProgress := 0, 0
+	for _, team := range r.Teams {
+		totalDone += team.Done
+		totalInProgress += team.InProgress
+	}
+	buf.WriteString(fmt.Sprintf("### %s %s\n\n", r.Title, r.ReportDate.Format("20060102")))
+	buf.WriteString(fmt.Sprintf("All teams: %s\n\n", formatRollupCounts(totalDone, totalInProgress)))
+
+	for _, team := range r.Teams {
+		buf.WriteString(fmt.Sprintf("### %s\n\n", team.Name))
+		if team.Template == nil {
+			buf.WriteString("No report yet.\n\n")
+			continue
+		}
+		counts := formatRollupCounts(team.Done, team.InProgress)
+		if team.ReportDate.Format("20060102") != r.ReportDate.Format("20060102") {
+			counts += fmt.Sprintf(" (report of %s)", team.ReportDate.Format("2006-01-02"))
+		}
+		buf.WriteString("**" + counts + "**\n\n")
+		if team.Summary != "" {
+			buf.WriteString(team.Summary + "\n\n")
+		}
+		body := *team.Template
+		body.PrefixLines = nil
+		buf.WriteString(renderBossMarkdown(&body))
+		buf.WriteString("\n")
+	}
+	return strings.TrimSpace(buf.String()) + "\n"
+}
+
+func formatRollupCounts(done, inProgress int) string {
+	return fmt.Sprintf("%d done, %d in progress", done, inProgress)
+}
+
+// WriteRollupFiles writes the roll-up as <title>_YYYYMMDD.md and as a
+// multipart .eml draft next to it.
+func WriteRollupFiles(r Rollup, outputDir string) (mdPath, emlPath string, err error) {
+	content := RenderRollupMarkdown(r)
+	if mdPath, err = WriteReportFile(content, outputDir, r.ReportDate, r.Title); err != nil {
+		return "", "", err
+	}
+	if emlPath, err = WriteEmailDraftFile(content, outputDir, r.ReportDate, r.Title); err != nil {
+		return "", "", err
+	}
+	return mdPath, emlPath, nil
+}
+
+// FormatRollupSummary is a one-line description of a roll-up for Slack and
+// the command line.
+func FormatRollupSummary(r Rollup) string {
+	var parts []string
+	for _, team := range r.Teams {
+		if team.Template == nil {
+			parts = append(parts, team.Name+": no report")
+			continue
+		}
+		parts = append(parts, team.Name+": "+formatRollupCounts(team.Done, team.InProgress))
+	}
+	return fmt.Sprintf("%s roll-up for %s. %s.", r.Title, r.ReportDate.Format("2006-01-02"), strings.Join(parts, "; "))
+}
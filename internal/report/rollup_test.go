@@ -0,0 +1,100 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildRollupCombinesLatestTeamReports(t *testing.T) {
+	root := t.TempDir()
+	platformDir, mobileDir := filepath.Join(root, "platform"), filepath.Join(root, "mobile")
+	write := func(dir, name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(platformDir, "Platform_20260227.md", "### Platform 20260227\n\n#### Old\n\n- **Pat One** - Old item (done)\n")
+	write(platformDir, "Platform_20260306.md", `### Platform 20260306
+
+#### Infrastructure
+
+- **Pat One** - Migrate CI runners (done)
+- **Sam Two** - Upgrade Postgres (in progress)
+- **Sam Two** - Capacity review (in testing)
+`)
+	// Next week's report must not be picked for this week's roll-up.
+	write(platformDir, "Platform_20260313.md", "### Platform 20260313\n\n#### Future\n\n- **Pat One** - Future item (done)\n")
+	write(mobileDir, "Mobile_20260227.md", `### Mobile 20260227
+
+#### App
+
+- **Lee Three** - Release 4.2 (done)
+- **Lee Three** - Dark mode (blocked)
+`)
+
+	orig := summarizeTeamFn
+	defer func() { summarizeTeamFn = orig }()
+	var summarized []string
+	summarizeTeamFn = func(_ Config, teamName, report string) (string, LLMUsage, error) {
+		summarized = append(summarized, teamName)
+		if !strings.Contains(report, "Migrate CI runners") && !strings.Contains(report, "Release 4.2") {
+			t.Fatalf("summary request without the team report: %q", report)
+		}
+		return teamName + " shipped things.", LLMUsage{InputTokens: 10, OutputTokens: 5}, nil
+	}
+
+	cfg := Config{RollupTitle: "Engineering"}
+	sources := []RollupSource{
+		{TeamName: "Platform", OutputDir: platformDir},
+		{TeamName: "Mobile", OutputDir: mobileDir},
+		{TeamName: "Data", OutputDir: filepath.Join(root, "data")},
+	}
+	r, err := BuildRollup(cfg, sources, mustDate(t, "20260306"), true)
+	if err != nil {
+		t.Fatalf("BuildRollup: %v", err)
+	}
+	if strings.Join(summarized, ",") != "Platform,Mobile" || r.Usage.TotalTokens() != 30 {
+		t.Fatalf("summaries for %v, usage %+v", summarized, r.Usage)
+	}
+	platform, mobile := r.Teams[0], r.Teams[1]
+	if platform.Done != 1 || platform.InProgress != 2 || mobile.Done != 1 || mobile.InProgress != 0 {
+		t.Fatalf("unexpected counts: platform=%d/%d mobile=%d/%d", platform.Done, platform.InProgress, mobile.Done, mobile.InProgress)
+	}
+
+	md := RenderRollupMarkdown(r)
+	for _, want := range []string{
+		"### Engineering 20260306",
+		"All teams: 2 done, 2 in progress",
+		"### Platform\n\n**1 done, 2 in progress**\n\nPlatform shipped things.\n\n#### Infrastructure (Pat One, Sam Two)",
+		"### Mobile\n\n**1 done, 0 in progress (report of 2026-02-27)**",
+		"### Data\n\nNo report yet.",
+	} {
+		if !strings.Contains(md, want) {
+			t.Fatalf("roll-up missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "Future item") || strings.Contains(md, "Old item") || strings.Contains(md, "### Platform 20260306") {
+		t.Fatalf("roll-up should only contain each team's latest report body:\n%s", md)
+	}
+
+	mdPath, emlPath, err := WriteRollupFiles(r, root)
+	if err != nil {
+		t.Fatalf("WriteRollupFiles: %v", err)
+	}
+	if filepath.Base(mdPath) != "Engineering_20260306.md" || filepath.Base(emlPath) != "Engineering_20260306.eml" {
+		t.Fatalf("unexpected paths %s %s", mdPath, emlPath)
+	}
+	eml, err := os.ReadFile(emlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(eml), "Subject: Engineering 20260306") || !strings.Contains(string(eml), ">Mobile</div>") {
+		t.Fatalf("unexpected eml:\n%s", eml)
+	}
+}
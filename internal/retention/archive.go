@@ -0,0 +1,67 @@
+package retention
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// archiveRecord is one line of a retention archive.
+type archiveRecord struct {
+	Table string         `json:"table"`
+	Row   map[string]any `json:"row"`
+}
+
+// jsonlArchive writes purged rows as gzip-compressed JSON lines.
+type jsonlArchive struct {
+	path string
+	file *os.File
+	gz   *gzip.Writer
+	buf  *bufio.Writer
+	enc  *json.Encoder
+	rows int
+}
+
+func openArchive(dir string, now time.Time) (*jsonlArchive, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create archive dir: %w", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("reportbot-retention-%s.jsonl.gz", now.Format("20060102-150405")))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("create archive: %w", err)
+	}
+	gz := gzip.NewWriter(f)
+	buf := bufio.NewWriter(gz)
+	return &jsonlArchive{path: path, file: f, gz: gz, buf: buf, enc: json.NewEncoder(buf)}, nil
+}
+
+func (a *jsonlArchive) Write(table string, row map[string]any) error {
+	a.rows++
+	return a.enc.Encode(archiveRecord{Table: table, Row: row})
+}
+
+// Close flushes and syncs the archive so it is durable before the purge
+// commits.
+func (a *jsonlArchive) Close() error {
+	if a.file == nil {
+		return nil
+	}
+	f := a.file
+	a.file = nil
+	err := a.buf.Flush()
+	if cerr := a.gz.Close(); err == nil {
+		err = cerr
+	}
+	if serr := f.Sync(); err == nil {
+		err = serr
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
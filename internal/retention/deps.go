@@ -0,0 +1,11 @@
+package retention
+
+import (
+	"reportbot/internal/config"
+	"reportbot/internal/domain"
+	"reportbot/internal/storage"
+)
+
+type Config = config.Config
+type Store = storage.Store
+type Policy = domain.RetentionPolicy
@@ -0,0 +1,163 @@
+// Package retention removes old rows according to the retention_* config,
+// either archiving them to a gzipped JSONL file first or purging outright.
+package retention
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"reportbot/internal/config"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/slack-go/slack"
+)
+
+// Result describes one retention run. ArchivePath is empty when nothing was
+// archived.
+type Result struct {
+	Deleted     map[string]int
+	ArchivePath string
+	DryRun      bool
+}
+
+// Total is the number of rows removed (or, for a dry run, that would be).
+func (r Result) Total() int {
+	total := 0
+	for _, n := range r.Deleted {
+		total += n
+	}
+	return total
+}
+
+// PolicyAt converts the configured retention days into cutoffs relative to
+// now. Days are counted in now's location, but the cutoffs are returned in
+// UTC: timestamps are stored in UTC and SQLite compares them as text, so a
+// cutoff bound with another offset would be off by that offset.
+func PolicyAt(cfg Config, now time.Time) Policy {
+	cutoff := func(days int) time.Time {
+		if days <= 0 {
+			return time.Time{}
+		}
+		return now.AddDate(0, 0, -days).UTC()
+	}
+	return Policy{
+		WorkItemsBefore:       cutoff(cfg.RetentionWorkItemsDays),
+		ClassificationsBefore: cutoff(cfg.RetentionClassificationDays),
+		CorrectionsBefore:     cutoff(cfg.RetentionCorrectionsDays),
+		SupersededCorrections: cfg.RetentionSupersededCorrections,
+	}
+}
+
+// Run applies the retention policy once. In archive mode the deleted rows
+// are written to retention_archive_dir first; a dry run only counts.
+func Run(cfg Config, db Store, now time.Time, dryRun bool) (Result, error) {
+	result := Result{DryRun: dryRun}
+	policy := PolicyAt(cfg, now)
+
+	var archive *jsonlArchive
+	if !dryRun && cfg.RetentionMode == config.RetentionModeArchive {
+		var err error
+		archive, err = openArchive(cfg.RetentionArchiveDir, now)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	var deleted map[string]int
+	var err error
+	if archive != nil {
+		deleted, err = db.PurgeExpired(policy, archive, dryRun)
+		archive.Close()
+		if err != nil || archive.rows == 0 {
+			os.Remove(archive.path)
+		} else {
+			result.ArchivePath = archive.path
+		}
+	} else {
+		deleted, err = db.PurgeExpired(policy, nil, dryRun)
+	}
+	if err != nil {
+		return result, fmt.Errorf("retention: %w", err)
+	}
+	result.Deleted = deleted
+	return result, nil
+}
+
+// FormatSummary renders a one-line summary for logs and Slack.
+func FormatSummary(r Result) string {
+	verb := "Retention removed"
+	if r.DryRun {
+		verb = "Retention dry run would remove"
+	}
+	if r.Total() == 0 {
+		return verb + " nothing."
+	}
+	tables := make([]string, 0, len(r.Deleted))
+	for table, n := range r.Deleted {
+		if n > 0 {
+			tables = append(tables, table)
+		}
+	}
+	sort.Strings(tables)
+	parts := make([]string, 0, len(tables))
+	for _, table := range tables {
+		parts = append(parts, fmt.Sprintf("%d %s", r.Deleted[table], table))
+	}
+	msg := fmt.Sprintf("%s %s.", verb, strings.Join(parts, ", "))
+	if r.ArchivePath != "" {
+		msg += fmt.Sprintf(" Archived to %s.", r.ArchivePath)
+	}
+	return msg
+}
+
+// StartRetentionScheduler runs the retention job on retention_schedule (a
+// standard 5-field cron expression) and posts a summary to the notice
+// channels (see Config.NoticeChannelIDs) when anything was, or would be,
+// removed.
+func StartRetentionScheduler(cfg Config, db Store, api *slack.Client) {
+	schedule := strings.TrimSpace(cfg.RetentionSchedule)
+	if schedule == "" {
+		log.Println("Retention disabled (retention_schedule not set)")
+		return
+	}
+	if !cfg.RetentionConfigured() {
+		log.Println("Retention disabled: no retention_*_days or retention_superseded_corrections configured")
+		return
+	}
+
+	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+	sched, err := parser.Parse(schedule)
+	if err != nil {
+		log.Printf("Invalid retention_schedule '%s': %v — retention disabled", schedule, err)
+		return
+	}
+	log.Printf("Retention scheduled (cron: %s) mode=%s dry_run=%t", schedule, cfg.RetentionMode, cfg.RetentionDryRun)
+
+	go func() {
+		for {
+			now := time.Now().In(cfg.Location)
+			next := sched.Next(now)
+			time.Sleep(next.Sub(now))
+
+			result, err := Run(cfg, db, time.Now().In(cfg.Location), cfg.RetentionDryRun)
+			if err != nil {
+				log.Printf("Retention error: %v", err)
+				continue
+			}
+			summary := FormatSummary(result)
+			log.Printf("Retention complete: %s", summary)
+
+			if result.Total() == 0 {
+				continue
+			}
+			for _, channelID := range cfg.NoticeChannelIDs() {
+				if _, _, postErr := api.PostMessage(channelID, slack.MsgOptionText(summary, false)); postErr != nil {
+					log.Printf("Retention post error channel=%s: %v", channelID, postErr)
+				}
+			}
+		}
+	}()
+}
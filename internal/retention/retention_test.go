@@ -0,0 +1,142 @@
+package retention
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reportbot/internal/config"
+	"reportbot/internal/domain"
+	"reportbot/internal/storage/sqlite"
+	"testing"
+	"time"
+)
+
+func TestPolicyAtSkipsUnsetTables(t *testing.T) {
+	now := time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)
+	p := PolicyAt(Config{RetentionWorkItemsDays: 730, RetentionClassificationDays: 180}, now)
+	if !p.WorkItemsBefore.Equal(now.AddDate(0, 0, -730)) || !p.ClassificationsBefore.Equal(now.AddDate(0, 0, -180)) {
+		t.Fatalf("unexpected cutoffs: %+v", p)
+	}
+	if !p.CorrectionsBefore.IsZero() || p.SupersededCorrections {
+		t.Fatalf("corrections should be kept: %+v", p)
+	}
+}
+
+func TestRunBindsCutoffsInUTC(t *testing.T) {
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "retention.db"))
+	if err != nil {
+		t.Fatalf("sqlite.Open: %v", err)
+	}
+	defer db.Close()
+
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	// 20:00 PDT is 03:00 UTC the next day, so the cutoff falls on a
+	// different calendar date in UTC than in the configured zone.
+	now := time.Date(2026, 10, 15, 20, 0, 0, 0, la)
+	cutoff := now.AddDate(0, 0, -730)
+	if _, err := db.InsertWorkItems([]domain.WorkItem{
+		{Description: "Just expired", Author: "Alice", Source: "slack", ReportedAt: cutoff.Add(-2 * time.Hour).UTC()},
+		{Description: "Just kept", Author: "Bob", Source: "slack", ReportedAt: cutoff.Add(2 * time.Hour).UTC()},
+	}, ""); err != nil {
+		t.Fatalf("InsertWorkItems: %v", err)
+	}
+
+	cfg := Config{Location: la, RetentionMode: config.RetentionModePurge, RetentionWorkItemsDays: 730}
+	if p := PolicyAt(cfg, now); p.WorkItemsBefore.Location() != time.UTC || !p.WorkItemsBefore.Equal(cutoff) {
+		t.Fatalf("expected the cutoff %s in UTC, got %s", cutoff.UTC(), p.WorkItemsBefore)
+	}
+	result, err := Run(cfg, db, now, false)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Deleted["work_items"] != 1 {
+		t.Fatalf("expected only the expired item removed, got %+v", result)
+	}
+	items, err := db.GetItemsByDateRange(cutoff.AddDate(0, 0, -1), now)
+	if err != nil || len(items) != 1 || items[0].Description != "Just kept" {
+		t.Fatalf("unexpected remaining items: %+v err=%v", items, err)
+	}
+}
+
+func TestRunArchivesPurgedRows(t *testing.T) {
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "retention.db"))
+	if err != nil {
+		t.Fatalf("sqlite.Open: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)
+	if _, err := db.InsertWorkItems([]domain.WorkItem{
+		{Description: "Old item", Author: "Alice", Source: "slack", ReportedAt: now.AddDate(-3, 0, 0)},
+		{Description: "New item", Author: "Bob", Source: "slack", ReportedAt: now.AddDate(0, 0, -1)},
+	}, ""); err != nil {
+		t.Fatalf("InsertWorkItems: %v", err)
+	}
+
+	cfg := Config{
+		RetentionMode:          config.RetentionModeArchive,
+		RetentionArchiveDir:    filepath.Join(t.TempDir(), "archive"),
+		RetentionWorkItemsDays: 730,
+	}
+
+	dry, err := Run(cfg, db, now, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if dry.Deleted["work_items"] != 1 || dry.ArchivePath != "" {
+		t.Fatalf("unexpected dry run result: %+v", dry)
+	}
+	if got := FormatSummary(dry); got != "Retention dry run would remove 1 work_item_events, 1 work_items." {
+		t.Fatalf("dry run summary = %q", got)
+	}
+	if entries, _ := os.ReadDir(cfg.RetentionArchiveDir); len(entries) != 0 {
+		t.Fatalf("dry run wrote an archive: %v", entries)
+	}
+
+	result, err := Run(cfg, db, now, false)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Deleted["work_items"] != 1 || result.ArchivePath == "" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	f, err := os.Open(result.ArchivePath)
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	tables := map[string]int{}
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		var rec archiveRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("decode archive line %q: %v", scanner.Text(), err)
+		}
+		tables[rec.Table]++
+		if rec.Table == "work_items" && rec.Row["description"] != "Old item" {
+			t.Fatalf("archived wrong item: %v", rec.Row)
+		}
+	}
+	if tables["work_items"] != 1 || tables["work_item_events"] != 1 {
+		t.Fatalf("unexpected archive contents: %v", tables)
+	}
+
+	// Nothing left to remove: no empty archive is kept.
+	again, err := Run(cfg, db, now.Add(time.Minute), false)
+	if err != nil || again.Total() != 0 || again.ArchivePath != "" {
+		t.Fatalf("second run: %+v err=%v", again, err)
+	}
+	if entries, _ := os.ReadDir(cfg.RetentionArchiveDir); len(entries) != 1 {
+		t.Fatalf("expected one archive file, got %d", len(entries))
+	}
+}
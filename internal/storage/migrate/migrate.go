@@ -0,0 +1,191 @@
+// Package migrate applies numbered, forward-only schema migrations and
+// records them in a schema_migrations table. The SQLite and PostgreSQL
+// backends each define their own migration list and dialect.
+package migrate
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// Migration is one schema change. Versions start at 1 and never change once
+// released; Up runs inside a transaction together with the bookkeeping row.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *sql.Tx) error
+}
+
+// Dialect holds the backend-specific SQL for the schema_migrations table.
+type Dialect struct {
+	// CreateTable creates schema_migrations(version, name, applied_at) if it
+	// does not exist.
+	CreateTable string
+	// Insert records one applied migration; it takes version, name and
+	// applied_at as parameters.
+	Insert string
+}
+
+// Status describes one migration. Known is false for versions recorded in
+// the database that this binary has no definition for.
+type Status struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Known     bool
+}
+
+// ErrSchemaTooNew is returned when the database has migrations applied that
+// this binary does not know about, i.e. it was written by a newer release.
+var ErrSchemaTooNew = errors.New("database schema is newer than this binary supports")
+
+// Latest returns the highest version in migrations.
+func Latest(migrations []Migration) int {
+	latest := 0
+	for _, m := range migrations {
+		if m.Version > latest {
+			latest = m.Version
+		}
+	}
+	return latest
+}
+
+// Current returns the highest applied version in statuses, or 0.
+func Current(statuses []Status) int {
+	current := 0
+	for _, s := range statuses {
+		if s.Applied && s.Version > current {
+			current = s.Version
+		}
+	}
+	return current
+}
+
+// List returns every known migration plus any unknown applied version,
+// ordered by version.
+func List(db *sql.DB, d Dialect, migrations []Migration) ([]Status, error) {
+	if err := validate(migrations); err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(d.CreateTable); err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Status
+	for _, m := range migrations {
+		s := Status{Version: m.Version, Name: m.Name, Known: true}
+		if a, ok := applied[m.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = a.AppliedAt
+			delete(applied, m.Version)
+		}
+		out = append(out, s)
+	}
+	for _, a := range applied {
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// Up applies every pending migration in order, each in its own transaction,
+// and returns the ones it applied. It refuses to touch a database whose
+// schema is newer than migrations.
+func Up(db *sql.DB, d Dialect, migrations []Migration) ([]Status, error) {
+	statuses, err := List(db, d, migrations)
+	if err != nil {
+		return nil, err
+	}
+	latest := Latest(migrations)
+	if current := Current(statuses); current > latest {
+		return nil, fmt.Errorf("%w: database is at version %d, this binary knows up to %d", ErrSchemaTooNew, current, latest)
+	}
+
+	byVersion := make(map[int]Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+	var done []Status
+	for _, s := range statuses {
+		if s.Applied {
+			continue
+		}
+		m := byVersion[s.Version]
+		appliedAt := time.Now().UTC()
+		if err := apply(db, d, m, appliedAt); err != nil {
+			return done, fmt.Errorf("migration %d (%s): %w", m.Version, m.Name, err)
+		}
+		log.Printf("storage migration applied version=%d name=%s", m.Version, m.Name)
+		s.Applied = true
+		s.AppliedAt = appliedAt
+		done = append(done, s)
+	}
+	return done, nil
+}
+
+func apply(db *sql.DB, d Dialect, m Migration, appliedAt time.Time) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := m.Up(tx); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(d.Insert, m.Version, m.Name, appliedAt); err != nil {
+		return fmt.Errorf("record migration: %w", err)
+	}
+	return tx.Commit()
+}
+
+func appliedVersions(db *sql.DB) (map[int]Status, error) {
+	rows, err := db.Query(`SELECT version, name, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+	out := make(map[int]Status)
+	for rows.Next() {
+		s := Status{Applied: true}
+		if err := rows.Scan(&s.Version, &s.Name, &s.AppliedAt); err != nil {
+			return nil, fmt.Errorf("read schema_migrations: %w", err)
+		}
+		out[s.Version] = s
+	}
+	return out, rows.Err()
+}
+
+func validate(migrations []Migration) error {
+	seen := make(map[int]bool, len(migrations))
+	for _, m := range migrations {
+		if m.Version <= 0 || m.Up == nil {
+			return fmt.Errorf("invalid migration %d (%s)", m.Version, m.Name)
+		}
+		if seen[m.Version] {
+			return fmt.Errorf("duplicate migration version %d", m.Version)
+		}
+		seen[m.Version] = true
+	}
+	return nil
+}
+
+// Exec returns an Up func that runs each statement in order.
+func Exec(statements ...string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		for _, stmt := range statements {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,732 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"regexp"
+	"reportbot/internal/domain"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type WorkItem = domain.WorkItem
+type ClassificationRecord = domain.ClassificationRecord
+type ClassificationCorrection = domain.ClassificationCorrection
+type ClassificationStats = domain.ClassificationStats
+type SectionCorrectionStat = domain.SectionCorrectionStat
+type WeeklyTrend = domain.WeeklyTrend
+type HistoricalItem = domain.HistoricalItem
+type ConfidenceSample = domain.ConfidenceSample
+type LabeledWorkItem = domain.LabeledWorkItem
+
+// Store implements storage.Store on PostgreSQL. Queries mirror the SQLite
+// backend; only dialect differs (placeholders, ON CONFLICT, ANY arrays).
+type Store struct {
+	DB   *sql.DB
+	team domain.TeamScope
+}
+
+// Open connects to the database at dsn (a postgres:// URL or key=value
+// string) and applies any pending schema migrations.
+func Open(dsn string) (*Store, error) {
+	s, err := OpenUnmigrated(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.Migrate(); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// OpenUnmigrated connects to dsn without touching the schema, for inspecting
+// or applying migrations explicitly.
+func OpenUnmigrated(dsn string) (*Store, error) {
+	if strings.TrimSpace(dsn) == "" {
+		return nil, fmt.Errorf("db_dsn is required when db_driver=postgres")
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connect to postgres: %w", err)
+	}
+	return &Store{DB: db}, nil
+}
+
+func (s *Store) Close() error { return s.DB.Close() }
+
+var dsnPasswordPattern = regexp.MustCompile(`(?i)(password\s*=\s*)('[^']*'|\S+)`)
+
+// RedactDSN hides the password in dsn so it can be logged.
+func RedactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), "xxxxx")
+		}
+		return u.String()
+	}
+	return dsnPasswordPattern.ReplaceAllString(dsn, "${1}xxxxx")
+}
+
+const workItemColumns = `id, description, author, author_id, source, source_ref, category, status, ticket_ids, reported_at, created_at, team_id`
+
+func scanWorkItems(rows *sql.Rows) ([]WorkItem, error) {
+	defer rows.Close()
+	var items []WorkItem
+	for rows.Next() {
+		var item WorkItem
+		if err := rows.Scan(
+			&item.ID, &item.Description, &item.Author, &item.AuthorID, &item.Source,
+			&item.SourceRef, &item.Category, &item.Status, &item.TicketIDs,
+			&item.ReportedAt, &item.CreatedAt, &item.TeamID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+const insertWorkItemSQL = `INSERT INTO work_items (description, author, author_id, source, source_ref, category, status, ticket_ids, reported_at, team_id)
+	 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	 ON CONFLICT DO NOTHING
+	 RETURNING id`
+
+func (s *Store) InsertWorkItem(item WorkItem, actorID string) error {
+	_, err := s.InsertWorkItems([]WorkItem{item}, actorID)
+	return err
+}
+
+func (s *Store) InsertWorkItems(items []WorkItem, actorID string) (int, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(insertWorkItemSQL)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	inserted := 0
+	for _, item := range s.team.Assign(items) {
+		var id int64
+		err := stmt.QueryRow(
+			item.Description, item.Author, item.AuthorID, item.Source, item.SourceRef,
+			item.Category, item.Status, item.TicketIDs, item.ReportedAt, item.TeamID,
+		).Scan(&id)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return inserted, err
+		}
+		if err := insertWorkItemEvent(tx, id, domain.WorkItemEventCreated, "", item.Description, actorID); err != nil {
+			return inserted, err
+		}
+		inserted++
+	}
+	return inserted, tx.Commit()
+}
+
+// SourceRefExists also counts deleted items, so a deleted MR/PR is not
+// imported again by the next fetch. A team-scoped store only sees its own
+// team's items, so each team tracks a shared MR/PR separately.
+func (s *Store) SourceRefExists(sourceRef string) (bool, error) {
+	filter, filterArgs := s.teamFilter("team_id", 2)
+	var count int
+	err := s.DB.QueryRow(`SELECT COUNT(*) FROM work_items WHERE source_ref = $1`+filter,
+		append([]any{sourceRef}, filterArgs...)...).Scan(&count)
+	return count > 0, err
+}
+
+// GetWorkItemBySourceRef returns the live item imported from sourceRef. It
+// returns sql.ErrNoRows when there is none or the item was deleted.
+func (s *Store) GetWorkItemBySourceRef(sourceRef string) (WorkItem, error) {
+	filter, filterArgs := s.teamFilter("team_id", 2)
+	var item WorkItem
+	err := s.DB.QueryRow(
+		`SELECT `+workItemColumns+`
+		 FROM work_items WHERE source_ref = $1 AND source_ref <> '' AND deleted_at IS NULL`+filter+`
+		 ORDER BY id LIMIT 1`,
+		append([]any{sourceRef}, filterArgs...)...,
+	).Scan(
+		&item.ID, &item.Description, &item.Author, &item.AuthorID, &item.Source,
+		&item.SourceRef, &item.Category, &item.Status, &item.TicketIDs,
+		&item.ReportedAt, &item.CreatedAt, &item.TeamID,
+	)
+	return item, err
+}
+
+func (s *Store) GetItemsByDateRange(from, to time.Time) ([]WorkItem, error) {
+	filter, filterArgs := s.teamFilter("team_id", 3)
+	rows, err := s.DB.Query(
+		`SELECT `+workItemColumns+`
+		 FROM work_items WHERE reported_at >= $1 AND reported_at < $2 AND deleted_at IS NULL`+filter+`
+		 ORDER BY category, author, reported_at, id`,
+		append([]any{from, to}, filterArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanWorkItems(rows)
+}
+
+func (s *Store) GetWorkItemByID(id int64) (WorkItem, error) {
+	filter, filterArgs := s.teamFilter("team_id", 2)
+	var item WorkItem
+	err := s.DB.QueryRow(
+		`SELECT `+workItemColumns+` FROM work_items WHERE id = $1 AND deleted_at IS NULL`+filter,
+		append([]any{id}, filterArgs...)...,
+	).Scan(
+		&item.ID, &item.Description, &item.Author, &item.AuthorID, &item.Source,
+		&item.SourceRef, &item.Category, &item.Status, &item.TicketIDs,
+		&item.ReportedAt, &item.CreatedAt, &item.TeamID,
+	)
+	return item, err
+}
+
+func (s *Store) UpdateWorkItemTextAndStatus(id int64, description, status, actorID string) error {
+	return s.updateWorkItem(id, actorID,
+		workItemChange{column: "description", eventType: domain.WorkItemEventEdited, value: description},
+		workItemChange{column: "status", eventType: domain.WorkItemEventStatusChanged, value: status},
+	)
+}
+
+func (s *Store) UpdateWorkItemStatus(id int64, status, actorID string) error {
+	return s.updateWorkItem(id, actorID,
+		workItemChange{column: "status", eventType: domain.WorkItemEventStatusChanged, value: status})
+}
+
+// UpdateWorkItemFromSource brings an item imported from an MR/PR up to date
+// with its current title, status and report time. Title and status changes
+// are recorded as edited and status_changed events.
+func (s *Store) UpdateWorkItemFromSource(id int64, description, status string, reportedAt time.Time, actorID string) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	err = s.applyWorkItemChanges(tx, id, actorID,
+		workItemChange{column: "description", eventType: domain.WorkItemEventEdited, value: description},
+		workItemChange{column: "status", eventType: domain.WorkItemEventStatusChanged, value: status},
+	)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE work_items SET reported_at = $1 WHERE id = $2`, reportedAt, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateWorkItemAuthorID sets the Slack author ID of an item that has none.
+func (s *Store) UpdateWorkItemAuthorID(id int64, authorID string) error {
+	filter, filterArgs := s.teamFilter("team_id", 3)
+	_, err := s.DB.Exec(`UPDATE work_items SET author_id = $1 WHERE id = $2 AND author_id = ''`+filter,
+		append([]any{authorID, id}, filterArgs...)...)
+	return err
+}
+
+func (s *Store) MarkWorkItemDoneFromNudge(id int64, actorID string) error {
+	return s.updateWorkItem(id, actorID,
+		workItemChange{column: "status", eventType: domain.WorkItemEventNudgeDone, value: "done", always: true})
+}
+
+func (s *Store) UpdateWorkItemCategory(id int64, category, actorID string) error {
+	return s.updateWorkItem(id, actorID,
+		workItemChange{column: "category", eventType: domain.WorkItemEventCategoryChanged, value: category})
+}
+
+func (s *Store) UpdateCategories(categorized map[int64]string) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for id, category := range categorized {
+		err := s.applyWorkItemChanges(tx, id, "",
+			workItemChange{column: "category", eventType: domain.WorkItemEventCategoryChanged, value: category})
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *Store) UpdateTicketIDs(ticketMap map[int64]string) error {
+	filter, filterArgs := s.teamFilter("team_id", 3)
+	return s.updateColumnByID(`UPDATE work_items SET ticket_ids = $1 WHERE id = $2`+filter, ticketMap, filterArgs...)
+}
+
+// updateColumnByID runs query with each value and id, followed by extra.
+func (s *Store) updateColumnByID(query string, values map[int64]string, extra ...any) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for id, value := range values {
+		if _, err := stmt.Exec(append([]any{value, id}, extra...)...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *Store) DeleteWorkItemByID(id int64, actorID string) error {
+	return s.setWorkItemDeleted(id, true, actorID)
+}
+
+func (s *Store) GetPendingSlackItemsByAuthorAndDateRange(author string, from, to time.Time) ([]WorkItem, error) {
+	filter, filterArgs := s.teamFilter("team_id", 4)
+	rows, err := s.DB.Query(
+		`SELECT `+workItemColumns+`
+		 FROM work_items
+		 WHERE author = $1 AND source = 'slack' AND reported_at >= $2 AND reported_at < $3
+		   AND lower(trim(status)) <> 'done' AND deleted_at IS NULL`+filter+`
+		 ORDER BY reported_at DESC, id DESC`,
+		append([]any{author, from, to}, filterArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanWorkItems(rows)
+}
+
+func (s *Store) GetSlackItemsByAuthorAndDateRange(author string, from, to time.Time) ([]WorkItem, error) {
+	filter, filterArgs := s.teamFilter("team_id", 4)
+	rows, err := s.DB.Query(
+		`SELECT `+workItemColumns+`
+		 FROM work_items
+		 WHERE author = $1 AND source = 'slack' AND reported_at >= $2 AND reported_at < $3
+		   AND deleted_at IS NULL`+filter+`
+		 ORDER BY reported_at DESC, id DESC`,
+		append([]any{author, from, to}, filterArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanWorkItems(rows)
+}
+
+func (s *Store) GetSlackAuthorsByDateRange(from, to time.Time) (map[string]bool, error) {
+	filter, filterArgs := s.teamFilter("team_id", 3)
+	return s.distinctStrings(
+		`SELECT DISTINCT author FROM work_items
+		 WHERE reported_at >= $1 AND reported_at < $2 AND source = 'slack' AND deleted_at IS NULL`+filter,
+		append([]any{from, to}, filterArgs...)...,
+	)
+}
+
+func (s *Store) GetSlackAuthorIDsByDateRange(from, to time.Time) (map[string]bool, error) {
+	filter, filterArgs := s.teamFilter("team_id", 3)
+	return s.distinctStrings(
+		`SELECT DISTINCT author_id FROM work_items
+		 WHERE reported_at >= $1 AND reported_at < $2 AND source = 'slack' AND author_id <> ''
+		   AND deleted_at IS NULL`+filter,
+		append([]any{from, to}, filterArgs...)...,
+	)
+}
+
+func (s *Store) distinctStrings(query string, args ...any) (map[string]bool, error) {
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string]bool)
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		if v != "" {
+			out[v] = true
+		}
+	}
+	return out, rows.Err()
+}
+
+// --- Classification History ---
+
+const classificationColumns = `ch.id, ch.work_item_id, ch.section_id, ch.section_label, ch.confidence,
+	ch.normalized_status, ch.ticket_ids, ch.duplicate_of, ch.llm_provider, ch.llm_model, ch.classified_at,
+	ch.raw_confidence, ch.alternative_section_ids, ch.cache_key`
+
+func scanClassification(row interface{ Scan(...any) error }) (ClassificationRecord, error) {
+	var r ClassificationRecord
+	err := row.Scan(
+		&r.ID, &r.WorkItemID, &r.SectionID, &r.SectionLabel, &r.Confidence,
+		&r.NormalizedStatus, &r.TicketIDs, &r.DuplicateOf,
+		&r.LLMProvider, &r.LLMModel, &r.ClassifiedAt,
+		&r.RawConfidence, &r.AlternativeSectionIDs, &r.CacheKey,
+	)
+	return r, err
+}
+
+func (s *Store) InsertClassificationHistory(records []ClassificationRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(
+		`INSERT INTO classification_history
+		 (work_item_id, section_id, section_label, confidence, normalized_status, ticket_ids, duplicate_of, llm_provider, llm_model,
+		  raw_confidence, alternative_section_ids, cache_key)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+	)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, r := range records {
+		if _, err := stmt.Exec(
+			r.WorkItemID, r.SectionID, r.SectionLabel, r.Confidence,
+			r.NormalizedStatus, r.TicketIDs, r.DuplicateOf,
+			r.LLMProvider, r.LLMModel,
+			r.RawConfidence, r.AlternativeSectionIDs, r.CacheKey,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *Store) GetLatestClassification(workItemID int64) (ClassificationRecord, error) {
+	filter, filterArgs := s.itemTeamFilter("ch.work_item_id", 2)
+	return scanClassification(s.DB.QueryRow(
+		`SELECT `+classificationColumns+`
+		 FROM classification_history ch
+		 WHERE ch.work_item_id = $1`+filter+`
+		 ORDER BY ch.classified_at DESC, ch.id DESC LIMIT 1`,
+		append([]any{workItemID}, filterArgs...)...,
+	))
+}
+
+// LookupClassifications returns the newest uncorrected decision per cache
+// key; see sqlite.GetCachedClassifications.
+func (s *Store) LookupClassifications(keys []string) (map[string]ClassificationRecord, error) {
+	out := make(map[string]ClassificationRecord, len(keys))
+	if len(keys) == 0 {
+		return out, nil
+	}
+	filter, filterArgs := s.itemTeamFilter("ch.work_item_id", 2)
+	rows, err := s.DB.Query(
+		`SELECT `+classificationColumns+`
+		 FROM classification_history ch
+		 WHERE ch.cache_key = ANY($1)
+		   AND NOT EXISTS (
+		     SELECT 1 FROM classification_corrections cc
+		     WHERE cc.work_item_id = ch.work_item_id AND cc.corrected_at >= ch.classified_at
+		   )`+filter+`
+		 ORDER BY ch.classified_at, ch.id`,
+		append([]any{pq.Array(keys)}, filterArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		r, err := scanClassification(rows)
+		if err != nil {
+			return nil, err
+		}
+		out[r.CacheKey] = r
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) GetConfidenceSamples(since time.Time, limit int) ([]ConfidenceSample, error) {
+	filter, filterArgs := s.itemTeamFilter("ch.work_item_id", 3)
+	rows, err := s.DB.Query(
+		`SELECT ch.raw_confidence,
+		        EXISTS (
+		          SELECT 1 FROM classification_corrections cc
+		          WHERE cc.work_item_id = ch.work_item_id
+		            AND cc.original_section_id = ch.section_id
+		            AND cc.corrected_at >= ch.classified_at
+		        )
+		 FROM classification_history ch
+		 WHERE ch.raw_confidence > 0 AND ch.classified_at >= $1`+filter+`
+		 ORDER BY ch.classified_at DESC, ch.id DESC
+		 LIMIT $2`,
+		append([]any{since, limit}, filterArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []ConfidenceSample
+	for rows.Next() {
+		var cs ConfidenceSample
+		if err := rows.Scan(&cs.RawConfidence, &cs.Corrected); err != nil {
+			return nil, err
+		}
+		out = append(out, cs)
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) GetClassifiedItemsWithSections(since time.Time, limit int) ([]HistoricalItem, error) {
+	filter, filterArgs := s.teamFilter("w.team_id", 3)
+	rows, err := s.DB.Query(
+		`SELECT w.id, w.description, ch.section_id, ch.section_label
+		 FROM classification_history ch
+		 JOIN work_items w ON w.id = ch.work_item_id
+		 WHERE ch.confidence >= 0.70 AND ch.classified_at >= $1 AND w.deleted_at IS NULL`+filter+`
+		 ORDER BY ch.classified_at DESC, ch.id DESC
+		 LIMIT $2`,
+		append([]any{since, limit}, filterArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []HistoricalItem
+	for rows.Next() {
+		var h HistoricalItem
+		if err := rows.Scan(&h.WorkItemID, &h.Description, &h.SectionID, &h.SectionLabel); err != nil {
+			return nil, err
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) GetLabeledWorkItems(since time.Time, limit int) ([]LabeledWorkItem, error) {
+	filter, filterArgs := s.teamFilter("w.team_id", 3)
+	rows, err := s.DB.Query(
+		`SELECT w.id, w.description, w.author, w.author_id, w.source, w.source_ref, w.category,
+		        w.status, w.ticket_ids, w.reported_at, w.created_at, w.team_id,
+		        ch.section_id, ch.section_label,
+		        COALESCE(cc.corrected_section_id, ''), COALESCE(cc.corrected_label, '')
+		 FROM work_items w
+		 JOIN classification_history ch
+		   ON ch.id = (SELECT MAX(id) FROM classification_history WHERE work_item_id = w.id)
+		 LEFT JOIN classification_corrections cc
+		   ON cc.id = (SELECT MAX(id) FROM classification_corrections WHERE work_item_id = w.id)
+		 WHERE w.reported_at >= $1 AND w.deleted_at IS NULL`+filter+`
+		 ORDER BY w.reported_at, w.id
+		 LIMIT $2`,
+		append([]any{since, limit}, filterArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []LabeledWorkItem
+	for rows.Next() {
+		var l LabeledWorkItem
+		var correctedID, correctedLabel string
+		if err := rows.Scan(
+			&l.Item.ID, &l.Item.Description, &l.Item.Author, &l.Item.AuthorID, &l.Item.Source,
+			&l.Item.SourceRef, &l.Item.Category, &l.Item.Status, &l.Item.TicketIDs,
+			&l.Item.ReportedAt, &l.Item.CreatedAt, &l.Item.TeamID,
+			&l.PredictedSection, &l.PredictedLabel,
+			&correctedID, &correctedLabel,
+		); err != nil {
+			return nil, err
+		}
+		l.FinalSection, l.FinalLabel = l.PredictedSection, l.PredictedLabel
+		if correctedID != "" {
+			l.FinalSection, l.FinalLabel = correctedID, correctedLabel
+			l.Corrected = true
+		}
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
+
+// --- Classification Corrections ---
+
+func (s *Store) InsertClassificationCorrection(c ClassificationCorrection) error {
+	_, err := s.DB.Exec(
+		`INSERT INTO classification_corrections
+		 (work_item_id, original_section_id, original_label, corrected_section_id, corrected_label, description, corrected_by)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		c.WorkItemID, c.OriginalSectionID, c.OriginalLabel,
+		c.CorrectedSectionID, c.CorrectedLabel, c.Description, c.CorrectedBy,
+	)
+	return err
+}
+
+func (s *Store) GetRecentCorrections(since time.Time, limit int) ([]ClassificationCorrection, error) {
+	filter, filterArgs := s.itemTeamFilter("work_item_id", 3)
+	rows, err := s.DB.Query(
+		`SELECT id, work_item_id, original_section_id, original_label,
+		        corrected_section_id, corrected_label, description, corrected_by, corrected_at
+		 FROM classification_corrections
+		 WHERE corrected_at >= $1`+filter+`
+		 ORDER BY corrected_at DESC
+		 LIMIT $2`,
+		append([]any{since, limit}, filterArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []ClassificationCorrection
+	for rows.Next() {
+		var c ClassificationCorrection
+		if err := rows.Scan(
+			&c.ID, &c.WorkItemID, &c.OriginalSectionID, &c.OriginalLabel,
+			&c.CorrectedSectionID, &c.CorrectedLabel, &c.Description,
+			&c.CorrectedBy, &c.CorrectedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) CountCorrectionsByPhrase(description, correctedSectionID string) (int, error) {
+	filter, filterArgs := s.itemTeamFilter("work_item_id", 3)
+	var count int
+	err := s.DB.QueryRow(
+		`SELECT COUNT(*) FROM classification_corrections
+		 WHERE LOWER(TRIM(description)) = LOWER(TRIM($1))
+		   AND corrected_section_id = $2`+filter,
+		append([]any{description, correctedSectionID}, filterArgs...)...,
+	).Scan(&count)
+	return count, err
+}
+
+// --- Classification Stats ---
+
+func (s *Store) GetClassificationStats(since time.Time) (ClassificationStats, error) {
+	var st ClassificationStats
+	filter, filterArgs := s.itemTeamFilter("work_item_id", 2)
+	err := s.DB.QueryRow(
+		`SELECT COUNT(*), COALESCE(AVG(confidence), 0),
+		        COALESCE(SUM(CASE WHEN confidence < 0.50 THEN 1 ELSE 0 END), 0),
+		        COALESCE(SUM(CASE WHEN confidence >= 0.50 AND confidence < 0.70 THEN 1 ELSE 0 END), 0),
+		        COALESCE(SUM(CASE WHEN confidence >= 0.70 AND confidence < 0.90 THEN 1 ELSE 0 END), 0),
+		        COALESCE(SUM(CASE WHEN confidence >= 0.90 THEN 1 ELSE 0 END), 0)
+		 FROM classification_history WHERE classified_at >= $1`+filter,
+		append([]any{since}, filterArgs...)...,
+	).Scan(&st.TotalClassifications, &st.AvgConfidence,
+		&st.BucketBelow50, &st.Bucket50to70, &st.Bucket70to90, &st.Bucket90Plus)
+	if err != nil {
+		return st, err
+	}
+
+	err = s.DB.QueryRow(
+		`SELECT COUNT(*) FROM classification_corrections WHERE corrected_at >= $1`+filter,
+		append([]any{since}, filterArgs...)...,
+	).Scan(&st.TotalCorrections)
+	return st, err
+}
+
+func (s *Store) GetCorrectionsBySection(since time.Time) ([]SectionCorrectionStat, error) {
+	filter, filterArgs := s.itemTeamFilter("work_item_id", 2)
+	rows, err := s.DB.Query(
+		`SELECT original_section_id, COALESCE(MAX(original_label), ''), COUNT(*) AS cnt
+		 FROM classification_corrections
+		 WHERE corrected_at >= $1`+filter+`
+		 GROUP BY original_section_id
+		 ORDER BY cnt DESC
+		 LIMIT 10`,
+		append([]any{since}, filterArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []SectionCorrectionStat
+	for rows.Next() {
+		var cs SectionCorrectionStat
+		if err := rows.Scan(&cs.OriginalSectionID, &cs.OriginalLabel, &cs.CorrectionCount); err != nil {
+			return nil, err
+		}
+		out = append(out, cs)
+	}
+	return out, rows.Err()
+}
+
+// GetWeeklyClassificationTrend groups by the Monday of each week, matching
+// the SQLite backend's 'weekday 0', '-6 days' arithmetic.
+func (s *Store) GetWeeklyClassificationTrend(since time.Time) ([]WeeklyTrend, error) {
+	filter, filterArgs := s.itemTeamFilter("work_item_id", 2)
+	rows, err := s.DB.Query(
+		`SELECT to_char(date_trunc('week', classified_at), 'YYYY-MM-DD') AS week_start,
+		        COUNT(*), COALESCE(AVG(confidence), 0)
+		 FROM classification_history
+		 WHERE classified_at >= $1`+filter+`
+		 GROUP BY week_start
+		 ORDER BY week_start DESC`,
+		append([]any{since}, filterArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var trends []WeeklyTrend
+	for rows.Next() {
+		var t WeeklyTrend
+		if err := rows.Scan(&t.WeekStart, &t.Classifications, &t.AvgConfidence); err != nil {
+			return nil, err
+		}
+		trends = append(trends, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	corrRows, err := s.DB.Query(
+		`SELECT to_char(date_trunc('week', corrected_at), 'YYYY-MM-DD') AS week_start, COUNT(*)
+		 FROM classification_corrections
+		 WHERE corrected_at >= $1`+filter+`
+		 GROUP BY week_start`,
+		append([]any{since}, filterArgs...)...,
+	)
+	if err != nil {
+		return trends, nil // non-fatal
+	}
+	defer corrRows.Close()
+
+	corrMap := make(map[string]int)
+	for corrRows.Next() {
+		var ws string
+		var cnt int
+		if err := corrRows.Scan(&ws, &cnt); err != nil {
+			continue
+		}
+		corrMap[ws] = cnt
+	}
+	for i := range trends {
+		trends[i].Corrections = corrMap[trends[i].WeekStart]
+	}
+	return trends, nil
+}
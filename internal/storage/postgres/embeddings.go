@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reportbot/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+type Embedding = domain.Embedding
+
+func (s *Store) LoadEmbeddings(model string, workItemIDs []int64) (map[int64]Embedding, error) {
+	out := make(map[int64]Embedding, len(workItemIDs))
+	if len(workItemIDs) == 0 {
+		return out, nil
+	}
+	rows, err := s.DB.Query(
+		`SELECT work_item_id, text_hash, dims, vector
+		 FROM work_item_embeddings
+		 WHERE model = $1 AND work_item_id = ANY($2)`,
+		model, pq.Array(workItemIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var e Embedding
+		var dims int
+		var blob []byte
+		if err := rows.Scan(&e.WorkItemID, &e.TextHash, &dims, &blob); err != nil {
+			return nil, err
+		}
+		if len(blob) != 4*dims {
+			return nil, fmt.Errorf("embedding for work item %d: vector is %d bytes, want %d", e.WorkItemID, len(blob), 4*dims)
+		}
+		e.Model = model
+		e.Vector = make([]float32, dims)
+		for i := range e.Vector {
+			e.Vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(blob[4*i:]))
+		}
+		out[e.WorkItemID] = e
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) SaveEmbeddings(embeddings []Embedding) error {
+	if len(embeddings) == 0 {
+		return nil
+	}
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.Prepare(
+		`INSERT INTO work_item_embeddings (work_item_id, model, text_hash, dims, vector)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (work_item_id, model) DO UPDATE
+		 SET text_hash = EXCLUDED.text_hash, dims = EXCLUDED.dims, vector = EXCLUDED.vector, created_at = now()`,
+	)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, e := range embeddings {
+		buf := make([]byte, 4*len(e.Vector))
+		for i, v := range e.Vector {
+			binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+		}
+		if _, err := stmt.Exec(e.WorkItemID, e.Model, e.TextHash, len(e.Vector), buf); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
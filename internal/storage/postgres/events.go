@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"reportbot/internal/domain"
+	"time"
+)
+
+type WorkItemEvent = domain.WorkItemEvent
+
+// workItemChange mirrors the SQLite backend: one column update recorded as
+// an event of eventType, skipped when unchanged unless always is set.
+type workItemChange struct {
+	column    string // description, status or category
+	eventType string
+	value     string
+	always    bool
+}
+
+func (s *Store) updateWorkItem(id int64, actorID string, changes ...workItemChange) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := s.applyWorkItemChanges(tx, id, actorID, changes...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// applyWorkItemChanges is sqlite's applyWorkItemChanges; items of another
+// team are reported as sql.ErrNoRows.
+func (s *Store) applyWorkItemChanges(tx *sql.Tx, id int64, actorID string, changes ...workItemChange) error {
+	filter, filterArgs := s.teamFilter("team_id", 2)
+	var description, status, category string
+	err := tx.QueryRow(
+		`SELECT description, status, category FROM work_items WHERE id = $1 AND deleted_at IS NULL`+filter+` FOR UPDATE`,
+		append([]any{id}, filterArgs...)...,
+	).Scan(&description, &status, &category)
+	if err != nil {
+		return err
+	}
+	current := map[string]string{"description": description, "status": status, "category": category}
+	for _, c := range changes {
+		old, ok := current[c.column]
+		if !ok {
+			return fmt.Errorf("unsupported work item column %q", c.column)
+		}
+		if old == c.value && !c.always {
+			continue
+		}
+		if old != c.value {
+			if _, err := tx.Exec(`UPDATE work_items SET `+c.column+` = $1 WHERE id = $2`, c.value, id); err != nil {
+				return err
+			}
+			current[c.column] = c.value
+		}
+		if err := insertWorkItemEvent(tx, id, c.eventType, old, c.value, actorID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertWorkItemEvent(tx *sql.Tx, workItemID int64, eventType, oldValue, newValue, actorID string) error {
+	_, err := tx.Exec(
+		`INSERT INTO work_item_events (work_item_id, event_type, old_value, new_value, actor_id, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		workItemID, eventType, oldValue, newValue, actorID, time.Now().UTC(),
+	)
+	return err
+}
+
+func (s *Store) setWorkItemDeleted(id int64, deleted bool, actorID string) error {
+	filter, filterArgs := s.teamFilter("team_id", 2)
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var description string
+	var deletedAt sql.NullTime
+	err = tx.QueryRow(`SELECT description, deleted_at FROM work_items WHERE id = $1`+filter+` FOR UPDATE`, append([]any{id}, filterArgs...)...).Scan(&description, &deletedAt)
+	if err == sql.ErrNoRows && deleted {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if deletedAt.Valid == deleted {
+		if deleted {
+			return nil
+		}
+		return fmt.Errorf("work item %d is not deleted", id)
+	}
+
+	if deleted {
+		_, err = tx.Exec(`UPDATE work_items SET deleted_at = $1 WHERE id = $2`, time.Now().UTC(), id)
+		if err == nil {
+			err = insertWorkItemEvent(tx, id, domain.WorkItemEventDeleted, description, "", actorID)
+		}
+	} else {
+		_, err = tx.Exec(`UPDATE work_items SET deleted_at = NULL WHERE id = $1`, id)
+		if err == nil {
+			err = insertWorkItemEvent(tx, id, domain.WorkItemEventRestored, "", description, actorID)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) RestoreWorkItem(id int64, actorID string) error {
+	return s.setWorkItemDeleted(id, false, actorID)
+}
+
+func (s *Store) GetWorkItemEvents(workItemID int64) ([]WorkItemEvent, error) {
+	filter, filterArgs := s.itemTeamFilter("work_item_id", 2)
+	rows, err := s.DB.Query(
+		`SELECT id, work_item_id, event_type, old_value, new_value, actor_id, created_at
+		 FROM work_item_events
+		 WHERE work_item_id = $1`+filter+`
+		 ORDER BY created_at, id`,
+		append([]any{workItemID}, filterArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []WorkItemEvent
+	for rows.Next() {
+		var e WorkItemEvent
+		if err := rows.Scan(&e.ID, &e.WorkItemID, &e.EventType, &e.OldValue, &e.NewValue, &e.ActorID, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) GetDeletedItemsByDateRange(from, to time.Time) ([]WorkItem, error) {
+	filter, filterArgs := s.teamFilter("team_id", 3)
+	rows, err := s.DB.Query(
+		`SELECT `+workItemColumns+`
+		 FROM work_items
+		 WHERE reported_at >= $1 AND reported_at < $2 AND deleted_at IS NOT NULL`+filter+`
+		 ORDER BY reported_at DESC, id DESC`,
+		append([]any{from, to}, filterArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanWorkItems(rows)
+}
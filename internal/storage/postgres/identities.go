@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"reportbot/internal/domain"
+	"time"
+)
+
+type Identity = domain.Identity
+
+// identityColumn names the account and confirmation columns of one kind.
+type identityColumn struct {
+	account, confirmed string
+}
+
+// identityColumns maps account kinds to their identities columns.
+var identityColumns = map[string]identityColumn{
+	domain.IdentityGitLab: {"gitlab_username", "gitlab_confirmed"},
+	domain.IdentityGitHub: {"github_login", "github_confirmed"},
+	domain.IdentityEmail:  {"email", "email_confirmed"},
+}
+
+const identitySelect = `SELECT slack_id, gitlab_username, github_login, email,
+	gitlab_confirmed, github_confirmed, email_confirmed, updated_at
+	FROM identities`
+
+func scanIdentity(row interface{ Scan(...any) error }) (Identity, error) {
+	var id Identity
+	err := row.Scan(&id.SlackID, &id.GitLabUsername, &id.GitHubLogin, &id.Email,
+		&id.GitLabConfirmed, &id.GitHubConfirmed, &id.EmailConfirmed, &id.UpdatedAt)
+	return id, err
+}
+
+func (s *Store) GetIdentity(slackID string) (Identity, error) {
+	return scanIdentity(s.DB.QueryRow(identitySelect+` WHERE slack_id = $1`, slackID))
+}
+
+func (s *Store) ListIdentities() ([]Identity, error) {
+	rows, err := s.DB.Query(identitySelect + ` ORDER BY slack_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Identity
+	for rows.Next() {
+		id, err := scanIdentity(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) LinkIdentity(slackID, kind, account string) error {
+	return s.linkIdentity(slackID, kind, account, false)
+}
+
+func (s *Store) ReassignIdentity(slackID, kind, account string) error {
+	return s.linkIdentity(slackID, kind, account, true)
+}
+
+func (s *Store) linkIdentity(slackID, kind, account string, manager bool) error {
+	col, ok := identityColumns[kind]
+	if !ok {
+		return fmt.Errorf("unknown account kind %q", kind)
+	}
+	account = domain.NormalizeAccount(account)
+	confirmed := manager && account != ""
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if account != "" && manager {
+		if _, err := tx.Exec(
+			`UPDATE identities SET `+col.account+` = '', `+col.confirmed+` = FALSE, updated_at = $1
+			 WHERE `+col.account+` = $2 AND slack_id <> $3`,
+			time.Now(), account, slackID,
+		); err != nil {
+			return err
+		}
+	} else if account != "" {
+		var owner string
+		err := tx.QueryRow(`SELECT slack_id FROM identities WHERE `+col.account+` = $1 AND slack_id <> $2`, account, slackID).Scan(&owner)
+		if err == nil {
+			return domain.ErrIdentityTaken
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO identities (slack_id, `+col.account+`, `+col.confirmed+`, updated_at) VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (slack_id) DO UPDATE SET
+		   `+col.confirmed+` = EXCLUDED.`+col.confirmed+` OR (identities.`+col.account+` = EXCLUDED.`+col.account+` AND identities.`+col.confirmed+`),
+		   `+col.account+` = EXCLUDED.`+col.account+`,
+		   updated_at = EXCLUDED.updated_at`,
+		slackID, account, confirmed, time.Now(),
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,157 @@
+package postgres
+
+import "reportbot/internal/storage/migrate"
+
+var dialect = migrate.Dialect{
+	CreateTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL
+	)`,
+	Insert: `INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)`,
+}
+
+// migrations is the PostgreSQL schema history. Versions match the SQLite
+// backend so both report the same schema version; append, never renumber.
+var migrations = []migrate.Migration{
+	{Version: 1, Name: "baseline", Up: migrate.Exec(baselineSchema)},
+	{Version: 2, Name: "unique_source_ref", Up: migrate.Exec(
+		`DELETE FROM work_items
+		 WHERE source_ref <> ''
+		   AND id NOT IN (
+		     SELECT MIN(id)
+		     FROM work_items
+		     WHERE source_ref <> ''
+		     GROUP BY source, source_ref
+		   )`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_work_items_unique_source_ref
+		 ON work_items(source, source_ref)
+		 WHERE source_ref <> ''`,
+	)},
+	{Version: 3, Name: "work_item_events", Up: migrate.Exec(
+		`CREATE TABLE work_item_events (
+			id           BIGSERIAL PRIMARY KEY,
+			work_item_id BIGINT NOT NULL,
+			event_type   TEXT NOT NULL,
+			old_value    TEXT NOT NULL DEFAULT '',
+			new_value    TEXT NOT NULL DEFAULT '',
+			actor_id     TEXT NOT NULL DEFAULT '',
+			created_at   TIMESTAMPTZ NOT NULL
+		)`,
+		`CREATE INDEX idx_work_item_events_item ON work_item_events(work_item_id, created_at)`,
+		`ALTER TABLE work_items ADD COLUMN deleted_at TIMESTAMPTZ`,
+	)},
+	{Version: 4, Name: "work_item_team", Up: migrate.Exec(
+		`ALTER TABLE work_items ADD COLUMN team_id TEXT NOT NULL DEFAULT ''`,
+		`CREATE INDEX idx_work_items_team_reported_at ON work_items(team_id, reported_at)`,
+	)},
+	{Version: 5, Name: "identities", Up: migrate.Exec(
+		`CREATE TABLE identities (
+			slack_id         TEXT PRIMARY KEY,
+			gitlab_username  TEXT NOT NULL DEFAULT '',
+			github_login     TEXT NOT NULL DEFAULT '',
+			email            TEXT NOT NULL DEFAULT '',
+			gitlab_confirmed BOOLEAN NOT NULL DEFAULT FALSE,
+			github_confirmed BOOLEAN NOT NULL DEFAULT FALSE,
+			email_confirmed  BOOLEAN NOT NULL DEFAULT FALSE,
+			updated_at       TIMESTAMPTZ NOT NULL
+		)`,
+		// An account belongs to one Slack user.
+		`CREATE UNIQUE INDEX idx_identities_gitlab ON identities(gitlab_username) WHERE gitlab_username <> ''`,
+		`CREATE UNIQUE INDEX idx_identities_github ON identities(github_login) WHERE github_login <> ''`,
+		`CREATE UNIQUE INDEX idx_identities_email ON identities(email) WHERE email <> ''`,
+	)},
+	{Version: 6, Name: "llm_usage_team", Up: migrate.Exec(
+		`ALTER TABLE llm_usage ADD COLUMN team_id TEXT NOT NULL DEFAULT ''`,
+		`CREATE INDEX idx_llm_usage_team_created ON llm_usage(team_id, created_at)`,
+	)},
+	{Version: 7, Name: "unique_source_ref_per_team", Up: migrate.Exec(
+		// Teams track a shared MR/PR independently, so an external reference
+		// is unique within a team rather than across the database.
+		`DROP INDEX IF EXISTS idx_work_items_unique_source_ref`,
+		`CREATE UNIQUE INDEX idx_work_items_unique_team_source_ref
+		 ON work_items(team_id, source, source_ref)
+		 WHERE source_ref <> ''`,
+	)},
+}
+
+func (s *Store) Migrate() ([]migrate.Status, error) { return migrate.Up(s.DB, dialect, migrations) }
+
+func (s *Store) ListMigrations() ([]migrate.Status, error) {
+	return migrate.List(s.DB, dialect, migrations)
+}
+
+const baselineSchema = `
+CREATE TABLE IF NOT EXISTS work_items (
+	id          BIGSERIAL PRIMARY KEY,
+	description TEXT NOT NULL,
+	author      TEXT NOT NULL,
+	author_id   TEXT NOT NULL DEFAULT '',
+	source      TEXT NOT NULL DEFAULT 'slack',
+	source_ref  TEXT NOT NULL DEFAULT '',
+	category    TEXT NOT NULL DEFAULT '',
+	status      TEXT NOT NULL DEFAULT 'done',
+	ticket_ids  TEXT NOT NULL DEFAULT '',
+	reported_at TIMESTAMPTZ NOT NULL,
+	created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_work_items_reported_at ON work_items(reported_at);
+CREATE INDEX IF NOT EXISTS idx_work_items_author ON work_items(author);
+
+CREATE TABLE IF NOT EXISTS classification_history (
+	id                      BIGSERIAL PRIMARY KEY,
+	work_item_id            BIGINT NOT NULL,
+	section_id              TEXT NOT NULL,
+	section_label           TEXT NOT NULL DEFAULT '',
+	confidence              DOUBLE PRECISION NOT NULL,
+	normalized_status       TEXT NOT NULL DEFAULT '',
+	ticket_ids              TEXT NOT NULL DEFAULT '',
+	duplicate_of            TEXT NOT NULL DEFAULT '',
+	llm_provider            TEXT NOT NULL DEFAULT '',
+	llm_model               TEXT NOT NULL DEFAULT '',
+	raw_confidence          DOUBLE PRECISION NOT NULL DEFAULT 0,
+	alternative_section_ids TEXT NOT NULL DEFAULT '',
+	cache_key               TEXT NOT NULL DEFAULT '',
+	classified_at           TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_ch_work_item ON classification_history(work_item_id);
+CREATE INDEX IF NOT EXISTS idx_ch_date ON classification_history(classified_at);
+CREATE INDEX IF NOT EXISTS idx_ch_cache_key ON classification_history(cache_key);
+
+CREATE TABLE IF NOT EXISTS classification_corrections (
+	id                   BIGSERIAL PRIMARY KEY,
+	work_item_id         BIGINT NOT NULL,
+	original_section_id  TEXT NOT NULL,
+	original_label       TEXT NOT NULL DEFAULT '',
+	corrected_section_id TEXT NOT NULL,
+	corrected_label      TEXT NOT NULL DEFAULT '',
+	description          TEXT NOT NULL DEFAULT '',
+	corrected_by         TEXT NOT NULL DEFAULT '',
+	corrected_at         TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_cc_date ON classification_corrections(corrected_at);
+
+CREATE TABLE IF NOT EXISTS work_item_embeddings (
+	work_item_id BIGINT NOT NULL,
+	model        TEXT NOT NULL,
+	text_hash    TEXT NOT NULL,
+	dims         INTEGER NOT NULL,
+	vector       BYTEA NOT NULL,
+	created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY (work_item_id, model)
+);
+
+CREATE TABLE IF NOT EXISTS llm_usage (
+	id                          BIGSERIAL PRIMARY KEY,
+	provider                    TEXT NOT NULL,
+	model                       TEXT NOT NULL,
+	purpose                     TEXT NOT NULL,
+	input_tokens                BIGINT NOT NULL DEFAULT 0,
+	output_tokens               BIGINT NOT NULL DEFAULT 0,
+	cache_creation_input_tokens BIGINT NOT NULL DEFAULT 0,
+	cache_read_input_tokens     BIGINT NOT NULL DEFAULT 0,
+	cost_usd                    DOUBLE PRECISION NOT NULL DEFAULT 0,
+	created_at                  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_llm_usage_created ON llm_usage(created_at);
+`
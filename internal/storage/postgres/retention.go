@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"reportbot/internal/domain"
+	"reportbot/internal/storage/purge"
+)
+
+type RetentionPolicy = domain.RetentionPolicy
+
+// PurgeExpired deletes rows outside the retention policy, archiving them
+// first when archive is non-nil, and returns the deleted row count per
+// table. A dry run reports the counts without changing anything.
+func (s *Store) PurgeExpired(p RetentionPolicy, archive domain.RetentionArchive, dryRun bool) (map[string]int, error) {
+	return purge.Run(s.DB, retentionRules(p), archive, dryRun)
+}
+
+func retentionRules(p RetentionPolicy) []purge.Rule {
+	var rules []purge.Rule
+	if !p.WorkItemsBefore.IsZero() {
+		// Dependent rows go first so the subquery still sees their items.
+		expired := `work_item_id IN (SELECT id FROM work_items WHERE reported_at < $1)`
+		for _, table := range []string{"work_item_events", "work_item_embeddings", "classification_history", "classification_corrections"} {
+			rules = append(rules, purge.Rule{Table: table, Where: expired, Args: []any{p.WorkItemsBefore}})
+		}
+		rules = append(rules, purge.Rule{Table: "work_items", Where: `reported_at < $1`, Args: []any{p.WorkItemsBefore}})
+	}
+	if !p.ClassificationsBefore.IsZero() {
+		rules = append(rules, purge.Rule{Table: "classification_history", Where: `classified_at < $1`, Args: []any{p.ClassificationsBefore}})
+	}
+	if !p.CorrectionsBefore.IsZero() {
+		// Decisions a purged correction overrode go with it; the decision
+		// cache only skips history that has a later correction, and would
+		// otherwise reuse them.
+		rules = append(rules, purge.Rule{Table: "classification_history", Where: `EXISTS (
+			SELECT 1 FROM classification_corrections cc
+			WHERE cc.work_item_id = classification_history.work_item_id
+			  AND cc.corrected_at >= classification_history.classified_at
+			  AND cc.corrected_at < $1)`, Args: []any{p.CorrectionsBefore}})
+		rules = append(rules, purge.Rule{Table: "classification_corrections", Where: `corrected_at < $1`, Args: []any{p.CorrectionsBefore}})
+	}
+	if p.SupersededCorrections {
+		rules = append(rules, purge.Rule{Table: "classification_corrections", Where: `id < (
+			SELECT MAX(n.id) FROM classification_corrections n
+			WHERE n.work_item_id = classification_corrections.work_item_id)`})
+	}
+	return rules
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"fmt"
+	"reportbot/internal/domain"
+	"strings"
+)
+
+type SearchQuery = domain.SearchQuery
+
+// SearchWorkItems returns live work items of s's team matching q, newest first. Each
+// word must occur (case-insensitively) in the description, author, ticket
+// IDs or source_ref.
+func (s *Store) SearchWorkItems(q SearchQuery) ([]WorkItem, error) {
+	var where []string
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	for _, term := range strings.Fields(q.Text) {
+		where = append(where, `(description || ' ' || author || ' ' || ticket_ids || ' ' || source_ref) ILIKE `+arg(likePattern(term))+` ESCAPE '\'`)
+	}
+	if q.Author != "" {
+		where = append(where, `author ILIKE `+arg(likePattern(q.Author))+` ESCAPE '\'`)
+	}
+	if q.AuthorID != "" {
+		where = append(where, `author_id = `+arg(q.AuthorID))
+	}
+	if q.Status != "" {
+		where = append(where, `lower(trim(status)) = `+arg(strings.ToLower(strings.TrimSpace(q.Status))))
+	}
+	if !q.Since.IsZero() {
+		where = append(where, `reported_at >= `+arg(q.Since))
+	}
+	where = append(where, `deleted_at IS NULL`)
+	if s.team.Scoped {
+		where = append(where, `team_id = `+arg(s.team.TeamID))
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	rows, err := s.DB.Query(
+		`SELECT `+workItemColumns+`
+		 FROM work_items
+		 WHERE `+strings.Join(where, " AND ")+`
+		 ORDER BY reported_at DESC, id DESC
+		 LIMIT `+arg(limit)+` OFFSET `+arg(q.Offset),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanWorkItems(rows)
+}
+
+func likePattern(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+	return "%" + s + "%"
+}
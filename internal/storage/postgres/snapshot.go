@@ -0,0 +1,19 @@
+package postgres
+
+import (
+	"reportbot/internal/domain"
+	"reportbot/internal/storage/snapshot"
+)
+
+// ExportState reads live items with their classification history and
+// corrections for `reportbot export`.
+func (s *Store) ExportState() (domain.StateSnapshot, error) {
+	return snapshot.Export(s.DB)
+}
+
+// ImportState restores a snapshot idempotently; see snapshot.Import. A
+// team-scoped store assigns its team to imported items that have none.
+func (s *Store) ImportState(snap domain.StateSnapshot) (domain.ImportResult, error) {
+	snap.WorkItems = s.team.Assign(snap.WorkItems)
+	return snapshot.Import(s.DB, snapshot.Postgres, snap)
+}
@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"fmt"
+	"reportbot/internal/domain"
+)
+
+// ForTeam returns a Store that shares s's connection but reads only teamID's
+// work items and assigns new items to it.
+func (s *Store) ForTeam(teamID string) *Store {
+	return &Store{DB: s.DB, team: domain.ScopeTeam(teamID)}
+}
+
+func (s *Store) AdoptUnassignedWorkItems(teamID string) (int, error) {
+	res, err := s.DB.Exec(`UPDATE work_items SET team_id = $1 WHERE team_id = ''`, teamID)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// teamFilter returns an " AND column = $n" condition and its argument for a
+// team-scoped read, or nothing when s reads all teams. n is the placeholder
+// number after the query's own arguments.
+func (s *Store) teamFilter(column string, n int) (string, []any) {
+	if !s.team.Scoped {
+		return "", nil
+	}
+	return fmt.Sprintf(" AND %s = $%d", column, n), []any{s.team.TeamID}
+}
+
+// itemTeamFilter is teamFilter for tables that reference work items by id.
+func (s *Store) itemTeamFilter(column string, n int) (string, []any) {
+	if !s.team.Scoped {
+		return "", nil
+	}
+	return fmt.Sprintf(" AND %s IN (SELECT id FROM work_items WHERE team_id = $%d)", column, n), []any{s.team.TeamID}
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"reportbot/internal/domain"
+	"time"
+)
+
+type LLMUsageRecord = domain.LLMUsageRecord
+type LLMUsageSummary = domain.LLMUsageSummary
+
+// RecordLLMUsage records rec against the store's team, if it has one.
+func (s *Store) RecordLLMUsage(rec LLMUsageRecord) error {
+	createdAt := rec.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	createdAt = createdAt.UTC()
+	_, err := s.DB.Exec(
+		`INSERT INTO llm_usage (provider, model, purpose, input_tokens, output_tokens,
+		  cache_creation_input_tokens, cache_read_input_tokens, cost_usd, created_at, team_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		rec.Provider, rec.Model, rec.Purpose, rec.InputTokens, rec.OutputTokens,
+		rec.CacheCreationInputTokens, rec.CacheReadInputTokens, rec.CostUSD, createdAt, s.team.TeamID,
+	)
+	return err
+}
+
+func (s *Store) LLMSpendSince(since time.Time) (float64, error) {
+	filter, filterArgs := s.teamFilter("team_id", 2)
+	var spend float64
+	err := s.DB.QueryRow(`SELECT COALESCE(SUM(cost_usd), 0) FROM llm_usage WHERE created_at >= $1`+filter,
+		append([]any{since.UTC()}, filterArgs...)...).Scan(&spend)
+	return spend, err
+}
+
+func (s *Store) GetLLMUsageSummary(since time.Time) ([]LLMUsageSummary, error) {
+	filter, filterArgs := s.teamFilter("team_id", 2)
+	rows, err := s.DB.Query(
+		`SELECT provider, model, purpose, COUNT(*),
+		        COALESCE(SUM(input_tokens + cache_creation_input_tokens + cache_read_input_tokens), 0),
+		        COALESCE(SUM(output_tokens), 0), COALESCE(SUM(cost_usd), 0)
+		 FROM llm_usage
+		 WHERE created_at >= $1`+filter+`
+		 GROUP BY provider, model, purpose
+		 ORDER BY SUM(cost_usd) DESC, COUNT(*) DESC`,
+		append([]any{since.UTC()}, filterArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []LLMUsageSummary
+	for rows.Next() {
+		var u LLMUsageSummary
+		if err := rows.Scan(&u.Provider, &u.Model, &u.Purpose, &u.Calls, &u.InputTokens, &u.OutputTokens, &u.CostUSD); err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,86 @@
+// Package purge deletes expired rows for the retention job. The SQLite and
+// PostgreSQL backends each build their own rules; Run executes them in one
+// transaction so a dry run can roll back and report exact counts.
+package purge
+
+import (
+	"database/sql"
+	"fmt"
+	"reportbot/internal/domain"
+)
+
+// Rule deletes the rows of Table matching Where, which is written in the
+// backend's placeholder syntax and takes Args.
+type Rule struct {
+	Table string
+	Where string
+	Args  []any
+}
+
+// Run applies rules in order and returns the number of rows removed per
+// table. With an archive every row is written to it before deletion; with
+// dryRun nothing is archived and the transaction is rolled back.
+func Run(db *sql.DB, rules []Rule, archive domain.RetentionArchive, dryRun bool) (map[string]int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	counts := make(map[string]int)
+	for _, r := range rules {
+		if archive != nil && !dryRun {
+			if err := archiveRows(tx, r, archive); err != nil {
+				return nil, fmt.Errorf("archive %s: %w", r.Table, err)
+			}
+		}
+		res, err := tx.Exec(`DELETE FROM `+r.Table+` WHERE `+r.Where, r.Args...)
+		if err != nil {
+			return nil, fmt.Errorf("purge %s: %w", r.Table, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		counts[r.Table] += int(n)
+	}
+	if dryRun {
+		return counts, nil
+	}
+	if archive != nil {
+		if err := archive.Close(); err != nil {
+			return nil, fmt.Errorf("close archive: %w", err)
+		}
+	}
+	return counts, tx.Commit()
+}
+
+func archiveRows(tx *sql.Tx, r Rule, archive domain.RetentionArchive) error {
+	rows, err := tx.Query(`SELECT * FROM `+r.Table+` WHERE `+r.Where, r.Args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		values := make([]any, len(cols))
+		ptrs := make([]any, len(cols))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		row := make(map[string]any, len(cols))
+		for i, col := range cols {
+			row[col] = values[i]
+		}
+		if err := archive.Write(r.Table, row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,273 @@
+// Package snapshot reads and restores the portable part of the database for
+// `reportbot export` and `reportbot import`. Both backends share it; queries
+// are written with ? placeholders and passed through the backend's Bind.
+package snapshot
+
+import (
+	"database/sql"
+	"fmt"
+	"reportbot/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bind rewrites ? placeholders into the backend's syntax.
+type Bind func(query string) string
+
+// SQLite leaves queries unchanged.
+func SQLite(query string) string { return query }
+
+// Postgres numbers placeholders as $1, $2, ...
+func Postgres(query string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Export reads live work items and the classification history and
+// corrections that belong to them, oldest first.
+func Export(db *sql.DB) (domain.StateSnapshot, error) {
+	var snap domain.StateSnapshot
+
+	rows, err := db.Query(
+		`SELECT id, description, author, COALESCE(author_id, ''), source, COALESCE(source_ref, ''),
+		        COALESCE(category, ''), COALESCE(status, ''), COALESCE(ticket_ids, ''), reported_at, team_id
+		 FROM work_items WHERE deleted_at IS NULL ORDER BY id`)
+	if err != nil {
+		return snap, fmt.Errorf("export work items: %w", err)
+	}
+	for rows.Next() {
+		var item domain.WorkItem
+		if err := rows.Scan(&item.ID, &item.Description, &item.Author, &item.AuthorID, &item.Source,
+			&item.SourceRef, &item.Category, &item.Status, &item.TicketIDs, &item.ReportedAt, &item.TeamID); err != nil {
+			rows.Close()
+			return snap, fmt.Errorf("export work items: %w", err)
+		}
+		snap.WorkItems = append(snap.WorkItems, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return snap, fmt.Errorf("export work items: %w", err)
+	}
+
+	rows, err = db.Query(
+		`SELECT work_item_id, section_id, COALESCE(section_label, ''), confidence,
+		        COALESCE(normalized_status, ''), COALESCE(ticket_ids, ''), COALESCE(duplicate_of, ''),
+		        COALESCE(llm_provider, ''), COALESCE(llm_model, ''), classified_at,
+		        COALESCE(raw_confidence, 0), COALESCE(alternative_section_ids, ''), COALESCE(cache_key, '')
+		 FROM classification_history
+		 WHERE work_item_id IN (SELECT id FROM work_items WHERE deleted_at IS NULL)
+		 ORDER BY id`)
+	if err != nil {
+		return snap, fmt.Errorf("export classification history: %w", err)
+	}
+	for rows.Next() {
+		var r domain.ClassificationRecord
+		if err := rows.Scan(&r.WorkItemID, &r.SectionID, &r.SectionLabel, &r.Confidence,
+			&r.NormalizedStatus, &r.TicketIDs, &r.DuplicateOf, &r.LLMProvider, &r.LLMModel, &r.ClassifiedAt,
+			&r.RawConfidence, &r.AlternativeSectionIDs, &r.CacheKey); err != nil {
+			rows.Close()
+			return snap, fmt.Errorf("export classification history: %w", err)
+		}
+		snap.ClassificationHistory = append(snap.ClassificationHistory, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return snap, fmt.Errorf("export classification history: %w", err)
+	}
+
+	rows, err = db.Query(
+		`SELECT work_item_id, original_section_id, COALESCE(original_label, ''), corrected_section_id,
+		        COALESCE(corrected_label, ''), COALESCE(description, ''), COALESCE(corrected_by, ''), corrected_at
+		 FROM classification_corrections
+		 WHERE work_item_id IN (SELECT id FROM work_items WHERE deleted_at IS NULL)
+		 ORDER BY id`)
+	if err != nil {
+		return snap, fmt.Errorf("export corrections: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var c domain.ClassificationCorrection
+		if err := rows.Scan(&c.WorkItemID, &c.OriginalSectionID, &c.OriginalLabel, &c.CorrectedSectionID,
+			&c.CorrectedLabel, &c.Description, &c.CorrectedBy, &c.CorrectedAt); err != nil {
+			return snap, fmt.Errorf("export corrections: %w", err)
+		}
+		snap.Corrections = append(snap.Corrections, c)
+	}
+	if err := rows.Err(); err != nil {
+		return snap, fmt.Errorf("export corrections: %w", err)
+	}
+	return snap, nil
+}
+
+// Import restores snap in one transaction. Work items with a source_ref are
+// matched on (team_id, source, source_ref) like the unique index; Slack items on
+// source, author, description and reported_at. Matched items keep their
+// existing row, and history and corrections already recorded for them are
+// skipped, so importing the same snapshot twice changes nothing.
+func Import(db *sql.DB, bind Bind, snap domain.StateSnapshot) (domain.ImportResult, error) {
+	var res domain.ImportResult
+	tx, err := db.Begin()
+	if err != nil {
+		return res, err
+	}
+	defer tx.Rollback()
+
+	// Snapshot item ID -> database item ID, and whether the item is new.
+	ids := make(map[int64]int64, len(snap.WorkItems))
+	fresh := make(map[int64]bool, len(snap.WorkItems))
+	for _, item := range snap.WorkItems {
+		id, found, err := findWorkItem(tx, bind, item)
+		if err != nil {
+			return res, fmt.Errorf("import work item %d: %w", item.ID, err)
+		}
+		if found {
+			res.WorkItemsExisting++
+		} else {
+			if err := tx.QueryRow(bind(
+				`INSERT INTO work_items (description, author, author_id, source, source_ref, category, status, ticket_ids, reported_at, team_id)
+				 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`),
+				item.Description, item.Author, item.AuthorID, item.Source, item.SourceRef,
+				item.Category, item.Status, item.TicketIDs, item.ReportedAt, item.TeamID,
+			).Scan(&id); err != nil {
+				return res, fmt.Errorf("import work item %d: %w", item.ID, err)
+			}
+			if _, err := tx.Exec(bind(
+				`INSERT INTO work_item_events (work_item_id, event_type, old_value, new_value, actor_id, created_at)
+				 VALUES (?, ?, '', ?, '', ?)`),
+				id, domain.WorkItemEventCreated, item.Description, time.Now().UTC(),
+			); err != nil {
+				return res, fmt.Errorf("import work item %d: %w", item.ID, err)
+			}
+			fresh[item.ID] = true
+			res.WorkItemsInserted++
+		}
+		ids[item.ID] = id
+	}
+
+	for _, r := range snap.ClassificationHistory {
+		id, ok := ids[r.WorkItemID]
+		if !ok {
+			return res, fmt.Errorf("classification history refers to unknown work item %d", r.WorkItemID)
+		}
+		if !fresh[r.WorkItemID] {
+			exists, err := rowExists(tx, bind,
+				`SELECT classified_at FROM classification_history WHERE work_item_id = ? AND section_id = ?`,
+				r.ClassifiedAt, id, r.SectionID)
+			if err != nil {
+				return res, fmt.Errorf("import classification history: %w", err)
+			}
+			if exists {
+				res.HistoryExisting++
+				continue
+			}
+		}
+		if _, err := tx.Exec(bind(
+			`INSERT INTO classification_history
+			 (work_item_id, section_id, section_label, confidence, normalized_status, ticket_ids, duplicate_of,
+			  llm_provider, llm_model, classified_at, raw_confidence, alternative_section_ids, cache_key)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+			id, r.SectionID, r.SectionLabel, r.Confidence, r.NormalizedStatus, r.TicketIDs, r.DuplicateOf,
+			r.LLMProvider, r.LLMModel, r.ClassifiedAt, r.RawConfidence, r.AlternativeSectionIDs, r.CacheKey,
+		); err != nil {
+			return res, fmt.Errorf("import classification history: %w", err)
+		}
+		res.HistoryInserted++
+	}
+
+	for _, c := range snap.Corrections {
+		id, ok := ids[c.WorkItemID]
+		if !ok {
+			return res, fmt.Errorf("correction refers to unknown work item %d", c.WorkItemID)
+		}
+		if !fresh[c.WorkItemID] {
+			exists, err := rowExists(tx, bind,
+				`SELECT corrected_at FROM classification_corrections WHERE work_item_id = ? AND corrected_section_id = ?`,
+				c.CorrectedAt, id, c.CorrectedSectionID)
+			if err != nil {
+				return res, fmt.Errorf("import corrections: %w", err)
+			}
+			if exists {
+				res.CorrectionsExisting++
+				continue
+			}
+		}
+		if _, err := tx.Exec(bind(
+			`INSERT INTO classification_corrections
+			 (work_item_id, original_section_id, original_label, corrected_section_id, corrected_label,
+			  description, corrected_by, corrected_at)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`),
+			id, c.OriginalSectionID, c.OriginalLabel, c.CorrectedSectionID, c.CorrectedLabel,
+			c.Description, c.CorrectedBy, c.CorrectedAt,
+		); err != nil {
+			return res, fmt.Errorf("import corrections: %w", err)
+		}
+		res.CorrectionsInserted++
+	}
+
+	return res, tx.Commit()
+}
+
+// findWorkItem returns the ID of the database row item duplicates. Deleted
+// rows count, so an import does not bring back an item deleted on purpose.
+func findWorkItem(tx *sql.Tx, bind Bind, item domain.WorkItem) (int64, bool, error) {
+	if item.SourceRef != "" {
+		var id int64
+		err := tx.QueryRow(bind(`SELECT id FROM work_items WHERE team_id = ? AND source = ? AND source_ref = ?`),
+			item.TeamID, item.Source, item.SourceRef).Scan(&id)
+		if err == sql.ErrNoRows {
+			return 0, false, nil
+		}
+		return id, err == nil, err
+	}
+
+	rows, err := tx.Query(bind(
+		`SELECT id, reported_at FROM work_items
+		 WHERE source = ? AND COALESCE(source_ref, '') = '' AND author = ? AND description = ?`),
+		item.Source, item.Author, item.Description)
+	if err != nil {
+		return 0, false, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		var reportedAt time.Time
+		if err := rows.Scan(&id, &reportedAt); err != nil {
+			return 0, false, err
+		}
+		if reportedAt.Equal(item.ReportedAt) {
+			return id, true, nil
+		}
+	}
+	return 0, false, rows.Err()
+}
+
+// rowExists runs query, which selects one timestamp column, and reports
+// whether any returned timestamp equals at. Timestamps are compared in Go
+// because SQLite stores them as text in more than one format.
+func rowExists(tx *sql.Tx, bind Bind, query string, at time.Time, args ...any) (bool, error) {
+	rows, err := tx.Query(bind(query), args...)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t time.Time
+		if err := rows.Scan(&t); err != nil {
+			return false, err
+		}
+		if t.Equal(at) {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
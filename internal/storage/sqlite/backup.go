@@ -0,0 +1,49 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Backup writes a consistent copy of the live database to dest with VACUUM
+// INTO. dest must not exist.
+func Backup(db *sql.DB, dest string) error {
+	if _, err := db.Exec(`VACUUM INTO ?`, dest); err != nil {
+		return fmt.Errorf("vacuum into %s: %w", dest, err)
+	}
+	return nil
+}
+
+// CheckIntegrity opens the database file at path read-only and runs
+// PRAGMA integrity_check.
+func CheckIntegrity(path string) error {
+	db, err := sql.Open("sqlite3", "file:"+url.PathEscape(path)+"?mode=ro")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	rows, err := db.Query(`PRAGMA integrity_check`)
+	if err != nil {
+		return fmt.Errorf("integrity check %s: %w", path, err)
+	}
+	defer rows.Close()
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return err
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("integrity check %s: %w", path, err)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("integrity check %s failed: %s", path, strings.Join(problems, "; "))
+	}
+	return nil
+}
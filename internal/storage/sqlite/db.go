@@ -3,6 +3,7 @@ package sqlite
 import (
 	"database/sql"
 	"reportbot/internal/domain"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -13,109 +14,54 @@ type ClassificationRecord = domain.ClassificationRecord
 type ClassificationCorrection = domain.ClassificationCorrection
 type ClassificationStats = domain.ClassificationStats
 type historicalItem = domain.HistoricalItem
+type ConfidenceSample = domain.ConfidenceSample
+type LabeledWorkItem = domain.LabeledWorkItem
+type Embedding = domain.Embedding
+type LLMUsageRecord = domain.LLMUsageRecord
+type LLMUsageSummary = domain.LLMUsageSummary
+type SectionCorrectionStat = domain.SectionCorrectionStat
+type WeeklyTrend = domain.WeeklyTrend
+type WorkItemEvent = domain.WorkItemEvent
+
+const (
+	WorkItemEventCreated         = domain.WorkItemEventCreated
+	WorkItemEventEdited          = domain.WorkItemEventEdited
+	WorkItemEventStatusChanged   = domain.WorkItemEventStatusChanged
+	WorkItemEventCategoryChanged = domain.WorkItemEventCategoryChanged
+	WorkItemEventDeleted         = domain.WorkItemEventDeleted
+	WorkItemEventRestored        = domain.WorkItemEventRestored
+	WorkItemEventNudgeDone       = domain.WorkItemEventNudgeDone
+)
 
+// InitDB opens the SQLite database at path, applies any pending schema
+// migrations and syncs the search index. It fails if the database was
+// migrated by a newer release.
 func InitDB(path string) (*sql.DB, error) {
 	db, err := sql.Open("sqlite3", path)
 	if err != nil {
 		return nil, err
 	}
-
-	schema := `
-	CREATE TABLE IF NOT EXISTS work_items (
-		id          INTEGER PRIMARY KEY AUTOINCREMENT,
-		description TEXT NOT NULL,
-		author      TEXT NOT NULL,
-		source      TEXT NOT NULL DEFAULT 'slack',
-		source_ref  TEXT DEFAULT '',
-		category    TEXT DEFAULT '',
-		status      TEXT DEFAULT 'done',
-		ticket_ids  TEXT DEFAULT '',
-		reported_at DATETIME NOT NULL,
-		created_at  DATETIME DEFAULT CURRENT_TIMESTAMP
-	);
-	CREATE INDEX IF NOT EXISTS idx_work_items_reported_at ON work_items(reported_at);
-	CREATE INDEX IF NOT EXISTS idx_work_items_author ON work_items(author);
-
-	CREATE TABLE IF NOT EXISTS classification_history (
-		id               INTEGER PRIMARY KEY AUTOINCREMENT,
-		work_item_id     INTEGER NOT NULL,
-		section_id       TEXT NOT NULL,
-		section_label    TEXT DEFAULT '',
-		confidence       REAL NOT NULL,
-		normalized_status TEXT DEFAULT '',
-		ticket_ids       TEXT DEFAULT '',
-		duplicate_of     TEXT DEFAULT '',
-		llm_provider     TEXT DEFAULT '',
-		llm_model        TEXT DEFAULT '',
-		classified_at    DATETIME DEFAULT CURRENT_TIMESTAMP
-	);
-	CREATE INDEX IF NOT EXISTS idx_ch_work_item ON classification_history(work_item_id);
-	CREATE INDEX IF NOT EXISTS idx_ch_date ON classification_history(classified_at);
-
-	CREATE TABLE IF NOT EXISTS classification_corrections (
-		id                   INTEGER PRIMARY KEY AUTOINCREMENT,
-		work_item_id         INTEGER NOT NULL,
-		original_section_id  TEXT NOT NULL,
-		original_label       TEXT DEFAULT '',
-		corrected_section_id TEXT NOT NULL,
-		corrected_label      TEXT DEFAULT '',
-		description          TEXT DEFAULT '',
-		corrected_by         TEXT DEFAULT '',
-		corrected_at         DATETIME DEFAULT CURRENT_TIMESTAMP
-	);
-	CREATE INDEX IF NOT EXISTS idx_cc_date ON classification_corrections(corrected_at);
-	`
-	_, err = db.Exec(schema)
-	if err != nil {
+	if _, err := Migrate(db); err != nil {
+		db.Close()
 		return nil, err
 	}
-
-	// Migration: add author_id column if missing.
-	var colCount int
-	_ = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('work_items') WHERE name = 'author_id'`).Scan(&colCount)
-	if colCount == 0 {
-		_, _ = db.Exec(`ALTER TABLE work_items ADD COLUMN author_id TEXT DEFAULT ''`)
-	}
-
-	// Migration: remove duplicate external items before adding uniqueness constraint.
-	_, err = db.Exec(`
-		DELETE FROM work_items
-		WHERE source_ref <> ''
-		  AND id NOT IN (
-		    SELECT MIN(id)
-		    FROM work_items
-		    WHERE source_ref <> ''
-		    GROUP BY source, source_ref
-		  )
-	`)
-	if err != nil {
+	if err := ensureSearchIndex(db); err != nil {
+		db.Close()
 		return nil, err
 	}
-
-	// Enforce uniqueness for externally sourced references (MR/PR URL) at DB level.
-	_, err = db.Exec(`
-		CREATE UNIQUE INDEX IF NOT EXISTS idx_work_items_unique_source_ref
-		ON work_items(source, source_ref)
-		WHERE source_ref <> ''
-	`)
-	if err != nil {
-		return nil, err
-	}
-
 	return db, nil
 }
 
-func InsertWorkItem(db *sql.DB, item WorkItem) error {
-	_, err := db.Exec(
-		`INSERT OR IGNORE INTO work_items (description, author, author_id, source, source_ref, category, status, ticket_ids, reported_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		item.Description, item.Author, item.AuthorID, item.Source, item.SourceRef,
-		item.Category, item.Status, item.TicketIDs, item.ReportedAt,
-	)
+// InsertWorkItem stores item unless its source_ref already exists and
+// records a created event for actorID.
+func InsertWorkItem(db *sql.DB, item WorkItem, actorID string) error {
+	_, err := InsertWorkItems(db, []WorkItem{item}, actorID)
 	return err
 }
 
-func InsertWorkItems(db *sql.DB, items []WorkItem) (int, error) {
+// InsertWorkItems stores items, skipping duplicate source_refs, and records a
+// created event for each inserted row. It returns how many were inserted.
+func InsertWorkItems(db *sql.DB, items []WorkItem, actorID string) (int, error) {
 	tx, err := db.Begin()
 	if err != nil {
 		return 0, err
@@ -123,8 +69,8 @@ func InsertWorkItems(db *sql.DB, items []WorkItem) (int, error) {
 	defer tx.Rollback()
 
 	stmt, err := tx.Prepare(
-		`INSERT OR IGNORE INTO work_items (description, author, author_id, source, source_ref, category, status, ticket_ids, reported_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		`INSERT OR IGNORE INTO work_items (description, author, author_id, source, source_ref, category, status, ticket_ids, reported_at, team_id)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 	)
 	if err != nil {
 		return 0, err
@@ -135,7 +81,7 @@ func InsertWorkItems(db *sql.DB, items []WorkItem) (int, error) {
 	for _, item := range items {
 		res, err := stmt.Exec(
 			item.Description, item.Author, item.AuthorID, item.Source, item.SourceRef,
-			item.Category, item.Status, item.TicketIDs, item.ReportedAt,
+			item.Category, item.Status, item.TicketIDs, item.ReportedAt, item.TeamID,
 		)
 		if err != nil {
 			return inserted, err
@@ -145,6 +91,13 @@ func InsertWorkItems(db *sql.DB, items []WorkItem) (int, error) {
 			return inserted, err
 		}
 		if rows > 0 {
+			id, err := res.LastInsertId()
+			if err != nil {
+				return inserted, err
+			}
+			if err := insertWorkItemEvent(tx, id, WorkItemEventCreated, "", item.Description, actorID); err != nil {
+				return inserted, err
+			}
 			inserted++
 		}
 	}
@@ -152,17 +105,42 @@ func InsertWorkItems(db *sql.DB, items []WorkItem) (int, error) {
 	return inserted, tx.Commit()
 }
 
-func SourceRefExists(db *sql.DB, sourceRef string) (bool, error) {
+// SourceRefExists also counts deleted items, so a deleted MR/PR is not
+// imported again by the next fetch. A scoped team only sees its own items,
+// so each team tracks a shared MR/PR separately.
+func SourceRefExists(db *sql.DB, team TeamScope, sourceRef string) (bool, error) {
+	filter, filterArgs := teamFilter(team, "team_id")
 	var count int
-	err := db.QueryRow("SELECT COUNT(*) FROM work_items WHERE source_ref = ?", sourceRef).Scan(&count)
+	err := db.QueryRow("SELECT COUNT(*) FROM work_items WHERE source_ref = ?"+filter,
+		append([]any{sourceRef}, filterArgs...)...).Scan(&count)
 	return count > 0, err
 }
 
-func GetItemsByDateRange(db *sql.DB, from, to time.Time) ([]WorkItem, error) {
+// GetWorkItemBySourceRef returns the live item imported from sourceRef. It
+// returns sql.ErrNoRows when there is none or the item was deleted.
+func GetWorkItemBySourceRef(db *sql.DB, team TeamScope, sourceRef string) (WorkItem, error) {
+	filter, filterArgs := teamFilter(team, "team_id")
+	var item WorkItem
+	err := db.QueryRow(
+		`SELECT id, description, author, author_id, source, source_ref, category, status, ticket_ids, reported_at, created_at, team_id
+		 FROM work_items WHERE source_ref = ? AND source_ref <> '' AND deleted_at IS NULL`+filter+`
+		 ORDER BY id LIMIT 1`,
+		append([]any{sourceRef}, filterArgs...)...,
+	).Scan(
+		&item.ID, &item.Description, &item.Author, &item.AuthorID, &item.Source,
+		&item.SourceRef, &item.Category, &item.Status, &item.TicketIDs,
+		&item.ReportedAt, &item.CreatedAt, &item.TeamID,
+	)
+	return item, err
+}
+
+func GetItemsByDateRange(db *sql.DB, team TeamScope, from, to time.Time) ([]WorkItem, error) {
+	filter, filterArgs := teamFilter(team, "team_id")
 	rows, err := db.Query(
-		`SELECT id, description, author, author_id, source, source_ref, category, status, ticket_ids, reported_at, created_at
-		 FROM work_items WHERE reported_at >= ? AND reported_at < ? ORDER BY category, author, reported_at, id`,
-		from, to,
+		`SELECT id, description, author, author_id, source, source_ref, category, status, ticket_ids, reported_at, created_at, team_id
+		 FROM work_items WHERE reported_at >= ? AND reported_at < ? AND deleted_at IS NULL`+filter+`
+		 ORDER BY category, author, reported_at, id`,
+		append([]any{from, to}, filterArgs...)...,
 	)
 	if err != nil {
 		return nil, err
@@ -175,7 +153,7 @@ func GetItemsByDateRange(db *sql.DB, from, to time.Time) ([]WorkItem, error) {
 		err := rows.Scan(
 			&item.ID, &item.Description, &item.Author, &item.AuthorID, &item.Source,
 			&item.SourceRef, &item.Category, &item.Status, &item.TicketIDs,
-			&item.ReportedAt, &item.CreatedAt,
+			&item.ReportedAt, &item.CreatedAt, &item.TeamID,
 		)
 		if err != nil {
 			return nil, err
@@ -185,53 +163,87 @@ func GetItemsByDateRange(db *sql.DB, from, to time.Time) ([]WorkItem, error) {
 	return items, rows.Err()
 }
 
-func GetWorkItemByID(db *sql.DB, id int64) (WorkItem, error) {
+func GetWorkItemByID(db *sql.DB, team TeamScope, id int64) (WorkItem, error) {
+	filter, filterArgs := teamFilter(team, "team_id")
 	var item WorkItem
 	err := db.QueryRow(
-		`SELECT id, description, author, author_id, source, source_ref, category, status, ticket_ids, reported_at, created_at
-		 FROM work_items WHERE id = ?`,
-		id,
+		`SELECT id, description, author, author_id, source, source_ref, category, status, ticket_ids, reported_at, created_at, team_id
+		 FROM work_items WHERE id = ? AND deleted_at IS NULL`+filter,
+		append([]any{id}, filterArgs...)...,
 	).Scan(
 		&item.ID, &item.Description, &item.Author, &item.AuthorID, &item.Source,
 		&item.SourceRef, &item.Category, &item.Status, &item.TicketIDs,
-		&item.ReportedAt, &item.CreatedAt,
+		&item.ReportedAt, &item.CreatedAt, &item.TeamID,
 	)
 	return item, err
 }
 
-func UpdateWorkItemTextAndStatus(db *sql.DB, id int64, description, status string) error {
-	_, err := db.Exec(
-		`UPDATE work_items
-		 SET description = ?, status = ?
-		 WHERE id = ?`,
-		description, status, id,
+func UpdateWorkItemTextAndStatus(db *sql.DB, team TeamScope, id int64, description, status, actorID string) error {
+	return updateWorkItem(db, team, id, actorID,
+		workItemChange{column: "description", eventType: WorkItemEventEdited, value: description},
+		workItemChange{column: "status", eventType: WorkItemEventStatusChanged, value: status},
 	)
-	return err
 }
 
-func UpdateWorkItemStatus(db *sql.DB, id int64, status string) error {
-	_, err := db.Exec(
-		`UPDATE work_items
-		 SET status = ?
-		 WHERE id = ?`,
-		status, id,
+func UpdateWorkItemStatus(db *sql.DB, team TeamScope, id int64, status, actorID string) error {
+	return updateWorkItem(db, team, id, actorID,
+		workItemChange{column: "status", eventType: WorkItemEventStatusChanged, value: status})
+}
+
+// UpdateWorkItemFromSource brings an item imported from an MR/PR up to date
+// with its current title, status and report time. Title and status changes
+// are recorded as edited and status_changed events.
+func UpdateWorkItemFromSource(db *sql.DB, team TeamScope, id int64, description, status string, reportedAt time.Time, actorID string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	err = applyWorkItemChanges(tx, team, id, actorID,
+		workItemChange{column: "description", eventType: WorkItemEventEdited, value: description},
+		workItemChange{column: "status", eventType: WorkItemEventStatusChanged, value: status},
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE work_items SET reported_at = ? WHERE id = ?`, reportedAt, id); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func DeleteWorkItemByID(db *sql.DB, id int64) error {
-	_, err := db.Exec(`DELETE FROM work_items WHERE id = ?`, id)
+// UpdateWorkItemAuthorID sets the Slack author ID of an item that has none,
+// such as one imported before its author linked their Git account. Items
+// with an author ID keep it.
+func UpdateWorkItemAuthorID(db *sql.DB, team TeamScope, id int64, authorID string) error {
+	filter, filterArgs := teamFilter(team, "team_id")
+	_, err := db.Exec(`UPDATE work_items SET author_id = ? WHERE id = ? AND author_id = ''`+filter,
+		append([]any{authorID, id}, filterArgs...)...)
 	return err
 }
 
-func GetPendingSlackItemsByAuthorAndDateRange(db *sql.DB, author string, from, to time.Time) ([]WorkItem, error) {
+// MarkWorkItemDoneFromNudge sets status to done and records a nudge_done
+// event, even if the item was already done.
+func MarkWorkItemDoneFromNudge(db *sql.DB, team TeamScope, id int64, actorID string) error {
+	return updateWorkItem(db, team, id, actorID,
+		workItemChange{column: "status", eventType: WorkItemEventNudgeDone, value: "done", always: true})
+}
+
+// DeleteWorkItemByID soft-deletes the item: it disappears from every query
+// but keeps its row and history so RestoreWorkItem can bring it back.
+func DeleteWorkItemByID(db *sql.DB, team TeamScope, id int64, actorID string) error {
+	return setWorkItemDeleted(db, team, id, true, actorID)
+}
+
+func GetPendingSlackItemsByAuthorAndDateRange(db *sql.DB, team TeamScope, author string, from, to time.Time) ([]WorkItem, error) {
+	filter, filterArgs := teamFilter(team, "team_id")
 	rows, err := db.Query(
-		`SELECT id, description, author, author_id, source, source_ref, category, status, ticket_ids, reported_at, created_at
+		`SELECT id, description, author, author_id, source, source_ref, category, status, ticket_ids, reported_at, created_at, team_id
 		 FROM work_items
 		 WHERE author = ? AND source = 'slack' AND reported_at >= ? AND reported_at < ?
-		   AND lower(trim(status)) <> 'done'
+		   AND lower(trim(status)) <> 'done' AND deleted_at IS NULL`+filter+`
 		 ORDER BY reported_at DESC, id DESC`,
-		author, from, to,
+		append([]any{author, from, to}, filterArgs...)...,
 	)
 	if err != nil {
 		return nil, err
@@ -244,7 +256,7 @@ func GetPendingSlackItemsByAuthorAndDateRange(db *sql.DB, author string, from, t
 		err := rows.Scan(
 			&item.ID, &item.Description, &item.Author, &item.AuthorID, &item.Source,
 			&item.SourceRef, &item.Category, &item.Status, &item.TicketIDs,
-			&item.ReportedAt, &item.CreatedAt,
+			&item.ReportedAt, &item.CreatedAt, &item.TeamID,
 		)
 		if err != nil {
 			return nil, err
@@ -254,13 +266,15 @@ func GetPendingSlackItemsByAuthorAndDateRange(db *sql.DB, author string, from, t
 	return items, rows.Err()
 }
 
-func GetSlackItemsByAuthorAndDateRange(db *sql.DB, author string, from, to time.Time) ([]WorkItem, error) {
+func GetSlackItemsByAuthorAndDateRange(db *sql.DB, team TeamScope, author string, from, to time.Time) ([]WorkItem, error) {
+	filter, filterArgs := teamFilter(team, "team_id")
 	rows, err := db.Query(
-		`SELECT id, description, author, author_id, source, source_ref, category, status, ticket_ids, reported_at, created_at
+		`SELECT id, description, author, author_id, source, source_ref, category, status, ticket_ids, reported_at, created_at, team_id
 		 FROM work_items
 		 WHERE author = ? AND source = 'slack' AND reported_at >= ? AND reported_at < ?
+		   AND deleted_at IS NULL`+filter+`
 		 ORDER BY reported_at DESC, id DESC`,
-		author, from, to,
+		append([]any{author, from, to}, filterArgs...)...,
 	)
 	if err != nil {
 		return nil, err
@@ -273,7 +287,7 @@ func GetSlackItemsByAuthorAndDateRange(db *sql.DB, author string, from, to time.
 		err := rows.Scan(
 			&item.ID, &item.Description, &item.Author, &item.AuthorID, &item.Source,
 			&item.SourceRef, &item.Category, &item.Status, &item.TicketIDs,
-			&item.ReportedAt, &item.CreatedAt,
+			&item.ReportedAt, &item.CreatedAt, &item.TeamID,
 		)
 		if err != nil {
 			return nil, err
@@ -283,11 +297,12 @@ func GetSlackItemsByAuthorAndDateRange(db *sql.DB, author string, from, to time.
 	return items, rows.Err()
 }
 
-func GetSlackAuthorsByDateRange(db *sql.DB, from, to time.Time) (map[string]bool, error) {
+func GetSlackAuthorsByDateRange(db *sql.DB, team TeamScope, from, to time.Time) (map[string]bool, error) {
+	filter, filterArgs := teamFilter(team, "team_id")
 	rows, err := db.Query(
 		`SELECT DISTINCT author FROM work_items
-		 WHERE reported_at >= ? AND reported_at < ? AND source = 'slack'`,
-		from, to,
+		 WHERE reported_at >= ? AND reported_at < ? AND source = 'slack' AND deleted_at IS NULL`+filter,
+		append([]any{from, to}, filterArgs...)...,
 	)
 	if err != nil {
 		return nil, err
@@ -307,11 +322,13 @@ func GetSlackAuthorsByDateRange(db *sql.DB, from, to time.Time) (map[string]bool
 	return authors, rows.Err()
 }
 
-func GetSlackAuthorIDsByDateRange(db *sql.DB, from, to time.Time) (map[string]bool, error) {
+func GetSlackAuthorIDsByDateRange(db *sql.DB, team TeamScope, from, to time.Time) (map[string]bool, error) {
+	filter, filterArgs := teamFilter(team, "team_id")
 	rows, err := db.Query(
 		`SELECT DISTINCT author_id FROM work_items
-		 WHERE reported_at >= ? AND reported_at < ? AND source = 'slack' AND author_id <> ''`,
-		from, to,
+		 WHERE reported_at >= ? AND reported_at < ? AND source = 'slack' AND author_id <> ''
+		   AND deleted_at IS NULL`+filter,
+		append([]any{from, to}, filterArgs...)...,
 	)
 	if err != nil {
 		return nil, err
@@ -331,51 +348,51 @@ func GetSlackAuthorIDsByDateRange(db *sql.DB, from, to time.Time) (map[string]bo
 	return authorIDs, rows.Err()
 }
 
-func UpdateCategories(db *sql.DB, categorized map[int64]string) error {
+// UpdateCategories sets categories chosen by the bot, recording a
+// category_changed event without an actor for each change. Deleted or
+// missing items are skipped.
+func UpdateCategories(db *sql.DB, team TeamScope, categorized map[int64]string) error {
 	tx, err := db.Begin()
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	stmt, err := tx.Prepare("UPDATE work_items SET category = ? WHERE id = ?")
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
-
 	for id, category := range categorized {
-		if _, err := stmt.Exec(category, id); err != nil {
+		err := applyWorkItemChanges(tx, team, id, "",
+			workItemChange{column: "category", eventType: WorkItemEventCategoryChanged, value: category})
+		if err != nil && err != sql.ErrNoRows {
 			return err
 		}
 	}
 	return tx.Commit()
 }
 
-func UpdateTicketIDs(db *sql.DB, ticketMap map[int64]string) error {
+func UpdateTicketIDs(db *sql.DB, team TeamScope, ticketMap map[int64]string) error {
+	filter, filterArgs := teamFilter(team, "team_id")
 	tx, err := db.Begin()
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	stmt, err := tx.Prepare("UPDATE work_items SET ticket_ids = ? WHERE id = ?")
+	stmt, err := tx.Prepare("UPDATE work_items SET ticket_ids = ? WHERE id = ?" + filter)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
 	for id, tickets := range ticketMap {
-		if _, err := stmt.Exec(tickets, id); err != nil {
+		if _, err := stmt.Exec(append([]any{tickets, id}, filterArgs...)...); err != nil {
 			return err
 		}
 	}
 	return tx.Commit()
 }
 
-func UpdateWorkItemCategory(db *sql.DB, id int64, category string) error {
-	_, err := db.Exec("UPDATE work_items SET category = ? WHERE id = ?", category, id)
-	return err
+func UpdateWorkItemCategory(db *sql.DB, team TeamScope, id int64, category, actorID string) error {
+	return updateWorkItem(db, team, id, actorID,
+		workItemChange{column: "category", eventType: WorkItemEventCategoryChanged, value: category})
 }
 
 // --- Classification History ---
@@ -392,8 +409,9 @@ func InsertClassificationHistory(db *sql.DB, records []ClassificationRecord) err
 
 	stmt, err := tx.Prepare(
 		`INSERT INTO classification_history
-		 (work_item_id, section_id, section_label, confidence, normalized_status, ticket_ids, duplicate_of, llm_provider, llm_model)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		 (work_item_id, section_id, section_label, confidence, normalized_status, ticket_ids, duplicate_of, llm_provider, llm_model,
+		  raw_confidence, alternative_section_ids, cache_key)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 	)
 	if err != nil {
 		return err
@@ -405,6 +423,7 @@ func InsertClassificationHistory(db *sql.DB, records []ClassificationRecord) err
 			r.WorkItemID, r.SectionID, r.SectionLabel, r.Confidence,
 			r.NormalizedStatus, r.TicketIDs, r.DuplicateOf,
 			r.LLMProvider, r.LLMModel,
+			r.RawConfidence, r.AlternativeSectionIDs, r.CacheKey,
 		); err != nil {
 			return err
 		}
@@ -412,23 +431,117 @@ func InsertClassificationHistory(db *sql.DB, records []ClassificationRecord) err
 	return tx.Commit()
 }
 
-func GetLatestClassification(db *sql.DB, workItemID int64) (ClassificationRecord, error) {
+func GetLatestClassification(db *sql.DB, team TeamScope, workItemID int64) (ClassificationRecord, error) {
+	filter, filterArgs := itemTeamFilter(team, "work_item_id")
 	var r ClassificationRecord
 	err := db.QueryRow(
 		`SELECT id, work_item_id, section_id, section_label, confidence,
-		        normalized_status, ticket_ids, duplicate_of, llm_provider, llm_model, classified_at
+		        normalized_status, ticket_ids, duplicate_of, llm_provider, llm_model, classified_at,
+		        COALESCE(raw_confidence, 0), COALESCE(alternative_section_ids, ''), COALESCE(cache_key, '')
 		 FROM classification_history
-		 WHERE work_item_id = ?
+		 WHERE work_item_id = ?`+filter+`
 		 ORDER BY classified_at DESC LIMIT 1`,
-		workItemID,
+		append([]any{workItemID}, filterArgs...)...,
 	).Scan(
 		&r.ID, &r.WorkItemID, &r.SectionID, &r.SectionLabel, &r.Confidence,
 		&r.NormalizedStatus, &r.TicketIDs, &r.DuplicateOf,
 		&r.LLMProvider, &r.LLMModel, &r.ClassifiedAt,
+		&r.RawConfidence, &r.AlternativeSectionIDs, &r.CacheKey,
 	)
 	return r, err
 }
 
+// GetCachedClassifications returns, for each cache key, the most recent
+// classification recorded under it. Decisions for work items a manager has
+// since corrected are skipped so those items go back to the LLM, which now
+// sees the correction in its prompt.
+func GetCachedClassifications(db *sql.DB, team TeamScope, keys []string) (map[string]ClassificationRecord, error) {
+	filter, filterArgs := itemTeamFilter(team, "ch.work_item_id")
+	out := make(map[string]ClassificationRecord, len(keys))
+	const chunk = 500
+	for start := 0; start < len(keys); start += chunk {
+		end := start + chunk
+		if end > len(keys) {
+			end = len(keys)
+		}
+		args := make([]any, 0, end-start)
+		for _, k := range keys[start:end] {
+			args = append(args, k)
+		}
+		rows, err := db.Query(
+			`SELECT ch.id, ch.work_item_id, ch.section_id, ch.section_label, ch.confidence,
+			        ch.normalized_status, ch.ticket_ids, ch.duplicate_of, ch.llm_provider, ch.llm_model, ch.classified_at,
+			        COALESCE(ch.raw_confidence, 0), COALESCE(ch.alternative_section_ids, ''), ch.cache_key
+			 FROM classification_history ch
+			 WHERE ch.cache_key IN (?`+strings.Repeat(",?", end-start-1)+`)
+			   AND NOT EXISTS (
+			     SELECT 1 FROM classification_corrections cc
+			     WHERE cc.work_item_id = ch.work_item_id AND cc.corrected_at >= ch.classified_at
+			   )`+filter+`
+			 ORDER BY ch.classified_at, ch.id`,
+			append(args, filterArgs...)...,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var r ClassificationRecord
+			if err := rows.Scan(
+				&r.ID, &r.WorkItemID, &r.SectionID, &r.SectionLabel, &r.Confidence,
+				&r.NormalizedStatus, &r.TicketIDs, &r.DuplicateOf,
+				&r.LLMProvider, &r.LLMModel, &r.ClassifiedAt,
+				&r.RawConfidence, &r.AlternativeSectionIDs, &r.CacheKey,
+			); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			// Later rows overwrite earlier ones: the newest decision wins.
+			out[r.CacheKey] = r
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+	}
+	return out, nil
+}
+
+// GetConfidenceSamples returns raw model confidences since the given time,
+// each marked corrected when a manager later moved that item out of the
+// section the model chose.
+func GetConfidenceSamples(db *sql.DB, team TeamScope, since time.Time, limit int) ([]ConfidenceSample, error) {
+	filter, filterArgs := itemTeamFilter(team, "ch.work_item_id")
+	rows, err := db.Query(
+		`SELECT ch.raw_confidence,
+		        EXISTS (
+		          SELECT 1 FROM classification_corrections cc
+		          WHERE cc.work_item_id = ch.work_item_id
+		            AND cc.original_section_id = ch.section_id
+		            AND cc.corrected_at >= ch.classified_at
+		        )
+		 FROM classification_history ch
+		 WHERE ch.raw_confidence > 0 AND ch.classified_at >= ?`+filter+`
+		 ORDER BY ch.classified_at DESC, ch.id DESC
+		 LIMIT ?`,
+		append(append([]any{since}, filterArgs...), limit)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []ConfidenceSample
+	for rows.Next() {
+		var s ConfidenceSample
+		if err := rows.Scan(&s.RawConfidence, &s.Corrected); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
 // --- Classification Corrections ---
 
 func InsertClassificationCorrection(db *sql.DB, c ClassificationCorrection) error {
@@ -442,15 +555,16 @@ func InsertClassificationCorrection(db *sql.DB, c ClassificationCorrection) erro
 	return err
 }
 
-func GetRecentCorrections(db *sql.DB, since time.Time, limit int) ([]ClassificationCorrection, error) {
+func GetRecentCorrections(db *sql.DB, team TeamScope, since time.Time, limit int) ([]ClassificationCorrection, error) {
+	filter, filterArgs := itemTeamFilter(team, "work_item_id")
 	rows, err := db.Query(
 		`SELECT id, work_item_id, original_section_id, original_label,
 		        corrected_section_id, corrected_label, description, corrected_by, corrected_at
 		 FROM classification_corrections
-		 WHERE corrected_at >= ?
+		 WHERE corrected_at >= ?`+filter+`
 		 ORDER BY corrected_at DESC
 		 LIMIT ?`,
-		since, limit,
+		append(append([]any{since}, filterArgs...), limit)...,
 	)
 	if err != nil {
 		return nil, err
@@ -472,15 +586,16 @@ func GetRecentCorrections(db *sql.DB, since time.Time, limit int) ([]Classificat
 	return out, rows.Err()
 }
 
-func GetClassifiedItemsWithSections(db *sql.DB, since time.Time, limit int) ([]historicalItem, error) {
+func GetClassifiedItemsWithSections(db *sql.DB, team TeamScope, since time.Time, limit int) ([]historicalItem, error) {
+	filter, filterArgs := teamFilter(team, "w.team_id")
 	rows, err := db.Query(
-		`SELECT w.description, ch.section_id, ch.section_label
+		`SELECT w.id, w.description, ch.section_id, ch.section_label
 		 FROM classification_history ch
 		 JOIN work_items w ON w.id = ch.work_item_id
-		 WHERE ch.confidence >= 0.70 AND ch.classified_at >= ?
+		 WHERE ch.confidence >= 0.70 AND ch.classified_at >= ? AND w.deleted_at IS NULL`+filter+`
 		 ORDER BY ch.classified_at DESC, ch.id DESC
 		 LIMIT ?`,
-		since, limit,
+		append(append([]any{since}, filterArgs...), limit)...,
 	)
 	if err != nil {
 		return nil, err
@@ -490,7 +605,7 @@ func GetClassifiedItemsWithSections(db *sql.DB, since time.Time, limit int) ([]h
 	var out []historicalItem
 	for rows.Next() {
 		var h historicalItem
-		if err := rows.Scan(&h.Description, &h.SectionID, &h.SectionLabel); err != nil {
+		if err := rows.Scan(&h.WorkItemID, &h.Description, &h.SectionID, &h.SectionLabel); err != nil {
 			return nil, err
 		}
 		out = append(out, h)
@@ -498,29 +613,79 @@ func GetClassifiedItemsWithSections(db *sql.DB, since time.Time, limit int) ([]h
 	return out, rows.Err()
 }
 
-func CountCorrectionsByPhrase(db *sql.DB, description, correctedSectionID string) (int, error) {
+// GetLabeledWorkItems returns work items reported since the given time that
+// have been classified, paired with their final section: the latest
+// correction if a manager made one, otherwise the latest LLM decision.
+func GetLabeledWorkItems(db *sql.DB, team TeamScope, since time.Time, limit int) ([]LabeledWorkItem, error) {
+	filter, filterArgs := teamFilter(team, "w.team_id")
+	rows, err := db.Query(
+		`SELECT w.id, w.description, w.author, w.author_id, w.source, w.source_ref, w.category,
+		        w.status, w.ticket_ids, w.reported_at, w.created_at, w.team_id,
+		        ch.section_id, ch.section_label,
+		        COALESCE(cc.corrected_section_id, ''), COALESCE(cc.corrected_label, '')
+		 FROM work_items w
+		 JOIN classification_history ch
+		   ON ch.id = (SELECT MAX(id) FROM classification_history WHERE work_item_id = w.id)
+		 LEFT JOIN classification_corrections cc
+		   ON cc.id = (SELECT MAX(id) FROM classification_corrections WHERE work_item_id = w.id)
+		 WHERE w.reported_at >= ? AND w.deleted_at IS NULL`+filter+`
+		 ORDER BY w.reported_at, w.id
+		 LIMIT ?`,
+		append(append([]any{since}, filterArgs...), limit)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []LabeledWorkItem
+	for rows.Next() {
+		var l LabeledWorkItem
+		var correctedID, correctedLabel string
+		if err := rows.Scan(
+			&l.Item.ID, &l.Item.Description, &l.Item.Author, &l.Item.AuthorID, &l.Item.Source,
+			&l.Item.SourceRef, &l.Item.Category, &l.Item.Status, &l.Item.TicketIDs,
+			&l.Item.ReportedAt, &l.Item.CreatedAt, &l.Item.TeamID,
+			&l.PredictedSection, &l.PredictedLabel,
+			&correctedID, &correctedLabel,
+		); err != nil {
+			return nil, err
+		}
+		l.FinalSection, l.FinalLabel = l.PredictedSection, l.PredictedLabel
+		if correctedID != "" {
+			l.FinalSection, l.FinalLabel = correctedID, correctedLabel
+			l.Corrected = true
+		}
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
+
+func CountCorrectionsByPhrase(db *sql.DB, team TeamScope, description, correctedSectionID string) (int, error) {
+	filter, filterArgs := itemTeamFilter(team, "work_item_id")
 	var count int
 	err := db.QueryRow(
 		`SELECT COUNT(*) FROM classification_corrections
 		 WHERE LOWER(TRIM(description)) = LOWER(TRIM(?))
-		   AND corrected_section_id = ?`,
-		description, correctedSectionID,
+		   AND corrected_section_id = ?`+filter,
+		append([]any{description, correctedSectionID}, filterArgs...)...,
 	).Scan(&count)
 	return count, err
 }
 
 // --- Classification Stats ---
 
-func GetClassificationStats(db *sql.DB, since time.Time) (ClassificationStats, error) {
+func GetClassificationStats(db *sql.DB, team TeamScope, since time.Time) (ClassificationStats, error) {
 	var s ClassificationStats
+	filter, filterArgs := itemTeamFilter(team, "work_item_id")
 	err := db.QueryRow(
 		`SELECT COUNT(*), COALESCE(AVG(confidence), 0),
 		        COALESCE(SUM(CASE WHEN confidence < 0.50 THEN 1 ELSE 0 END), 0),
 		        COALESCE(SUM(CASE WHEN confidence >= 0.50 AND confidence < 0.70 THEN 1 ELSE 0 END), 0),
 		        COALESCE(SUM(CASE WHEN confidence >= 0.70 AND confidence < 0.90 THEN 1 ELSE 0 END), 0),
 		        COALESCE(SUM(CASE WHEN confidence >= 0.90 THEN 1 ELSE 0 END), 0)
-		 FROM classification_history WHERE classified_at >= ?`,
-		since,
+		 FROM classification_history WHERE classified_at >= ?`+filter,
+		append([]any{since}, filterArgs...)...,
 	).Scan(&s.TotalClassifications, &s.AvgConfidence,
 		&s.BucketBelow50, &s.Bucket50to70, &s.Bucket70to90, &s.Bucket90Plus)
 	if err != nil {
@@ -528,27 +693,22 @@ func GetClassificationStats(db *sql.DB, since time.Time) (ClassificationStats, e
 	}
 
 	err = db.QueryRow(
-		`SELECT COUNT(*) FROM classification_corrections WHERE corrected_at >= ?`,
-		since,
+		`SELECT COUNT(*) FROM classification_corrections WHERE corrected_at >= ?`+filter,
+		append([]any{since}, filterArgs...)...,
 	).Scan(&s.TotalCorrections)
 	return s, err
 }
 
-type SectionCorrectionStat struct {
-	OriginalSectionID string
-	OriginalLabel     string
-	CorrectionCount   int
-}
-
-func GetCorrectionsBySection(db *sql.DB, since time.Time) ([]SectionCorrectionStat, error) {
+func GetCorrectionsBySection(db *sql.DB, team TeamScope, since time.Time) ([]SectionCorrectionStat, error) {
+	filter, filterArgs := itemTeamFilter(team, "work_item_id")
 	rows, err := db.Query(
 		`SELECT original_section_id, COALESCE(MAX(original_label), ''), COUNT(*) as cnt
 		 FROM classification_corrections
-		 WHERE corrected_at >= ?
+		 WHERE corrected_at >= ?`+filter+`
 		 GROUP BY original_section_id
 		 ORDER BY cnt DESC
 		 LIMIT 10`,
-		since,
+		append([]any{since}, filterArgs...)...,
 	)
 	if err != nil {
 		return nil, err
@@ -566,24 +726,18 @@ func GetCorrectionsBySection(db *sql.DB, since time.Time) ([]SectionCorrectionSt
 	return out, rows.Err()
 }
 
-type WeeklyTrend struct {
-	WeekStart       string
-	Classifications int
-	Corrections     int
-	AvgConfidence   float64
-}
-
-func GetWeeklyClassificationTrend(db *sql.DB, since time.Time) ([]WeeklyTrend, error) {
+func GetWeeklyClassificationTrend(db *sql.DB, team TeamScope, since time.Time) ([]WeeklyTrend, error) {
+	filter, filterArgs := itemTeamFilter(team, "work_item_id")
 	rows, err := db.Query(
 		`SELECT
 		    strftime('%Y-%m-%d', classified_at, 'weekday 0', '-6 days') as week_start,
 		    COUNT(*) as classifications,
 		    COALESCE(AVG(confidence), 0) as avg_confidence
 		 FROM classification_history
-		 WHERE classified_at >= ?
+		 WHERE classified_at >= ?`+filter+`
 		 GROUP BY week_start
 		 ORDER BY week_start DESC`,
-		since,
+		append([]any{since}, filterArgs...)...,
 	)
 	if err != nil {
 		return nil, err
@@ -608,9 +762,9 @@ func GetWeeklyClassificationTrend(db *sql.DB, since time.Time) ([]WeeklyTrend, e
 		    strftime('%Y-%m-%d', corrected_at, 'weekday 0', '-6 days') as week_start,
 		    COUNT(*) as corrections
 		 FROM classification_corrections
-		 WHERE corrected_at >= ?
+		 WHERE corrected_at >= ?`+filter+`
 		 GROUP BY week_start`,
-		since,
+		append([]any{since}, filterArgs...)...,
 	)
 	if err != nil {
 		return trends, nil // non-fatal
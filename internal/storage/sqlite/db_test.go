@@ -42,7 +42,7 @@ func TestWorkItemCRUDAndQueries(t *testing.T) {
 		Status:      "in progress",
 		ReportedAt:  base,
 	}
-	if err := InsertWorkItem(db, item1); err != nil {
+	if err := InsertWorkItem(db, item1, ""); err != nil {
 		t.Fatalf("InsertWorkItem failed: %v", err)
 	}
 
@@ -65,7 +65,7 @@ func TestWorkItemCRUDAndQueries(t *testing.T) {
 			ReportedAt:  base.Add(20 * time.Minute),
 		},
 	}
-	inserted, err := InsertWorkItems(db, items)
+	inserted, err := InsertWorkItems(db, items, "")
 	if err != nil {
 		t.Fatalf("InsertWorkItems failed: %v", err)
 	}
@@ -79,11 +79,11 @@ func TestWorkItemCRUDAndQueries(t *testing.T) {
 		Source:      "slack",
 		Status:      "done",
 		ReportedAt:  base.Add(30 * time.Minute),
-	}); err != nil {
+	}, ""); err != nil {
 		t.Fatalf("InsertWorkItem legacy slack failed: %v", err)
 	}
 
-	exists, err := SourceRefExists(db, "https://gitlab.example.com/group/proj/-/merge_requests/1")
+	exists, err := SourceRefExists(db, TeamScope{}, "https://gitlab.example.com/group/proj/-/merge_requests/1")
 	if err != nil {
 		t.Fatalf("SourceRefExists failed: %v", err)
 	}
@@ -93,7 +93,7 @@ func TestWorkItemCRUDAndQueries(t *testing.T) {
 
 	from := base.Add(-1 * time.Hour)
 	to := base.Add(2 * time.Hour)
-	all, err := GetItemsByDateRange(db, from, to)
+	all, err := GetItemsByDateRange(db, TeamScope{}, from, to)
 	if err != nil {
 		t.Fatalf("GetItemsByDateRange failed: %v", err)
 	}
@@ -106,7 +106,7 @@ func TestWorkItemCRUDAndQueries(t *testing.T) {
 		idByDesc[it.Description] = it.ID
 	}
 
-	slackItems, err := GetSlackItemsByAuthorAndDateRange(db, "Alice", from, to)
+	slackItems, err := GetSlackItemsByAuthorAndDateRange(db, TeamScope{}, "Alice", from, to)
 	if err != nil {
 		t.Fatalf("GetSlackItemsByAuthorAndDateRange failed: %v", err)
 	}
@@ -114,7 +114,7 @@ func TestWorkItemCRUDAndQueries(t *testing.T) {
 		t.Fatalf("expected 2 Slack items for Alice, got %d", len(slackItems))
 	}
 
-	pending, err := GetPendingSlackItemsByAuthorAndDateRange(db, "Alice", from, to)
+	pending, err := GetPendingSlackItemsByAuthorAndDateRange(db, TeamScope{}, "Alice", from, to)
 	if err != nil {
 		t.Fatalf("GetPendingSlackItemsByAuthorAndDateRange failed: %v", err)
 	}
@@ -125,7 +125,7 @@ func TestWorkItemCRUDAndQueries(t *testing.T) {
 		t.Fatalf("unexpected pending item: %q", pending[0].Description)
 	}
 
-	authors, err := GetSlackAuthorsByDateRange(db, from, to)
+	authors, err := GetSlackAuthorsByDateRange(db, TeamScope{}, from, to)
 	if err != nil {
 		t.Fatalf("GetSlackAuthorsByDateRange failed: %v", err)
 	}
@@ -136,7 +136,7 @@ func TestWorkItemCRUDAndQueries(t *testing.T) {
 		t.Fatal("did not expect Bob in slack authors map")
 	}
 
-	authorIDs, err := GetSlackAuthorIDsByDateRange(db, from, to)
+	authorIDs, err := GetSlackAuthorIDsByDateRange(db, TeamScope{}, from, to)
 	if err != nil {
 		t.Fatalf("GetSlackAuthorIDsByDateRange failed: %v", err)
 	}
@@ -148,23 +148,23 @@ func TestWorkItemCRUDAndQueries(t *testing.T) {
 	}
 
 	updateID := idByDesc["Implement feature A"]
-	if err := UpdateWorkItemTextAndStatus(db, updateID, "Implement feature A v2", "in testing"); err != nil {
+	if err := UpdateWorkItemTextAndStatus(db, TeamScope{}, updateID, "Implement feature A v2", "in testing", ""); err != nil {
 		t.Fatalf("UpdateWorkItemTextAndStatus failed: %v", err)
 	}
-	if err := UpdateWorkItemStatus(db, updateID, "done"); err != nil {
+	if err := UpdateWorkItemStatus(db, TeamScope{}, updateID, "done", ""); err != nil {
 		t.Fatalf("UpdateWorkItemStatus failed: %v", err)
 	}
-	if err := UpdateCategories(db, map[int64]string{updateID: "S0_0"}); err != nil {
+	if err := UpdateCategories(db, TeamScope{}, map[int64]string{updateID: "S0_0"}); err != nil {
 		t.Fatalf("UpdateCategories failed: %v", err)
 	}
-	if err := UpdateTicketIDs(db, map[int64]string{updateID: "123456"}); err != nil {
+	if err := UpdateTicketIDs(db, TeamScope{}, map[int64]string{updateID: "123456"}); err != nil {
 		t.Fatalf("UpdateTicketIDs failed: %v", err)
 	}
-	if err := UpdateWorkItemCategory(db, updateID, "S1_0"); err != nil {
+	if err := UpdateWorkItemCategory(db, TeamScope{}, updateID, "S1_0", ""); err != nil {
 		t.Fatalf("UpdateWorkItemCategory failed: %v", err)
 	}
 
-	updated, err := GetWorkItemByID(db, updateID)
+	updated, err := GetWorkItemByID(db, TeamScope{}, updateID)
 	if err != nil {
 		t.Fatalf("GetWorkItemByID failed: %v", err)
 	}
@@ -182,10 +182,10 @@ func TestWorkItemCRUDAndQueries(t *testing.T) {
 	}
 
 	deleteID := idByDesc["Fix bug B"]
-	if err := DeleteWorkItemByID(db, deleteID); err != nil {
+	if err := DeleteWorkItemByID(db, TeamScope{}, deleteID, ""); err != nil {
 		t.Fatalf("DeleteWorkItemByID failed: %v", err)
 	}
-	if _, err := GetWorkItemByID(db, deleteID); err == nil {
+	if _, err := GetWorkItemByID(db, TeamScope{}, deleteID); err == nil {
 		t.Fatal("expected deleted item lookup to fail")
 	}
 }
@@ -212,11 +212,11 @@ func TestClassificationHistoryCorrectionsAndStats(t *testing.T) {
 			ReportedAt:  now.Add(1 * time.Minute),
 		},
 	}
-	if _, err := InsertWorkItems(db, workItems); err != nil {
+	if _, err := InsertWorkItems(db, workItems, ""); err != nil {
 		t.Fatalf("InsertWorkItems failed: %v", err)
 	}
 
-	all, err := GetItemsByDateRange(db, now.Add(-1*time.Hour), now.Add(1*time.Hour))
+	all, err := GetItemsByDateRange(db, TeamScope{}, now.Add(-1*time.Hour), now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetItemsByDateRange failed: %v", err)
 	}
@@ -255,7 +255,7 @@ func TestClassificationHistoryCorrectionsAndStats(t *testing.T) {
 		t.Fatalf("InsertClassificationHistory failed: %v", err)
 	}
 
-	latest, err := GetLatestClassification(db, id1)
+	latest, err := GetLatestClassification(db, TeamScope{}, id1)
 	if err != nil {
 		t.Fatalf("GetLatestClassification failed: %v", err)
 	}
@@ -288,7 +288,7 @@ func TestClassificationHistoryCorrectionsAndStats(t *testing.T) {
 
 	since := now.Add(-24 * time.Hour)
 
-	corrections, err := GetRecentCorrections(db, since, 10)
+	corrections, err := GetRecentCorrections(db, TeamScope{}, since, 10)
 	if err != nil {
 		t.Fatalf("GetRecentCorrections failed: %v", err)
 	}
@@ -296,7 +296,7 @@ func TestClassificationHistoryCorrectionsAndStats(t *testing.T) {
 		t.Fatalf("expected 2 corrections, got %d", len(corrections))
 	}
 
-	count, err := CountCorrectionsByPhrase(db, "Improve API throughput", "S0_0")
+	count, err := CountCorrectionsByPhrase(db, TeamScope{}, "Improve API throughput", "S0_0")
 	if err != nil {
 		t.Fatalf("CountCorrectionsByPhrase failed: %v", err)
 	}
@@ -304,7 +304,7 @@ func TestClassificationHistoryCorrectionsAndStats(t *testing.T) {
 		t.Fatalf("expected count=1, got %d", count)
 	}
 
-	historical, err := GetClassifiedItemsWithSections(db, since, 10)
+	historical, err := GetClassifiedItemsWithSections(db, TeamScope{}, since, 10)
 	if err != nil {
 		t.Fatalf("GetClassifiedItemsWithSections failed: %v", err)
 	}
@@ -312,7 +312,7 @@ func TestClassificationHistoryCorrectionsAndStats(t *testing.T) {
 		t.Fatal("expected at least one historical item")
 	}
 
-	stats, err := GetClassificationStats(db, since)
+	stats, err := GetClassificationStats(db, TeamScope{}, since)
 	if err != nil {
 		t.Fatalf("GetClassificationStats failed: %v", err)
 	}
@@ -323,7 +323,7 @@ func TestClassificationHistoryCorrectionsAndStats(t *testing.T) {
 		t.Fatalf("expected total corrections=2, got %d", stats.TotalCorrections)
 	}
 
-	bySection, err := GetCorrectionsBySection(db, since)
+	bySection, err := GetCorrectionsBySection(db, TeamScope{}, since)
 	if err != nil {
 		t.Fatalf("GetCorrectionsBySection failed: %v", err)
 	}
@@ -331,7 +331,7 @@ func TestClassificationHistoryCorrectionsAndStats(t *testing.T) {
 		t.Fatal("expected corrections by section to be non-empty")
 	}
 
-	trend, err := GetWeeklyClassificationTrend(db, since)
+	trend, err := GetWeeklyClassificationTrend(db, TeamScope{}, since)
 	if err != nil {
 		t.Fatalf("GetWeeklyClassificationTrend failed: %v", err)
 	}
@@ -362,7 +362,7 @@ func TestSourceRefUniquenessIgnoresDuplicates(t *testing.T) {
 			ReportedAt:  base.Add(1 * time.Minute),
 		},
 	}
-	inserted, err := InsertWorkItems(db, items)
+	inserted, err := InsertWorkItems(db, items, "")
 	if err != nil {
 		t.Fatalf("InsertWorkItems failed: %v", err)
 	}
@@ -378,7 +378,7 @@ func TestSourceRefUniquenessIgnoresDuplicates(t *testing.T) {
 		SourceRef:   "https://gitlab.example.com/group/proj/-/merge_requests/99",
 		Status:      "done",
 		ReportedAt:  base.Add(2 * time.Minute),
-	}); err != nil {
+	}, ""); err != nil {
 		t.Fatalf("InsertWorkItem duplicate should not fail: %v", err)
 	}
 
@@ -463,3 +463,225 @@ func TestInitDBMigrationDeduplicatesExistingSourceRefs(t *testing.T) {
 		t.Fatal("expected unique constraint error when inserting duplicate source/source_ref after migration")
 	}
 }
+
+func TestGetConfidenceSamplesMarksCorrectedDecisions(t *testing.T) {
+	db := newTestDB(t)
+
+	history := []ClassificationRecord{
+		{WorkItemID: 1, SectionID: "S0_0", Confidence: 0.8, RawConfidence: 0.85, AlternativeSectionIDs: "S1_0"},
+		{WorkItemID: 2, SectionID: "S0_0", Confidence: 0.6, RawConfidence: 0.55},
+		{WorkItemID: 3, SectionID: "S1_0", Confidence: 0.99},
+	}
+	if err := InsertClassificationHistory(db, history); err != nil {
+		t.Fatalf("InsertClassificationHistory failed: %v", err)
+	}
+	if err := InsertClassificationCorrection(db, ClassificationCorrection{
+		WorkItemID:         2,
+		OriginalSectionID:  "S0_0",
+		CorrectedSectionID: "S1_0",
+	}); err != nil {
+		t.Fatalf("InsertClassificationCorrection failed: %v", err)
+	}
+
+	samples, err := GetConfidenceSamples(db, TeamScope{}, time.Time{}, 100)
+	if err != nil {
+		t.Fatalf("GetConfidenceSamples failed: %v", err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("expected only rows with raw confidence, got %+v", samples)
+	}
+	byRaw := map[float64]bool{}
+	for _, s := range samples {
+		byRaw[s.RawConfidence] = s.Corrected
+	}
+	if byRaw[0.85] || !byRaw[0.55] {
+		t.Fatalf("unexpected corrected flags: %+v", samples)
+	}
+
+	latest, err := GetLatestClassification(db, TeamScope{}, 1)
+	if err != nil {
+		t.Fatalf("GetLatestClassification failed: %v", err)
+	}
+	if latest.RawConfidence != 0.85 || latest.AlternativeSectionIDs != "S1_0" {
+		t.Fatalf("expected raw confidence and alternatives to round-trip, got %+v", latest)
+	}
+}
+
+func TestGetLabeledWorkItemsPrefersCorrections(t *testing.T) {
+	db := newTestDB(t)
+	now := time.Now().UTC().Truncate(time.Second)
+	if _, err := InsertWorkItems(db, []WorkItem{
+		{Description: "Upgrade cluster", Author: "Alex", Source: "slack", Status: "done", ReportedAt: now},
+		{Description: "Tune postgres", Author: "Casey", Source: "slack", Status: "done", ReportedAt: now.Add(time.Minute)},
+		{Description: "Never classified", Author: "Casey", Source: "slack", Status: "done", ReportedAt: now.Add(2 * time.Minute)},
+	}, ""); err != nil {
+		t.Fatalf("InsertWorkItems failed: %v", err)
+	}
+	items, err := GetItemsByDateRange(db, TeamScope{}, now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetItemsByDateRange failed: %v", err)
+	}
+	ids := map[string]int64{}
+	for _, it := range items {
+		ids[it.Description] = it.ID
+	}
+
+	if err := InsertClassificationHistory(db, []ClassificationRecord{
+		{WorkItemID: ids["Upgrade cluster"], SectionID: "S0_0", SectionLabel: "Infra", Confidence: 0.9},
+		{WorkItemID: ids["Tune postgres"], SectionID: "S0_0", SectionLabel: "Infra", Confidence: 0.9},
+	}); err != nil {
+		t.Fatalf("InsertClassificationHistory failed: %v", err)
+	}
+	if err := InsertClassificationCorrection(db, ClassificationCorrection{
+		WorkItemID: ids["Tune postgres"], OriginalSectionID: "S0_0", CorrectedSectionID: "S1_0", CorrectedLabel: "Database",
+	}); err != nil {
+		t.Fatalf("InsertClassificationCorrection failed: %v", err)
+	}
+
+	labeled, err := GetLabeledWorkItems(db, TeamScope{}, now.Add(-time.Hour), 100)
+	if err != nil {
+		t.Fatalf("GetLabeledWorkItems failed: %v", err)
+	}
+	if len(labeled) != 2 {
+		t.Fatalf("expected only classified items, got %d", len(labeled))
+	}
+	if l := labeled[0]; l.Corrected || l.FinalLabel != "Infra" {
+		t.Fatalf("unexpected uncorrected item: %+v", l)
+	}
+	if l := labeled[1]; !l.Corrected || l.FinalSection != "S1_0" || l.FinalLabel != "Database" || l.PredictedLabel != "Infra" {
+		t.Fatalf("unexpected corrected item: %+v", l)
+	}
+}
+
+func TestEmbeddingsRoundTripAndReplace(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := SaveEmbeddings(db, []Embedding{
+		{WorkItemID: 1, Model: "m1", TextHash: "h1", Vector: []float32{0.25, -1.5, 3}},
+		{WorkItemID: 2, Model: "m1", TextHash: "h2", Vector: []float32{1, 0}},
+		{WorkItemID: 1, Model: "m2", TextHash: "h1", Vector: []float32{9}},
+	}); err != nil {
+		t.Fatalf("SaveEmbeddings failed: %v", err)
+	}
+
+	got, err := GetEmbeddings(db, "m1", []int64{1, 2, 3})
+	if err != nil {
+		t.Fatalf("GetEmbeddings failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 cached vectors for m1, got %+v", got)
+	}
+	if v := got[1].Vector; len(v) != 3 || v[0] != 0.25 || v[1] != -1.5 || v[2] != 3 || got[1].TextHash != "h1" {
+		t.Fatalf("unexpected vector round-trip: %+v", got[1])
+	}
+
+	if err := SaveEmbeddings(db, []Embedding{{WorkItemID: 1, Model: "m1", TextHash: "h1b", Vector: []float32{7}}}); err != nil {
+		t.Fatalf("SaveEmbeddings (replace) failed: %v", err)
+	}
+	got, err = GetEmbeddings(db, "m1", []int64{1})
+	if err != nil {
+		t.Fatalf("GetEmbeddings failed: %v", err)
+	}
+	if got[1].TextHash != "h1b" || len(got[1].Vector) != 1 {
+		t.Fatalf("expected vector to be replaced, got %+v", got[1])
+	}
+}
+
+func TestGetCachedClassificationsSkipsCorrectedItems(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := InsertClassificationHistory(db, []ClassificationRecord{
+		{WorkItemID: 1, SectionID: "S0_0", Confidence: 0.7, CacheKey: "k1"},
+		{WorkItemID: 2, SectionID: "S1_0", Confidence: 0.9, CacheKey: "k2"},
+		{WorkItemID: 3, SectionID: "S0_0", Confidence: 0.9, CacheKey: "k3"},
+	}); err != nil {
+		t.Fatalf("InsertClassificationHistory failed: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE classification_history SET classified_at = datetime('now', '-1 hour')`); err != nil {
+		t.Fatalf("backdate history failed: %v", err)
+	}
+	if err := InsertClassificationHistory(db, []ClassificationRecord{
+		{WorkItemID: 1, SectionID: "S2_0", Confidence: 0.95, CacheKey: "k1", AlternativeSectionIDs: "S0_0"},
+	}); err != nil {
+		t.Fatalf("InsertClassificationHistory failed: %v", err)
+	}
+	if err := InsertClassificationCorrection(db, ClassificationCorrection{
+		WorkItemID: 3, OriginalSectionID: "S0_0", CorrectedSectionID: "S1_0",
+	}); err != nil {
+		t.Fatalf("InsertClassificationCorrection failed: %v", err)
+	}
+
+	got, err := GetCachedClassifications(db, TeamScope{}, []string{"k1", "k2", "k3", "missing"})
+	if err != nil {
+		t.Fatalf("GetCachedClassifications failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected k1 and k2 only, got %+v", got)
+	}
+	if got["k1"].SectionID != "S2_0" || got["k1"].AlternativeSectionIDs != "S0_0" {
+		t.Fatalf("expected newest decision for k1, got %+v", got["k1"])
+	}
+	if got["k2"].WorkItemID != 2 {
+		t.Fatalf("unexpected k2 record: %+v", got["k2"])
+	}
+}
+
+func TestLLMUsageSpendAndSummary(t *testing.T) {
+	db := newTestDB(t)
+	now := time.Now()
+
+	for _, rec := range []LLMUsageRecord{
+		{Provider: "openai", Model: "gpt-5-mini", Purpose: "classify", InputTokens: 1000, OutputTokens: 100, CostUSD: 0.5, CreatedAt: now},
+		{Provider: "openai", Model: "gpt-5-mini", Purpose: "classify", InputTokens: 2000, OutputTokens: 200, CostUSD: 1.0, CreatedAt: now},
+		{Provider: "openai", Model: "gpt-5-mini", Purpose: "critic", InputTokens: 500, OutputTokens: 50, CostUSD: 0.25, CreatedAt: now},
+		{Provider: "openai", Model: "gpt-5-mini", Purpose: "classify", InputTokens: 9999, CostUSD: 9, CreatedAt: now.AddDate(0, 0, -40)},
+	} {
+		if err := InsertLLMUsage(db, TeamScope{}, rec); err != nil {
+			t.Fatalf("InsertLLMUsage failed: %v", err)
+		}
+	}
+
+	since := now.AddDate(0, 0, -7)
+	spend, err := GetLLMSpendSince(db, TeamScope{}, since)
+	if err != nil {
+		t.Fatalf("GetLLMSpendSince failed: %v", err)
+	}
+	if spend != 1.75 {
+		t.Fatalf("expected 1.75 spend in range, got %v", spend)
+	}
+
+	summary, err := GetLLMUsageSummary(db, TeamScope{}, since)
+	if err != nil {
+		t.Fatalf("GetLLMUsageSummary failed: %v", err)
+	}
+	if len(summary) != 2 || summary[0].Purpose != "classify" || summary[0].Calls != 2 || summary[0].InputTokens != 3000 || summary[0].OutputTokens != 300 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+}
+
+func TestLLMUsageSpendComparesAcrossTimeZones(t *testing.T) {
+	db := newTestDB(t)
+	tokyo := time.FixedZone("JST", 9*3600)
+	newYork := time.FixedZone("EST", -5*3600)
+	now := time.Now()
+
+	// Recorded an hour ago in Tokyo time, queried from New York: the stored
+	// text only compares correctly when both sides are UTC.
+	if err := InsertLLMUsage(db, TeamScope{}, LLMUsageRecord{Provider: "openai", Model: "gpt-5-mini", Purpose: "classify", CostUSD: 0.5, CreatedAt: now.Add(-time.Hour).In(tokyo)}); err != nil {
+		t.Fatalf("InsertLLMUsage failed: %v", err)
+	}
+	spend, err := GetLLMSpendSince(db, TeamScope{}, now.Add(-2*time.Hour).In(newYork))
+	if err != nil {
+		t.Fatalf("GetLLMSpendSince failed: %v", err)
+	}
+	if spend != 0.5 {
+		t.Fatalf("expected the record in range, got spend %v", spend)
+	}
+	spend, err = GetLLMSpendSince(db, TeamScope{}, now.In(newYork))
+	if err != nil {
+		t.Fatalf("GetLLMSpendSince failed: %v", err)
+	}
+	if spend != 0 {
+		t.Fatalf("expected no spend after the record, got %v", spend)
+	}
+}
@@ -0,0 +1,107 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// GetEmbeddings returns cached vectors for the given work items under model,
+// keyed by work item ID. Items without a cached vector are absent.
+func GetEmbeddings(db *sql.DB, model string, workItemIDs []int64) (map[int64]Embedding, error) {
+	out := make(map[int64]Embedding, len(workItemIDs))
+	// Stay well under SQLite's bound-parameter limit.
+	const chunk = 500
+	for start := 0; start < len(workItemIDs); start += chunk {
+		end := start + chunk
+		if end > len(workItemIDs) {
+			end = len(workItemIDs)
+		}
+		ids := workItemIDs[start:end]
+		args := make([]any, 0, len(ids)+1)
+		args = append(args, model)
+		for _, id := range ids {
+			args = append(args, id)
+		}
+		rows, err := db.Query(
+			`SELECT work_item_id, text_hash, dims, vector
+			 FROM work_item_embeddings
+			 WHERE model = ? AND work_item_id IN (?`+strings.Repeat(",?", len(ids)-1)+`)`,
+			args...,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var e Embedding
+			var dims int
+			var blob []byte
+			if err := rows.Scan(&e.WorkItemID, &e.TextHash, &dims, &blob); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			vec, err := decodeVector(blob, dims)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("embedding for work item %d: %w", e.WorkItemID, err)
+			}
+			e.Model = model
+			e.Vector = vec
+			out[e.WorkItemID] = e
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+	}
+	return out, nil
+}
+
+// SaveEmbeddings stores vectors, replacing any earlier vector for the same
+// work item and model.
+func SaveEmbeddings(db *sql.DB, embeddings []Embedding) error {
+	if len(embeddings) == 0 {
+		return nil
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.Prepare(
+		`INSERT OR REPLACE INTO work_item_embeddings (work_item_id, model, text_hash, dims, vector)
+		 VALUES (?, ?, ?, ?, ?)`,
+	)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, e := range embeddings {
+		if _, err := stmt.Exec(e.WorkItemID, e.Model, e.TextHash, len(e.Vector), encodeVector(e.Vector)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func encodeVector(vec []float32) []byte {
+	buf := make([]byte, 4*len(vec))
+	for i, v := range vec {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return buf
+}
+
+func decodeVector(blob []byte, dims int) ([]float32, error) {
+	if len(blob) != 4*dims {
+		return nil, fmt.Errorf("vector is %d bytes, want %d", len(blob), 4*dims)
+	}
+	vec := make([]float32, dims)
+	for i := range vec {
+		vec[i] = math.Float32frombits(binary.LittleEndian.Uint32(blob[4*i:]))
+	}
+	return vec, nil
+}
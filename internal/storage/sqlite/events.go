@@ -0,0 +1,180 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// workItemChange sets one work_items column and is recorded as an event of
+// eventType. Unchanged values are skipped unless always is set.
+type workItemChange struct {
+	column    string // description, status or category
+	eventType string
+	value     string
+	always    bool
+}
+
+func updateWorkItem(db *sql.DB, team TeamScope, id int64, actorID string, changes ...workItemChange) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := applyWorkItemChanges(tx, team, id, actorID, changes...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// applyWorkItemChanges updates a live item and records an event per changed
+// value with the old and new value. It returns sql.ErrNoRows for missing or
+// deleted items and for items of another team.
+func applyWorkItemChanges(tx *sql.Tx, team TeamScope, id int64, actorID string, changes ...workItemChange) error {
+	filter, filterArgs := teamFilter(team, "team_id")
+	var description, status, category string
+	err := tx.QueryRow(
+		`SELECT description, status, category FROM work_items WHERE id = ? AND deleted_at IS NULL`+filter,
+		append([]any{id}, filterArgs...)...,
+	).Scan(&description, &status, &category)
+	if err != nil {
+		return err
+	}
+	current := map[string]string{"description": description, "status": status, "category": category}
+	for _, c := range changes {
+		old, ok := current[c.column]
+		if !ok {
+			return fmt.Errorf("unsupported work item column %q", c.column)
+		}
+		if old == c.value && !c.always {
+			continue
+		}
+		if old != c.value {
+			if _, err := tx.Exec(`UPDATE work_items SET `+c.column+` = ? WHERE id = ?`, c.value, id); err != nil {
+				return err
+			}
+			current[c.column] = c.value
+		}
+		if err := insertWorkItemEvent(tx, id, c.eventType, old, c.value, actorID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertWorkItemEvent(tx *sql.Tx, workItemID int64, eventType, oldValue, newValue, actorID string) error {
+	_, err := tx.Exec(
+		`INSERT INTO work_item_events (work_item_id, event_type, old_value, new_value, actor_id, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		workItemID, eventType, oldValue, newValue, actorID, time.Now().UTC(),
+	)
+	return err
+}
+
+// setWorkItemDeleted soft-deletes or restores an item. Deleting an already
+// deleted item is a no-op; restoring a live or missing item is an error.
+// Items of another team count as missing.
+func setWorkItemDeleted(db *sql.DB, team TeamScope, id int64, deleted bool, actorID string) error {
+	filter, filterArgs := teamFilter(team, "team_id")
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var description string
+	var deletedAt sql.NullTime
+	err = tx.QueryRow(`SELECT description, deleted_at FROM work_items WHERE id = ?`+filter, append([]any{id}, filterArgs...)...).Scan(&description, &deletedAt)
+	if err == sql.ErrNoRows && deleted {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if deletedAt.Valid == deleted {
+		if deleted {
+			return nil
+		}
+		return fmt.Errorf("work item %d is not deleted", id)
+	}
+
+	if deleted {
+		_, err = tx.Exec(`UPDATE work_items SET deleted_at = ? WHERE id = ?`, time.Now().UTC(), id)
+		if err == nil {
+			err = insertWorkItemEvent(tx, id, WorkItemEventDeleted, description, "", actorID)
+		}
+	} else {
+		_, err = tx.Exec(`UPDATE work_items SET deleted_at = NULL WHERE id = ?`, id)
+		if err == nil {
+			err = insertWorkItemEvent(tx, id, WorkItemEventRestored, "", description, actorID)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RestoreWorkItem undoes a soft delete.
+func RestoreWorkItem(db *sql.DB, team TeamScope, id int64, actorID string) error {
+	return setWorkItemDeleted(db, team, id, false, actorID)
+}
+
+// GetWorkItemEvents returns the audit log of one item, oldest first. Events
+// of deleted items are included.
+func GetWorkItemEvents(db *sql.DB, team TeamScope, workItemID int64) ([]WorkItemEvent, error) {
+	filter, filterArgs := itemTeamFilter(team, "work_item_id")
+	rows, err := db.Query(
+		`SELECT id, work_item_id, event_type, old_value, new_value, actor_id, created_at
+		 FROM work_item_events
+		 WHERE work_item_id = ?`+filter+`
+		 ORDER BY created_at, id`,
+		append([]any{workItemID}, filterArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []WorkItemEvent
+	for rows.Next() {
+		var e WorkItemEvent
+		if err := rows.Scan(&e.ID, &e.WorkItemID, &e.EventType, &e.OldValue, &e.NewValue, &e.ActorID, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// GetDeletedItemsByDateRange returns soft-deleted items reported in
+// [from, to), most recently reported first.
+func GetDeletedItemsByDateRange(db *sql.DB, team TeamScope, from, to time.Time) ([]WorkItem, error) {
+	filter, filterArgs := teamFilter(team, "team_id")
+	rows, err := db.Query(
+		`SELECT id, description, author, author_id, source, source_ref, category, status, ticket_ids, reported_at, created_at, team_id
+		 FROM work_items
+		 WHERE reported_at >= ? AND reported_at < ? AND deleted_at IS NOT NULL`+filter+`
+		 ORDER BY reported_at DESC, id DESC`,
+		append([]any{from, to}, filterArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []WorkItem
+	for rows.Next() {
+		var item WorkItem
+		err := rows.Scan(
+			&item.ID, &item.Description, &item.Author, &item.AuthorID, &item.Source,
+			&item.SourceRef, &item.Category, &item.Status, &item.TicketIDs,
+			&item.ReportedAt, &item.CreatedAt, &item.TeamID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
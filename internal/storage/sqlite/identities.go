@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"reportbot/internal/domain"
+	"time"
+)
+
+type Identity = domain.Identity
+
+// identityColumn names the account and confirmation columns of one kind.
+type identityColumn struct {
+	account, confirmed string
+}
+
+// identityColumns maps account kinds to their identities columns.
+var identityColumns = map[string]identityColumn{
+	domain.IdentityGitLab: {"gitlab_username", "gitlab_confirmed"},
+	domain.IdentityGitHub: {"github_login", "github_confirmed"},
+	domain.IdentityEmail:  {"email", "email_confirmed"},
+}
+
+const identitySelect = `SELECT slack_id, gitlab_username, github_login, email,
+	gitlab_confirmed, github_confirmed, email_confirmed, updated_at
+	FROM identities`
+
+func scanIdentity(row interface{ Scan(...any) error }) (Identity, error) {
+	var id Identity
+	err := row.Scan(&id.SlackID, &id.GitLabUsername, &id.GitHubLogin, &id.Email,
+		&id.GitLabConfirmed, &id.GitHubConfirmed, &id.EmailConfirmed, &id.UpdatedAt)
+	return id, err
+}
+
+// GetIdentity returns slackID's identity, or sql.ErrNoRows if they linked
+// nothing.
+func GetIdentity(db *sql.DB, slackID string) (Identity, error) {
+	return scanIdentity(db.QueryRow(identitySelect+` WHERE slack_id = ?`, slackID))
+}
+
+func ListIdentities(db *sql.DB) ([]Identity, error) {
+	rows, err := db.Query(identitySelect + ` ORDER BY slack_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Identity
+	for rows.Next() {
+		id, err := scanIdentity(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+// LinkIdentity sets the account of the given kind on slackID's identity,
+// creating it if needed; an empty account unlinks that kind. It returns
+// domain.ErrIdentityTaken when another Slack user linked the account. A
+// new account is unconfirmed; relinking the same one keeps its
+// confirmation.
+func LinkIdentity(db *sql.DB, slackID, kind, account string) error {
+	return linkIdentity(db, slackID, kind, account, false)
+}
+
+// ReassignIdentity is LinkIdentity for managers: the account is confirmed,
+// and one another Slack user linked is moved to slackID instead of refused.
+func ReassignIdentity(db *sql.DB, slackID, kind, account string) error {
+	return linkIdentity(db, slackID, kind, account, true)
+}
+
+func linkIdentity(db *sql.DB, slackID, kind, account string, manager bool) error {
+	col, ok := identityColumns[kind]
+	if !ok {
+		return fmt.Errorf("unknown account kind %q", kind)
+	}
+	account = domain.NormalizeAccount(account)
+	confirmed := manager && account != ""
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if account != "" && manager {
+		if _, err := tx.Exec(
+			`UPDATE identities SET `+col.account+` = '', `+col.confirmed+` = 0, updated_at = ?
+			 WHERE `+col.account+` = ? AND slack_id <> ?`,
+			time.Now(), account, slackID,
+		); err != nil {
+			return err
+		}
+	} else if account != "" {
+		var owner string
+		err := tx.QueryRow(`SELECT slack_id FROM identities WHERE `+col.account+` = ? AND slack_id <> ?`, account, slackID).Scan(&owner)
+		if err == nil {
+			return domain.ErrIdentityTaken
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO identities (slack_id, `+col.account+`, `+col.confirmed+`, updated_at) VALUES (?, ?, ?, ?)
+		 ON CONFLICT(slack_id) DO UPDATE SET
+		   `+col.confirmed+` = excluded.`+col.confirmed+` OR (identities.`+col.account+` = excluded.`+col.account+` AND identities.`+col.confirmed+`),
+		   `+col.account+` = excluded.`+col.account+`,
+		   updated_at = excluded.updated_at`,
+		slackID, account, confirmed, time.Now(),
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
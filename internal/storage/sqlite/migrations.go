@@ -0,0 +1,200 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"reportbot/internal/storage/migrate"
+)
+
+var dialect = migrate.Dialect{
+	CreateTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at DATETIME NOT NULL
+	)`,
+	Insert: `INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+}
+
+// migrations is the SQLite schema history. Append new entries; never edit or
+// renumber one that has been released.
+var migrations = []migrate.Migration{
+	{Version: 1, Name: "baseline", Up: migrateBaseline},
+	{Version: 2, Name: "unique_source_ref", Up: migrate.Exec(
+		// Remove duplicate external items before adding the uniqueness constraint.
+		`DELETE FROM work_items
+		 WHERE source_ref <> ''
+		   AND id NOT IN (
+		     SELECT MIN(id)
+		     FROM work_items
+		     WHERE source_ref <> ''
+		     GROUP BY source, source_ref
+		   )`,
+		// Enforce uniqueness for externally sourced references (MR/PR URL) at DB level.
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_work_items_unique_source_ref
+		 ON work_items(source, source_ref)
+		 WHERE source_ref <> ''`,
+	)},
+	{Version: 3, Name: "work_item_events", Up: migrate.Exec(
+		`CREATE TABLE work_item_events (
+			id           INTEGER PRIMARY KEY AUTOINCREMENT,
+			work_item_id INTEGER NOT NULL,
+			event_type   TEXT NOT NULL,
+			old_value    TEXT NOT NULL DEFAULT '',
+			new_value    TEXT NOT NULL DEFAULT '',
+			actor_id     TEXT NOT NULL DEFAULT '',
+			created_at   DATETIME NOT NULL
+		)`,
+		`CREATE INDEX idx_work_item_events_item ON work_item_events(work_item_id, created_at)`,
+		`ALTER TABLE work_items ADD COLUMN deleted_at DATETIME`,
+	)},
+	{Version: 4, Name: "work_item_team", Up: migrate.Exec(
+		`ALTER TABLE work_items ADD COLUMN team_id TEXT NOT NULL DEFAULT ''`,
+		`CREATE INDEX idx_work_items_team_reported_at ON work_items(team_id, reported_at)`,
+	)},
+	{Version: 5, Name: "identities", Up: migrate.Exec(
+		`CREATE TABLE identities (
+			slack_id         TEXT PRIMARY KEY,
+			gitlab_username  TEXT NOT NULL DEFAULT '',
+			github_login     TEXT NOT NULL DEFAULT '',
+			email            TEXT NOT NULL DEFAULT '',
+			gitlab_confirmed INTEGER NOT NULL DEFAULT 0,
+			github_confirmed INTEGER NOT NULL DEFAULT 0,
+			email_confirmed  INTEGER NOT NULL DEFAULT 0,
+			updated_at       DATETIME NOT NULL
+		)`,
+		// An account belongs to one Slack user.
+		`CREATE UNIQUE INDEX idx_identities_gitlab ON identities(gitlab_username) WHERE gitlab_username <> ''`,
+		`CREATE UNIQUE INDEX idx_identities_github ON identities(github_login) WHERE github_login <> ''`,
+		`CREATE UNIQUE INDEX idx_identities_email ON identities(email) WHERE email <> ''`,
+	)},
+	{Version: 6, Name: "llm_usage_team", Up: migrate.Exec(
+		`ALTER TABLE llm_usage ADD COLUMN team_id TEXT NOT NULL DEFAULT ''`,
+		`CREATE INDEX idx_llm_usage_team_created ON llm_usage(team_id, created_at)`,
+	)},
+	{Version: 7, Name: "unique_source_ref_per_team", Up: migrate.Exec(
+		// Teams track a shared MR/PR independently, so an external reference
+		// is unique within a team rather than across the database.
+		`DROP INDEX IF EXISTS idx_work_items_unique_source_ref`,
+		`CREATE UNIQUE INDEX idx_work_items_unique_team_source_ref
+		 ON work_items(team_id, source, source_ref)
+		 WHERE source_ref <> ''`,
+	)},
+}
+
+// Migrate applies pending migrations and returns the ones it applied.
+func Migrate(db *sql.DB) ([]migrate.Status, error) {
+	return migrate.Up(db, dialect, migrations)
+}
+
+// ListMigrations reports known and applied migrations without changing the
+// schema.
+func ListMigrations(db *sql.DB) ([]migrate.Status, error) {
+	return migrate.List(db, dialect, migrations)
+}
+
+// LatestMigration is the schema version this binary migrates to.
+func LatestMigration() int { return migrate.Latest(migrations) }
+
+// migrateBaseline creates the schema as it stood before versioned
+// migrations. Databases created by earlier releases already have some or all
+// of it, so tables are created only if missing and columns added by those
+// releases are added only if absent.
+func migrateBaseline(tx *sql.Tx) error {
+	if err := migrate.Exec(`
+	CREATE TABLE IF NOT EXISTS work_items (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		description TEXT NOT NULL,
+		author      TEXT NOT NULL,
+		source      TEXT NOT NULL DEFAULT 'slack',
+		source_ref  TEXT DEFAULT '',
+		category    TEXT DEFAULT '',
+		status      TEXT DEFAULT 'done',
+		ticket_ids  TEXT DEFAULT '',
+		reported_at DATETIME NOT NULL,
+		created_at  DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_work_items_reported_at ON work_items(reported_at);
+	CREATE INDEX IF NOT EXISTS idx_work_items_author ON work_items(author);
+
+	CREATE TABLE IF NOT EXISTS classification_history (
+		id               INTEGER PRIMARY KEY AUTOINCREMENT,
+		work_item_id     INTEGER NOT NULL,
+		section_id       TEXT NOT NULL,
+		section_label    TEXT DEFAULT '',
+		confidence       REAL NOT NULL,
+		normalized_status TEXT DEFAULT '',
+		ticket_ids       TEXT DEFAULT '',
+		duplicate_of     TEXT DEFAULT '',
+		llm_provider     TEXT DEFAULT '',
+		llm_model        TEXT DEFAULT '',
+		classified_at    DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_ch_work_item ON classification_history(work_item_id);
+	CREATE INDEX IF NOT EXISTS idx_ch_date ON classification_history(classified_at);
+
+	CREATE TABLE IF NOT EXISTS classification_corrections (
+		id                   INTEGER PRIMARY KEY AUTOINCREMENT,
+		work_item_id         INTEGER NOT NULL,
+		original_section_id  TEXT NOT NULL,
+		original_label       TEXT DEFAULT '',
+		corrected_section_id TEXT NOT NULL,
+		corrected_label      TEXT DEFAULT '',
+		description          TEXT DEFAULT '',
+		corrected_by         TEXT DEFAULT '',
+		corrected_at         DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_cc_date ON classification_corrections(corrected_at);
+
+	CREATE TABLE IF NOT EXISTS work_item_embeddings (
+		work_item_id INTEGER NOT NULL,
+		model        TEXT NOT NULL,
+		text_hash    TEXT NOT NULL,
+		dims         INTEGER NOT NULL,
+		vector       BLOB NOT NULL,
+		created_at   DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (work_item_id, model)
+	);
+
+	CREATE TABLE IF NOT EXISTS llm_usage (
+		id                          INTEGER PRIMARY KEY AUTOINCREMENT,
+		provider                    TEXT NOT NULL,
+		model                       TEXT NOT NULL,
+		purpose                     TEXT NOT NULL,
+		input_tokens                INTEGER NOT NULL DEFAULT 0,
+		output_tokens               INTEGER NOT NULL DEFAULT 0,
+		cache_creation_input_tokens INTEGER NOT NULL DEFAULT 0,
+		cache_read_input_tokens     INTEGER NOT NULL DEFAULT 0,
+		cost_usd                    REAL NOT NULL DEFAULT 0,
+		created_at                  DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_llm_usage_created ON llm_usage(created_at);
+	`)(tx); err != nil {
+		return err
+	}
+
+	for _, col := range []struct{ table, name, ddl string }{
+		{"work_items", "author_id", `ALTER TABLE work_items ADD COLUMN author_id TEXT DEFAULT ''`},
+		{"classification_history", "raw_confidence", `ALTER TABLE classification_history ADD COLUMN raw_confidence REAL DEFAULT 0`},
+		{"classification_history", "alternative_section_ids", `ALTER TABLE classification_history ADD COLUMN alternative_section_ids TEXT DEFAULT ''`},
+		{"classification_history", "cache_key", `ALTER TABLE classification_history ADD COLUMN cache_key TEXT DEFAULT ''`},
+	} {
+		exists, err := columnExists(tx, col.table, col.name)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			if _, err := tx.Exec(col.ddl); err != nil {
+				return fmt.Errorf("add %s.%s: %w", col.table, col.name, err)
+			}
+		}
+	}
+
+	_, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_ch_cache_key ON classification_history(cache_key)`)
+	return err
+}
+
+func columnExists(tx *sql.Tx, table, column string) (bool, error) {
+	var count int
+	err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column).Scan(&count)
+	return count > 0, err
+}
@@ -0,0 +1,147 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"reportbot/internal/storage/migrate"
+	"testing"
+	"time"
+)
+
+func TestMigrateRecordsVersionsAndIsIdempotent(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "reportbot-test.db")
+	db, err := InitDB(dbPath)
+	if err != nil {
+		t.Fatalf("InitDB failed: %v", err)
+	}
+	statuses, err := ListMigrations(db)
+	if err != nil {
+		t.Fatalf("ListMigrations failed: %v", err)
+	}
+	if len(statuses) != LatestMigration() {
+		t.Fatalf("expected %d migrations, got %+v", LatestMigration(), statuses)
+	}
+	for _, s := range statuses {
+		if !s.Applied || !s.Known || s.AppliedAt.IsZero() {
+			t.Fatalf("expected migration %d to be applied, got %+v", s.Version, s)
+		}
+	}
+	_ = db.Close()
+
+	db, err = InitDB(dbPath)
+	if err != nil {
+		t.Fatalf("re-opening InitDB failed: %v", err)
+	}
+	defer db.Close()
+	applied, err := Migrate(db)
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if len(applied) != 0 {
+		t.Fatalf("expected nothing to apply on an up-to-date schema, got %+v", applied)
+	}
+	var rows int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&rows); err != nil {
+		t.Fatalf("count schema_migrations: %v", err)
+	}
+	if rows != LatestMigration() {
+		t.Fatalf("expected %d schema_migrations rows, got %d", LatestMigration(), rows)
+	}
+}
+
+func TestMigrateAdoptsPreVersioningDatabase(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "legacy.db")
+	rawDB, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("sql.Open failed: %v", err)
+	}
+	// A database from a release before schema_migrations: author_id and the
+	// unique index already exist, the classification_history columns do not.
+	_, err = rawDB.Exec(`
+		CREATE TABLE work_items (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			description TEXT NOT NULL,
+			author      TEXT NOT NULL,
+			source      TEXT NOT NULL DEFAULT 'slack',
+			source_ref  TEXT DEFAULT '',
+			category    TEXT DEFAULT '',
+			status      TEXT DEFAULT 'done',
+			ticket_ids  TEXT DEFAULT '',
+			reported_at DATETIME NOT NULL,
+			created_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+			author_id   TEXT DEFAULT ''
+		);
+		CREATE UNIQUE INDEX idx_work_items_unique_source_ref ON work_items(source, source_ref) WHERE source_ref <> '';
+		CREATE TABLE classification_history (
+			id           INTEGER PRIMARY KEY AUTOINCREMENT,
+			work_item_id INTEGER NOT NULL,
+			section_id   TEXT NOT NULL,
+			section_label TEXT DEFAULT '',
+			confidence   REAL NOT NULL,
+			normalized_status TEXT DEFAULT '',
+			ticket_ids   TEXT DEFAULT '',
+			duplicate_of TEXT DEFAULT '',
+			llm_provider TEXT DEFAULT '',
+			llm_model    TEXT DEFAULT '',
+			classified_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+	`)
+	if err != nil {
+		_ = rawDB.Close()
+		t.Fatalf("create legacy schema failed: %v", err)
+	}
+	if _, err := rawDB.Exec(
+		`INSERT INTO work_items (description, author, author_id, source, reported_at) VALUES (?, ?, ?, ?, ?)`,
+		"Legacy item", "Alice", "U001", "slack", time.Now().UTC(),
+	); err != nil {
+		_ = rawDB.Close()
+		t.Fatalf("seed legacy row failed: %v", err)
+	}
+	_ = rawDB.Close()
+
+	db, err := InitDB(dbPath)
+	if err != nil {
+		t.Fatalf("InitDB on legacy database failed: %v", err)
+	}
+	defer db.Close()
+
+	if err := InsertClassificationHistory(db, []ClassificationRecord{{
+		WorkItemID: 1, SectionID: "S0_0", Confidence: 0.9, RawConfidence: 0.8, CacheKey: "k",
+	}}); err != nil {
+		t.Fatalf("expected added columns to be usable: %v", err)
+	}
+	item, err := GetWorkItemByID(db, TeamScope{}, 1)
+	if err != nil || item.AuthorID != "U001" {
+		t.Fatalf("expected legacy row to survive migration, got %+v err=%v", item, err)
+	}
+}
+
+func TestMigrateRefusesNewerSchema(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "newer.db")
+	db, err := InitDB(dbPath)
+	if err != nil {
+		t.Fatalf("InitDB failed: %v", err)
+	}
+	future := LatestMigration() + 1
+	if _, err := db.Exec(
+		`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+		future, "from_the_future", time.Now().UTC(),
+	); err != nil {
+		t.Fatalf("insert future migration: %v", err)
+	}
+
+	statuses, err := ListMigrations(db)
+	if err != nil {
+		t.Fatalf("ListMigrations failed: %v", err)
+	}
+	last := statuses[len(statuses)-1]
+	if last.Version != future || last.Known || !last.Applied || last.Name != "from_the_future" {
+		t.Fatalf("expected unknown applied version %d last, got %+v", future, last)
+	}
+	_ = db.Close()
+
+	if _, err := InitDB(dbPath); !errors.Is(err, migrate.ErrSchemaTooNew) {
+		t.Fatalf("expected ErrSchemaTooNew, got %v", err)
+	}
+}
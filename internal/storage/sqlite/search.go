@@ -0,0 +1,186 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"reportbot/internal/domain"
+	"strings"
+)
+
+type SearchQuery = domain.SearchQuery
+
+// The FTS5 index over work_items is derived data, so it is maintained here
+// rather than by a numbered migration: FTS5 is only compiled into
+// go-sqlite3 with `-tags sqlite_fts5`, and a database must stay writable by
+// builds without it. Those builds drop the sync triggers and search with
+// LIKE instead; the next FTS5 build recreates them and rebuilds the index.
+const searchIndexSchema = `
+CREATE VIRTUAL TABLE IF NOT EXISTS work_items_fts USING fts5(
+	description, author, ticket_ids, source_ref,
+	content='work_items', content_rowid='id'
+);
+CREATE TRIGGER IF NOT EXISTS work_items_fts_ai AFTER INSERT ON work_items BEGIN
+	INSERT INTO work_items_fts(rowid, description, author, ticket_ids, source_ref)
+	VALUES (new.id, new.description, new.author, new.ticket_ids, new.source_ref);
+END;
+CREATE TRIGGER IF NOT EXISTS work_items_fts_ad AFTER DELETE ON work_items BEGIN
+	INSERT INTO work_items_fts(work_items_fts, rowid, description, author, ticket_ids, source_ref)
+	VALUES ('delete', old.id, old.description, old.author, old.ticket_ids, old.source_ref);
+END;
+CREATE TRIGGER IF NOT EXISTS work_items_fts_au AFTER UPDATE OF description, author, ticket_ids, source_ref ON work_items BEGIN
+	INSERT INTO work_items_fts(work_items_fts, rowid, description, author, ticket_ids, source_ref)
+	VALUES ('delete', old.id, old.description, old.author, old.ticket_ids, old.source_ref);
+	INSERT INTO work_items_fts(rowid, description, author, ticket_ids, source_ref)
+	VALUES (new.id, new.description, new.author, new.ticket_ids, new.source_ref);
+END;
+`
+
+var searchIndexTriggers = []string{"work_items_fts_ai", "work_items_fts_ad", "work_items_fts_au"}
+
+// ftsAvailable reports whether this SQLite build includes FTS5.
+func ftsAvailable(db *sql.DB) bool {
+	var enabled int
+	if err := db.QueryRow(`SELECT sqlite_compileoption_used('ENABLE_FTS5')`).Scan(&enabled); err != nil {
+		return false
+	}
+	return enabled == 1
+}
+
+// ensureSearchIndex creates the FTS5 index and its triggers, rebuilding the
+// index whenever a trigger was missing (new index, or writes by a build
+// without FTS5).
+func ensureSearchIndex(db *sql.DB) error {
+	var triggers int
+	err := db.QueryRow(
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name IN (?, ?, ?)`,
+		searchIndexTriggers[0], searchIndexTriggers[1], searchIndexTriggers[2],
+	).Scan(&triggers)
+	if err != nil {
+		return err
+	}
+
+	if !ftsAvailable(db) {
+		for _, name := range searchIndexTriggers {
+			if _, err := db.Exec(`DROP TRIGGER IF EXISTS ` + name); err != nil {
+				return err
+			}
+		}
+		if triggers > 0 {
+			log.Printf("sqlite built without FTS5: dropped search index triggers; /search uses LIKE")
+		}
+		return nil
+	}
+	if triggers == len(searchIndexTriggers) {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(searchIndexSchema); err != nil {
+		return fmt.Errorf("create search index: %w", err)
+	}
+	if _, err := tx.Exec(`INSERT INTO work_items_fts(work_items_fts) VALUES ('rebuild')`); err != nil {
+		return fmt.Errorf("rebuild search index: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	log.Printf("sqlite search index rebuilt")
+	return nil
+}
+
+// SearchWorkItems returns live work items of team matching q, newest first.
+func SearchWorkItems(db *sql.DB, team TeamScope, q SearchQuery) ([]WorkItem, error) {
+	var where []string
+	var args []any
+
+	terms := strings.Fields(q.Text)
+	if len(terms) > 0 {
+		if ftsAvailable(db) {
+			where = append(where, `id IN (SELECT rowid FROM work_items_fts WHERE work_items_fts MATCH ?)`)
+			args = append(args, ftsMatchExpr(terms))
+		} else {
+			for _, term := range terms {
+				where = append(where, `lower(description || ' ' || author || ' ' || COALESCE(ticket_ids, '') || ' ' || COALESCE(source_ref, '')) LIKE ? ESCAPE '\'`)
+				args = append(args, likePattern(term))
+			}
+		}
+	}
+	if q.Author != "" {
+		where = append(where, `lower(author) LIKE ? ESCAPE '\'`)
+		args = append(args, likePattern(q.Author))
+	}
+	if q.AuthorID != "" {
+		where = append(where, `author_id = ?`)
+		args = append(args, q.AuthorID)
+	}
+	if q.Status != "" {
+		where = append(where, `lower(trim(status)) = ?`)
+		args = append(args, strings.ToLower(strings.TrimSpace(q.Status)))
+	}
+	if !q.Since.IsZero() {
+		where = append(where, `reported_at >= ?`)
+		args = append(args, q.Since)
+	}
+	where = append(where, `deleted_at IS NULL`)
+	if team.Scoped {
+		where = append(where, `team_id = ?`)
+		args = append(args, team.TeamID)
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	args = append(args, limit, q.Offset)
+
+	rows, err := db.Query(
+		`SELECT id, description, author, author_id, source, source_ref, category, status, ticket_ids, reported_at, created_at, team_id
+		 FROM work_items
+		 WHERE `+strings.Join(where, " AND ")+`
+		 ORDER BY reported_at DESC, id DESC
+		 LIMIT ? OFFSET ?`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []WorkItem
+	for rows.Next() {
+		var item WorkItem
+		err := rows.Scan(
+			&item.ID, &item.Description, &item.Author, &item.AuthorID, &item.Source,
+			&item.SourceRef, &item.Category, &item.Status, &item.TicketIDs,
+			&item.ReportedAt, &item.CreatedAt, &item.TeamID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// ftsMatchExpr turns words into an FTS5 query that requires every word as
+// a prefix phrase, so user input never reaches the FTS5 query syntax.
+func ftsMatchExpr(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"*`
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// likePattern matches s anywhere, case-insensitively, with LIKE wildcards
+// in s escaped.
+func likePattern(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+	return "%" + s + "%"
+}
@@ -0,0 +1,42 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFTSMatchExprQuotesUserInput(t *testing.T) {
+	got := ftsMatchExpr([]string{"login", `say"hi`, "OR"})
+	want := `"login"* AND "say""hi"* AND "OR"*`
+	if got != want {
+		t.Fatalf("ftsMatchExpr = %s, want %s", got, want)
+	}
+}
+
+func TestEnsureSearchIndexRebuildsAfterWritesWithoutTriggers(t *testing.T) {
+	db := newTestDB(t)
+	if !ftsAvailable(db) {
+		t.Skip("built without -tags sqlite_fts5")
+	}
+
+	// Simulate a build without FTS5 writing to the database.
+	for _, name := range searchIndexTriggers {
+		if _, err := db.Exec(`DROP TRIGGER ` + name); err != nil {
+			t.Fatalf("drop trigger: %v", err)
+		}
+	}
+	if err := InsertWorkItem(db, WorkItem{Description: "Rotate gateway certificates", Author: "Alice", Source: "slack", Status: "done", ReportedAt: time.Now()}, ""); err != nil {
+		t.Fatalf("InsertWorkItem: %v", err)
+	}
+	if items, _ := SearchWorkItems(db, TeamScope{}, SearchQuery{Text: "gateway"}); len(items) != 0 {
+		t.Fatalf("expected stale index before rebuild, got %+v", items)
+	}
+
+	if err := ensureSearchIndex(db); err != nil {
+		t.Fatalf("ensureSearchIndex: %v", err)
+	}
+	items, err := SearchWorkItems(db, TeamScope{}, SearchQuery{Text: "gateway"})
+	if err != nil || len(items) != 1 {
+		t.Fatalf("expected rebuilt index to find the item, got %+v err=%v", items, err)
+	}
+}
@@ -0,0 +1,18 @@
+package sqlite
+
+import (
+	"database/sql"
+	"reportbot/internal/domain"
+	"reportbot/internal/storage/snapshot"
+)
+
+// ExportState reads live items with their classification history and
+// corrections for `reportbot export`.
+func ExportState(db *sql.DB) (domain.StateSnapshot, error) {
+	return snapshot.Export(db)
+}
+
+// ImportState restores a snapshot idempotently; see snapshot.Import.
+func ImportState(db *sql.DB, snap domain.StateSnapshot) (domain.ImportResult, error) {
+	return snapshot.Import(db, snapshot.SQLite, snap)
+}
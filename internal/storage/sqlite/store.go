@@ -0,0 +1,231 @@
+package sqlite
+
+import (
+	"database/sql"
+	"reportbot/internal/domain"
+	"reportbot/internal/storage/migrate"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Store implements storage.Store on top of the package's free functions.
+type Store struct {
+	DB   *sql.DB
+	team TeamScope
+}
+
+// Open initializes the SQLite database at path and wraps it in a Store.
+func Open(path string) (*Store, error) {
+	db, err := InitDB(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{DB: db}, nil
+}
+
+// OpenUnmigrated opens the database at path without touching its schema,
+// for inspecting or applying migrations explicitly.
+func OpenUnmigrated(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{DB: db}, nil
+}
+
+func (s *Store) Close() error { return s.DB.Close() }
+
+// ForTeam returns a Store that shares s's connection but reads only teamID's
+// work items and assigns new items to it.
+func (s *Store) ForTeam(teamID string) *Store {
+	return &Store{DB: s.DB, team: domain.ScopeTeam(teamID)}
+}
+
+func (s *Store) AdoptUnassignedWorkItems(teamID string) (int, error) {
+	return AdoptUnassignedWorkItems(s.DB, teamID)
+}
+
+func (s *Store) ListMigrations() ([]migrate.Status, error) { return ListMigrations(s.DB) }
+
+func (s *Store) Migrate() ([]migrate.Status, error) { return Migrate(s.DB) }
+
+func (s *Store) Backup(dest string) error { return Backup(s.DB, dest) }
+
+func (s *Store) InsertWorkItem(item WorkItem, actorID string) error {
+	_, err := InsertWorkItems(s.DB, s.team.Assign([]WorkItem{item}), actorID)
+	return err
+}
+
+func (s *Store) InsertWorkItems(items []WorkItem, actorID string) (int, error) {
+	return InsertWorkItems(s.DB, s.team.Assign(items), actorID)
+}
+
+func (s *Store) SourceRefExists(sourceRef string) (bool, error) {
+	return SourceRefExists(s.DB, s.team, sourceRef)
+}
+
+func (s *Store) GetWorkItemBySourceRef(sourceRef string) (WorkItem, error) {
+	return GetWorkItemBySourceRef(s.DB, s.team, sourceRef)
+}
+
+func (s *Store) GetItemsByDateRange(from, to time.Time) ([]WorkItem, error) {
+	return GetItemsByDateRange(s.DB, s.team, from, to)
+}
+
+func (s *Store) GetWorkItemByID(id int64) (WorkItem, error) { return GetWorkItemByID(s.DB, s.team, id) }
+
+func (s *Store) UpdateWorkItemTextAndStatus(id int64, description, status, actorID string) error {
+	return UpdateWorkItemTextAndStatus(s.DB, s.team, id, description, status, actorID)
+}
+
+func (s *Store) UpdateWorkItemStatus(id int64, status, actorID string) error {
+	return UpdateWorkItemStatus(s.DB, s.team, id, status, actorID)
+}
+
+func (s *Store) UpdateWorkItemFromSource(id int64, description, status string, reportedAt time.Time, actorID string) error {
+	return UpdateWorkItemFromSource(s.DB, s.team, id, description, status, reportedAt, actorID)
+}
+
+func (s *Store) UpdateWorkItemAuthorID(id int64, authorID string) error {
+	return UpdateWorkItemAuthorID(s.DB, s.team, id, authorID)
+}
+
+func (s *Store) MarkWorkItemDoneFromNudge(id int64, actorID string) error {
+	return MarkWorkItemDoneFromNudge(s.DB, s.team, id, actorID)
+}
+
+func (s *Store) UpdateWorkItemCategory(id int64, category, actorID string) error {
+	return UpdateWorkItemCategory(s.DB, s.team, id, category, actorID)
+}
+
+func (s *Store) UpdateCategories(categorized map[int64]string) error {
+	return UpdateCategories(s.DB, s.team, categorized)
+}
+
+func (s *Store) UpdateTicketIDs(ticketMap map[int64]string) error {
+	return UpdateTicketIDs(s.DB, s.team, ticketMap)
+}
+
+func (s *Store) DeleteWorkItemByID(id int64, actorID string) error {
+	return DeleteWorkItemByID(s.DB, s.team, id, actorID)
+}
+
+func (s *Store) RestoreWorkItem(id int64, actorID string) error {
+	return RestoreWorkItem(s.DB, s.team, id, actorID)
+}
+
+func (s *Store) GetWorkItemEvents(workItemID int64) ([]WorkItemEvent, error) {
+	return GetWorkItemEvents(s.DB, s.team, workItemID)
+}
+
+func (s *Store) GetDeletedItemsByDateRange(from, to time.Time) ([]WorkItem, error) {
+	return GetDeletedItemsByDateRange(s.DB, s.team, from, to)
+}
+
+func (s *Store) SearchWorkItems(q SearchQuery) ([]WorkItem, error) {
+	return SearchWorkItems(s.DB, s.team, q)
+}
+
+func (s *Store) GetPendingSlackItemsByAuthorAndDateRange(author string, from, to time.Time) ([]WorkItem, error) {
+	return GetPendingSlackItemsByAuthorAndDateRange(s.DB, s.team, author, from, to)
+}
+
+func (s *Store) GetSlackItemsByAuthorAndDateRange(author string, from, to time.Time) ([]WorkItem, error) {
+	return GetSlackItemsByAuthorAndDateRange(s.DB, s.team, author, from, to)
+}
+
+func (s *Store) GetSlackAuthorsByDateRange(from, to time.Time) (map[string]bool, error) {
+	return GetSlackAuthorsByDateRange(s.DB, s.team, from, to)
+}
+
+func (s *Store) GetSlackAuthorIDsByDateRange(from, to time.Time) (map[string]bool, error) {
+	return GetSlackAuthorIDsByDateRange(s.DB, s.team, from, to)
+}
+
+func (s *Store) InsertClassificationHistory(records []ClassificationRecord) error {
+	return InsertClassificationHistory(s.DB, records)
+}
+
+func (s *Store) GetLatestClassification(workItemID int64) (ClassificationRecord, error) {
+	return GetLatestClassification(s.DB, s.team, workItemID)
+}
+
+func (s *Store) LookupClassifications(keys []string) (map[string]ClassificationRecord, error) {
+	return GetCachedClassifications(s.DB, s.team, keys)
+}
+
+func (s *Store) GetConfidenceSamples(since time.Time, limit int) ([]ConfidenceSample, error) {
+	return GetConfidenceSamples(s.DB, s.team, since, limit)
+}
+
+func (s *Store) GetClassifiedItemsWithSections(since time.Time, limit int) ([]historicalItem, error) {
+	return GetClassifiedItemsWithSections(s.DB, s.team, since, limit)
+}
+
+func (s *Store) GetLabeledWorkItems(since time.Time, limit int) ([]LabeledWorkItem, error) {
+	return GetLabeledWorkItems(s.DB, s.team, since, limit)
+}
+
+func (s *Store) InsertClassificationCorrection(c ClassificationCorrection) error {
+	return InsertClassificationCorrection(s.DB, c)
+}
+
+func (s *Store) GetRecentCorrections(since time.Time, limit int) ([]ClassificationCorrection, error) {
+	return GetRecentCorrections(s.DB, s.team, since, limit)
+}
+
+func (s *Store) CountCorrectionsByPhrase(description, correctedSectionID string) (int, error) {
+	return CountCorrectionsByPhrase(s.DB, s.team, description, correctedSectionID)
+}
+
+func (s *Store) GetClassificationStats(since time.Time) (ClassificationStats, error) {
+	return GetClassificationStats(s.DB, s.team, since)
+}
+
+func (s *Store) GetCorrectionsBySection(since time.Time) ([]SectionCorrectionStat, error) {
+	return GetCorrectionsBySection(s.DB, s.team, since)
+}
+
+func (s *Store) GetWeeklyClassificationTrend(since time.Time) ([]WeeklyTrend, error) {
+	return GetWeeklyClassificationTrend(s.DB, s.team, since)
+}
+
+func (s *Store) LoadEmbeddings(model string, workItemIDs []int64) (map[int64]Embedding, error) {
+	return GetEmbeddings(s.DB, model, workItemIDs)
+}
+
+func (s *Store) SaveEmbeddings(embeddings []Embedding) error { return SaveEmbeddings(s.DB, embeddings) }
+
+func (s *Store) RecordLLMUsage(rec LLMUsageRecord) error { return InsertLLMUsage(s.DB, s.team, rec) }
+
+func (s *Store) PurgeExpired(p RetentionPolicy, archive domain.RetentionArchive, dryRun bool) (map[string]int, error) {
+	return PurgeExpired(s.DB, p, archive, dryRun)
+}
+
+func (s *Store) ExportState() (domain.StateSnapshot, error) { return ExportState(s.DB) }
+
+func (s *Store) ImportState(snap domain.StateSnapshot) (domain.ImportResult, error) {
+	snap.WorkItems = s.team.Assign(snap.WorkItems)
+	return ImportState(s.DB, snap)
+}
+
+func (s *Store) LLMSpendSince(since time.Time) (float64, error) {
+	return GetLLMSpendSince(s.DB, s.team, since)
+}
+
+func (s *Store) GetLLMUsageSummary(since time.Time) ([]LLMUsageSummary, error) {
+	return GetLLMUsageSummary(s.DB, s.team, since)
+}
+
+func (s *Store) GetIdentity(slackID string) (Identity, error) { return GetIdentity(s.DB, slackID) }
+
+func (s *Store) ListIdentities() ([]Identity, error) { return ListIdentities(s.DB) }
+
+func (s *Store) LinkIdentity(slackID, kind, account string) error {
+	return LinkIdentity(s.DB, slackID, kind, account)
+}
+
+func (s *Store) ReassignIdentity(slackID, kind, account string) error {
+	return ReassignIdentity(s.DB, slackID, kind, account)
+}
@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"database/sql"
+	"reportbot/internal/domain"
+)
+
+type TeamScope = domain.TeamScope
+
+// teamFilter returns an " AND column = ?" condition and its argument for a
+// team-scoped read, or nothing when the scope covers all teams.
+func teamFilter(scope TeamScope, column string) (string, []any) {
+	if !scope.Scoped {
+		return "", nil
+	}
+	return " AND " + column + " = ?", []any{scope.TeamID}
+}
+
+// itemTeamFilter is teamFilter for tables that reference work items by id.
+func itemTeamFilter(scope TeamScope, column string) (string, []any) {
+	if !scope.Scoped {
+		return "", nil
+	}
+	return " AND " + column + " IN (SELECT id FROM work_items WHERE team_id = ?)", []any{scope.TeamID}
+}
+
+// AdoptUnassignedWorkItems moves work items recorded without a team, such as
+// those of a single-team deployment that has since configured teams, to
+// teamID. It returns how many items moved.
+func AdoptUnassignedWorkItems(db *sql.DB, teamID string) (int, error) {
+	res, err := db.Exec(`UPDATE work_items SET team_id = ? WHERE team_id = ''`, teamID)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+)
+
+// InsertLLMUsage records rec against team; an unscoped team records it
+// without one.
+func InsertLLMUsage(db *sql.DB, team TeamScope, rec LLMUsageRecord) error {
+	createdAt := rec.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	createdAt = createdAt.UTC()
+	_, err := db.Exec(
+		`INSERT INTO llm_usage (provider, model, purpose, input_tokens, output_tokens,
+		  cache_creation_input_tokens, cache_read_input_tokens, cost_usd, created_at, team_id)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rec.Provider, rec.Model, rec.Purpose, rec.InputTokens, rec.OutputTokens,
+		rec.CacheCreationInputTokens, rec.CacheReadInputTokens, rec.CostUSD, createdAt, team.TeamID,
+	)
+	return err
+}
+
+// GetLLMSpendSince returns the total recorded cost in USD since the given time.
+func GetLLMSpendSince(db *sql.DB, team TeamScope, since time.Time) (float64, error) {
+	filter, filterArgs := teamFilter(team, "team_id")
+	var spend float64
+	err := db.QueryRow(`SELECT COALESCE(SUM(cost_usd), 0) FROM llm_usage WHERE created_at >= ?`+filter,
+		append([]any{since.UTC()}, filterArgs...)...).Scan(&spend)
+	return spend, err
+}
+
+// GetLLMUsageSummary groups usage since the given time by provider, model
+// and purpose, most expensive first.
+func GetLLMUsageSummary(db *sql.DB, team TeamScope, since time.Time) ([]LLMUsageSummary, error) {
+	filter, filterArgs := teamFilter(team, "team_id")
+	rows, err := db.Query(
+		`SELECT provider, model, purpose, COUNT(*),
+		        COALESCE(SUM(input_tokens + cache_creation_input_tokens + cache_read_input_tokens), 0),
+		        COALESCE(SUM(output_tokens), 0), COALESCE(SUM(cost_usd), 0)
+		 FROM llm_usage
+		 WHERE created_at >= ?`+filter+`
+		 GROUP BY provider, model, purpose
+		 ORDER BY SUM(cost_usd) DESC, COUNT(*) DESC`,
+		append([]any{since.UTC()}, filterArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []LLMUsageSummary
+	for rows.Next() {
+		var s LLMUsageSummary
+		if err := rows.Scan(&s.Provider, &s.Model, &s.Purpose, &s.Calls, &s.InputTokens, &s.OutputTokens, &s.CostUSD); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
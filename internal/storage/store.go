@@ -0,0 +1,202 @@
+package storage
+
+import (
+	"fmt"
+	"reportbot/internal/config"
+	"reportbot/internal/domain"
+	"reportbot/internal/storage/migrate"
+	"reportbot/internal/storage/postgres"
+	"reportbot/internal/storage/sqlite"
+	"strings"
+	"time"
+)
+
+type Config = config.Config
+type WorkItem = domain.WorkItem
+type WorkItemEvent = domain.WorkItemEvent
+type SearchQuery = domain.SearchQuery
+type ClassificationRecord = domain.ClassificationRecord
+type ClassificationCorrection = domain.ClassificationCorrection
+type ClassificationStats = domain.ClassificationStats
+type SectionCorrectionStat = domain.SectionCorrectionStat
+type WeeklyTrend = domain.WeeklyTrend
+type HistoricalItem = domain.HistoricalItem
+type ConfidenceSample = domain.ConfidenceSample
+type LabeledWorkItem = domain.LabeledWorkItem
+type Embedding = domain.Embedding
+type LLMUsageRecord = domain.LLMUsageRecord
+type LLMUsageSummary = domain.LLMUsageSummary
+type RetentionPolicy = domain.RetentionPolicy
+type RetentionArchive = domain.RetentionArchive
+type StateSnapshot = domain.StateSnapshot
+type ImportResult = domain.ImportResult
+type Identity = domain.Identity
+type MigrationStatus = migrate.Status
+
+// ErrSchemaTooNew is returned by Open when the database was migrated by a
+// newer release than this binary.
+var ErrSchemaTooNew = migrate.ErrSchemaTooNew
+
+// Store is the persistence layer shared by the Slack bot, schedulers and
+// tools. The SQLite and PostgreSQL backends implement it with the same
+// semantics; db_driver selects one at startup. A Store from Open reads every
+// team; ForTeam narrows it to one.
+type Store interface {
+	// Work items.
+	// Writes take the Slack ID of the acting user (empty for the bot itself)
+	// and record work_item_events in the same transaction.
+	InsertWorkItem(item WorkItem, actorID string) error
+	InsertWorkItems(items []WorkItem, actorID string) (int, error)
+	// SourceRefExists reports whether sourceRef was imported for this
+	// store's team, counting deleted items.
+	SourceRefExists(sourceRef string) (bool, error)
+	// GetWorkItemBySourceRef returns sql.ErrNoRows unless a live item was
+	// imported from sourceRef.
+	GetWorkItemBySourceRef(sourceRef string) (WorkItem, error)
+	GetItemsByDateRange(from, to time.Time) ([]WorkItem, error)
+	GetWorkItemByID(id int64) (WorkItem, error)
+	UpdateWorkItemTextAndStatus(id int64, description, status, actorID string) error
+	UpdateWorkItemStatus(id int64, status, actorID string) error
+	UpdateWorkItemFromSource(id int64, description, status string, reportedAt time.Time, actorID string) error
+	// UpdateWorkItemAuthorID sets author_id on an item that has none.
+	UpdateWorkItemAuthorID(id int64, authorID string) error
+	MarkWorkItemDoneFromNudge(id int64, actorID string) error
+	UpdateWorkItemCategory(id int64, category, actorID string) error
+	UpdateCategories(categorized map[int64]string) error
+	UpdateTicketIDs(ticketMap map[int64]string) error
+	DeleteWorkItemByID(id int64, actorID string) error
+	RestoreWorkItem(id int64, actorID string) error
+	GetWorkItemEvents(workItemID int64) ([]WorkItemEvent, error)
+	GetDeletedItemsByDateRange(from, to time.Time) ([]WorkItem, error)
+	SearchWorkItems(q SearchQuery) ([]WorkItem, error)
+	GetPendingSlackItemsByAuthorAndDateRange(author string, from, to time.Time) ([]WorkItem, error)
+	GetSlackItemsByAuthorAndDateRange(author string, from, to time.Time) ([]WorkItem, error)
+	GetSlackAuthorsByDateRange(from, to time.Time) (map[string]bool, error)
+	GetSlackAuthorIDsByDateRange(from, to time.Time) (map[string]bool, error)
+	// AdoptUnassignedWorkItems moves items without a team to teamID.
+	AdoptUnassignedWorkItems(teamID string) (int, error)
+
+	// Classification history.
+	InsertClassificationHistory(records []ClassificationRecord) error
+	GetLatestClassification(workItemID int64) (ClassificationRecord, error)
+	LookupClassifications(keys []string) (map[string]ClassificationRecord, error)
+	GetConfidenceSamples(since time.Time, limit int) ([]ConfidenceSample, error)
+	GetClassifiedItemsWithSections(since time.Time, limit int) ([]HistoricalItem, error)
+	GetLabeledWorkItems(since time.Time, limit int) ([]LabeledWorkItem, error)
+
+	// Corrections and accuracy stats.
+	InsertClassificationCorrection(c ClassificationCorrection) error
+	GetRecentCorrections(since time.Time, limit int) ([]ClassificationCorrection, error)
+	CountCorrectionsByPhrase(description, correctedSectionID string) (int, error)
+	GetClassificationStats(since time.Time) (ClassificationStats, error)
+	GetCorrectionsBySection(since time.Time) ([]SectionCorrectionStat, error)
+	GetWeeklyClassificationTrend(since time.Time) ([]WeeklyTrend, error)
+
+	// Embedding cache and LLM usage ledger.
+	LoadEmbeddings(model string, workItemIDs []int64) (map[int64]Embedding, error)
+	SaveEmbeddings(embeddings []Embedding) error
+	RecordLLMUsage(rec LLMUsageRecord) error
+	LLMSpendSince(since time.Time) (float64, error)
+	GetLLMUsageSummary(since time.Time) ([]LLMUsageSummary, error)
+
+	// Retention. PurgeExpired deletes (and optionally archives) rows outside
+	// the policy and returns deleted rows per table; dryRun only counts.
+	PurgeExpired(p RetentionPolicy, archive RetentionArchive, dryRun bool) (map[string]int, error)
+
+	// Identities link Slack users to GitLab/GitHub accounts and email; they
+	// are shared by all teams. GetIdentity returns sql.ErrNoRows for a user
+	// who linked nothing. LinkIdentity sets one account kind (an empty
+	// account unlinks it) and fails with domain.ErrIdentityTaken when another
+	// user linked that account. ReassignIdentity is the manager's link: it
+	// confirms the account and takes it from another user instead.
+	GetIdentity(slackID string) (Identity, error)
+	ListIdentities() ([]Identity, error)
+	LinkIdentity(slackID, kind, account string) error
+	ReassignIdentity(slackID, kind, account string) error
+
+	// Export/import of work items, classification history and corrections.
+	// Import is idempotent and dedupes items like the source_ref index.
+	ExportState() (StateSnapshot, error)
+	ImportState(snap StateSnapshot) (ImportResult, error)
+
+	Close() error
+}
+
+// Migrator inspects and applies schema migrations; `reportbot migrate` uses
+// it so status can be read without changing the schema.
+type Migrator interface {
+	ListMigrations() ([]MigrationStatus, error)
+	Migrate() ([]MigrationStatus, error)
+	Close() error
+}
+
+// Backuper is implemented by backends that can copy themselves while the bot
+// is running. Only SQLite does; PostgreSQL has its own tooling (pg_dump).
+type Backuper interface {
+	Backup(dest string) error
+}
+
+var (
+	_ Backuper = (*sqlite.Store)(nil)
+	_ Store    = (*sqlite.Store)(nil)
+	_ Store    = (*postgres.Store)(nil)
+	_ Migrator = (*sqlite.Store)(nil)
+	_ Migrator = (*postgres.Store)(nil)
+)
+
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// Open connects to the backend selected by cfg.DBDriver and brings its
+// schema up to date.
+func Open(cfg Config) (Store, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.DBDriver)) {
+	case "", DriverSQLite:
+		return sqlite.Open(cfg.DBPath)
+	case DriverPostgres:
+		return postgres.Open(cfg.DBDSN)
+	default:
+		return nil, fmt.Errorf("unknown db_driver %q (supported: %s, %s)", cfg.DBDriver, DriverSQLite, DriverPostgres)
+	}
+}
+
+// ForTeam returns a Store sharing s's connection whose work item reads, and
+// the classification history, corrections and stats derived from them,
+// cover only teamID, and which assigns new items to teamID. Closing it
+// closes s.
+func ForTeam(s Store, teamID string) Store {
+	switch b := s.(type) {
+	case *sqlite.Store:
+		return b.ForTeam(teamID)
+	case *postgres.Store:
+		return b.ForTeam(teamID)
+	default:
+		return s
+	}
+}
+
+// OpenMigrator connects to the backend selected by cfg.DBDriver without
+// applying migrations.
+func OpenMigrator(cfg Config) (Migrator, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.DBDriver)) {
+	case "", DriverSQLite:
+		return sqlite.OpenUnmigrated(cfg.DBPath)
+	case DriverPostgres:
+		return postgres.OpenUnmigrated(cfg.DBDSN)
+	default:
+		return nil, fmt.Errorf("unknown db_driver %q (supported: %s, %s)", cfg.DBDriver, DriverSQLite, DriverPostgres)
+	}
+}
+
+// CheckBackup verifies a backup file written by Backuper.Backup.
+func CheckBackup(path string) error { return sqlite.CheckIntegrity(path) }
+
+// Describe returns a log-safe description of where cfg stores data.
+func Describe(cfg Config) string {
+	if strings.EqualFold(strings.TrimSpace(cfg.DBDriver), DriverPostgres) {
+		return DriverPostgres + " " + postgres.RedactDSN(cfg.DBDSN)
+	}
+	return DriverSQLite + " " + cfg.DBPath
+}
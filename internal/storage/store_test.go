@@ -0,0 +1,950 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reportbot/internal/domain"
+	"reportbot/internal/storage/postgres"
+	"reportbot/internal/storage/sqlite"
+	"strings"
+	"testing"
+	"time"
+)
+
+// postgresDSNEnv names the database the contract tests also run against,
+// e.g. a throwaway container in CI. Its tables are truncated per test.
+const postgresDSNEnv = "REPORTBOT_TEST_POSTGRES_DSN"
+
+// forEachStore runs fn against a fresh SQLite store and, when
+// REPORTBOT_TEST_POSTGRES_DSN is set, against PostgreSQL.
+func forEachStore(t *testing.T, fn func(t *testing.T, s Store)) {
+	t.Run("sqlite", func(t *testing.T) {
+		s, err := sqlite.Open(filepath.Join(t.TempDir(), "store-test.db"))
+		if err != nil {
+			t.Fatalf("sqlite.Open: %v", err)
+		}
+		t.Cleanup(func() { _ = s.Close() })
+		fn(t, s)
+	})
+	t.Run("postgres", func(t *testing.T) {
+		dsn := os.Getenv(postgresDSNEnv)
+		if dsn == "" {
+			t.Skipf("%s not set", postgresDSNEnv)
+		}
+		s, err := postgres.Open(dsn)
+		if err != nil {
+			t.Fatalf("postgres.Open: %v", err)
+		}
+		t.Cleanup(func() { _ = s.Close() })
+		if _, err := s.DB.Exec(`TRUNCATE work_items, classification_history, classification_corrections,
+			work_item_embeddings, llm_usage, work_item_events, identities RESTART IDENTITY`); err != nil {
+			t.Fatalf("truncate: %v", err)
+		}
+		fn(t, s)
+	})
+}
+
+func TestOpenRejectsUnknownDriver(t *testing.T) {
+	if _, err := Open(Config{DBDriver: "mysql"}); err == nil {
+		t.Fatal("expected error for unknown driver")
+	}
+	if _, err := Open(Config{DBDriver: "postgres"}); err == nil {
+		t.Fatal("expected error for postgres without db_dsn")
+	}
+}
+
+func TestDescribeRedactsPassword(t *testing.T) {
+	got := Describe(Config{DBDriver: "postgres", DBDSN: "postgres://bot:s3cret@db:5432/reportbot?sslmode=disable"})
+	if got != "postgres postgres://bot:xxxxx@db:5432/reportbot?sslmode=disable" {
+		t.Fatalf("unexpected description: %s", got)
+	}
+	got = Describe(Config{DBDriver: "postgres", DBDSN: "host=db user=bot password=s3cret dbname=reportbot"})
+	if got != "postgres host=db user=bot password=xxxxx dbname=reportbot" {
+		t.Fatalf("unexpected description: %s", got)
+	}
+}
+
+func TestStoreWorkItems(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+		if err := s.InsertWorkItem(WorkItem{Description: "Slack item", Author: "Alice", AuthorID: "U1", Source: "slack", Status: "in progress", ReportedAt: base}, ""); err != nil {
+			t.Fatalf("InsertWorkItem: %v", err)
+		}
+		mr := WorkItem{Description: "MR", Author: "Bob", Source: "gitlab", SourceRef: "https://gitlab/mr/1", Status: "done", ReportedAt: base.Add(time.Hour)}
+		inserted, err := s.InsertWorkItems([]WorkItem{mr, mr}, "")
+		if err != nil || inserted != 1 {
+			t.Fatalf("expected duplicate source_ref ignored, inserted=%d err=%v", inserted, err)
+		}
+		if exists, err := s.SourceRefExists(mr.SourceRef); err != nil || !exists {
+			t.Fatalf("SourceRefExists: %v %v", exists, err)
+		}
+
+		items, err := s.GetItemsByDateRange(base, base.Add(24*time.Hour))
+		if err != nil || len(items) != 2 {
+			t.Fatalf("GetItemsByDateRange: %d items, err=%v", len(items), err)
+		}
+		id := items[0].ID
+		if items[0].Author != "Alice" || !items[0].ReportedAt.Equal(base) {
+			t.Fatalf("unexpected first item: %+v", items[0])
+		}
+
+		if err := s.UpdateWorkItemTextAndStatus(id, "Slack item v2", "done", ""); err != nil {
+			t.Fatalf("UpdateWorkItemTextAndStatus: %v", err)
+		}
+		if err := s.UpdateCategories(map[int64]string{id: "S0_0"}); err != nil {
+			t.Fatalf("UpdateCategories: %v", err)
+		}
+		if err := s.UpdateTicketIDs(map[int64]string{id: "JIRA-1"}); err != nil {
+			t.Fatalf("UpdateTicketIDs: %v", err)
+		}
+		got, err := s.GetWorkItemByID(id)
+		if err != nil || got.Description != "Slack item v2" || got.Status != "done" || got.Category != "S0_0" || got.TicketIDs != "JIRA-1" {
+			t.Fatalf("GetWorkItemByID: %+v err=%v", got, err)
+		}
+
+		pending, err := s.GetPendingSlackItemsByAuthorAndDateRange("Alice", base, base.Add(time.Hour))
+		if err != nil || len(pending) != 0 {
+			t.Fatalf("expected no pending items after marking done, got %d err=%v", len(pending), err)
+		}
+		authors, err := s.GetSlackAuthorsByDateRange(base, base.Add(time.Hour))
+		if err != nil || !authors["Alice"] || authors["Bob"] {
+			t.Fatalf("GetSlackAuthorsByDateRange: %v err=%v", authors, err)
+		}
+		ids, err := s.GetSlackAuthorIDsByDateRange(base, base.Add(time.Hour))
+		if err != nil || !ids["U1"] {
+			t.Fatalf("GetSlackAuthorIDsByDateRange: %v err=%v", ids, err)
+		}
+
+		if err := s.DeleteWorkItemByID(id, ""); err != nil {
+			t.Fatalf("DeleteWorkItemByID: %v", err)
+		}
+		if _, err := s.GetWorkItemByID(id); err == nil {
+			t.Fatal("expected error loading deleted item")
+		}
+	})
+}
+
+func TestStoreWorkItemEventsAndSoftDelete(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+		if err := s.InsertWorkItem(WorkItem{Description: "Fix login", Author: "Alice", AuthorID: "U1", Source: "slack", Status: "in progress", ReportedAt: base}, "UMGR"); err != nil {
+			t.Fatalf("InsertWorkItem: %v", err)
+		}
+		items, err := s.GetItemsByDateRange(base, base.Add(time.Hour))
+		if err != nil || len(items) != 1 {
+			t.Fatalf("GetItemsByDateRange: %d items, err=%v", len(items), err)
+		}
+		id := items[0].ID
+
+		if err := s.UpdateWorkItemTextAndStatus(id, "Fix login flow", "in progress", "U1"); err != nil {
+			t.Fatalf("UpdateWorkItemTextAndStatus: %v", err)
+		}
+		if err := s.UpdateWorkItemCategory(id, "S0_0", "UMGR"); err != nil {
+			t.Fatalf("UpdateWorkItemCategory: %v", err)
+		}
+		if err := s.MarkWorkItemDoneFromNudge(id, "U1"); err != nil {
+			t.Fatalf("MarkWorkItemDoneFromNudge: %v", err)
+		}
+		if err := s.DeleteWorkItemByID(id, "UMGR"); err != nil {
+			t.Fatalf("DeleteWorkItemByID: %v", err)
+		}
+		if items, _ := s.GetItemsByDateRange(base, base.Add(time.Hour)); len(items) != 0 {
+			t.Fatalf("expected deleted item hidden, got %+v", items)
+		}
+		if exists, _ := s.SourceRefExists(""); !exists {
+			t.Fatal("expected deleted rows to still count for SourceRefExists")
+		}
+		if err := s.UpdateWorkItemStatus(id, "blocked", "U1"); err == nil {
+			t.Fatal("expected updating a deleted item to fail")
+		}
+		deleted, err := s.GetDeletedItemsByDateRange(base, base.Add(time.Hour))
+		if err != nil || len(deleted) != 1 || deleted[0].ID != id {
+			t.Fatalf("GetDeletedItemsByDateRange: %+v err=%v", deleted, err)
+		}
+
+		if err := s.RestoreWorkItem(id, "UMGR"); err != nil {
+			t.Fatalf("RestoreWorkItem: %v", err)
+		}
+		if err := s.RestoreWorkItem(id, "UMGR"); err == nil {
+			t.Fatal("expected restoring a live item to fail")
+		}
+		got, err := s.GetWorkItemByID(id)
+		if err != nil || got.Description != "Fix login flow" || got.Status != "done" || got.Category != "S0_0" {
+			t.Fatalf("restored item: %+v err=%v", got, err)
+		}
+
+		events, err := s.GetWorkItemEvents(id)
+		if err != nil {
+			t.Fatalf("GetWorkItemEvents: %v", err)
+		}
+		want := []WorkItemEvent{
+			{EventType: "created", NewValue: "Fix login", ActorID: "UMGR"},
+			{EventType: "edited", OldValue: "Fix login", NewValue: "Fix login flow", ActorID: "U1"},
+			{EventType: "category_changed", OldValue: "", NewValue: "S0_0", ActorID: "UMGR"},
+			{EventType: "nudge_done", OldValue: "in progress", NewValue: "done", ActorID: "U1"},
+			{EventType: "deleted", OldValue: "Fix login flow", ActorID: "UMGR"},
+			{EventType: "restored", NewValue: "Fix login flow", ActorID: "UMGR"},
+		}
+		if len(events) != len(want) {
+			t.Fatalf("expected %d events, got %+v", len(want), events)
+		}
+		for i, w := range want {
+			e := events[i]
+			if e.WorkItemID != id || e.EventType != w.EventType || e.OldValue != w.OldValue || e.NewValue != w.NewValue || e.ActorID != w.ActorID || e.CreatedAt.IsZero() {
+				t.Fatalf("event %d: got %+v, want %+v", i, e, w)
+			}
+		}
+	})
+}
+
+func TestStoreUpdateWorkItemFromSource(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		opened := time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC)
+		merged := opened.Add(48 * time.Hour)
+		ref := "https://gitlab.example.com/g/p/-/merge_requests/7"
+		if err := s.InsertWorkItem(WorkItem{Description: "Add cache", Author: "Alice", Source: "gitlab", SourceRef: ref, Status: "in progress", ReportedAt: opened}, ""); err != nil {
+			t.Fatalf("InsertWorkItem: %v", err)
+		}
+		item, err := s.GetWorkItemBySourceRef(ref)
+		if err != nil || item.Description != "Add cache" {
+			t.Fatalf("GetWorkItemBySourceRef: %+v err=%v", item, err)
+		}
+		if _, err := s.GetWorkItemBySourceRef("https://gitlab.example.com/g/p/-/merge_requests/8"); err != sql.ErrNoRows {
+			t.Fatalf("expected sql.ErrNoRows for an unknown ref, got %v", err)
+		}
+		if _, err := ForTeam(s, "other").GetWorkItemBySourceRef(ref); err != sql.ErrNoRows {
+			t.Fatalf("expected another team's lookup to miss, got %v", err)
+		}
+
+		if err := s.UpdateWorkItemFromSource(item.ID, "Add cache layer", "done", merged, ""); err != nil {
+			t.Fatalf("UpdateWorkItemFromSource: %v", err)
+		}
+		got, err := s.GetWorkItemByID(item.ID)
+		if err != nil || got.Description != "Add cache layer" || got.Status != "done" || !got.ReportedAt.Equal(merged) {
+			t.Fatalf("updated item: %+v err=%v", got, err)
+		}
+		events, err := s.GetWorkItemEvents(item.ID)
+		if err != nil || len(events) != 3 || events[1].EventType != "edited" || events[2].EventType != "status_changed" || events[2].NewValue != "done" {
+			t.Fatalf("events: %+v err=%v", events, err)
+		}
+
+		if err := s.DeleteWorkItemByID(item.ID, "UMGR"); err != nil {
+			t.Fatalf("DeleteWorkItemByID: %v", err)
+		}
+		if _, err := s.GetWorkItemBySourceRef(ref); err != sql.ErrNoRows {
+			t.Fatalf("expected deleted item to be skipped, got %v", err)
+		}
+		if err := s.UpdateWorkItemFromSource(item.ID, "Add cache layer", "closed", merged, ""); err == nil {
+			t.Fatal("expected updating a deleted item to fail")
+		}
+	})
+}
+
+func TestStoreSearchWorkItems(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 9, 0, 0, 0, time.UTC) }
+		if _, err := s.InsertWorkItems([]WorkItem{
+			{Description: "Fix login timeout", Author: "Alice", AuthorID: "U1", Source: "slack", Status: "done", TicketIDs: "PROJ-12", ReportedAt: day(1, 10)},
+			{Description: "Add login audit", Author: "Bob", Source: "gitlab", SourceRef: "https://gitlab.example.com/g/p/-/merge_requests/77", Status: "in progress", ReportedAt: day(2, 10)},
+			{Description: "Refactor billing", Author: "Alice", AuthorID: "U1", Source: "slack", Status: "Done", ReportedAt: day(3, 1)},
+			{Description: "Login cleanup", Author: "Carol", Source: "slack", Status: "done", ReportedAt: day(3, 2)},
+		}, ""); err != nil {
+			t.Fatalf("InsertWorkItems: %v", err)
+		}
+		if err := s.DeleteWorkItemByID(4, ""); err != nil {
+			t.Fatalf("DeleteWorkItemByID: %v", err)
+		}
+
+		descriptions := func(q SearchQuery) []string {
+			t.Helper()
+			items, err := s.SearchWorkItems(q)
+			if err != nil {
+				t.Fatalf("SearchWorkItems(%+v): %v", q, err)
+			}
+			var out []string
+			for _, it := range items {
+				out = append(out, it.Description)
+			}
+			return out
+		}
+		cases := []struct {
+			q    SearchQuery
+			want string
+		}{
+			{SearchQuery{Text: "login"}, "Add login audit|Fix login timeout"},
+			{SearchQuery{Text: "LOGIN fix"}, "Fix login timeout"},
+			{SearchQuery{Text: "login", Author: "ali"}, "Fix login timeout"},
+			{SearchQuery{AuthorID: "U1", Status: "done"}, "Refactor billing|Fix login timeout"},
+			{SearchQuery{Status: "done", Since: day(2, 1)}, "Refactor billing"},
+			{SearchQuery{Text: "merge_requests/77"}, "Add login audit"},
+			{SearchQuery{Text: "proj-12"}, "Fix login timeout"},
+			{SearchQuery{Text: `50% "quoted`}, ""},
+			{SearchQuery{Limit: 2, Offset: 2}, "Fix login timeout"},
+		}
+		for _, c := range cases {
+			if got := strings.Join(descriptions(c.q), "|"); got != c.want {
+				t.Fatalf("SearchWorkItems(%+v) = %q, want %q", c.q, got, c.want)
+			}
+		}
+
+		// Edits are searchable immediately.
+		if err := s.UpdateWorkItemTextAndStatus(1, "Fix signin timeout", "done", ""); err != nil {
+			t.Fatalf("UpdateWorkItemTextAndStatus: %v", err)
+		}
+		if got := strings.Join(descriptions(SearchQuery{Text: "login"}), "|"); got != "Add login audit" {
+			t.Fatalf("expected edited item to drop out of results, got %q", got)
+		}
+		if got := strings.Join(descriptions(SearchQuery{Text: "signin"}), "|"); got != "Fix signin timeout" {
+			t.Fatalf("expected edited item to match its new text, got %q", got)
+		}
+	})
+}
+
+func TestStoreForTeamScopesItems(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+		if err := s.InsertWorkItem(WorkItem{Description: "Legacy item", Author: "Alice", Source: "slack", Status: "done", ReportedAt: base}, ""); err != nil {
+			t.Fatalf("InsertWorkItem: %v", err)
+		}
+		if n, err := s.AdoptUnassignedWorkItems("platform"); err != nil || n != 1 {
+			t.Fatalf("AdoptUnassignedWorkItems: n=%d err=%v", n, err)
+		}
+
+		platform, mobile := ForTeam(s, "platform"), ForTeam(s, "mobile")
+		if err := platform.InsertWorkItem(WorkItem{Description: "Platform login fix", Author: "Alice", Source: "slack", Status: "done", ReportedAt: base.Add(time.Hour)}, ""); err != nil {
+			t.Fatalf("platform InsertWorkItem: %v", err)
+		}
+		if _, err := mobile.InsertWorkItems([]WorkItem{
+			{Description: "Mobile login fix", Author: "Bob", Source: "slack", Status: "done", ReportedAt: base.Add(time.Hour)},
+			{Description: "Platform item filed elsewhere", Author: "Bob", Source: "slack", Status: "done", TeamID: "platform", ReportedAt: base.Add(time.Hour)},
+		}, ""); err != nil {
+			t.Fatalf("mobile InsertWorkItems: %v", err)
+		}
+
+		teams := func(st Store) string {
+			t.Helper()
+			items, err := st.GetItemsByDateRange(base, base.Add(2*time.Hour))
+			if err != nil {
+				t.Fatalf("GetItemsByDateRange: %v", err)
+			}
+			var out []string
+			for _, it := range items {
+				out = append(out, it.TeamID+":"+it.Description)
+			}
+			return strings.Join(out, "|")
+		}
+		if got := teams(platform); got != "platform:Legacy item|platform:Platform login fix|platform:Platform item filed elsewhere" {
+			t.Fatalf("platform items = %q", got)
+		}
+		if got := teams(mobile); got != "mobile:Mobile login fix" {
+			t.Fatalf("mobile items = %q", got)
+		}
+		if items, _ := s.GetItemsByDateRange(base, base.Add(2*time.Hour)); len(items) != 4 {
+			t.Fatalf("expected the unscoped store to read every team, got %d items", len(items))
+		}
+
+		found, err := mobile.SearchWorkItems(SearchQuery{Text: "login"})
+		if err != nil || len(found) != 1 || found[0].Description != "Mobile login fix" {
+			t.Fatalf("mobile SearchWorkItems: %+v err=%v", found, err)
+		}
+		authors, err := platform.GetSlackAuthorsByDateRange(base, base.Add(2*time.Hour))
+		if err != nil || len(authors) != 2 || !authors["Alice"] || !authors["Bob"] {
+			t.Fatalf("platform GetSlackAuthorsByDateRange: %v err=%v", authors, err)
+		}
+		if n, err := s.AdoptUnassignedWorkItems("mobile"); err != nil || n != 0 {
+			t.Fatalf("expected nothing left to adopt, n=%d err=%v", n, err)
+		}
+	})
+}
+
+func TestStoreForTeamScopesSourceRefs(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		const ref = "https://gitlab.example.com/g/shared/-/merge_requests/9"
+		platform, mobile := ForTeam(s, "platform"), ForTeam(s, "mobile")
+		mr := WorkItem{Description: "Shared library bump", Author: "Alice", Source: "gitlab", SourceRef: ref, Status: "done",
+			ReportedAt: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)}
+
+		if err := platform.InsertWorkItem(mr, ""); err != nil {
+			t.Fatalf("platform InsertWorkItem: %v", err)
+		}
+		if exists, err := mobile.SourceRefExists(ref); err != nil || exists {
+			t.Fatalf("expected mobile not to see platform's MR, exists=%v err=%v", exists, err)
+		}
+		if n, err := mobile.InsertWorkItems([]WorkItem{mr}, ""); err != nil || n != 1 {
+			t.Fatalf("expected mobile to import its own copy, n=%d err=%v", n, err)
+		}
+		if n, err := mobile.InsertWorkItems([]WorkItem{mr}, ""); err != nil || n != 0 {
+			t.Fatalf("expected the ref to stay unique within a team, n=%d err=%v", n, err)
+		}
+		for _, st := range []Store{platform, mobile, s} {
+			if exists, err := st.SourceRefExists(ref); err != nil || !exists {
+				t.Fatalf("SourceRefExists: %v %v", exists, err)
+			}
+		}
+		mobileItem, err := mobile.GetWorkItemBySourceRef(ref)
+		if err != nil || mobileItem.TeamID != "mobile" {
+			t.Fatalf("mobile GetWorkItemBySourceRef: %+v err=%v", mobileItem, err)
+		}
+	})
+}
+
+func TestStoreForTeamScopesItemsByID(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		platform, mobile := ForTeam(s, "platform"), ForTeam(s, "mobile")
+		if err := mobile.InsertWorkItem(WorkItem{Description: "Mobile login fix", Author: "Bob", Source: "slack", Status: "in progress", ReportedAt: time.Now()}, "U2"); err != nil {
+			t.Fatalf("InsertWorkItem: %v", err)
+		}
+		if err := s.InsertClassificationHistory([]ClassificationRecord{{WorkItemID: 1, SectionID: "S0_0", Confidence: 0.9}}); err != nil {
+			t.Fatalf("InsertClassificationHistory: %v", err)
+		}
+
+		if _, err := platform.GetWorkItemByID(1); err != sql.ErrNoRows {
+			t.Fatalf("platform GetWorkItemByID: expected sql.ErrNoRows, got %v", err)
+		}
+		if _, err := platform.GetLatestClassification(1); err != sql.ErrNoRows {
+			t.Fatalf("platform GetLatestClassification: expected sql.ErrNoRows, got %v", err)
+		}
+		if events, err := platform.GetWorkItemEvents(1); err != nil || len(events) != 0 {
+			t.Fatalf("platform GetWorkItemEvents: %+v err=%v", events, err)
+		}
+		for name, write := range map[string]func() error{
+			"UpdateWorkItemTextAndStatus": func() error { return platform.UpdateWorkItemTextAndStatus(1, "Hijacked", "done", "U1") },
+			"UpdateWorkItemStatus":        func() error { return platform.UpdateWorkItemStatus(1, "done", "U1") },
+			"UpdateWorkItemFromSource":    func() error { return platform.UpdateWorkItemFromSource(1, "Hijacked", "done", time.Now(), "U1") },
+			"MarkWorkItemDoneFromNudge":   func() error { return platform.MarkWorkItemDoneFromNudge(1, "U1") },
+			"UpdateWorkItemCategory":      func() error { return platform.UpdateWorkItemCategory(1, "S9_0", "U1") },
+			"RestoreWorkItem":             func() error { return platform.RestoreWorkItem(1, "U1") },
+		} {
+			if err := write(); err != sql.ErrNoRows {
+				t.Fatalf("platform %s: expected sql.ErrNoRows, got %v", name, err)
+			}
+		}
+		if err := platform.UpdateCategories(map[int64]string{1: "S9_0"}); err != nil {
+			t.Fatalf("platform UpdateCategories: %v", err)
+		}
+		if err := platform.UpdateTicketIDs(map[int64]string{1: "999"}); err != nil {
+			t.Fatalf("platform UpdateTicketIDs: %v", err)
+		}
+		if err := platform.UpdateWorkItemAuthorID(1, "U1"); err != nil {
+			t.Fatalf("platform UpdateWorkItemAuthorID: %v", err)
+		}
+		if err := platform.DeleteWorkItemByID(1, "U1"); err != nil {
+			t.Fatalf("platform DeleteWorkItemByID: %v", err)
+		}
+
+		item, err := mobile.GetWorkItemByID(1)
+		if err != nil {
+			t.Fatalf("mobile GetWorkItemByID: %v", err)
+		}
+		if item.Description != "Mobile login fix" || item.Status != "in progress" || item.Category != "" || item.TicketIDs != "" || item.AuthorID != "" {
+			t.Fatalf("another team's writes changed the item: %+v", item)
+		}
+		if events, err := mobile.GetWorkItemEvents(1); err != nil || len(events) != 1 || events[0].EventType != domain.WorkItemEventCreated {
+			t.Fatalf("expected only the created event, got %+v err=%v", events, err)
+		}
+		// Only an item without an author ID gets one.
+		for _, authorID := range []string{"U2", "U3"} {
+			if err := mobile.UpdateWorkItemAuthorID(1, authorID); err != nil {
+				t.Fatalf("mobile UpdateWorkItemAuthorID: %v", err)
+			}
+		}
+		if item, err := mobile.GetWorkItemByID(1); err != nil || item.AuthorID != "U2" {
+			t.Fatalf("author_id = %q err=%v, want U2", item.AuthorID, err)
+		}
+		if err := mobile.DeleteWorkItemByID(1, "U2"); err != nil {
+			t.Fatalf("mobile DeleteWorkItemByID: %v", err)
+		}
+		if err := platform.RestoreWorkItem(1, "U1"); err != sql.ErrNoRows {
+			t.Fatalf("platform RestoreWorkItem of a deleted item: expected sql.ErrNoRows, got %v", err)
+		}
+	})
+}
+
+func TestStoreForTeamScopesClassificationsAndUsage(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		platform, mobile := ForTeam(s, "platform"), ForTeam(s, "mobile")
+		now := time.Now().UTC()
+		if err := platform.InsertWorkItem(WorkItem{Description: "Rotate certificates", Author: "Alice", Source: "slack", Status: "done", ReportedAt: now}, ""); err != nil {
+			t.Fatalf("platform InsertWorkItem: %v", err)
+		}
+		if err := mobile.InsertWorkItem(WorkItem{Description: "Rotate certificates", Author: "Bob", Source: "slack", Status: "done", ReportedAt: now}, ""); err != nil {
+			t.Fatalf("mobile InsertWorkItem: %v", err)
+		}
+		// S0_0 is a different section in each team's template.
+		if err := s.InsertClassificationHistory([]ClassificationRecord{
+			{WorkItemID: 1, SectionID: "S0_0", Confidence: 0.9, RawConfidence: 0.9, CacheKey: "shared"},
+			{WorkItemID: 2, SectionID: "S1_0", Confidence: 0.8, RawConfidence: 0.8},
+		}); err != nil {
+			t.Fatalf("InsertClassificationHistory: %v", err)
+		}
+		if err := s.InsertClassificationCorrection(ClassificationCorrection{WorkItemID: 2, OriginalSectionID: "S1_0", CorrectedSectionID: "S0_0", Description: "Rotate certificates"}); err != nil {
+			t.Fatalf("InsertClassificationCorrection: %v", err)
+		}
+		for _, rec := range []struct {
+			st   Store
+			cost float64
+		}{{platform, 1}, {mobile, 2}} {
+			if err := rec.st.RecordLLMUsage(LLMUsageRecord{Provider: "openai", Model: "m", Purpose: "classify", CostUSD: rec.cost, CreatedAt: now}); err != nil {
+				t.Fatalf("RecordLLMUsage: %v", err)
+			}
+		}
+
+		if cached, err := mobile.LookupClassifications([]string{"shared"}); err != nil || len(cached) != 0 {
+			t.Fatalf("mobile LookupClassifications: %+v err=%v", cached, err)
+		}
+		if cached, err := platform.LookupClassifications([]string{"shared"}); err != nil || cached["shared"].WorkItemID != 1 {
+			t.Fatalf("platform LookupClassifications: %+v err=%v", cached, err)
+		}
+		if samples, err := platform.GetConfidenceSamples(now.Add(-time.Hour), 10); err != nil || len(samples) != 1 || samples[0].Corrected {
+			t.Fatalf("platform GetConfidenceSamples: %+v err=%v", samples, err)
+		}
+		if labeled, err := mobile.GetLabeledWorkItems(now.Add(-time.Hour), 10); err != nil || len(labeled) != 1 || labeled[0].Item.ID != 2 || !labeled[0].Corrected {
+			t.Fatalf("mobile GetLabeledWorkItems: %+v err=%v", labeled, err)
+		}
+		if n, err := platform.CountCorrectionsByPhrase("Rotate certificates", "S0_0"); err != nil || n != 0 {
+			t.Fatalf("platform CountCorrectionsByPhrase: n=%d err=%v", n, err)
+		}
+		if n, err := mobile.CountCorrectionsByPhrase("Rotate certificates", "S0_0"); err != nil || n != 1 {
+			t.Fatalf("mobile CountCorrectionsByPhrase: n=%d err=%v", n, err)
+		}
+		if spend, err := platform.LLMSpendSince(now.Add(-time.Hour)); err != nil || spend != 1 {
+			t.Fatalf("platform LLMSpendSince: %v err=%v", spend, err)
+		}
+		if summary, err := mobile.GetLLMUsageSummary(now.Add(-time.Hour)); err != nil || len(summary) != 1 || summary[0].CostUSD != 2 {
+			t.Fatalf("mobile GetLLMUsageSummary: %+v err=%v", summary, err)
+		}
+		if spend, err := s.LLMSpendSince(now.Add(-time.Hour)); err != nil || spend != 3 {
+			t.Fatalf("unscoped LLMSpendSince: %v err=%v", spend, err)
+		}
+	})
+}
+
+func TestStoreClassificationHistoryAndCorrections(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		base := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+		if _, err := s.InsertWorkItems([]WorkItem{
+			{Description: "Fix DB timeout", Author: "Alice", Source: "slack", ReportedAt: base},
+			{Description: "Add SSO", Author: "Bob", Source: "slack", ReportedAt: base},
+		}, ""); err != nil {
+			t.Fatalf("InsertWorkItems: %v", err)
+		}
+		if err := s.InsertClassificationHistory([]ClassificationRecord{
+			{WorkItemID: 1, SectionID: "S0_0", SectionLabel: "Infra", Confidence: 0.9, RawConfidence: 0.92, AlternativeSectionIDs: "S1_0", CacheKey: "k1"},
+			{WorkItemID: 2, SectionID: "S0_0", SectionLabel: "Infra", Confidence: 0.6, RawConfidence: 0.6, CacheKey: "k2"},
+		}); err != nil {
+			t.Fatalf("InsertClassificationHistory: %v", err)
+		}
+		latest, err := s.GetLatestClassification(1)
+		if err != nil || latest.SectionID != "S0_0" || latest.AlternativeSectionIDs != "S1_0" || latest.CacheKey != "k1" {
+			t.Fatalf("GetLatestClassification: %+v err=%v", latest, err)
+		}
+
+		if err := s.InsertClassificationCorrection(ClassificationCorrection{
+			WorkItemID: 2, OriginalSectionID: "S0_0", OriginalLabel: "Infra",
+			CorrectedSectionID: "S1_0", CorrectedLabel: "Auth", Description: "Add SSO", CorrectedBy: "UMGR",
+		}); err != nil {
+			t.Fatalf("InsertClassificationCorrection: %v", err)
+		}
+
+		cached, err := s.LookupClassifications([]string{"k1", "k2", "missing"})
+		if err != nil {
+			t.Fatalf("LookupClassifications: %v", err)
+		}
+		if _, ok := cached["k1"]; !ok || len(cached) != 1 {
+			t.Fatalf("expected only the uncorrected decision cached, got %+v", cached)
+		}
+
+		since := base.Add(-time.Hour)
+		samples, err := s.GetConfidenceSamples(since, 10)
+		if err != nil || len(samples) != 2 {
+			t.Fatalf("GetConfidenceSamples: %+v err=%v", samples, err)
+		}
+		corrected := 0
+		for _, cs := range samples {
+			if cs.Corrected {
+				corrected++
+			}
+		}
+		if corrected != 1 {
+			t.Fatalf("expected one corrected sample, got %+v", samples)
+		}
+
+		historical, err := s.GetClassifiedItemsWithSections(since, 10)
+		if err != nil || len(historical) != 1 || historical[0].WorkItemID != 1 {
+			t.Fatalf("GetClassifiedItemsWithSections: %+v err=%v", historical, err)
+		}
+		labeled, err := s.GetLabeledWorkItems(since, 10)
+		if err != nil || len(labeled) != 2 || !labeled[1].Corrected || labeled[1].FinalSection != "S1_0" {
+			t.Fatalf("GetLabeledWorkItems: %+v err=%v", labeled, err)
+		}
+
+		corrections, err := s.GetRecentCorrections(since, 10)
+		if err != nil || len(corrections) != 1 || corrections[0].CorrectedBy != "UMGR" {
+			t.Fatalf("GetRecentCorrections: %+v err=%v", corrections, err)
+		}
+		if n, err := s.CountCorrectionsByPhrase("  add sso ", "S1_0"); err != nil || n != 1 {
+			t.Fatalf("CountCorrectionsByPhrase: %d err=%v", n, err)
+		}
+
+		stats, err := s.GetClassificationStats(since)
+		if err != nil || stats.TotalClassifications != 2 || stats.TotalCorrections != 1 || stats.Bucket50to70 != 1 || stats.Bucket90Plus != 1 {
+			t.Fatalf("GetClassificationStats: %+v err=%v", stats, err)
+		}
+		bySection, err := s.GetCorrectionsBySection(since)
+		if err != nil || len(bySection) != 1 || bySection[0].OriginalLabel != "Infra" || bySection[0].CorrectionCount != 1 {
+			t.Fatalf("GetCorrectionsBySection: %+v err=%v", bySection, err)
+		}
+		trend, err := s.GetWeeklyClassificationTrend(since)
+		if err != nil || len(trend) == 0 {
+			t.Fatalf("GetWeeklyClassificationTrend: %+v err=%v", trend, err)
+		}
+		week := trend[0]
+		if week.Classifications+trendTotal(trend[1:]) != 2 || len(week.WeekStart) != len("2006-01-02") {
+			t.Fatalf("unexpected trend: %+v", trend)
+		}
+	})
+}
+
+func trendTotal(trends []WeeklyTrend) int {
+	total := 0
+	for _, t := range trends {
+		total += t.Classifications
+	}
+	return total
+}
+
+func TestStoreEmbeddingsAndUsage(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		if err := s.SaveEmbeddings([]Embedding{{WorkItemID: 1, Model: "m", TextHash: "h1", Vector: []float32{0.5, -1}}}); err != nil {
+			t.Fatalf("SaveEmbeddings: %v", err)
+		}
+		if err := s.SaveEmbeddings([]Embedding{{WorkItemID: 1, Model: "m", TextHash: "h2", Vector: []float32{1, 2, 3}}}); err != nil {
+			t.Fatalf("SaveEmbeddings (replace): %v", err)
+		}
+		got, err := s.LoadEmbeddings("m", []int64{1, 2})
+		if err != nil || len(got) != 1 || got[1].TextHash != "h2" || len(got[1].Vector) != 3 || got[1].Vector[2] != 3 {
+			t.Fatalf("LoadEmbeddings: %+v err=%v", got, err)
+		}
+
+		now := time.Now().UTC()
+		for _, rec := range []LLMUsageRecord{
+			{Provider: "anthropic", Model: "m", Purpose: "classify", InputTokens: 100, OutputTokens: 10, CostUSD: 0.5, CreatedAt: now},
+			{Provider: "anthropic", Model: "m", Purpose: "classify", InputTokens: 50, CacheReadInputTokens: 50, CostUSD: 0.25, CreatedAt: now},
+			{Provider: "anthropic", Model: "m", Purpose: "critic", InputTokens: 10, CostUSD: 1, CreatedAt: now.AddDate(0, 0, -40)},
+		} {
+			if err := s.RecordLLMUsage(rec); err != nil {
+				t.Fatalf("RecordLLMUsage: %v", err)
+			}
+		}
+		spend, err := s.LLMSpendSince(now.AddDate(0, 0, -7))
+		if err != nil || spend != 0.75 {
+			t.Fatalf("LLMSpendSince: %v err=%v", spend, err)
+		}
+		summary, err := s.GetLLMUsageSummary(now.AddDate(0, 0, -7))
+		if err != nil || len(summary) != 1 || summary[0].Calls != 2 || summary[0].InputTokens != 200 {
+			t.Fatalf("GetLLMUsageSummary: %+v err=%v", summary, err)
+		}
+	})
+}
+
+type recordingArchive struct {
+	rows   map[string]int
+	closed bool
+}
+
+func (a *recordingArchive) Write(table string, row map[string]any) error {
+	if a.rows == nil {
+		a.rows = make(map[string]int)
+	}
+	if _, ok := row["id"]; !ok && table != "work_item_embeddings" {
+		return fmt.Errorf("%s row without id: %v", table, row)
+	}
+	a.rows[table]++
+	return nil
+}
+
+func (a *recordingArchive) Close() error { a.closed = true; return nil }
+
+func TestStorePurgeExpired(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		now := time.Now().UTC().Truncate(time.Second)
+		if _, err := s.InsertWorkItems([]WorkItem{
+			{Description: "Ancient fix", Author: "Alice", Source: "slack", ReportedAt: now.AddDate(-3, 0, 0)},
+			{Description: "Fresh fix", Author: "Bob", Source: "slack", ReportedAt: now},
+		}, ""); err != nil {
+			t.Fatalf("InsertWorkItems: %v", err)
+		}
+		if err := s.InsertClassificationHistory([]ClassificationRecord{
+			{WorkItemID: 1, SectionID: "S0_0", Confidence: 0.9},
+			{WorkItemID: 2, SectionID: "S0_0", Confidence: 0.9},
+		}); err != nil {
+			t.Fatalf("InsertClassificationHistory: %v", err)
+		}
+		if err := s.SaveEmbeddings([]Embedding{{WorkItemID: 1, Model: "m", TextHash: "h", Vector: []float32{1}}}); err != nil {
+			t.Fatalf("SaveEmbeddings: %v", err)
+		}
+		for _, c := range []ClassificationCorrection{
+			{WorkItemID: 1, OriginalSectionID: "S0_0", CorrectedSectionID: "S1_0"},
+			{WorkItemID: 2, OriginalSectionID: "S0_0", CorrectedSectionID: "S1_0"},
+			{WorkItemID: 2, OriginalSectionID: "S1_0", CorrectedSectionID: "S2_0"},
+		} {
+			if err := s.InsertClassificationCorrection(c); err != nil {
+				t.Fatalf("InsertClassificationCorrection: %v", err)
+			}
+		}
+
+		policy := RetentionPolicy{WorkItemsBefore: now.AddDate(-2, 0, 0), SupersededCorrections: true}
+		want := map[string]int{
+			"work_items": 1, "work_item_events": 1, "work_item_embeddings": 1,
+			"classification_history": 1, "classification_corrections": 2,
+		}
+		dry, err := s.PurgeExpired(policy, nil, true)
+		if err != nil {
+			t.Fatalf("PurgeExpired dry run: %v", err)
+		}
+		for table, n := range want {
+			if dry[table] != n {
+				t.Fatalf("dry run %s = %d, want %d (all: %v)", table, dry[table], n, dry)
+			}
+		}
+		if _, err := s.GetWorkItemByID(1); err != nil {
+			t.Fatalf("dry run deleted item 1: %v", err)
+		}
+
+		archive := &recordingArchive{}
+		deleted, err := s.PurgeExpired(policy, archive, false)
+		if err != nil {
+			t.Fatalf("PurgeExpired: %v", err)
+		}
+		if !archive.closed {
+			t.Fatal("archive not closed before commit")
+		}
+		for table, n := range want {
+			if deleted[table] != n || archive.rows[table] != n {
+				t.Fatalf("%s: deleted %d, archived %d, want %d", table, deleted[table], archive.rows[table], n)
+			}
+		}
+		if _, err := s.GetWorkItemByID(1); err == nil {
+			t.Fatal("expected item 1 purged")
+		}
+		corrections, err := s.GetRecentCorrections(now.AddDate(0, 0, -1), 10)
+		if err != nil || len(corrections) != 1 || corrections[0].CorrectedSectionID != "S2_0" {
+			t.Fatalf("expected only the newest correction kept: %+v err=%v", corrections, err)
+		}
+
+		again, err := s.PurgeExpired(policy, nil, false)
+		if err != nil {
+			t.Fatalf("PurgeExpired again: %v", err)
+		}
+		for table, n := range again {
+			if n != 0 {
+				t.Fatalf("second purge removed %d %s", n, table)
+			}
+		}
+	})
+}
+
+func TestStorePurgeCorrectionsDropsOverriddenDecisions(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		if _, err := s.InsertWorkItems([]WorkItem{
+			{Description: "Corrected fix", Author: "Alice", Source: "slack", ReportedAt: time.Now()},
+			{Description: "Accepted fix", Author: "Bob", Source: "slack", ReportedAt: time.Now()},
+		}, ""); err != nil {
+			t.Fatalf("InsertWorkItems: %v", err)
+		}
+		if err := s.InsertClassificationHistory([]ClassificationRecord{
+			{WorkItemID: 1, SectionID: "S0_0", Confidence: 0.9, CacheKey: "k1"},
+			{WorkItemID: 2, SectionID: "S0_0", Confidence: 0.9, CacheKey: "k2"},
+		}); err != nil {
+			t.Fatalf("InsertClassificationHistory: %v", err)
+		}
+		if err := s.InsertClassificationCorrection(ClassificationCorrection{WorkItemID: 1, OriginalSectionID: "S0_0", CorrectedSectionID: "S1_0"}); err != nil {
+			t.Fatalf("InsertClassificationCorrection: %v", err)
+		}
+
+		deleted, err := s.PurgeExpired(RetentionPolicy{CorrectionsBefore: time.Now().UTC().Add(time.Hour)}, nil, false)
+		if err != nil {
+			t.Fatalf("PurgeExpired: %v", err)
+		}
+		if deleted["classification_corrections"] != 1 || deleted["classification_history"] != 1 {
+			t.Fatalf("expected the correction and the decision it overrode purged, got %v", deleted)
+		}
+		cached, err := s.LookupClassifications([]string{"k1", "k2"})
+		if err != nil {
+			t.Fatalf("LookupClassifications: %v", err)
+		}
+		if _, ok := cached["k1"]; ok || cached["k2"].WorkItemID != 2 {
+			t.Fatalf("expected only the uncorrected decision cached, got %+v", cached)
+		}
+	})
+}
+
+func TestStoreExportImportState(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		src, err := sqlite.Open(filepath.Join(t.TempDir(), "export-src.db"))
+		if err != nil {
+			t.Fatalf("sqlite.Open: %v", err)
+		}
+		defer src.Close()
+		base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+		if _, err := src.InsertWorkItems([]WorkItem{
+			{Description: "Slack item", Author: "Alice", AuthorID: "U1", Source: "slack", Status: "done", ReportedAt: base},
+			{Description: "MR", Author: "Bob", Source: "gitlab", SourceRef: "https://gitlab/mr/1", Status: "done", ReportedAt: base},
+			{Description: "Gone", Author: "Bob", Source: "slack", ReportedAt: base},
+		}, ""); err != nil {
+			t.Fatalf("InsertWorkItems: %v", err)
+		}
+		if err := src.DeleteWorkItemByID(3, "U1"); err != nil {
+			t.Fatalf("DeleteWorkItemByID: %v", err)
+		}
+		if err := src.InsertClassificationHistory([]ClassificationRecord{
+			{WorkItemID: 1, SectionID: "S0_0", Confidence: 0.9, CacheKey: "k1"},
+			{WorkItemID: 2, SectionID: "S1_0", Confidence: 0.8},
+			{WorkItemID: 3, SectionID: "S1_0", Confidence: 0.8},
+		}); err != nil {
+			t.Fatalf("InsertClassificationHistory: %v", err)
+		}
+		if err := src.InsertClassificationCorrection(ClassificationCorrection{
+			WorkItemID: 2, OriginalSectionID: "S1_0", CorrectedSectionID: "S0_0", CorrectedBy: "UMGR",
+		}); err != nil {
+			t.Fatalf("InsertClassificationCorrection: %v", err)
+		}
+
+		snap, err := src.ExportState()
+		if err != nil {
+			t.Fatalf("ExportState: %v", err)
+		}
+		if len(snap.WorkItems) != 2 || len(snap.ClassificationHistory) != 2 || len(snap.Corrections) != 1 {
+			t.Fatalf("expected deleted item excluded, got %d items, %d history, %d corrections",
+				len(snap.WorkItems), len(snap.ClassificationHistory), len(snap.Corrections))
+		}
+
+		// The target already has the MR under another ID.
+		if _, err := s.InsertWorkItems([]WorkItem{
+			{Description: "Other", Author: "Carol", Source: "slack", ReportedAt: base},
+			{Description: "MR (edited)", Author: "Bob", Source: "gitlab", SourceRef: "https://gitlab/mr/1", Status: "done", ReportedAt: base},
+		}, ""); err != nil {
+			t.Fatalf("InsertWorkItems target: %v", err)
+		}
+		res, err := s.ImportState(snap)
+		if err != nil {
+			t.Fatalf("ImportState: %v", err)
+		}
+		want := ImportResult{WorkItemsInserted: 1, WorkItemsExisting: 1, HistoryInserted: 2, CorrectionsInserted: 1}
+		if res != want {
+			t.Fatalf("first import = %+v, want %+v", res, want)
+		}
+		mr, err := s.GetLatestClassification(2)
+		if err != nil || mr.SectionID != "S1_0" {
+			t.Fatalf("history not attached to the existing MR row: %+v err=%v", mr, err)
+		}
+
+		res, err = s.ImportState(snap)
+		if err != nil {
+			t.Fatalf("second ImportState: %v", err)
+		}
+		want = ImportResult{WorkItemsExisting: 2, HistoryExisting: 2, CorrectionsExisting: 1}
+		if res != want {
+			t.Fatalf("second import = %+v, want %+v", res, want)
+		}
+
+		// Round trip back into the source changes nothing either.
+		back, err := s.ExportState()
+		if err != nil {
+			t.Fatalf("ExportState target: %v", err)
+		}
+		res, err = src.ImportState(back)
+		if err != nil {
+			t.Fatalf("ImportState back: %v", err)
+		}
+		if res.HistoryInserted != 0 || res.CorrectionsInserted != 0 || res.WorkItemsInserted != 1 {
+			t.Fatalf("import back = %+v, want only Carol's item inserted", res)
+		}
+	})
+}
+
+func TestStoreLinkIdentity(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		if _, err := s.GetIdentity("U1"); err != sql.ErrNoRows {
+			t.Fatalf("expected sql.ErrNoRows before linking, got %v", err)
+		}
+		if err := s.LinkIdentity("U1", domain.IdentityGitLab, " @JDoe "); err != nil {
+			t.Fatalf("LinkIdentity gitlab: %v", err)
+		}
+		// Identities are shared by all teams.
+		if err := ForTeam(s, "web").LinkIdentity("U1", domain.IdentityGitHub, "jdoe-gh"); err != nil {
+			t.Fatalf("LinkIdentity github: %v", err)
+		}
+		id, err := s.GetIdentity("U1")
+		if err != nil || id.GitLabUsername != "jdoe" || id.GitHubLogin != "jdoe-gh" || id.Email != "" {
+			t.Fatalf("identity: %+v err=%v", id, err)
+		}
+
+		if err := s.LinkIdentity("U2", domain.IdentityGitLab, "JDOE"); err != domain.ErrIdentityTaken {
+			t.Fatalf("expected ErrIdentityTaken, got %v", err)
+		}
+		if err := s.LinkIdentity("U1", "bitbucket", "jdoe"); err == nil {
+			t.Fatal("expected an error for an unknown account kind")
+		}
+
+		// Unlinking frees the account for someone else.
+		if err := s.LinkIdentity("U1", domain.IdentityGitLab, ""); err != nil {
+			t.Fatalf("unlink: %v", err)
+		}
+		if err := s.LinkIdentity("U2", domain.IdentityGitLab, "jdoe"); err != nil {
+			t.Fatalf("LinkIdentity after unlink: %v", err)
+		}
+		list, err := s.ListIdentities()
+		if err != nil || len(list) != 2 || list[0].GitLabUsername != "" || list[1].GitLabUsername != "jdoe" {
+			t.Fatalf("ListIdentities: %+v err=%v", list, err)
+		}
+
+		// Reassigning moves a taken account instead of failing.
+		if err := s.ReassignIdentity("U1", domain.IdentityGitLab, "JDoe"); err != nil {
+			t.Fatalf("ReassignIdentity: %v", err)
+		}
+		if id, err := s.GetIdentity("U1"); err != nil || id.GitLabUsername != "jdoe" || !id.GitLabConfirmed || id.GitHubLogin != "jdoe-gh" || id.GitHubConfirmed {
+			t.Fatalf("reassigned identity: %+v err=%v", id, err)
+		}
+		if id, err := s.GetIdentity("U2"); err != nil || id.GitLabUsername != "" || id.GitLabConfirmed {
+			t.Fatalf("previous owner: %+v err=%v", id, err)
+		}
+
+		// Relinking the confirmed account keeps the confirmation; linking
+		// another one drops it.
+		if err := s.LinkIdentity("U1", domain.IdentityGitLab, "jdoe"); err != nil {
+			t.Fatalf("relink: %v", err)
+		}
+		if id, _ := s.GetIdentity("U1"); !id.GitLabConfirmed {
+			t.Fatalf("relinking dropped the confirmation: %+v", id)
+		}
+		if err := s.LinkIdentity("U1", domain.IdentityGitLab, "jdoe2"); err != nil {
+			t.Fatalf("link another account: %v", err)
+		}
+		if err := s.LinkIdentity("U1", domain.IdentityEmail, "J.Doe@Example.com"); err != nil {
+			t.Fatalf("link email: %v", err)
+		}
+		if id, _ := s.GetIdentity("U1"); id.GitLabUsername != "jdoe2" || id.GitLabConfirmed || id.Email != "j.doe@example.com" || id.EmailConfirmed {
+			t.Fatalf("self-service links: %+v", id)
+		}
+	})
+}
+
+func TestOpenAppliesEveryMigration(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		statuses, err := s.(Migrator).ListMigrations()
+		if err != nil {
+			t.Fatalf("ListMigrations: %v", err)
+		}
+		if len(statuses) != sqlite.LatestMigration() {
+			t.Fatalf("expected %d migrations (same numbering on every backend), got %+v", sqlite.LatestMigration(), statuses)
+		}
+		for _, st := range statuses {
+			if !st.Applied || !st.Known {
+				t.Fatalf("expected migration %d applied, got %+v", st.Version, st)
+			}
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+var csvHeader = []string{
+	"id", "reported_at", "author", "author_id", "source", "source_ref",
+	"status", "category", "ticket_ids", "description",
+}
+
+// WriteCSV writes items as a spreadsheet-friendly CSV with a header row.
+// Times are RFC 3339 in loc.
+func WriteCSV(w io.Writer, items []WorkItem, loc *time.Location) error {
+	if loc == nil {
+		loc = time.UTC
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := cw.Write([]string{
+			strconv.FormatInt(item.ID, 10),
+			item.ReportedAt.In(loc).Format(time.RFC3339),
+			item.Author,
+			item.AuthorID,
+			item.Source,
+			item.SourceRef,
+			item.Status,
+			item.Category,
+			item.TicketIDs,
+			item.Description,
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
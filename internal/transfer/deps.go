@@ -0,0 +1,13 @@
+package transfer
+
+import (
+	"reportbot/internal/config"
+	"reportbot/internal/domain"
+	"reportbot/internal/storage"
+)
+
+type Config = config.Config
+type Store = storage.Store
+type WorkItem = domain.WorkItem
+type StateSnapshot = domain.StateSnapshot
+type ImportResult = domain.ImportResult
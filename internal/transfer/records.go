@@ -0,0 +1,101 @@
+package transfer
+
+import (
+	"reportbot/internal/domain"
+	"time"
+)
+
+// The archive stores rows with explicit JSON names so the format does not
+// follow renames of the domain structs. Bump FormatVersion on any
+// incompatible change.
+
+type workItemRecord struct {
+	ID          int64     `json:"id"`
+	Description string    `json:"description"`
+	Author      string    `json:"author"`
+	AuthorID    string    `json:"author_id,omitempty"`
+	Source      string    `json:"source"`
+	SourceRef   string    `json:"source_ref,omitempty"`
+	Category    string    `json:"category,omitempty"`
+	Status      string    `json:"status"`
+	TicketIDs   string    `json:"ticket_ids,omitempty"`
+	ReportedAt  time.Time `json:"reported_at"`
+	TeamID      string    `json:"team_id,omitempty"`
+}
+
+type historyRecord struct {
+	WorkItemID            int64     `json:"work_item_id"`
+	SectionID             string    `json:"section_id"`
+	SectionLabel          string    `json:"section_label,omitempty"`
+	Confidence            float64   `json:"confidence"`
+	RawConfidence         float64   `json:"raw_confidence,omitempty"`
+	AlternativeSectionIDs string    `json:"alternative_section_ids,omitempty"`
+	NormalizedStatus      string    `json:"normalized_status,omitempty"`
+	TicketIDs             string    `json:"ticket_ids,omitempty"`
+	DuplicateOf           string    `json:"duplicate_of,omitempty"`
+	LLMProvider           string    `json:"llm_provider,omitempty"`
+	LLMModel              string    `json:"llm_model,omitempty"`
+	CacheKey              string    `json:"cache_key,omitempty"`
+	ClassifiedAt          time.Time `json:"classified_at"`
+}
+
+type correctionRecord struct {
+	WorkItemID         int64     `json:"work_item_id"`
+	OriginalSectionID  string    `json:"original_section_id"`
+	OriginalLabel      string    `json:"original_label,omitempty"`
+	CorrectedSectionID string    `json:"corrected_section_id"`
+	CorrectedLabel     string    `json:"corrected_label,omitempty"`
+	Description        string    `json:"description,omitempty"`
+	CorrectedBy        string    `json:"corrected_by,omitempty"`
+	CorrectedAt        time.Time `json:"corrected_at"`
+}
+
+func toWorkItemRecord(item WorkItem) workItemRecord {
+	return workItemRecord{
+		ID: item.ID, Description: item.Description, Author: item.Author, AuthorID: item.AuthorID,
+		Source: item.Source, SourceRef: item.SourceRef, Category: item.Category, Status: item.Status,
+		TicketIDs: item.TicketIDs, ReportedAt: item.ReportedAt, TeamID: item.TeamID,
+	}
+}
+
+func (r workItemRecord) toDomain() WorkItem {
+	return WorkItem{
+		ID: r.ID, Description: r.Description, Author: r.Author, AuthorID: r.AuthorID,
+		Source: r.Source, SourceRef: r.SourceRef, Category: r.Category, Status: r.Status,
+		TicketIDs: r.TicketIDs, ReportedAt: r.ReportedAt, TeamID: r.TeamID,
+	}
+}
+
+func toHistoryRecord(r domain.ClassificationRecord) historyRecord {
+	return historyRecord{
+		WorkItemID: r.WorkItemID, SectionID: r.SectionID, SectionLabel: r.SectionLabel,
+		Confidence: r.Confidence, RawConfidence: r.RawConfidence, AlternativeSectionIDs: r.AlternativeSectionIDs,
+		NormalizedStatus: r.NormalizedStatus, TicketIDs: r.TicketIDs, DuplicateOf: r.DuplicateOf,
+		LLMProvider: r.LLMProvider, LLMModel: r.LLMModel, CacheKey: r.CacheKey, ClassifiedAt: r.ClassifiedAt,
+	}
+}
+
+func (h historyRecord) toDomain() domain.ClassificationRecord {
+	return domain.ClassificationRecord{
+		WorkItemID: h.WorkItemID, SectionID: h.SectionID, SectionLabel: h.SectionLabel,
+		Confidence: h.Confidence, RawConfidence: h.RawConfidence, AlternativeSectionIDs: h.AlternativeSectionIDs,
+		NormalizedStatus: h.NormalizedStatus, TicketIDs: h.TicketIDs, DuplicateOf: h.DuplicateOf,
+		LLMProvider: h.LLMProvider, LLMModel: h.LLMModel, CacheKey: h.CacheKey, ClassifiedAt: h.ClassifiedAt,
+	}
+}
+
+func toCorrectionRecord(c domain.ClassificationCorrection) correctionRecord {
+	return correctionRecord{
+		WorkItemID: c.WorkItemID, OriginalSectionID: c.OriginalSectionID, OriginalLabel: c.OriginalLabel,
+		CorrectedSectionID: c.CorrectedSectionID, CorrectedLabel: c.CorrectedLabel,
+		Description: c.Description, CorrectedBy: c.CorrectedBy, CorrectedAt: c.CorrectedAt,
+	}
+}
+
+func (c correctionRecord) toDomain() domain.ClassificationCorrection {
+	return domain.ClassificationCorrection{
+		WorkItemID: c.WorkItemID, OriginalSectionID: c.OriginalSectionID, OriginalLabel: c.OriginalLabel,
+		CorrectedSectionID: c.CorrectedSectionID, CorrectedLabel: c.CorrectedLabel,
+		Description: c.Description, CorrectedBy: c.CorrectedBy, CorrectedAt: c.CorrectedAt,
+	}
+}
@@ -0,0 +1,372 @@
+// Package transfer moves the bot state between hosts: `reportbot export`
+// writes work items, classification history, corrections, the glossary and
+// generated report files to a versioned .tar.gz archive, and
+// `reportbot import` restores it idempotently.
+package transfer
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// FormatName identifies a reportbot export archive.
+	FormatName = "reportbot-export"
+	// FormatVersion is the archive layout this binary writes and the newest
+	// it can read.
+	FormatVersion = 1
+
+	manifestEntry    = "manifest.json"
+	workItemsEntry   = "work_items.jsonl"
+	historyEntry     = "classification_history.jsonl"
+	correctionsEntry = "classification_corrections.jsonl"
+	glossaryEntry    = "glossary.yaml"
+	reportsDir       = "reports/"
+)
+
+// Manifest is the first entry of every archive.
+type Manifest struct {
+	Format                string    `json:"format"`
+	Version               int       `json:"version"`
+	CreatedAt             time.Time `json:"created_at"`
+	TeamName              string    `json:"team_name"`
+	WorkItems             int       `json:"work_items"`
+	ClassificationHistory int       `json:"classification_history"`
+	Corrections           int       `json:"corrections"`
+	Glossary              bool      `json:"glossary"`
+	ReportFiles           int       `json:"report_files"`
+}
+
+// ImportSummary reports what an import changed.
+type ImportSummary struct {
+	Manifest Manifest
+	DB       ImportResult
+	// Files are the glossary and report files: written, already identical,
+	// or left alone because a different file exists and overwrite is off.
+	FilesWritten, FilesUnchanged, FilesSkipped int
+}
+
+// Export writes the archive to w.
+func Export(w io.Writer, cfg Config, db Store, now time.Time) (Manifest, error) {
+	snap, err := db.ExportState()
+	if err != nil {
+		return Manifest{}, err
+	}
+	m := Manifest{
+		Format:                FormatName,
+		Version:               FormatVersion,
+		CreatedAt:             now.UTC(),
+		TeamName:              cfg.TeamName,
+		WorkItems:             len(snap.WorkItems),
+		ClassificationHistory: len(snap.ClassificationHistory),
+		Corrections:           len(snap.Corrections),
+	}
+
+	var glossary []byte
+	if cfg.LLMGlossaryPath != "" {
+		glossary, err = os.ReadFile(cfg.LLMGlossaryPath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return m, fmt.Errorf("read glossary: %w", err)
+		}
+		m.Glossary = err == nil
+	}
+	reports, err := listReportFiles(cfg.ReportOutputDir)
+	if err != nil {
+		return m, err
+	}
+	m.ReportFiles = len(reports)
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return m, err
+	}
+	if err := writeEntry(tw, manifestEntry, append(manifest, '\n'), now); err != nil {
+		return m, err
+	}
+	workItems := make([]any, 0, len(snap.WorkItems))
+	for _, item := range snap.WorkItems {
+		workItems = append(workItems, toWorkItemRecord(item))
+	}
+	history := make([]any, 0, len(snap.ClassificationHistory))
+	for _, r := range snap.ClassificationHistory {
+		history = append(history, toHistoryRecord(r))
+	}
+	corrections := make([]any, 0, len(snap.Corrections))
+	for _, c := range snap.Corrections {
+		corrections = append(corrections, toCorrectionRecord(c))
+	}
+	for _, e := range []struct {
+		name string
+		rows []any
+	}{
+		{workItemsEntry, workItems},
+		{historyEntry, history},
+		{correctionsEntry, corrections},
+	} {
+		data, err := encodeJSONL(e.rows)
+		if err != nil {
+			return m, fmt.Errorf("encode %s: %w", e.name, err)
+		}
+		if err := writeEntry(tw, e.name, data, now); err != nil {
+			return m, err
+		}
+	}
+	if m.Glossary {
+		if err := writeEntry(tw, glossaryEntry, glossary, now); err != nil {
+			return m, err
+		}
+	}
+	for _, rel := range reports {
+		data, err := os.ReadFile(filepath.Join(cfg.ReportOutputDir, filepath.FromSlash(rel)))
+		if err != nil {
+			return m, fmt.Errorf("read report %s: %w", rel, err)
+		}
+		if err := writeEntry(tw, reportsDir+rel, data, now); err != nil {
+			return m, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return m, err
+	}
+	return m, gz.Close()
+}
+
+// Import restores an archive written by Export. The database part is
+// applied in one transaction and is idempotent; files are written only when
+// missing unless overwrite is set.
+func Import(r io.Reader, cfg Config, db Store, overwrite bool) (ImportSummary, error) {
+	var sum ImportSummary
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return sum, fmt.Errorf("not a reportbot export archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	var snap StateSnapshot
+	files := map[string][]byte{}
+	for first := true; ; first = false {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return sum, fmt.Errorf("read archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name, err := cleanEntryName(hdr.Name)
+		if err != nil {
+			return sum, err
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return sum, fmt.Errorf("read %s: %w", name, err)
+		}
+		if first {
+			if name != manifestEntry {
+				return sum, fmt.Errorf("not a reportbot export archive: first entry is %q", name)
+			}
+			if err := json.Unmarshal(data, &sum.Manifest); err != nil {
+				return sum, fmt.Errorf("read manifest: %w", err)
+			}
+			if sum.Manifest.Format != FormatName {
+				return sum, fmt.Errorf("not a reportbot export archive: format %q", sum.Manifest.Format)
+			}
+			if sum.Manifest.Version > FormatVersion {
+				return sum, fmt.Errorf("archive format version %d is newer than this binary supports (%d)", sum.Manifest.Version, FormatVersion)
+			}
+			continue
+		}
+		switch {
+		case name == workItemsEntry:
+			err = decodeJSONL(data, func(rec workItemRecord) { snap.WorkItems = append(snap.WorkItems, rec.toDomain()) })
+		case name == historyEntry:
+			err = decodeJSONL(data, func(rec historyRecord) {
+				snap.ClassificationHistory = append(snap.ClassificationHistory, rec.toDomain())
+			})
+		case name == correctionsEntry:
+			err = decodeJSONL(data, func(rec correctionRecord) { snap.Corrections = append(snap.Corrections, rec.toDomain()) })
+		case name == glossaryEntry, strings.HasPrefix(name, reportsDir):
+			files[name] = data
+		}
+		if err != nil {
+			return sum, fmt.Errorf("read %s: %w", name, err)
+		}
+	}
+	if sum.Manifest.Format == "" {
+		return sum, fmt.Errorf("not a reportbot export archive: empty")
+	}
+
+	sum.DB, err = db.ImportState(snap)
+	if err != nil {
+		return sum, fmt.Errorf("import database: %w", err)
+	}
+
+	for name, data := range files {
+		var dest string
+		if name == glossaryEntry {
+			if cfg.LLMGlossaryPath == "" {
+				sum.FilesSkipped++
+				continue
+			}
+			dest = cfg.LLMGlossaryPath
+		} else {
+			dest = filepath.Join(cfg.ReportOutputDir, filepath.FromSlash(strings.TrimPrefix(name, reportsDir)))
+		}
+		written, err := restoreFile(dest, data, overwrite)
+		if err != nil {
+			return sum, err
+		}
+		switch written {
+		case fileWritten:
+			sum.FilesWritten++
+		case fileUnchanged:
+			sum.FilesUnchanged++
+		default:
+			sum.FilesSkipped++
+		}
+	}
+	return sum, nil
+}
+
+// FormatImportSummary renders an import result for the command line.
+func FormatImportSummary(s ImportSummary) string {
+	return fmt.Sprintf(
+		"imported archive from %s (%s, format v%d)\n"+
+			"work items: %d new, %d already present\n"+
+			"classification history: %d new, %d already present\n"+
+			"corrections: %d new, %d already present\n"+
+			"files: %d written, %d unchanged, %d skipped (differ locally; use -overwrite)",
+		s.Manifest.CreatedAt.Format(time.RFC3339), s.Manifest.TeamName, s.Manifest.Version,
+		s.DB.WorkItemsInserted, s.DB.WorkItemsExisting,
+		s.DB.HistoryInserted, s.DB.HistoryExisting,
+		s.DB.CorrectionsInserted, s.DB.CorrectionsExisting,
+		s.FilesWritten, s.FilesUnchanged, s.FilesSkipped,
+	)
+}
+
+const (
+	fileWritten = iota
+	fileUnchanged
+	fileSkipped
+)
+
+func restoreFile(dest string, data []byte, overwrite bool) (int, error) {
+	existing, err := os.ReadFile(dest)
+	switch {
+	case err == nil && bytes.Equal(existing, data):
+		return fileUnchanged, nil
+	case err == nil && !overwrite:
+		return fileSkipped, nil
+	case err != nil && !errors.Is(err, fs.ErrNotExist):
+		return 0, fmt.Errorf("read %s: %w", dest, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(dest, data, 0644); err != nil {
+		return 0, fmt.Errorf("write %s: %w", dest, err)
+	}
+	return fileWritten, nil
+}
+
+// listReportFiles returns the regular files under dir as slash-separated
+// relative paths. A missing dir has no reports.
+func listReportFiles(dir string) ([]string, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	var out []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && p == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		out = append(out, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list reports: %w", err)
+	}
+	return out, nil
+}
+
+// cleanEntryName rejects absolute paths and paths escaping the archive root.
+func cleanEntryName(name string) (string, error) {
+	clean := path.Clean(strings.TrimPrefix(name, "./"))
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("unsafe path in archive: %q", name)
+	}
+	return clean, nil
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+func encodeJSONL(rows []any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeJSONL[T any](data []byte, add func(T)) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var rec T
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		add(rec)
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,136 @@
+package transfer
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reportbot/internal/domain"
+	"reportbot/internal/storage/sqlite"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openTestStore(t *testing.T) *sqlite.Store {
+	t.Helper()
+	s, err := sqlite.Open(filepath.Join(t.TempDir(), "transfer.db"))
+	if err != nil {
+		t.Fatalf("sqlite.Open: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	src := openTestStore(t)
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	if _, err := src.InsertWorkItems([]WorkItem{
+		{Description: "Fix login", Author: "Alice", Source: "slack", Status: "done", ReportedAt: base},
+		{Description: "MR", Author: "Bob", Source: "github", SourceRef: "https://github.com/o/r/pull/1", Status: "done", ReportedAt: base},
+	}, ""); err != nil {
+		t.Fatalf("InsertWorkItems: %v", err)
+	}
+	if err := src.InsertClassificationHistory([]domain.ClassificationRecord{{WorkItemID: 1, SectionID: "S0_0", Confidence: 0.9}}); err != nil {
+		t.Fatalf("InsertClassificationHistory: %v", err)
+	}
+
+	srcDir := t.TempDir()
+	srcCfg := Config{
+		TeamName:        "Team",
+		LLMGlossaryPath: filepath.Join(srcDir, "glossary.yaml"),
+		ReportOutputDir: filepath.Join(srcDir, "reports"),
+	}
+	os.WriteFile(srcCfg.LLMGlossaryPath, []byte("terms: []\n"), 0644)
+	os.MkdirAll(filepath.Join(srcCfg.ReportOutputDir, "archive"), 0755)
+	os.WriteFile(filepath.Join(srcCfg.ReportOutputDir, "TEAM_20260306.md"), []byte("# report\n"), 0644)
+	os.WriteFile(filepath.Join(srcCfg.ReportOutputDir, "archive", "old.md"), []byte("# old\n"), 0644)
+
+	var buf bytes.Buffer
+	m, err := Export(&buf, srcCfg, src, base)
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if m.WorkItems != 2 || m.ClassificationHistory != 1 || m.ReportFiles != 2 || !m.Glossary {
+		t.Fatalf("unexpected manifest: %+v", m)
+	}
+
+	dst := openTestStore(t)
+	dstDir := t.TempDir()
+	dstCfg := Config{
+		LLMGlossaryPath: filepath.Join(dstDir, "glossary.yaml"),
+		ReportOutputDir: filepath.Join(dstDir, "reports"),
+	}
+	os.WriteFile(dstCfg.LLMGlossaryPath, []byte("terms: [local]\n"), 0644)
+
+	sum, err := Import(bytes.NewReader(buf.Bytes()), dstCfg, dst, false)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if sum.DB.WorkItemsInserted != 2 || sum.DB.HistoryInserted != 1 || sum.FilesWritten != 2 || sum.FilesSkipped != 1 {
+		t.Fatalf("unexpected first import: %+v", sum)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dstCfg.ReportOutputDir, "archive", "old.md")); string(data) != "# old\n" {
+		t.Fatalf("nested report not restored: %q", data)
+	}
+	if data, _ := os.ReadFile(dstCfg.LLMGlossaryPath); string(data) != "terms: [local]\n" {
+		t.Fatalf("local glossary overwritten without -overwrite: %q", data)
+	}
+
+	sum, err = Import(bytes.NewReader(buf.Bytes()), dstCfg, dst, true)
+	if err != nil {
+		t.Fatalf("second Import: %v", err)
+	}
+	if sum.DB.WorkItemsInserted != 0 || sum.DB.WorkItemsExisting != 2 || sum.DB.HistoryExisting != 1 ||
+		sum.FilesUnchanged != 2 || sum.FilesWritten != 1 {
+		t.Fatalf("unexpected second import: %+v", sum)
+	}
+	if data, _ := os.ReadFile(dstCfg.LLMGlossaryPath); string(data) != "terms: []\n" {
+		t.Fatalf("glossary not overwritten: %q", data)
+	}
+}
+
+func TestImportRejectsBadArchives(t *testing.T) {
+	archive := func(entries map[string]string, order ...string) []byte {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		for _, name := range order {
+			writeEntry(tw, name, []byte(entries[name]), time.Now())
+		}
+		tw.Close()
+		gz.Close()
+		return buf.Bytes()
+	}
+	manifest := `{"format":"reportbot-export","version":1}`
+	cases := map[string][]byte{
+		"newer version": archive(map[string]string{manifestEntry: `{"format":"reportbot-export","version":99}`}, manifestEntry),
+		"no manifest":   archive(map[string]string{workItemsEntry: ""}, workItemsEntry),
+		"path escape":   archive(map[string]string{manifestEntry: manifest, "../evil": "x"}, manifestEntry, "../evil"),
+		"not gzip":      []byte("plain text"),
+	}
+	for name, data := range cases {
+		if _, err := Import(bytes.NewReader(data), Config{}, openTestStore(t), false); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, []WorkItem{{
+		ID: 7, Author: "Alice", Source: "slack", Status: "done",
+		Description: `Fix "quoted", comma`, ReportedAt: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+	}}, time.UTC)
+	if err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || lines[0] != strings.Join(csvHeader, ",") {
+		t.Fatalf("unexpected csv:\n%s", buf.String())
+	}
+	if want := `7,2026-03-02T09:00:00Z,Alice,,slack,,done,,,"Fix ""quoted"", comma"`; lines[1] != want {
+		t.Fatalf("row = %s\nwant  %s", lines[1], want)
+	}
+}
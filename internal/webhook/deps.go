@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"reportbot/internal/config"
+	"reportbot/internal/domain"
+	"reportbot/internal/fetch"
+	gh "reportbot/internal/integrations/github"
+	gl "reportbot/internal/integrations/gitlab"
+	"reportbot/internal/storage"
+)
+
+type Config = config.Config
+type Store = storage.Store
+type WorkItem = domain.WorkItem
+type GitLabMR = domain.GitLabMR
+type GitHubPR = domain.GitHubPR
+type FetchResult = fetch.FetchResult
+
+const (
+	gitlabMergeRequestEvent = gl.MergeRequestHookEvent
+	githubPullRequestEvent  = gh.PullRequestEvent
+)
+
+func ForTeam(db Store, teamID string) Store {
+	return storage.ForTeam(db, teamID)
+}
+
+func GetWorkItemBySourceRef(db Store, sourceRef string) (WorkItem, error) {
+	return db.GetWorkItemBySourceRef(sourceRef)
+}
+
+func IsTeamAuthor(cfg Config, name, username string, author fetch.Link) bool {
+	return fetch.IsTeamAuthor(cfg, name, username, author)
+}
+
+func LoadIdentities(db Store) fetch.Identities {
+	return fetch.LoadIdentities(db)
+}
+
+func ImportMR(cfg Config, db Store, mr GitLabMR) (FetchResult, error) {
+	return fetch.ImportMR(cfg, db, mr)
+}
+
+func ImportPR(cfg Config, db Store, pr GitHubPR) (FetchResult, error) {
+	return fetch.ImportPR(cfg, db, pr)
+}
+
+func VerifyGitLabToken(token, secret string) bool {
+	return gl.VerifyWebhookToken(token, secret)
+}
+
+func ParseMergeRequestHook(body []byte, ticketFieldLabel string) (gl.MergeRequestHook, error) {
+	return gl.ParseMergeRequestHook(body, ticketFieldLabel)
+}
+
+// fetchGitLabUser is a variable so tests can resolve authors without a
+// GitLab server.
+var fetchGitLabUser = func(cfg Config, id int64) (string, string, error) {
+	return gl.FetchUser(cfg, id)
+}
+
+func VerifyGitHubSignature(body []byte, signature, secret string) bool {
+	return gh.VerifyWebhookSignature(body, signature, secret)
+}
+
+func ParsePullRequestEvent(body []byte) (GitHubPR, string, error) {
+	return gh.ParsePullRequestEvent(body)
+}
+
+func RepoInScope(cfg Config, repo string) bool {
+	return gh.RepoInScope(cfg, repo)
+}
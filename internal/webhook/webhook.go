@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	GitLabPath = "/webhooks/gitlab"
+	GitHubPath = "/webhooks/github"
+
+	// maxBodyBytes bounds a webhook body; MR and PR payloads are far smaller.
+	maxBodyBytes = 5 << 20
+)
+
+// Start serves the webhook receiver on webhook_listen_addr in the background.
+// It is a no-op when the address is empty.
+func Start(cfg Config, db Store) {
+	addr := strings.TrimSpace(cfg.WebhookListenAddr)
+	if addr == "" {
+		log.Println("Webhook receiver disabled (webhook_listen_addr not set)")
+		return
+	}
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           NewHandler(cfg, db),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Printf("Webhook receiver listening on %s (gitlab=%t github=%t)",
+		addr, cfg.GitLabWebhookSecret != "", cfg.GitHubWebhookSecret != "")
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Webhook receiver error: %v", err)
+		}
+	}()
+}
+
+type handler struct {
+	cfg Config
+	db  Store
+}
+
+// NewHandler routes POST /webhooks/gitlab (merge request hooks) and POST
+// /webhooks/github (pull_request events). A source is only routed when its
+// secret is configured. Each MR/PR is imported into the team of its tracked
+// item or, when new, the first team listing its author as a member.
+func NewHandler(cfg Config, db Store) http.Handler {
+	h := &handler{cfg: cfg, db: db}
+	mux := http.NewServeMux()
+	if cfg.GitLabWebhookSecret != "" {
+		mux.HandleFunc("POST "+GitLabPath, h.handleGitLab)
+	}
+	if cfg.GitHubWebhookSecret != "" {
+		mux.HandleFunc("POST "+GitHubPath, h.handleGitHub)
+	}
+	return mux
+}
+
+func (h *handler) handleGitLab(w http.ResponseWriter, r *http.Request) {
+	if !VerifyGitLabToken(r.Header.Get("X-Gitlab-Token"), h.cfg.GitLabWebhookSecret) {
+		log.Printf("webhook gitlab rejected: bad token from %s", r.RemoteAddr)
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+	if event := r.Header.Get("X-Gitlab-Event"); event != gitlabMergeRequestEvent {
+		fmt.Fprintf(w, "ignored %s\n", event)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		http.Error(w, "reading body", http.StatusBadRequest)
+		return
+	}
+	hook, err := ParseMergeRequestHook(body, h.cfg.GitLabRefTicketLabel)
+	if err != nil {
+		log.Printf("webhook gitlab parse error: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	mr := hook.MR
+	if mr.Author == "" && !h.authorFromTrackedItem(mr.WebURL, &mr.AuthorName, &mr.Author) {
+		if hook.AuthorID == 0 || h.cfg.GitLabURL == "" || h.cfg.GitLabToken == "" {
+			log.Printf("webhook gitlab ignored ref=%s: unknown author", mr.WebURL)
+			fmt.Fprintln(w, "ignored: unknown author")
+			return
+		}
+		mr.Author, mr.AuthorName, err = fetchGitLabUser(h.cfg, hook.AuthorID)
+		if err != nil {
+			log.Printf("webhook gitlab author lookup error id=%d: %v", hook.AuthorID, err)
+			http.Error(w, "author lookup failed", http.StatusBadGateway)
+			return
+		}
+	}
+
+	h.apply(w, "gitlab", mr.WebURL, mr.State, mr.AuthorName, mr.Author, mr.AuthorEmail, func(cfg Config, db Store) (FetchResult, error) {
+		return ImportMR(cfg, db, mr)
+	})
+}
+
+func (h *handler) handleGitHub(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		http.Error(w, "reading body", http.StatusBadRequest)
+		return
+	}
+	if !VerifyGitHubSignature(body, r.Header.Get("X-Hub-Signature-256"), h.cfg.GitHubWebhookSecret) {
+		log.Printf("webhook github rejected: bad signature from %s", r.RemoteAddr)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	if event := r.Header.Get("X-GitHub-Event"); event != githubPullRequestEvent {
+		// Includes the ping GitHub sends when the hook is created.
+		fmt.Fprintf(w, "ignored %s\n", event)
+		return
+	}
+	pr, action, err := ParsePullRequestEvent(body)
+	if err != nil {
+		log.Printf("webhook github parse error: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !RepoInScope(h.cfg, pr.RepoFullName) {
+		log.Printf("webhook github ignored ref=%s: repo %s not in github_org/github_repos", pr.HTMLURL, pr.RepoFullName)
+		fmt.Fprintln(w, "ignored: repository not configured")
+		return
+	}
+	log.Printf("webhook github action=%s ref=%s", action, pr.HTMLURL)
+
+	h.apply(w, "github", pr.HTMLURL, pr.State, pr.AuthorName, pr.Author, pr.AuthorEmail, func(cfg Config, db Store) (FetchResult, error) {
+		return ImportPR(cfg, db, pr)
+	})
+}
+
+// apply imports one MR/PR into its team and reports the outcome.
+func (h *handler) apply(w http.ResponseWriter, source, ref, state, name, username, email string, importFn func(Config, Store) (FetchResult, error)) {
+	cfg, db, ok := h.teamFor(source, ref, name, username, email)
+	if !ok {
+		log.Printf("webhook %s skipped non-team author=%s username=%s ref=%s", source, name, username, ref)
+		fmt.Fprintln(w, "ignored: non-team author")
+		return
+	}
+	result, err := importFn(cfg, db)
+	if err != nil {
+		log.Printf("webhook %s import error ref=%s: %v", source, ref, err)
+		http.Error(w, "import failed", http.StatusInternalServerError)
+		return
+	}
+	log.Printf("webhook %s ref=%s state=%s team=%s inserted=%d updated=%d closed=%d alreadyTracked=%d skippedNonTeam=%d",
+		source, ref, state, cfg.TeamName, result.Inserted, result.Updated, result.Closed, result.AlreadyTracked, result.SkippedNonTeam)
+	fmt.Fprintf(w, "ok inserted=%d updated=%d closed=%d\n", result.Inserted, result.Updated, result.Closed)
+}
+
+// teamFor picks the team an MR/PR belongs to: the team of its tracked item
+// or, for a new one, the first team with the author as a member, by name or
+// through a linked account the team trusts. A single-team deployment applies
+// its own team_members filter in the import.
+func (h *handler) teamFor(source, ref, name, username, email string) (Config, Store, bool) {
+	if !h.cfg.MultiTeam() {
+		return h.cfg, h.db, true
+	}
+	item, err := GetWorkItemBySourceRef(h.db, ref)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("webhook lookup error ref=%s: %v", ref, err)
+	}
+	for _, teamCfg := range h.cfg.TeamConfigs() {
+		if err == nil && teamCfg.TeamID == item.TeamID {
+			return teamCfg, ForTeam(h.db, teamCfg.TeamID), true
+		}
+	}
+	author := LoadIdentities(h.db).Author(source, username, email)
+	for _, teamCfg := range h.cfg.TeamConfigs() {
+		if IsTeamAuthor(teamCfg, name, username, author) {
+			return teamCfg, ForTeam(h.db, teamCfg.TeamID), true
+		}
+	}
+	return h.cfg, h.db, false
+}
+
+// authorFromTrackedItem fills in the author of an MR that is already
+// tracked, so events triggered by someone else (a merge by a reviewer) need
+// no GitLab API call.
+func (h *handler) authorFromTrackedItem(ref string, name, username *string) bool {
+	item, err := GetWorkItemBySourceRef(h.db, ref)
+	if err != nil || strings.TrimSpace(item.Author) == "" {
+		return false
+	}
+	*name = item.Author
+	*username = item.Author
+	return true
+}
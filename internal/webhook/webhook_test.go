@@ -0,0 +1,259 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reportbot/internal/config"
+	"reportbot/internal/storage/sqlite"
+	"testing"
+	"time"
+)
+
+const (
+	testGitLabSecret = "gl-secret"
+	testGitHubSecret = "gh-secret"
+	gitlabRef        = "https://gitlab.example.com/platform/api/-/merge_requests/318"
+	githubRef        = "https://github.com/acme/web/pull/57"
+)
+
+func testConfig() Config {
+	return Config{
+		TeamName:            "Platform",
+		TeamMembers:         []string{"Alice Smith", "bob"},
+		GitHubOrg:           "acme",
+		GitLabWebhookSecret: testGitLabSecret,
+		GitHubWebhookSecret: testGitHubSecret,
+		Location:            time.UTC,
+	}
+}
+
+func openTestStore(t *testing.T) *sqlite.Store {
+	t.Helper()
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "webhook.db"))
+	if err != nil {
+		t.Fatalf("sqlite.Open: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func readPayload(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("read payload: %v", err)
+	}
+	return body
+}
+
+func postGitLab(t *testing.T, h http.Handler, payload, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, GitLabPath, bytes.NewReader(readPayload(t, payload)))
+	req.Header.Set("X-Gitlab-Event", "Merge Request Hook")
+	req.Header.Set("X-Gitlab-Token", token)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func postGitHub(t *testing.T, h http.Handler, event, payload, secret string) *httptest.ResponseRecorder {
+	t.Helper()
+	body := readPayload(t, payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	req := httptest.NewRequest(http.MethodPost, GitHubPath, bytes.NewReader(body))
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGitLabHookImportsAndReconciles(t *testing.T) {
+	db := openTestStore(t)
+	h := NewHandler(testConfig(), db)
+
+	if rec := postGitLab(t, h, "gitlab_mr_open.json", "wrong"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("bad token: status %d", rec.Code)
+	}
+	if _, err := db.GetWorkItemBySourceRef(gitlabRef); err == nil {
+		t.Fatal("expected nothing stored for a rejected hook")
+	}
+
+	if rec := postGitLab(t, h, "gitlab_mr_open.json", testGitLabSecret); rec.Code != http.StatusOK {
+		t.Fatalf("open hook: status %d %s", rec.Code, rec.Body)
+	}
+	item, err := db.GetWorkItemBySourceRef(gitlabRef)
+	if err != nil {
+		t.Fatalf("GetWorkItemBySourceRef: %v", err)
+	}
+	if item.Author != "Alice Smith" || item.Status != "in progress" || item.TicketIDs != "" || item.Source != "gitlab" {
+		t.Fatalf("imported item: %+v", item)
+	}
+
+	// The merge is triggered by a reviewer; the author comes from the
+	// tracked item.
+	if rec := postGitLab(t, h, "gitlab_mr_merge.json", testGitLabSecret); rec.Code != http.StatusOK {
+		t.Fatalf("merge hook: status %d %s", rec.Code, rec.Body)
+	}
+	item, err = db.GetWorkItemBySourceRef(gitlabRef)
+	if err != nil {
+		t.Fatalf("GetWorkItemBySourceRef: %v", err)
+	}
+	mergedAt := time.Date(2026, 3, 5, 16, 40, 2, 0, time.UTC)
+	if item.Status != "done" || !item.ReportedAt.Equal(mergedAt) {
+		t.Fatalf("merged item: %+v", item)
+	}
+}
+
+func TestGitLabHookResolvesAuthorOfNewMR(t *testing.T) {
+	db := openTestStore(t)
+	cfg := testConfig()
+	cfg.GitLabURL = "https://gitlab.example.com"
+	cfg.GitLabToken = "glpat-test"
+	orig := fetchGitLabUser
+	defer func() { fetchGitLabUser = orig }()
+	var lookedUp int64
+	fetchGitLabUser = func(_ Config, id int64) (string, string, error) {
+		lookedUp = id
+		return "asmith", "Alice Smith", nil
+	}
+
+	if rec := postGitLab(t, NewHandler(cfg, db), "gitlab_mr_merge.json", testGitLabSecret); rec.Code != http.StatusOK {
+		t.Fatalf("merge hook: status %d %s", rec.Code, rec.Body)
+	}
+	item, err := db.GetWorkItemBySourceRef(gitlabRef)
+	if lookedUp != 42 || err != nil || item.Author != "Alice Smith" || item.Status != "done" {
+		t.Fatalf("lookup=%d item=%+v err=%v", lookedUp, item, err)
+	}
+}
+
+func TestGitLabHookMatchesLinkedCommitEmail(t *testing.T) {
+	db := openTestStore(t)
+	if err := db.ReassignIdentity("U_ALICE", "email", "Alice@Example.com"); err != nil {
+		t.Fatalf("ReassignIdentity: %v", err)
+	}
+	cfg := testConfig()
+	cfg.TeamMembers = []string{"Carol"}
+	cfg.GitLabURL = "https://gitlab.example.com"
+	cfg.GitLabToken = "glpat-test"
+	orig := fetchGitLabUser
+	defer func() { fetchGitLabUser = orig }()
+	fetchGitLabUser = func(Config, int64) (string, string, error) { return "as42", "A. S.", nil }
+
+	if rec := postGitLab(t, NewHandler(cfg, db), "gitlab_mr_merge.json", testGitLabSecret); rec.Code != http.StatusOK {
+		t.Fatalf("merge hook: status %d %s", rec.Code, rec.Body)
+	}
+	item, err := db.GetWorkItemBySourceRef(gitlabRef)
+	if err != nil || item.AuthorID != "U_ALICE" {
+		t.Fatalf("item: %+v err=%v", item, err)
+	}
+}
+
+func TestGitHubEventImportsAndFlagsClosed(t *testing.T) {
+	db := openTestStore(t)
+	h := NewHandler(testConfig(), db)
+
+	if rec := postGitHub(t, h, "pull_request", "github_pr_opened.json", "wrong"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("bad signature: status %d", rec.Code)
+	}
+	if rec := postGitHub(t, h, "ping", "github_ping.json", testGitHubSecret); rec.Code != http.StatusOK {
+		t.Fatalf("ping: status %d", rec.Code)
+	}
+
+	if rec := postGitHub(t, h, "pull_request", "github_pr_opened.json", testGitHubSecret); rec.Code != http.StatusOK {
+		t.Fatalf("opened: status %d %s", rec.Code, rec.Body)
+	}
+	item, err := db.GetWorkItemBySourceRef(githubRef)
+	if err != nil || item.Author != "bob" || item.Status != "in progress" {
+		t.Fatalf("imported item: %+v err=%v", item, err)
+	}
+
+	if rec := postGitHub(t, h, "pull_request", "github_pr_closed.json", testGitHubSecret); rec.Code != http.StatusOK {
+		t.Fatalf("closed: status %d %s", rec.Code, rec.Body)
+	}
+	item, err = db.GetWorkItemBySourceRef(githubRef)
+	if err != nil || item.Status != "closed, not merged" || !item.ReportedAt.Equal(time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)) {
+		t.Fatalf("closed item: %+v err=%v", item, err)
+	}
+}
+
+func TestGitHubEventSkipsNonTeamAndOtherRepos(t *testing.T) {
+	db := openTestStore(t)
+
+	cfg := testConfig()
+	cfg.TeamMembers = []string{"Alice Smith"}
+	if rec := postGitHub(t, NewHandler(cfg, db), "pull_request", "github_pr_opened.json", testGitHubSecret); rec.Code != http.StatusOK {
+		t.Fatalf("non-team: status %d", rec.Code)
+	}
+	cfg = testConfig()
+	cfg.GitHubOrg = ""
+	cfg.GitHubRepos = []string{"acme/api"}
+	if rec := postGitHub(t, NewHandler(cfg, db), "pull_request", "github_pr_opened.json", testGitHubSecret); rec.Code != http.StatusOK {
+		t.Fatalf("other repo: status %d", rec.Code)
+	}
+	if exists, _ := db.SourceRefExists(githubRef); exists {
+		t.Fatal("expected the PR to be skipped")
+	}
+	// Closed without ever being tracked: nothing to flag.
+	if rec := postGitHub(t, NewHandler(testConfig(), db), "pull_request", "github_pr_closed.json", testGitHubSecret); rec.Code != http.StatusOK {
+		t.Fatalf("closed: status %d", rec.Code)
+	}
+	if exists, _ := db.SourceRefExists(githubRef); exists {
+		t.Fatal("expected a closed, untracked PR not to be imported")
+	}
+}
+
+func TestWebhookRoutesToAuthorsTeam(t *testing.T) {
+	db := openTestStore(t)
+	cfg := testConfig()
+	cfg.TeamMembers = nil
+	cfg.Teams = []config.Team{
+		{ID: "payments", Name: "Payments", Members: []string{"Alice Smith"}},
+		{ID: "web", Name: "Web", Members: []string{"bob"}},
+	}
+
+	if rec := postGitHub(t, NewHandler(cfg, db), "pull_request", "github_pr_opened.json", testGitHubSecret); rec.Code != http.StatusOK {
+		t.Fatalf("opened: status %d %s", rec.Code, rec.Body)
+	}
+	item, err := db.GetWorkItemBySourceRef(githubRef)
+	if err != nil || item.TeamID != "web" {
+		t.Fatalf("item: %+v err=%v", item, err)
+	}
+}
+
+func TestWebhookRoutesLinkedAuthorToListingTeam(t *testing.T) {
+	db := openTestStore(t)
+	if err := db.LinkIdentity("U0B0B", "github", "bob"); err != nil {
+		t.Fatalf("LinkIdentity: %v", err)
+	}
+	cfg := testConfig()
+	cfg.TeamMembers = nil
+	cfg.Teams = []config.Team{
+		{ID: "payments", Name: "Payments", Members: []string{"Alice Smith"}},
+		{ID: "web", Name: "Web", Members: []string{"U0B0B"}},
+	}
+
+	if rec := postGitHub(t, NewHandler(cfg, db), "pull_request", "github_pr_opened.json", testGitHubSecret); rec.Code != http.StatusOK {
+		t.Fatalf("opened: status %d %s", rec.Code, rec.Body)
+	}
+	item, err := db.GetWorkItemBySourceRef(githubRef)
+	if err != nil || item.TeamID != "web" || item.AuthorID != "U0B0B" {
+		t.Fatalf("item: %+v err=%v", item, err)
+	}
+}
+
+func TestNewHandlerOnlyRoutesConfiguredSources(t *testing.T) {
+	cfg := testConfig()
+	cfg.GitHubWebhookSecret = ""
+	rec := postGitHub(t, NewHandler(cfg, openTestStore(t)), "pull_request", "github_pr_opened.json", "")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without github_webhook_secret, got %d", rec.Code)
+	}
+}